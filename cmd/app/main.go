@@ -23,6 +23,7 @@ import (
 	"github.com/Harvey-AU/adapt/internal/loops"
 	"github.com/Harvey-AU/adapt/internal/notifications"
 	"github.com/Harvey-AU/adapt/internal/observability"
+	"github.com/Harvey-AU/adapt/internal/storage"
 	"github.com/getsentry/sentry-go"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
@@ -39,10 +40,16 @@ func minInt(a, b int) int {
 	return b
 }
 
+// schedulerClaimDuration is how long a claimed scheduler is held before it
+// becomes due again if the claiming instance fails to create its job
+const schedulerClaimDuration = 5 * time.Minute
+
 // startJobScheduler starts background service to create jobs from schedulers
 // It respects context cancellation for graceful shutdown
 // The WaitGroup must be marked Done when this function exits
-func startJobScheduler(ctx context.Context, wg *sync.WaitGroup, jobsManager *jobs.JobManager, pgDB *db.DB) {
+// Only the elected leader polls; due schedulers are also claimed atomically
+// so a brief overlap during leader handover cannot fire a scheduler twice
+func startJobScheduler(ctx context.Context, wg *sync.WaitGroup, jobsManager *jobs.JobManager, pgDB *db.DB, leader *db.LeaderElector) {
 	defer wg.Done()
 
 	ticker := time.NewTicker(30 * time.Second)
@@ -56,7 +63,11 @@ func startJobScheduler(ctx context.Context, wg *sync.WaitGroup, jobsManager *job
 			log.Info().Msg("Job scheduler stopped")
 			return
 		case <-ticker.C:
-			schedulers, err := pgDB.GetSchedulersReadyToRun(ctx, 50)
+			if !leader.IsLeader() {
+				continue
+			}
+
+			schedulers, err := pgDB.ClaimSchedulersReadyToRun(ctx, 50, schedulerClaimDuration)
 			if err != nil {
 				log.Error().Err(err).Msg("Failed to get schedulers ready to run")
 				continue
@@ -81,6 +92,13 @@ func startJobScheduler(ctx context.Context, wg *sync.WaitGroup, jobsManager *job
 					continue
 				}
 
+				now := time.Now().UTC()
+				nextRun, err := scheduler.NextRunAfter(now)
+				if err != nil {
+					log.Error().Err(err).Str("scheduler_id", scheduler.ID).Msg("Failed to calculate scheduler next run")
+					continue
+				}
+
 				// Check if a job started too recently (within half the schedule interval)
 				lastJobStart, err := pgDB.GetLastJobStartTimeForScheduler(ctx, scheduler.ID)
 				if err != nil {
@@ -89,7 +107,7 @@ func startJobScheduler(ctx context.Context, wg *sync.WaitGroup, jobsManager *job
 				}
 
 				if lastJobStart != nil {
-					minInterval := time.Duration(scheduler.ScheduleIntervalHours) * time.Hour / 2
+					minInterval := scheduler.MinRunGap(now)
 					timeSinceLastJob := time.Since(*lastJobStart)
 
 					if timeSinceLastJob < minInterval {
@@ -101,7 +119,6 @@ func startJobScheduler(ctx context.Context, wg *sync.WaitGroup, jobsManager *job
 							Msg("Skipping scheduled job - last job started too recently")
 
 						// Update next_run_at to the next valid time slot
-						nextRun := time.Now().UTC().Add(time.Duration(scheduler.ScheduleIntervalHours) * time.Hour)
 						if err := pgDB.UpdateSchedulerNextRun(ctx, scheduler.ID, nextRun); err != nil {
 							log.Error().Err(err).Str("scheduler_id", scheduler.ID).Msg("Failed to update scheduler next run")
 						}
@@ -127,6 +144,23 @@ func startJobScheduler(ctx context.Context, wg *sync.WaitGroup, jobsManager *job
 					SchedulerID:              &scheduler.ID,
 				}
 
+				// Delta schedulers compare the sitemap with their last completed
+				// run and only warm what changed, with a periodic full crawl
+				if scheduler.DeltaMode {
+					opts.TrackSitemapChanges = true
+					baselineJobID, deltaRuns, err := pgDB.GetDeltaBaseline(ctx, scheduler.ID)
+					if err != nil {
+						log.Warn().Err(err).Str("scheduler_id", scheduler.ID).Msg("Failed to get delta baseline, running a full crawl")
+					} else if baselineJobID != "" {
+						opts.BaselineJobID = &baselineJobID
+						if scheduler.UseDeltaRun(deltaRuns) {
+							opts.CrawlMode = jobs.CrawlModeDelta
+							// Links from changed pages would re-warm unchanged ones
+							opts.FindLinks = false
+						}
+					}
+				}
+
 				// Create job (standard flow)
 				job, err := jobsManager.CreateJob(ctx, opts)
 				if err != nil {
@@ -135,7 +169,6 @@ func startJobScheduler(ctx context.Context, wg *sync.WaitGroup, jobsManager *job
 				}
 
 				// Update scheduler next_run_at
-				nextRun := time.Now().UTC().Add(time.Duration(scheduler.ScheduleIntervalHours) * time.Hour)
 				if err := pgDB.UpdateSchedulerNextRun(ctx, scheduler.ID, nextRun); err != nil {
 					log.Error().Err(err).Str("scheduler_id", scheduler.ID).Msg("Failed to update scheduler next run")
 				} else {
@@ -151,10 +184,152 @@ func startJobScheduler(ctx context.Context, wg *sync.WaitGroup, jobsManager *job
 	}
 }
 
+const (
+	// keepWarmInterval is how often each keep-warm scheduler is checked
+	keepWarmInterval = 10 * time.Minute
+	// keepWarmLead is how long before expiry a page is re-warmed
+	keepWarmLead = 5 * time.Minute
+	// keepWarmDefaultPages caps a keep-warm job when the scheduler has no page limit
+	keepWarmDefaultPages = 500
+)
+
+// startKeepWarm re-warms pages from keep-warm schedulers shortly before their
+// edge cache entries expire. Every keepWarmInterval each scheduler gets a
+// small job for the pages due to go cold before the next check, busiest first.
+// It respects context cancellation for graceful shutdown
+// The WaitGroup must be marked Done when this function exits
+func startKeepWarm(ctx context.Context, wg *sync.WaitGroup, jobsManager *jobs.JobManager, pgDB *db.DB, leader *db.LeaderElector) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	log.Info().Msg("Keep-warm scheduler started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Keep-warm scheduler stopped")
+			return
+		case <-ticker.C:
+			if !leader.IsLeader() {
+				continue
+			}
+
+			schedulers, err := pgDB.ClaimKeepWarmSchedulers(ctx, keepWarmInterval, 50)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to get keep-warm schedulers")
+				continue
+			}
+			if len(schedulers) == 0 {
+				continue
+			}
+
+			domainIDs := make([]int, 0, len(schedulers))
+			for _, scheduler := range schedulers {
+				domainIDs = append(domainIDs, scheduler.DomainID)
+			}
+
+			domainNames, err := pgDB.GetDomainNames(ctx, domainIDs)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to get domain names for keep-warm schedulers")
+				continue
+			}
+
+			// Warm anything that would go cold before the next check, plus a lead
+			horizon := time.Now().UTC().Add(keepWarmInterval + keepWarmLead)
+
+			for _, scheduler := range schedulers {
+				domainName, ok := domainNames[scheduler.DomainID]
+				if !ok {
+					log.Warn().Int("domain_id", scheduler.DomainID).Str("scheduler_id", scheduler.ID).Msg("Domain name not found")
+					continue
+				}
+
+				limit := scheduler.MaxPages
+				if limit <= 0 {
+					limit = keepWarmDefaultPages
+				}
+
+				pages, err := pgDB.GetKeepWarmPages(ctx, scheduler.ID, horizon, limit)
+				if err != nil {
+					log.Error().Err(err).Str("scheduler_id", scheduler.ID).Msg("Failed to get keep-warm pages")
+					continue
+				}
+				if len(pages) == 0 {
+					continue
+				}
+
+				opts := &jobs.JobOptions{
+					Domain:          domainName,
+					OrganisationID:  &scheduler.OrganisationID,
+					Concurrency:     scheduler.Concurrency,
+					RequiredWorkers: scheduler.RequiredWorkers,
+					SourceDetail:    &scheduler.ID,
+					SchedulerID:     &scheduler.ID,
+				}
+
+				job, err := jobsManager.CreateKeepWarmJob(ctx, opts, pages)
+				if err != nil {
+					log.Error().Err(err).Str("scheduler_id", scheduler.ID).Msg("Failed to create keep-warm job")
+					continue
+				}
+
+				log.Info().
+					Str("scheduler_id", scheduler.ID).
+					Str("job_id", job.ID).
+					Str("domain", domainName).
+					Int("pages", len(pages)).
+					Msg("Created keep-warm job")
+			}
+		}
+	}
+}
+
+// storageRetentionInterval is how often stored crawl artefacts are checked
+// against the retention period
+const storageRetentionInterval = 6 * time.Hour
+
+// startStorageRetention deletes crawl artefacts older than the retention
+// period from storage, keeping any the database still points at
+// It respects context cancellation for graceful shutdown
+// The WaitGroup must be marked Done when this function exits
+func startStorageRetention(ctx context.Context, wg *sync.WaitGroup, backend storage.Backend, pgDB *db.DB, retention time.Duration, leader *db.LeaderElector) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(storageRetentionInterval)
+	defer ticker.Stop()
+
+	log.Info().Dur("retention", retention).Msg("Storage retention started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Storage retention stopped")
+			return
+		case <-ticker.C:
+			if !leader.IsLeader() {
+				continue
+			}
+
+			cutoff := time.Now().Add(-retention)
+			deleted, err := storage.DeleteOlderThan(ctx, backend, "", cutoff, pgDB.StorageKeysInUse)
+			if err != nil {
+				log.Error().Err(err).Int("deleted", deleted).Msg("Failed to delete expired crawl artefacts")
+				continue
+			}
+			if deleted > 0 {
+				log.Info().Int("deleted", deleted).Time("cutoff", cutoff).Msg("Deleted expired crawl artefacts")
+			}
+		}
+	}
+}
+
 // startHealthMonitoring starts background monitoring for job completion and system health
 // It respects context cancellation for graceful shutdown
 // The WaitGroup must be marked Done when this function exits
-func startHealthMonitoring(ctx context.Context, wg *sync.WaitGroup, pgDB *db.DB) {
+// Periodic checks only run on the elected leader to avoid duplicate alerts
+func startHealthMonitoring(ctx context.Context, wg *sync.WaitGroup, pgDB *db.DB, leader *db.LeaderElector) {
 	defer wg.Done() // Signal completion when exiting
 
 	completionTicker := time.NewTicker(30 * time.Second)
@@ -359,9 +534,13 @@ func startHealthMonitoring(ctx context.Context, wg *sync.WaitGroup, pgDB *db.DB)
 			log.Info().Msg("Health monitoring stopped")
 			return
 		case <-completionTicker.C:
-			checkJobCompletion()
+			if leader.IsLeader() {
+				checkJobCompletion()
+			}
 		case <-healthTicker.C:
-			checkSystemHealth()
+			if leader.IsLeader() {
+				checkSystemHealth()
+			}
 		}
 	}
 }
@@ -567,6 +746,7 @@ func main() {
 
 	// Initialise crawler
 	crawlerConfig := crawler.DefaultConfig()
+	crawlerConfig.MaxRedirects = getEnvInt("CRAWLER_MAX_REDIRECTS", crawlerConfig.MaxRedirects)
 	cr := crawler.New(crawlerConfig) // QUESTION: Should we change cr to crawler for clarity, as others have clearer names.
 
 	// Create database queue for operations
@@ -631,6 +811,19 @@ func main() {
 	// Set the job manager in the worker pool for duplicate checking
 	workerPool.SetJobManager(jobsManager)
 
+	// Open storage for crawl artefacts (non-fatal if not configured)
+	storageConfig := storage.ConfigFromEnv()
+	storageBackend, storageErr := storage.New(storageConfig)
+	switch {
+	case errors.Is(storageErr, storage.ErrNotConfigured):
+		log.Debug().Msg("Storage not configured - page HTML will not be stored (set BBB_STORAGE_BACKEND or SUPABASE_SERVICE_ROLE_KEY)")
+	case storageErr != nil:
+		log.Error().Err(storageErr).Msg("Failed to open storage - page HTML will not be stored")
+	default:
+		workerPool.SetStorage(storageBackend)
+		log.Info().Str("backend", storageConfig.SelectedDriver()).Str("bucket", storageBackend.Bucket()).Msg("Storage initialised for crawl artefacts")
+	}
+
 	// Create notification service with Slack channel
 	notificationService := notifications.NewService(pgDB)
 	slackChannel, err := notifications.NewSlackChannel(pgDB)
@@ -682,6 +875,11 @@ func main() {
 	// Setup API routes
 	apiHandler.SetupRoutes(mux)
 
+	// Serve signed URLs for artefacts kept in local storage
+	if local, ok := storageBackend.(*storage.Local); ok && local.MountPath() != "" {
+		mux.Handle(local.MountPath(), http.StripPrefix(strings.TrimSuffix(local.MountPath(), "/"), local))
+	}
+
 	// Create middleware stack with rate limiting.
 	// Static assets are excluded — browsers request many files in parallel
 	// on hard refresh and these are cheap to serve.
@@ -772,13 +970,28 @@ func main() {
 		log.Info().Msg("Worker pool stopped - all tasks completed and batches flushed")
 	}()
 
+	// Elect a single instance to run the scheduler and health monitoring loops
+	leaderElector := db.NewLeaderElector(pgDB, db.SchedulerLeaderLease)
+	backgroundWG.Go(func() {
+		leaderElector.Run(appCtx)
+	})
+
 	// Start background health monitoring with cancellable context
 	backgroundWG.Add(1)
-	go startHealthMonitoring(appCtx, &backgroundWG, pgDB)
+	go startHealthMonitoring(appCtx, &backgroundWG, pgDB, leaderElector)
 
 	// Start scheduler service
 	backgroundWG.Add(1)
-	go startJobScheduler(appCtx, &backgroundWG, jobsManager, pgDB)
+	go startJobScheduler(appCtx, &backgroundWG, jobsManager, pgDB, leaderElector)
+
+	backgroundWG.Add(1)
+	go startKeepWarm(appCtx, &backgroundWG, jobsManager, pgDB, leaderElector)
+
+	// Delete old crawl artefacts when a retention period is set
+	if storageBackend != nil && storageConfig.RetentionDays > 0 {
+		backgroundWG.Add(1)
+		go startStorageRetention(appCtx, &backgroundWG, storageBackend, pgDB, storageConfig.Retention(), leaderElector)
+	}
 
 	// Start notification listener (uses polling mode with Supabase pooler)
 	backgroundWG.Go(func() {
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Harvey-AU/adapt/internal/crawler"
+)
+
+// maxCacheReportPages caps the per-page list in a cache report response
+const maxCacheReportPages = 500
+
+// CacheReportSummary aggregates cacheability across a set of pages
+type CacheReportSummary struct {
+	Pages            int            `json:"pages"`
+	Cacheable        int            `json:"cacheable"`
+	Uncacheable      int            `json:"uncacheable"`
+	CacheablePercent float64        `json:"cacheable_percent"`
+	Reasons          map[string]int `json:"reasons"`
+	Warnings         map[string]int `json:"warnings"`
+	MinEdgeTTL       *int64         `json:"min_edge_ttl,omitempty"`
+	MedianEdgeTTL    *int64         `json:"median_edge_ttl,omitempty"`
+
+	ttls []int64
+}
+
+// CacheReportDirectory is the cache summary for one directory of a site
+type CacheReportDirectory struct {
+	Directory string `json:"directory"`
+	*CacheReportSummary
+}
+
+// CacheReportPage is the cacheability of a single page
+type CacheReportPage struct {
+	Path        string `json:"path"`
+	StatusCode  int    `json:"status_code"`
+	CacheStatus string `json:"cache_status,omitempty"`
+	crawler.Cacheability
+}
+
+func newCacheReportSummary() *CacheReportSummary {
+	return &CacheReportSummary{Reasons: map[string]int{}, Warnings: map[string]int{}}
+}
+
+func (s *CacheReportSummary) add(result crawler.Cacheability) {
+	s.Pages++
+	if result.Cacheable {
+		s.Cacheable++
+	} else {
+		s.Uncacheable++
+	}
+	for _, reason := range result.Reasons {
+		s.Reasons[reason]++
+	}
+	for _, warning := range result.Warnings {
+		s.Warnings[warning]++
+	}
+	if result.EdgeTTL != nil {
+		s.ttls = append(s.ttls, *result.EdgeTTL)
+	}
+}
+
+func (s *CacheReportSummary) finish() {
+	if s.Pages > 0 {
+		s.CacheablePercent = float64(s.Cacheable) * 100 / float64(s.Pages)
+	}
+	if len(s.ttls) > 0 {
+		slices.Sort(s.ttls)
+		s.MinEdgeTTL = &s.ttls[0]
+		s.MedianEdgeTTL = &s.ttls[len(s.ttls)/2]
+	}
+}
+
+// cacheReportDirectory returns the directory of a page path, truncated to
+// depth segments, e.g. "/blog/2024/post" at depth 1 is "/blog/"
+func cacheReportDirectory(path string, depth int) string {
+	path, _, _ = strings.Cut(path, "?")
+	dir := path[:strings.LastIndex(path, "/")+1]
+	segments := strings.Split(strings.Trim(dir, "/"), "/")
+	if len(segments) == 1 && segments[0] == "" {
+		return "/"
+	}
+	if len(segments) > depth {
+		segments = segments[:depth]
+	}
+	return "/" + strings.Join(segments, "/") + "/"
+}
+
+// getJobCacheReport handles GET /v1/jobs/:id/cache-report. It analyses the
+// stored response headers of every completed page and reports which pages can
+// be cached at the edge, why the rest can't, and their effective edge TTLs,
+// for the whole job and per directory.
+func (h *Handler) getJobCacheReport(w http.ResponseWriter, r *http.Request, jobID string) {
+	logger := loggerWithRequest(r)
+
+	user := h.validateJobAccess(w, r, jobID)
+	if user == nil {
+		return // validateJobAccess already wrote the error response
+	}
+
+	query := r.URL.Query()
+	depth := 1
+	if raw := query.Get("depth"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 5 {
+			BadRequest(w, r, "depth must be between 1 and 5")
+			return
+		}
+		depth = parsed
+	}
+	pagesFilter := query.Get("pages")
+	switch pagesFilter {
+	case "":
+		pagesFilter = "uncacheable"
+	case "uncacheable", "all", "none":
+	default:
+		BadRequest(w, r, "pages must be one of: uncacheable, all, none")
+		return
+	}
+
+	rows, err := h.DB.GetDB().QueryContext(r.Context(), `
+		SELECT p.path, COALESCE(t.status_code, 0), COALESCE(t.content_type, ''),
+		       COALESCE(t.cache_status, ''), t.headers
+		FROM tasks t
+		JOIN pages p ON t.page_id = p.id
+		WHERE t.job_id = $1
+		  AND t.status = 'completed'
+		  AND COALESCE(t.source_type, '') <> 'asset'
+		ORDER BY p.path
+	`, jobID)
+	if err != nil {
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to get tasks for cache report")
+		DatabaseError(w, r, err)
+		return
+	}
+	defer rows.Close()
+
+	summary := newCacheReportSummary()
+	directories := make(map[string]*CacheReportSummary)
+	pages := make([]CacheReportPage, 0)
+	truncated := false
+
+	for rows.Next() {
+		var page CacheReportPage
+		var contentType string
+		var rawHeaders []byte
+		if err := rows.Scan(&page.Path, &page.StatusCode, &contentType, &page.CacheStatus, &rawHeaders); err != nil {
+			logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to scan task for cache report")
+			DatabaseError(w, r, err)
+			return
+		}
+
+		headers := http.Header{}
+		if len(rawHeaders) > 0 {
+			if err := json.Unmarshal(rawHeaders, &headers); err != nil {
+				headers = http.Header{}
+			}
+		}
+
+		page.Cacheability = crawler.AnalyseCacheability(headers, page.StatusCode, contentType, page.CacheStatus)
+		summary.add(page.Cacheability)
+
+		dir := cacheReportDirectory(page.Path, depth)
+		if directories[dir] == nil {
+			directories[dir] = newCacheReportSummary()
+		}
+		directories[dir].add(page.Cacheability)
+
+		if pagesFilter == "all" || (pagesFilter == "uncacheable" && !page.Cacheable) {
+			if len(pages) < maxCacheReportPages {
+				pages = append(pages, page)
+			} else {
+				truncated = true
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to iterate tasks for cache report")
+		DatabaseError(w, r, err)
+		return
+	}
+
+	summary.finish()
+	dirList := make([]CacheReportDirectory, 0, len(directories))
+	for dir, dirSummary := range directories {
+		dirSummary.finish()
+		dirList = append(dirList, CacheReportDirectory{Directory: dir, CacheReportSummary: dirSummary})
+	}
+	// Directories with the most uncacheable pages first
+	sort.Slice(dirList, func(i, j int) bool {
+		if dirList[i].Uncacheable != dirList[j].Uncacheable {
+			return dirList[i].Uncacheable > dirList[j].Uncacheable
+		}
+		return dirList[i].Directory < dirList[j].Directory
+	})
+
+	response := map[string]any{
+		"job_id":      jobID,
+		"summary":     summary,
+		"directories": dirList,
+	}
+	if pagesFilter != "none" {
+		response["pages"] = pages
+		response["pages_truncated"] = truncated
+	}
+
+	WriteSuccess(w, r, response, "Cache report generated successfully")
+}
@@ -0,0 +1,267 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Harvey-AU/adapt/internal/util"
+)
+
+// maxCanonicalReportPages caps the per-page list in a canonical report response
+const maxCanonicalReportPages = 500
+
+// maxCanonicalChainHops bounds how far a canonical chain is followed
+const maxCanonicalChainHops = 10
+
+// Canonical issues, most severe first
+const (
+	canonicalIssueLoop      = "loop"      // Canonicals lead back to an earlier page
+	canonicalIssueChain     = "chain"     // Canonical target declares a different canonical
+	canonicalIssueBroken    = "broken"    // Canonical is invalid, or its target failed or redirects
+	canonicalIssueElsewhere = "elsewhere" // Canonical points to a different, working URL
+)
+
+// CanonicalReportSummary counts canonical outcomes across a job's pages
+type CanonicalReportSummary struct {
+	PagesChecked  int `json:"pages_checked"`
+	Missing       int `json:"missing"`        // No canonical declared
+	SelfCanonical int `json:"self_canonical"` // Canonical is the page itself
+	Elsewhere     int `json:"elsewhere"`
+	Broken        int `json:"broken"`
+	Chains        int `json:"chains"`
+	Loops         int `json:"loops"`
+}
+
+// CanonicalReportPage is a page whose canonical needs attention
+type CanonicalReportPage struct {
+	URL              string   `json:"url"`
+	Canonical        string   `json:"canonical"`
+	Issue            string   `json:"issue"`
+	Reason           string   `json:"reason,omitempty"`
+	TargetStatusCode int      `json:"target_status_code,omitempty"`
+	Chain            []string `json:"chain,omitempty"` // Canonical hops from the page, for chains and loops
+}
+
+// canonicalPage is the stored crawl data the report is built from
+type canonicalPage struct {
+	URL         string
+	StatusCode  int
+	Failed      bool
+	Error       string
+	RedirectURL string
+	Canonical   string
+}
+
+// normaliseCanonicalURL reduces a URL to the form used to match canonicals
+// with crawled pages: lower-case scheme and host, no default port, fragment
+// or trailing slash. Returns "" for anything that isn't an absolute HTTP(S) URL.
+func normaliseCanonicalURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	u.Host = host
+	u.Fragment = ""
+	if u.Path == "" {
+		u.Path = "/"
+	} else if u.Path != "/" {
+		u.Path = strings.TrimSuffix(u.Path, "/")
+	}
+	return u.String()
+}
+
+// buildCanonicalReport classifies each page's canonical against the other
+// pages crawled in the same job
+func buildCanonicalReport(pages []canonicalPage) (CanonicalReportSummary, []CanonicalReportPage) {
+	byURL := make(map[string]*canonicalPage, len(pages))
+	for i := range pages {
+		if key := normaliseCanonicalURL(pages[i].URL); key != "" {
+			byURL[key] = &pages[i]
+		}
+	}
+
+	summary := CanonicalReportSummary{}
+	issues := make([]CanonicalReportPage, 0)
+
+	for i := range pages {
+		page := &pages[i]
+		if page.Failed {
+			continue // Only pages that loaded can declare a canonical
+		}
+		summary.PagesChecked++
+
+		if page.Canonical == "" {
+			summary.Missing++
+			continue
+		}
+
+		self := normaliseCanonicalURL(page.URL)
+		target := normaliseCanonicalURL(page.Canonical)
+		if target == self || (page.RedirectURL != "" && target == normaliseCanonicalURL(page.RedirectURL)) {
+			summary.SelfCanonical++
+			continue
+		}
+
+		issue := CanonicalReportPage{URL: page.URL, Canonical: page.Canonical}
+		targetPage := byURL[target]
+
+		switch {
+		case target == "":
+			issue.Issue = canonicalIssueBroken
+			issue.Reason = "canonical is not an absolute http(s) URL"
+		case targetPage == nil:
+			issue.Issue = canonicalIssueElsewhere
+			issue.Reason = "target was not crawled in this job"
+		case targetPage.Failed || targetPage.StatusCode >= 400:
+			issue.Issue = canonicalIssueBroken
+			issue.TargetStatusCode = targetPage.StatusCode
+			issue.Reason = "target failed"
+			if targetPage.StatusCode > 0 {
+				issue.Reason = "target returned " + http.StatusText(targetPage.StatusCode)
+			} else if targetPage.Error != "" {
+				issue.Reason = "target failed: " + targetPage.Error
+			}
+		case targetPage.RedirectURL != "" && normaliseCanonicalURL(targetPage.RedirectURL) != target:
+			issue.Issue = canonicalIssueBroken
+			issue.TargetStatusCode = targetPage.StatusCode
+			issue.Reason = "target redirects to " + targetPage.RedirectURL
+		default:
+			issue.TargetStatusCode = targetPage.StatusCode
+			issue.Issue, issue.Chain = followCanonicalChain(self, target, byURL)
+		}
+
+		switch issue.Issue {
+		case canonicalIssueLoop:
+			summary.Loops++
+		case canonicalIssueChain:
+			summary.Chains++
+		case canonicalIssueBroken:
+			summary.Broken++
+		default:
+			summary.Elsewhere++
+		}
+		issues = append(issues, issue)
+	}
+
+	return summary, issues
+}
+
+// followCanonicalChain follows canonicals from target until one is
+// self-referencing, uncrawled or revisits an earlier page
+func followCanonicalChain(start, target string, byURL map[string]*canonicalPage) (string, []string) {
+	chain := []string{start, target}
+	seen := map[string]bool{start: true, target: true}
+	current := target
+
+	for range maxCanonicalChainHops {
+		page := byURL[current]
+		if page == nil || page.Canonical == "" {
+			break
+		}
+		next := normaliseCanonicalURL(page.Canonical)
+		if next == "" || next == current {
+			break
+		}
+		chain = append(chain, next)
+		if seen[next] {
+			return canonicalIssueLoop, chain
+		}
+		seen[next] = true
+		current = next
+	}
+
+	if len(chain) > 2 {
+		return canonicalIssueChain, chain
+	}
+	return canonicalIssueElsewhere, nil
+}
+
+// getJobCanonicalReport handles GET /v1/jobs/:id/canonical-report. It lists
+// pages whose canonical points to another URL, is broken, or starts a chain
+// or loop, based on the canonicals stored for every page in the job.
+func (h *Handler) getJobCanonicalReport(w http.ResponseWriter, r *http.Request, jobID string) {
+	logger := loggerWithRequest(r)
+
+	user := h.validateJobAccess(w, r, jobID)
+	if user == nil {
+		return // validateJobAccess already wrote the error response
+	}
+
+	issueFilter := r.URL.Query().Get("issue")
+	switch issueFilter {
+	case "", canonicalIssueElsewhere, canonicalIssueBroken, canonicalIssueChain, canonicalIssueLoop:
+	default:
+		BadRequest(w, r, "issue must be one of: elsewhere, broken, chain, loop")
+		return
+	}
+
+	rows, err := h.DB.GetDB().QueryContext(r.Context(), `
+		SELECT p.host, p.path, COALESCE(t.status_code, 0), t.status = 'failed',
+		       COALESCE(t.error, ''), COALESCE(t.redirect_url, ''), COALESCE(t.seo->>'canonical', '')
+		FROM tasks t
+		JOIN pages p ON t.page_id = p.id
+		WHERE t.job_id = $1
+		  AND t.status IN ('completed', 'failed')
+		  AND COALESCE(t.source_type, '') <> 'asset'
+		ORDER BY p.path
+	`, jobID)
+	if err != nil {
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to get tasks for canonical report")
+		DatabaseError(w, r, err)
+		return
+	}
+	defer rows.Close()
+
+	pages := make([]canonicalPage, 0)
+	for rows.Next() {
+		var page canonicalPage
+		var host, path string
+		if err := rows.Scan(&host, &path, &page.StatusCode, &page.Failed, &page.Error, &page.RedirectURL, &page.Canonical); err != nil {
+			logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to scan task for canonical report")
+			DatabaseError(w, r, err)
+			return
+		}
+		// Built the same way as the URL the crawler requested
+		page.URL = util.ConstructURL(host, path)
+		pages = append(pages, page)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to iterate tasks for canonical report")
+		DatabaseError(w, r, err)
+		return
+	}
+
+	summary, issues := buildCanonicalReport(pages)
+
+	filtered := make([]CanonicalReportPage, 0)
+	truncated := false
+	for _, issue := range issues {
+		if issueFilter != "" && issue.Issue != issueFilter {
+			continue
+		}
+		if len(filtered) >= maxCanonicalReportPages {
+			truncated = true
+			break
+		}
+		filtered = append(filtered, issue)
+	}
+
+	WriteSuccess(w, r, map[string]any{
+		"job_id":          jobID,
+		"summary":         summary,
+		"pages":           filtered,
+		"pages_truncated": truncated,
+	}, "Canonical report generated successfully")
+}
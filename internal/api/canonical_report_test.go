@@ -0,0 +1,67 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormaliseCanonicalURL(t *testing.T) {
+	assert.Equal(t, "https://example.com/about", normaliseCanonicalURL("HTTPS://Example.com:443/about/#team"))
+	assert.Equal(t, "https://example.com/", normaliseCanonicalURL("https://example.com"))
+	assert.Equal(t, "http://example.com:8080/?page=2", normaliseCanonicalURL("http://example.com:8080/?page=2"))
+	assert.Empty(t, normaliseCanonicalURL("/about"))
+	assert.Empty(t, normaliseCanonicalURL("ftp://example.com/file"))
+}
+
+func TestBuildCanonicalReport(t *testing.T) {
+	pages := []canonicalPage{
+		{URL: "https://example.com/", StatusCode: 200, Canonical: "https://example.com/"},
+		{URL: "https://example.com/no-canonical", StatusCode: 200},
+		{URL: "https://example.com/shoes?colour=red", StatusCode: 200, Canonical: "https://example.com/shoes"},
+		{URL: "https://example.com/shoes", StatusCode: 200, Canonical: "https://example.com/shoes/"},
+		{URL: "https://example.com/old", StatusCode: 200, Canonical: "https://example.com/gone"},
+		{URL: "https://example.com/gone", Failed: true, Error: "non-success status code: 404"},
+		{URL: "https://example.com/moved", StatusCode: 200, Canonical: "https://example.com/redirects"},
+		{URL: "https://example.com/redirects", StatusCode: 200, RedirectURL: "https://example.com/new", Canonical: "https://example.com/new"},
+		{URL: "https://example.com/a", StatusCode: 200, Canonical: "https://example.com/b"},
+		{URL: "https://example.com/b", StatusCode: 200, Canonical: "https://example.com/c"},
+		{URL: "https://example.com/c", StatusCode: 200, Canonical: "https://example.com/c"},
+		{URL: "https://example.com/x", StatusCode: 200, Canonical: "https://example.com/y"},
+		{URL: "https://example.com/y", StatusCode: 200, Canonical: "https://example.com/x"},
+		{URL: "https://example.com/relative", StatusCode: 200, Canonical: "about"},
+	}
+
+	summary, issues := buildCanonicalReport(pages)
+
+	assert.Equal(t, CanonicalReportSummary{
+		PagesChecked:  13,
+		Missing:       1,
+		SelfCanonical: 4,
+		Elsewhere:     2,
+		Broken:        3,
+		Chains:        1,
+		Loops:         2,
+	}, summary)
+
+	byURL := make(map[string]CanonicalReportPage, len(issues))
+	for _, issue := range issues {
+		byURL[issue.URL] = issue
+	}
+
+	require.Contains(t, byURL, "https://example.com/shoes?colour=red")
+	assert.Equal(t, canonicalIssueElsewhere, byURL["https://example.com/shoes?colour=red"].Issue)
+
+	assert.Equal(t, canonicalIssueBroken, byURL["https://example.com/old"].Issue)
+	assert.Equal(t, "target failed: non-success status code: 404", byURL["https://example.com/old"].Reason)
+	assert.Equal(t, canonicalIssueBroken, byURL["https://example.com/moved"].Issue)
+	assert.Equal(t, canonicalIssueBroken, byURL["https://example.com/relative"].Issue)
+
+	assert.Equal(t, canonicalIssueChain, byURL["https://example.com/a"].Issue)
+	assert.Equal(t, []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"}, byURL["https://example.com/a"].Chain)
+	assert.Equal(t, canonicalIssueElsewhere, byURL["https://example.com/b"].Issue)
+
+	assert.Equal(t, canonicalIssueLoop, byURL["https://example.com/x"].Issue)
+	assert.Equal(t, []string{"https://example.com/x", "https://example.com/y", "https://example.com/x"}, byURL["https://example.com/x"].Chain)
+}
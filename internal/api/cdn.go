@@ -0,0 +1,325 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Harvey-AU/adapt/internal/auth"
+	"github.com/Harvey-AU/adapt/internal/cdn"
+	"github.com/Harvey-AU/adapt/internal/db"
+	"github.com/Harvey-AU/adapt/internal/util"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// errNoCDNConnection is returned when a purge is requested for a domain
+// without a CDN connection
+var errNoCDNConnection = errors.New("no CDN connection for domain")
+
+// errCDNPurgeFailed wraps provider errors so callers can report them as
+// upstream failures rather than internal errors
+var errCDNPurgeFailed = errors.New("CDN purge failed")
+
+var cdnZoneID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// CDNProviderFactory builds the purge client for a connection. Tests replace
+// it to point providers at local stand-in servers.
+type CDNProviderFactory func(conn *db.CDNConnection, token string) (cdn.Provider, error)
+
+func defaultCDNProvider(conn *db.CDNConnection, token string) (cdn.Provider, error) {
+	return cdn.New(conn.Provider, conn.ZoneID, token, "")
+}
+
+// CDNConnectionRequest is the body for POST /v1/integrations/cdn
+type CDNConnectionRequest struct {
+	Provider       string `json:"provider"` // "cloudflare" or "fastly"
+	Domain         string `json:"domain"`
+	ZoneID         string `json:"zone_id"`   // Cloudflare zone ID or Fastly service ID
+	APIToken       string `json:"api_token"` // Stored in Vault, never returned
+	PurgeOnPublish *bool  `json:"purge_on_publish,omitempty"`
+}
+
+// CDNConnectionResponse represents a CDN connection in API responses
+type CDNConnectionResponse struct {
+	ID             string `json:"id"`
+	Provider       string `json:"provider"`
+	Domain         string `json:"domain"`
+	ZoneID         string `json:"zone_id"`
+	PurgeOnPublish bool   `json:"purge_on_publish"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+func cdnConnectionToResponse(conn *db.CDNConnection) CDNConnectionResponse {
+	return CDNConnectionResponse{
+		ID:             conn.ID,
+		Provider:       conn.Provider,
+		Domain:         conn.Domain,
+		ZoneID:         conn.ZoneID,
+		PurgeOnPublish: conn.PurgeOnPublish,
+		CreatedAt:      conn.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      conn.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// CDNConnectionsHandler handles requests to /v1/integrations/cdn
+func (h *Handler) CDNConnectionsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listCDNConnections(w, r)
+	case http.MethodPost:
+		h.createCDNConnection(w, r)
+	default:
+		MethodNotAllowed(w, r)
+	}
+}
+
+// CDNConnectionHandler handles requests to /v1/integrations/cdn/:id
+func (h *Handler) CDNConnectionHandler(w http.ResponseWriter, r *http.Request) {
+	connectionID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/integrations/cdn/"), "/")
+	if connectionID == "" {
+		BadRequest(w, r, "Connection ID is required")
+		return
+	}
+	if _, err := uuid.Parse(connectionID); err != nil {
+		BadRequest(w, r, "Invalid connection ID format")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodDelete:
+		h.deleteCDNConnection(w, r, connectionID)
+	default:
+		MethodNotAllowed(w, r)
+	}
+}
+
+// listCDNConnections lists the organisation's CDN connections
+func (h *Handler) listCDNConnections(w http.ResponseWriter, r *http.Request) {
+	logger := loggerWithRequest(r)
+
+	orgID := h.GetActiveOrganisation(w, r)
+	if orgID == "" {
+		return
+	}
+
+	connections, err := h.DB.ListCDNConnections(r.Context(), orgID)
+	if err != nil {
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		logger.Error().Err(err).Msg("Failed to list CDN connections")
+		DatabaseError(w, r, err)
+		return
+	}
+
+	response := make([]CDNConnectionResponse, 0, len(connections))
+	for _, conn := range connections {
+		response = append(response, cdnConnectionToResponse(conn))
+	}
+
+	WriteSuccess(w, r, response, "")
+}
+
+// createCDNConnection handles POST /v1/integrations/cdn. Only organisation
+// admins can add credentials.
+func (h *Handler) createCDNConnection(w http.ResponseWriter, r *http.Request) {
+	logger := loggerWithRequest(r)
+
+	orgID := h.GetActiveOrganisation(w, r)
+	if orgID == "" {
+		return
+	}
+
+	userClaims, ok := auth.GetUserFromContext(r.Context())
+	if !ok {
+		Unauthorised(w, r, "User information not found")
+		return
+	}
+	if ok := h.requireOrganisationAdmin(w, r, orgID, userClaims.UserID); !ok {
+		return
+	}
+
+	var req CDNConnectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		BadRequest(w, r, "Invalid JSON request body")
+		return
+	}
+
+	if !cdn.SupportedProvider(req.Provider) {
+		BadRequest(w, r, fmt.Sprintf("provider must be %s or %s", cdn.ProviderCloudflare, cdn.ProviderFastly))
+		return
+	}
+	if err := util.ValidateDomain(req.Domain); err != nil {
+		BadRequest(w, r, fmt.Sprintf("Invalid domain: %s", err.Error()))
+		return
+	}
+	if !cdnZoneID.MatchString(req.ZoneID) {
+		BadRequest(w, r, "zone_id must be 1-64 letters, numbers, hyphens or underscores")
+		return
+	}
+	if strings.TrimSpace(req.APIToken) == "" {
+		BadRequest(w, r, "api_token is required")
+		return
+	}
+
+	domainID, err := h.DB.GetOrCreateDomainID(r.Context(), util.NormaliseDomain(req.Domain))
+	if err != nil {
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		DatabaseError(w, r, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	conn := &db.CDNConnection{
+		ID:               uuid.New().String(),
+		OrganisationID:   orgID,
+		DomainID:         domainID,
+		Domain:           util.NormaliseDomain(req.Domain),
+		Provider:         req.Provider,
+		ZoneID:           req.ZoneID,
+		PurgeOnPublish:   req.PurgeOnPublish != nil && *req.PurgeOnPublish,
+		InstallingUserID: userClaims.UserID,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	inserted, err := h.DB.CreateCDNConnection(r.Context(), conn)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create CDN connection")
+		DatabaseError(w, r, err)
+		return
+	}
+
+	if err := h.DB.StoreCDNToken(r.Context(), conn.ID, strings.TrimSpace(req.APIToken)); err != nil {
+		logger.Error().Err(err).Str("connection_id", conn.ID).Msg("Failed to store CDN token")
+		// Don't leave a new connection behind that can never purge. A
+		// connection this request replaced keeps its working token.
+		if inserted {
+			if delErr := h.DB.DeleteCDNConnection(r.Context(), conn.ID, orgID); delErr != nil {
+				logger.Error().Err(delErr).Str("connection_id", conn.ID).Msg("Failed to remove CDN connection after token error")
+			}
+		}
+		InternalError(w, r, err)
+		return
+	}
+
+	logger.Info().
+		Str("connection_id", conn.ID).
+		Str("provider", conn.Provider).
+		Str("domain", conn.Domain).
+		Msg("CDN connection created")
+
+	WriteCreated(w, r, cdnConnectionToResponse(conn), "CDN connection created successfully")
+}
+
+// deleteCDNConnection deletes a CDN connection and its stored token
+func (h *Handler) deleteCDNConnection(w http.ResponseWriter, r *http.Request, connectionID string) {
+	logger := loggerWithRequest(r)
+
+	orgID := h.GetActiveOrganisation(w, r)
+	if orgID == "" {
+		return
+	}
+
+	userClaims, ok := auth.GetUserFromContext(r.Context())
+	if !ok {
+		Unauthorised(w, r, "User information not found")
+		return
+	}
+	if ok := h.requireOrganisationAdmin(w, r, orgID, userClaims.UserID); !ok {
+		return
+	}
+
+	if err := h.DB.DeleteCDNConnection(r.Context(), connectionID, orgID); err != nil {
+		if errors.Is(err, db.ErrCDNConnectionNotFound) {
+			NotFound(w, r, "CDN connection not found")
+			return
+		}
+		logger.Error().Err(err).Msg("Failed to delete CDN connection")
+		DatabaseError(w, r, err)
+		return
+	}
+
+	logger.Info().Str("connection_id", connectionID).Msg("CDN connection deleted")
+	WriteNoContent(w, r)
+}
+
+// purgeCDN purges the organisation's CDN for a domain and returns once the
+// provider has confirmed the purge, so the warming job that follows fetches
+// fresh content from the origin
+func (h *Handler) purgeCDN(ctx context.Context, orgID, domain string, req cdn.PurgeRequest, logger zerolog.Logger) (*cdn.PurgeResult, error) {
+	normalisedDomain := util.NormaliseDomain(domain)
+	if err := req.CheckDomain(normalisedDomain); err != nil {
+		return nil, err
+	}
+
+	conn, err := h.DB.GetCDNConnectionForDomain(ctx, orgID, normalisedDomain)
+	if err != nil {
+		return nil, err
+	}
+	if conn == nil {
+		return nil, fmt.Errorf("%w %s", errNoCDNConnection, normalisedDomain)
+	}
+
+	token, err := h.DB.GetCDNToken(ctx, conn.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	newProvider := h.CDNProviders
+	if newProvider == nil {
+		newProvider = defaultCDNProvider
+	}
+	provider, err := newProvider(conn, token)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := provider.Purge(ctx, req)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("connection_id", conn.ID).
+			Str("provider", conn.Provider).
+			Str("domain", normalisedDomain).
+			Msg("CDN purge failed")
+		return nil, fmt.Errorf("%w: %w", errCDNPurgeFailed, err)
+	}
+
+	logger.Info().
+		Str("connection_id", conn.ID).
+		Str("provider", conn.Provider).
+		Str("domain", normalisedDomain).
+		Str("scope", result.Scope).
+		Int("purged", result.Purged).
+		Int64("duration_ms", result.Duration).
+		Msg("CDN purge confirmed")
+
+	return result, nil
+}
+
+// writePurgeError writes the response for a failed purge-then-warm request
+func writePurgeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, errNoCDNConnection):
+		BadRequest(w, r, "No CDN connection is configured for this domain")
+	case errors.Is(err, cdn.ErrURLOutsideDomain):
+		BadRequest(w, r, fmt.Sprintf("Invalid purge: %s", err.Error()))
+	case errors.Is(err, errCDNPurgeFailed):
+		WriteErrorMessage(w, r, err.Error(), http.StatusBadGateway, ErrCodeServiceUnavailable)
+	default:
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		InternalError(w, r, err)
+	}
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Harvey-AU/adapt/internal/auth"
+	"github.com/Harvey-AU/adapt/internal/cdn"
+	"github.com/Harvey-AU/adapt/internal/db"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cdnTestDB stubs the CDN connection lookups used by purgeCDN and the
+// connection and job endpoints
+type cdnTestDB struct {
+	DBClient
+	conn  *db.CDNConnection
+	token string
+
+	role       string // Caller's organisation role
+	existingID string // Connection an upsert replaces, if any
+	tokenErr   error
+	deleted    []string
+}
+
+func (s *cdnTestDB) GetOrCreateUser(userID, email string, orgID *string) (*db.User, error) {
+	return &db.User{ID: userID, Email: email}, nil
+}
+
+func (s *cdnTestDB) GetEffectiveOrganisationID(user *db.User) string {
+	return "org-1"
+}
+
+func (s *cdnTestDB) GetOrganisationMemberRole(_ context.Context, userID, organisationID string) (string, error) {
+	return s.role, nil
+}
+
+func (s *cdnTestDB) GetOrCreateDomainID(_ context.Context, domain string) (int, error) {
+	return 7, nil
+}
+
+func (s *cdnTestDB) CreateCDNConnection(_ context.Context, conn *db.CDNConnection) (bool, error) {
+	if s.existingID != "" {
+		conn.ID = s.existingID
+		return false, nil
+	}
+	return true, nil
+}
+
+func (s *cdnTestDB) StoreCDNToken(_ context.Context, connectionID, token string) error {
+	return s.tokenErr
+}
+
+func (s *cdnTestDB) DeleteCDNConnection(_ context.Context, connectionID, organisationID string) error {
+	s.deleted = append(s.deleted, connectionID)
+	return nil
+}
+
+// cdnRequest builds an authenticated request for user-1
+func cdnRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	return req.WithContext(context.WithValue(req.Context(), auth.UserKey, &auth.UserClaims{UserID: "user-1", Email: "user@example.com"}))
+}
+
+func (s *cdnTestDB) GetCDNConnectionForDomain(_ context.Context, organisationID, domain string) (*db.CDNConnection, error) {
+	if s.conn == nil || organisationID != "org-1" || domain != s.conn.Domain {
+		return nil, nil
+	}
+	return s.conn, nil
+}
+
+func (s *cdnTestDB) GetCDNToken(_ context.Context, connectionID string) (string, error) {
+	return s.token, nil
+}
+
+// cloudflareStandIn replaces the Cloudflare API with a local server
+func cloudflareStandIn(t *testing.T, status int, body string) CDNProviderFactory {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/zones/zone-1/purge_cache", r.URL.Path)
+		assert.Equal(t, "Bearer cf-token", r.Header.Get("Authorization"))
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	factory := func(conn *db.CDNConnection, token string) (cdn.Provider, error) {
+		return cdn.New(conn.Provider, conn.ZoneID, token, server.URL)
+	}
+	return factory
+}
+
+func TestPurgeCDN(t *testing.T) {
+	conn := &db.CDNConnection{ID: "conn-1", Provider: cdn.ProviderCloudflare, ZoneID: "zone-1", Domain: "example.com"}
+
+	t.Run("confirmed", func(t *testing.T) {
+		factory := cloudflareStandIn(t, http.StatusOK, `{"success":true,"errors":[],"messages":[],"result":{"id":"purge-1"}}`)
+		stub := &cdnTestDB{conn: conn, token: "cf-token"}
+
+		h := &Handler{DB: stub, CDNProviders: factory}
+		result, err := h.purgeCDN(context.Background(), "org-1", "https://www.example.com/", cdn.PurgeRequest{Scope: cdn.ScopeEverything}, zerolog.Nop())
+		require.NoError(t, err)
+		assert.Equal(t, cdn.ProviderCloudflare, result.Provider)
+		assert.Equal(t, []string{"purge-1"}, result.IDs)
+	})
+
+	t.Run("provider rejects purge", func(t *testing.T) {
+		factory := cloudflareStandIn(t, http.StatusForbidden, `{"success":false,"errors":[{"code":10000,"message":"Authentication error"}]}`)
+		stub := &cdnTestDB{conn: conn, token: "cf-token"}
+
+		h := &Handler{DB: stub, CDNProviders: factory}
+		_, err := h.purgeCDN(context.Background(), "org-1", "example.com", cdn.PurgeRequest{Scope: cdn.ScopeEverything}, zerolog.Nop())
+		assert.ErrorIs(t, err, errCDNPurgeFailed)
+
+		rec := httptest.NewRecorder()
+		writePurgeError(rec, httptest.NewRequest(http.MethodPost, "/v1/jobs", nil), err)
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+	})
+
+	t.Run("url outside domain", func(t *testing.T) {
+		stub := &cdnTestDB{conn: conn, token: "cf-token"}
+
+		h := &Handler{DB: stub}
+		req := cdn.PurgeRequest{Scope: cdn.ScopeURLs, URLs: []string{"https://www.example.com/", "https://victim.test/"}}
+		_, err := h.purgeCDN(context.Background(), "org-1", "example.com", req, zerolog.Nop())
+		assert.ErrorIs(t, err, cdn.ErrURLOutsideDomain)
+
+		rec := httptest.NewRecorder()
+		writePurgeError(rec, httptest.NewRequest(http.MethodPost, "/v1/jobs", nil), err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("no connection", func(t *testing.T) {
+		stub := &cdnTestDB{}
+
+		h := &Handler{DB: stub}
+		_, err := h.purgeCDN(context.Background(), "org-1", "example.com", cdn.PurgeRequest{Scope: cdn.ScopeEverything}, zerolog.Nop())
+		assert.ErrorIs(t, err, errNoCDNConnection)
+
+		rec := httptest.NewRecorder()
+		writePurgeError(rec, httptest.NewRequest(http.MethodPost, "/v1/jobs", nil), err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestCreateCDNConnectionTokenFailure(t *testing.T) {
+	body := `{"provider":"cloudflare","domain":"example.com","zone_id":"zone-1","api_token":"bad-token"}`
+
+	t.Run("new connection is removed", func(t *testing.T) {
+		stub := &cdnTestDB{role: "admin", tokenErr: errors.New("vault unavailable")}
+		h := &Handler{DB: stub}
+
+		rec := httptest.NewRecorder()
+		h.createCDNConnection(rec, cdnRequest(http.MethodPost, "/v1/integrations/cdn", body))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Len(t, stub.deleted, 1)
+	})
+
+	t.Run("replaced connection is kept", func(t *testing.T) {
+		stub := &cdnTestDB{role: "admin", existingID: "conn-1", tokenErr: errors.New("vault unavailable")}
+		h := &Handler{DB: stub}
+
+		rec := httptest.NewRecorder()
+		h.createCDNConnection(rec, cdnRequest(http.MethodPost, "/v1/integrations/cdn", body))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Empty(t, stub.deleted, "a working connection should not be deleted")
+	})
+}
+
+func TestCreateJobPurgeEverythingRequiresAdmin(t *testing.T) {
+	stub := &cdnTestDB{role: "member"}
+	h := &Handler{DB: stub}
+
+	rec := httptest.NewRecorder()
+	h.createJob(rec, cdnRequest(http.MethodPost, "/v1/jobs", `{"domain":"example.com","purge":{"scope":"everything"}}`))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Harvey-AU/adapt/internal/db"
+)
+
+const (
+	defaultContentChangesLimit = 1000
+	maxContentChangesLimit     = 10000
+)
+
+// ContentChangePage is a page whose content differs between two jobs
+type ContentChangePage struct {
+	Host                string `json:"host"`
+	Path                string `json:"path"`
+	Change              string `json:"change"` // changed, added or removed
+	ContentHash         string `json:"content_hash,omitempty"`
+	PreviousContentHash string `json:"previous_content_hash,omitempty"`
+	DiffPath            string `json:"diff_path,omitempty"` // Main content diff from the earlier job's version
+}
+
+// contentChangePages converts stored content changes for the response and
+// counts them by kind
+func contentChangePages(changes []db.ContentChange) ([]ContentChangePage, map[string]int) {
+	counts := map[string]int{
+		db.ContentChangeChanged: 0,
+		db.ContentChangeAdded:   0,
+		db.ContentChangeRemoved: 0,
+	}
+	pages := make([]ContentChangePage, 0, len(changes))
+	for _, change := range changes {
+		counts[change.Change]++
+		pages = append(pages, ContentChangePage{
+			Host:                change.Host,
+			Path:                change.Path,
+			Change:              change.Change,
+			ContentHash:         change.Hash,
+			PreviousContentHash: change.PreviousHash,
+			DiffPath:            change.DiffPath,
+		})
+	}
+	return pages, counts
+}
+
+// getJobContentChanges handles GET /v1/jobs/:id/changes?since=:job_id. It
+// compares the normalised content hash of every page the two jobs crawled and
+// lists the pages whose content changed, appeared or disappeared since the
+// earlier job. Both jobs must belong to the organisation and the same domain.
+func (h *Handler) getJobContentChanges(w http.ResponseWriter, r *http.Request, jobID string) {
+	logger := loggerWithRequest(r)
+
+	query := r.URL.Query()
+	sinceJobID := query.Get("since")
+	if sinceJobID == "" {
+		BadRequest(w, r, "since is required")
+		return
+	}
+	if sinceJobID == jobID {
+		BadRequest(w, r, "since must be a different job")
+		return
+	}
+	limit := defaultContentChangesLimit
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxContentChangesLimit {
+			BadRequest(w, r, "limit must be between 1 and 10000")
+			return
+		}
+		limit = parsed
+	}
+
+	if h.validateJobAccess(w, r, jobID) == nil {
+		return // validateJobAccess already wrote the error response
+	}
+	if h.validateJobAccess(w, r, sinceJobID) == nil {
+		return
+	}
+
+	var domains int
+	err := h.DB.GetDB().QueryRowContext(r.Context(), `
+		SELECT COUNT(DISTINCT domain_id) FROM jobs WHERE id IN ($1, $2)
+	`, jobID, sinceJobID).Scan(&domains)
+	if err != nil {
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		logger.Error().Err(err).Str("job_id", jobID).Str("since_job_id", sinceJobID).Msg("Failed to compare job domains")
+		DatabaseError(w, r, err)
+		return
+	}
+	if domains != 1 {
+		BadRequest(w, r, "since must be a job for the same domain")
+		return
+	}
+
+	changes, err := h.DB.ListJobContentChanges(r.Context(), jobID, sinceJobID, limit)
+	if err != nil {
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		logger.Error().Err(err).Str("job_id", jobID).Str("since_job_id", sinceJobID).Msg("Failed to list content changes")
+		DatabaseError(w, r, err)
+		return
+	}
+
+	pages, counts := contentChangePages(changes)
+	WriteSuccess(w, r, map[string]any{
+		"job_id":       jobID,
+		"since_job_id": sinceJobID,
+		"counts":       counts,
+		"pages":        pages,
+		"truncated":    len(changes) == limit,
+	}, "Content changes retrieved successfully")
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/Harvey-AU/adapt/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentChangePages(t *testing.T) {
+	pages, counts := contentChangePages([]db.ContentChange{
+		{Host: "example.com", Path: "/new", Change: db.ContentChangeAdded, Hash: "c"},
+		{Host: "example.com", Path: "/pricing", Change: db.ContentChangeChanged, Hash: "b", PreviousHash: "a", DiffPath: "page-crawls/pages/1/diffs/a-b.diff"},
+		{Host: "example.com", Path: "/team", Change: db.ContentChangeChanged, Hash: "e", PreviousHash: "d"},
+	})
+
+	assert.Equal(t, map[string]int{"changed": 2, "added": 1, "removed": 0}, counts)
+	assert.Len(t, pages, 3)
+	assert.Equal(t, ContentChangePage{
+		Host:                "example.com",
+		Path:                "/pricing",
+		Change:              "changed",
+		ContentHash:         "b",
+		PreviousContentHash: "a",
+		DiffPath:            "page-crawls/pages/1/diffs/a-b.diff",
+	}, pages[1])
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/Harvey-AU/adapt/internal/util"
 )
@@ -28,6 +29,24 @@ func (h *Handler) DomainsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// DomainHandler handles requests to /v1/domains/:domain sub-routes
+func (h *Handler) DomainHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/domains/"), "/")
+	if parts[0] == "" {
+		BadRequest(w, r, "Domain is required")
+		return
+	}
+	if len(parts) != 2 || parts[1] != "structured-data" {
+		NotFound(w, r, "Endpoint not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		MethodNotAllowed(w, r)
+		return
+	}
+	h.getDomainStructuredData(w, r, parts[0])
+}
+
 // createDomain handles POST /v1/domains - creates domain without job side effects
 func (h *Handler) createDomain(w http.ResponseWriter, r *http.Request) {
 	logger := loggerWithRequest(r)
@@ -16,9 +16,11 @@ import (
 	"time"
 
 	"github.com/Harvey-AU/adapt/internal/auth"
+	"github.com/Harvey-AU/adapt/internal/cdn"
 	"github.com/Harvey-AU/adapt/internal/db"
 	"github.com/Harvey-AU/adapt/internal/jobs"
 	"github.com/Harvey-AU/adapt/internal/loops"
+	"github.com/Harvey-AU/adapt/internal/util"
 	"github.com/rs/zerolog/log"
 )
 
@@ -121,6 +123,7 @@ type DBClient interface {
 	GetLastJobStartTimeForScheduler(ctx context.Context, schedulerID string) (*time.Time, error)
 	GetDomainNameByID(ctx context.Context, domainID int) (string, error)
 	GetDomainNames(ctx context.Context, domainIDs []int) (map[int]string, error)
+	GetLeaderLease(ctx context.Context, name string) (*db.LeaderLease, error)
 	// Organisation membership methods
 	ListUserOrganisations(userID string) ([]db.UserOrganisation, error)
 	ValidateOrganisationMembership(userID, organisationID string) (bool, error)
@@ -142,6 +145,8 @@ type DBClient interface {
 	AcceptOrganisationInvite(ctx context.Context, token, userID string) (*db.OrganisationInvite, error)
 	SetOrganisationPlan(ctx context.Context, organisationID, planID string) error
 	GetOrganisationPlanID(ctx context.Context, organisationID string) (string, error)
+	GetOrganisationCacheHeaderMappings(ctx context.Context, organisationID string) ([]byte, error)
+	SetOrganisationCacheHeaderMappings(ctx context.Context, organisationID string, mappings []byte) error
 	ListDailyUsage(ctx context.Context, organisationID string, startDate, endDate time.Time) ([]db.DailyUsageEntry, error)
 	// Slack integration methods
 	CreateSlackConnection(ctx context.Context, conn *db.SlackConnection) error
@@ -166,6 +171,25 @@ type DBClient interface {
 	DeleteWebflowConnection(ctx context.Context, connectionID, organisationID string) error
 	StoreWebflowToken(ctx context.Context, connectionID, token string) error
 	GetWebflowToken(ctx context.Context, connectionID string) (string, error)
+	// CDN purge integration methods
+	CreateCDNConnection(ctx context.Context, conn *db.CDNConnection) (bool, error)
+	GetCDNConnection(ctx context.Context, connectionID, organisationID string) (*db.CDNConnection, error)
+	GetCDNConnectionForDomain(ctx context.Context, organisationID, domain string) (*db.CDNConnection, error)
+	ListCDNConnections(ctx context.Context, organisationID string) ([]*db.CDNConnection, error)
+	DeleteCDNConnection(ctx context.Context, connectionID, organisationID string) error
+	StoreCDNToken(ctx context.Context, connectionID, token string) error
+	GetCDNToken(ctx context.Context, connectionID string) (string, error)
+	// SEO audit findings
+	ListJobFindings(ctx context.Context, jobID string) ([]db.TaskFinding, error)
+	// TLS certificate audit
+	ListJobHostTLS(ctx context.Context, jobID string) ([]*db.HostTLS, error)
+	// External link checking
+	ListJobExternalLinks(ctx context.Context, jobID string, brokenOnly bool, limit int) ([]db.JobExternalLink, error)
+	// Fragment and anchor validation
+	ListJobMissingAnchors(ctx context.Context, jobID string, limit int) ([]db.MissingAnchor, error)
+	ListJobSitemapChanges(ctx context.Context, jobID string, limit int) ([]db.SitemapChange, error)
+	// Content change tracking
+	ListJobContentChanges(ctx context.Context, jobID, sinceJobID string, limit int) ([]db.ContentChange, error)
 	// Google Analytics integration methods
 	CreateGoogleConnection(ctx context.Context, conn *db.GoogleAnalyticsConnection) error
 	GetGoogleConnection(ctx context.Context, connectionID string) (*db.GoogleAnalyticsConnection, error)
@@ -220,6 +244,9 @@ type Handler struct {
 	Loops              *loops.Client
 	GoogleClientID     string
 	GoogleClientSecret string
+
+	// CDNProviders builds purge clients; nil uses the providers' public APIs
+	CDNProviders CDNProviderFactory
 }
 
 // NewHandler creates a new API handler with dependencies
@@ -287,6 +314,7 @@ func (h *Handler) SetupRoutes(mux *http.ServeMux) {
 	// Health check endpoints (no auth required)
 	mux.HandleFunc("/health", h.HealthCheck)
 	mux.HandleFunc("/health/db", h.DatabaseHealthCheck)
+	mux.HandleFunc("/health/leader", h.LeaderHealthCheck)
 
 	// V1 API routes with authentication
 	mux.Handle("/v1/jobs", auth.AuthMiddleware(http.HandlerFunc(h.JobsHandler)))
@@ -323,9 +351,11 @@ func (h *Handler) SetupRoutes(mux *http.ServeMux) {
 	mux.Handle("/v1/organisations/invites", auth.AuthMiddleware(http.HandlerFunc(h.OrganisationInvitesHandler)))
 	mux.Handle("/v1/organisations/invites/", auth.AuthMiddleware(http.HandlerFunc(h.OrganisationInviteHandler)))
 	mux.Handle("/v1/organisations/plan", auth.AuthMiddleware(http.HandlerFunc(h.OrganisationPlanHandler)))
+	mux.Handle("/v1/organisations/cache-mappings", auth.AuthMiddleware(http.HandlerFunc(h.OrganisationCacheMappingsHandler)))
 
 	// Domain routes (require auth)
 	mux.Handle("/v1/domains", auth.AuthMiddleware(http.HandlerFunc(h.DomainsHandler)))
+	mux.Handle("/v1/domains/", auth.AuthMiddleware(http.HandlerFunc(h.DomainHandler))) // For /v1/domains/:domain/structured-data
 
 	// Usage routes (require auth)
 	mux.Handle("/v1/usage", auth.AuthMiddleware(http.HandlerFunc(h.UsageHandler)))
@@ -355,6 +385,10 @@ func (h *Handler) SetupRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/v1/integrations/google/callback", h.HandleGoogleOAuthCallback) // No auth - state validation
 	mux.Handle("/v1/integrations/google/save-property", auth.AuthMiddleware(http.HandlerFunc(h.SaveGoogleProperty)))
 
+	// CDN purge integration endpoints
+	mux.Handle("/v1/integrations/cdn", auth.AuthMiddleware(http.HandlerFunc(h.CDNConnectionsHandler)))
+	mux.Handle("/v1/integrations/cdn/", auth.AuthMiddleware(http.HandlerFunc(h.CDNConnectionHandler)))
+
 	// Notification endpoints
 	mux.Handle("/v1/notifications", auth.AuthMiddleware(http.HandlerFunc(h.NotificationsHandler)))
 	mux.Handle("/v1/notifications/read-all", auth.AuthMiddleware(http.HandlerFunc(h.NotificationsReadAllHandler)))
@@ -477,6 +511,40 @@ func (h *Handler) DatabaseHealthCheck(w http.ResponseWriter, r *http.Request) {
 	WriteHealthy(w, r, "postgresql", "")
 }
 
+// LeaderHealthCheck reports whether any instance holds the background
+// scheduler lease and whether it is the instance serving this request. It is
+// unauthenticated, so the holder's identity is not included.
+func (h *Handler) LeaderHealthCheck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		MethodNotAllowed(w, r)
+		return
+	}
+
+	if h.DB == nil {
+		WriteUnhealthy(w, r, "scheduler-leader", fmt.Errorf("database connection not configured"))
+		return
+	}
+
+	lease, err := h.DB.GetLeaderLease(r.Context(), db.SchedulerLeaderLease)
+	if err != nil {
+		WriteUnhealthy(w, r, "scheduler-leader", err)
+		return
+	}
+
+	status, code := "healthy", http.StatusOK
+	if lease == nil {
+		// Expected briefly during deploys while a new leader is elected
+		status, code = "no_leader", http.StatusServiceUnavailable
+	}
+
+	WriteJSON(w, r, map[string]any{
+		"status":    status,
+		"timestamp": time.Now().Format(time.RFC3339),
+		"service":   "scheduler-leader",
+		"is_leader": lease != nil && lease.HolderID == db.InstanceID(),
+	}, code)
+}
+
 // ServeTestLogin serves the test login page
 func (h *Handler) ServeTestLogin(w http.ResponseWriter, r *http.Request) {
 	http.ServeFile(w, r, "test-login.html")
@@ -1081,14 +1149,40 @@ func (h *Handler) WebflowWebhook(w http.ResponseWriter, r *http.Request) {
 		SourceInfo:   &sourceInfo,
 	}
 
+	// Purge-then-warm: if the site's CDN is connected with purge on publish,
+	// clear it once the job is recorded so the job warms the newly published
+	// content
+	var purgeResult *cdn.PurgeResult
+	var purgeErr error
+	var beforeStart func(context.Context, *jobs.Job) error
+	if orgID != "" {
+		conn, err := h.DB.GetCDNConnectionForDomain(r.Context(), orgID, util.NormaliseDomain(selectedDomain))
+		if err != nil {
+			logger.Error().Err(err).Str("domain", selectedDomain).Msg("Failed to look up CDN connection for webhook")
+			InternalError(w, r, err)
+			return
+		}
+		if conn != nil && conn.PurgeOnPublish {
+			beforeStart = func(ctx context.Context, _ *jobs.Job) error {
+				purgeResult, purgeErr = h.purgeCDN(ctx, orgID, selectedDomain, cdn.PurgeRequest{Scope: cdn.ScopeEverything}, logger)
+				return purgeErr
+			}
+		}
+	}
+
 	// Shallow copy to avoid mutating the original user while injecting org context.
 	userForJob := *user
 	if orgID != "" {
 		userForJob.ActiveOrganisationID = &orgID
 		userForJob.OrganisationID = &orgID
 	}
-	job, err := h.createJobFromRequest(r.Context(), &userForJob, req, logger)
+	job, err := h.createJobFromRequest(r.Context(), &userForJob, req, beforeStart, logger)
 	if err != nil {
+		if purgeErr != nil {
+			// Fail the webhook so Webflow retries rather than warming stale content
+			writePurgeError(w, r, purgeErr)
+			return
+		}
 		logger.Error().Err(err).
 			Str("user_id", user.ID).
 			Str("domain", selectedDomain).
@@ -1113,5 +1207,6 @@ func (h *Handler) WebflowWebhook(w http.ResponseWriter, r *http.Request) {
 		"org_id":  orgID,
 		"domain":  selectedDomain,
 		"status":  "created",
+		"purge":   purgeResult,
 	}, "Job created successfully from webhook")
 }
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Harvey-AU/adapt/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// leaderTestDB returns a fixed scheduler lease
+type leaderTestDB struct {
+	DBClient
+	lease *db.LeaderLease
+}
+
+func (s *leaderTestDB) GetLeaderLease(_ context.Context, name string) (*db.LeaderLease, error) {
+	return s.lease, nil
+}
+
+func TestLeaderHealthCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		lease      *db.LeaderLease
+		wantStatus int
+		wantLeader bool
+	}{
+		{name: "this instance leads", lease: &db.LeaderLease{HolderID: db.InstanceID()}, wantStatus: http.StatusOK, wantLeader: true},
+		{name: "another instance leads", lease: &db.LeaderLease{HolderID: "other-host:1"}, wantStatus: http.StatusOK},
+		{name: "no leader", wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{DB: &leaderTestDB{lease: tt.lease}}
+			rec := httptest.NewRecorder()
+			h.LeaderHealthCheck(rec, httptest.NewRequest(http.MethodGet, "/health/leader", nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantLeader, body["is_leader"])
+			assert.NotContains(t, rec.Body.String(), "other-host", "instance identities should not be exposed")
+			assert.NotContains(t, body, "instance_id")
+		})
+	}
+}
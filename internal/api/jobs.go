@@ -7,13 +7,17 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Harvey-AU/adapt/internal/cdn"
+	"github.com/Harvey-AU/adapt/internal/crawler"
 	"github.com/Harvey-AU/adapt/internal/db"
 	"github.com/Harvey-AU/adapt/internal/jobs"
 	"github.com/Harvey-AU/adapt/internal/util"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog"
 )
 
@@ -58,6 +62,48 @@ func (h *Handler) JobHandler(w http.ResponseWriter, r *http.Request) {
 			}
 			MethodNotAllowed(w, r)
 			return
+		case "canonical-report":
+			if r.Method == http.MethodGet {
+				h.getJobCanonicalReport(w, r, jobID)
+				return
+			}
+			MethodNotAllowed(w, r)
+			return
+		case "cache-report":
+			if r.Method == http.MethodGet {
+				h.getJobCacheReport(w, r, jobID)
+				return
+			}
+			MethodNotAllowed(w, r)
+			return
+		case "security-report":
+			if r.Method == http.MethodGet {
+				h.getJobSecurityReport(w, r, jobID)
+				return
+			}
+			MethodNotAllowed(w, r)
+			return
+		case "variants":
+			if r.Method == http.MethodGet {
+				h.getJobVariants(w, r, jobID)
+				return
+			}
+			MethodNotAllowed(w, r)
+			return
+		case "changes":
+			if r.Method == http.MethodGet {
+				h.getJobContentChanges(w, r, jobID)
+				return
+			}
+			MethodNotAllowed(w, r)
+			return
+		case "retry-failed":
+			if r.Method == http.MethodPost {
+				h.retryFailedTasks(w, r, jobID)
+				return
+			}
+			MethodNotAllowed(w, r)
+			return
 		case "share-links":
 			if len(parts) == 2 {
 				switch r.Method {
@@ -108,11 +154,25 @@ type CreateJobRequest struct {
 	UseSitemap               *bool   `json:"use_sitemap,omitempty"`
 	FindLinks                *bool   `json:"find_links,omitempty"`
 	AllowCrossSubdomainLinks *bool   `json:"allow_cross_subdomain_links,omitempty"`
+	CrawlAssets              *bool   `json:"crawl_assets,omitempty"`
+	IgnoreRobotsDirectives   *bool   `json:"ignore_robots_directives,omitempty"`
+	CheckExternalLinks       *bool   `json:"check_external_links,omitempty"`
 	Concurrency              *int    `json:"concurrency,omitempty"`
 	MaxPages                 *int    `json:"max_pages,omitempty"`
 	SourceType               *string `json:"source_type,omitempty"`
 	SourceDetail             *string `json:"source_detail,omitempty"`
 	SourceInfo               *string `json:"source_info,omitempty"`
+
+	// WarmVariants warms each device/encoding/language combination per page
+	WarmVariants *crawler.VariantMatrix `json:"warm_variants,omitempty"`
+
+	// Purpose is "warm" (the default) or "integrity", which revalidates pages
+	// with conditional requests instead of full GETs
+	Purpose *string `json:"purpose,omitempty"`
+
+	// Purge clears the domain's CDN before the job is created. Requires a
+	// CDN connection for the domain.
+	Purge *cdn.PurgeRequest `json:"purge,omitempty"`
 }
 
 // JobResponse represents a job in API responses
@@ -134,12 +194,38 @@ type JobResponse struct {
 	AvgTimePerTaskSeconds *float64       `json:"avg_time_per_task_seconds,omitempty"`
 	Stats                 map[string]any `json:"stats,omitempty"`
 	SchedulerID           *string        `json:"scheduler_id,omitempty"`
+	ParentJobID           *string        `json:"parent_job_id,omitempty"`
 	// Job configuration fields
 	Concurrency          int     `json:"concurrency"`
 	MaxPages             int     `json:"max_pages"`
+	CrawlAssets          bool    `json:"crawl_assets"`
 	SourceType           *string `json:"source_type,omitempty"`
 	CrawlDelaySeconds    *int    `json:"crawl_delay_seconds,omitempty"`
 	AdaptiveDelaySeconds int     `json:"adaptive_delay_seconds"`
+
+	// IgnoreRobotsDirectives follows links on noindex/nofollow pages
+	IgnoreRobotsDirectives bool `json:"ignore_robots_directives"`
+
+	// CheckExternalLinks validates links to other sites without crawling them
+	CheckExternalLinks bool `json:"check_external_links"`
+
+	// SitemapErrors lists sitemaps that could not be fetched or read in full
+	SitemapErrors []crawler.SitemapError `json:"sitemap_errors,omitempty"`
+
+	// CrawlMode is "full", or "delta" when only sitemap URLs changed since
+	// BaselineJobID were warmed. SitemapChanges counts sitemap URLs by how
+	// they compare with the baseline.
+	CrawlMode      string         `json:"crawl_mode"`
+	BaselineJobID  *string        `json:"baseline_job_id,omitempty"`
+	SitemapChanges map[string]int `json:"sitemap_changes,omitempty"`
+
+	// Purpose is "warm" or "integrity". NotModifiedTasks counts pages an
+	// integrity check found unchanged (304).
+	Purpose          string `json:"purpose"`
+	NotModifiedTasks int    `json:"not_modified_tasks,omitempty"`
+
+	// Purge is the CDN's confirmation when the job was preceded by a purge
+	Purge *cdn.PurgeResult `json:"purge,omitempty"`
 }
 
 // listJobs handles GET /v1/jobs
@@ -231,8 +317,10 @@ func includeContains(include, key string) bool {
 	return false
 }
 
-// createJobFromRequest creates a job from a CreateJobRequest with user context
-func (h *Handler) createJobFromRequest(ctx context.Context, user *db.User, req CreateJobRequest, logger zerolog.Logger) (*jobs.Job, error) {
+// createJobFromRequest creates a job from a CreateJobRequest with user
+// context. beforeStart, if set, runs once the job is recorded and before it
+// starts (see jobs.JobOptions.BeforeStart).
+func (h *Handler) createJobFromRequest(ctx context.Context, user *db.User, req CreateJobRequest, beforeStart func(context.Context, *jobs.Job) error, logger zerolog.Logger) (*jobs.Job, error) {
 	// Set defaults
 	useSitemap := true
 	if req.UseSitemap != nil {
@@ -249,6 +337,25 @@ func (h *Handler) createJobFromRequest(ctx context.Context, user *db.User, req C
 		allowCrossSubdomainLinks = *req.AllowCrossSubdomainLinks
 	}
 
+	// Asset crawling is opt-in: it can multiply the task count per page
+	crawlAssets := false
+	if req.CrawlAssets != nil {
+		crawlAssets = *req.CrawlAssets
+	}
+
+	// Links on noindex/nofollow pages and rel="nofollow" links are not
+	// followed unless the job opts out
+	ignoreRobotsDirectives := false
+	if req.IgnoreRobotsDirectives != nil {
+		ignoreRobotsDirectives = *req.IgnoreRobotsDirectives
+	}
+
+	// External link checking is opt-in: it sends requests to other sites
+	checkExternalLinks := false
+	if req.CheckExternalLinks != nil {
+		checkExternalLinks = *req.CheckExternalLinks
+	}
+
 	concurrency := 20 // Default concurrency
 	if req.Concurrency != nil && *req.Concurrency > 0 {
 		concurrency = min(*req.Concurrency, 100)
@@ -259,6 +366,11 @@ func (h *Handler) createJobFromRequest(ctx context.Context, user *db.User, req C
 		maxPages = *req.MaxPages
 	}
 
+	purpose := jobs.JobPurposeWarm
+	if req.Purpose != nil {
+		purpose = *req.Purpose
+	}
+
 	// Use effective organisation (active org takes precedence over legacy org)
 	effectiveOrgID := h.DB.GetEffectiveOrganisationID(user)
 	var orgIDPtr *string
@@ -274,10 +386,16 @@ func (h *Handler) createJobFromRequest(ctx context.Context, user *db.User, req C
 		Concurrency:              concurrency,
 		FindLinks:                findLinks,
 		AllowCrossSubdomainLinks: allowCrossSubdomainLinks,
+		CrawlAssets:              crawlAssets,
+		IgnoreRobotsDirectives:   ignoreRobotsDirectives,
+		CheckExternalLinks:       checkExternalLinks,
+		WarmVariants:             req.WarmVariants,
+		Purpose:                  purpose,
 		MaxPages:                 maxPages,
 		SourceType:               req.SourceType,
 		SourceDetail:             req.SourceDetail,
 		SourceInfo:               req.SourceInfo,
+		BeforeStart:              beforeStart,
 	}
 
 	// Trigger GA4 data fetch in background if findLinks is enabled and organisation has GA4 connection
@@ -308,13 +426,11 @@ func (h *Handler) createJob(w http.ResponseWriter, r *http.Request) {
 	logger := loggerWithRequest(r)
 
 	// Get user and active organisation (validates auth and membership)
-	user, _, ok := h.GetActiveOrganisationWithUser(w, r)
+	user, orgID, ok := h.GetActiveOrganisationWithUser(w, r)
 	if !ok {
 		return // Error already written
 	}
 
-	_ = logger
-
 	var req CreateJobRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		BadRequest(w, r, "Invalid JSON request body")
@@ -332,6 +448,21 @@ func (h *Handler) createJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := req.WarmVariants.Validate(); err != nil {
+		BadRequest(w, r, fmt.Sprintf("Invalid warm_variants: %s", err.Error()))
+		return
+	}
+
+	if err := req.Purge.Validate(); err != nil {
+		BadRequest(w, r, fmt.Sprintf("Invalid purge: %s", err.Error()))
+		return
+	}
+
+	if req.Purpose != nil && *req.Purpose != jobs.JobPurposeWarm && *req.Purpose != jobs.JobPurposeIntegrity {
+		BadRequest(w, r, fmt.Sprintf("Invalid purpose: %q (must be %q or %q)", *req.Purpose, jobs.JobPurposeWarm, jobs.JobPurposeIntegrity))
+		return
+	}
+
 	// Set source information if not provided (dashboard creation)
 	if req.SourceType == nil {
 		sourceType := "dashboard"
@@ -354,8 +485,29 @@ func (h *Handler) createJob(w http.ResponseWriter, r *http.Request) {
 		req.SourceInfo = &sourceInfo
 	}
 
-	job, err := h.createJobFromRequest(r.Context(), user, req, logger)
+	// Purge-then-warm: the job is recorded first, so a job that cannot be
+	// created never leaves the CDN purged and cold, and only starts warming
+	// once the CDN has confirmed the purge
+	var purgeResult *cdn.PurgeResult
+	var purgeErr error
+	var beforeStart func(context.Context, *jobs.Job) error
+	if req.Purge != nil {
+		// Clearing the whole zone affects every site behind it
+		if req.Purge.Scope == cdn.ScopeEverything && !h.requireOrganisationAdmin(w, r, orgID, user.ID) {
+			return
+		}
+		beforeStart = func(ctx context.Context, _ *jobs.Job) error {
+			purgeResult, purgeErr = h.purgeCDN(ctx, orgID, req.Domain, *req.Purge, logger)
+			return purgeErr
+		}
+	}
+
+	job, err := h.createJobFromRequest(r.Context(), user, req, beforeStart, logger)
 	if err != nil {
+		if purgeErr != nil {
+			writePurgeError(w, r, purgeErr)
+			return
+		}
 		if HandlePoolSaturation(w, r, err) {
 			return
 		}
@@ -387,6 +539,7 @@ func (h *Handler) createJob(w http.ResponseWriter, r *http.Request) {
 		SkippedTasks:   job.SkippedTasks,
 		Progress:       0.0,
 		CreatedAt:      job.CreatedAt.Format(time.RFC3339),
+		Purge:          purgeResult,
 	}
 
 	WriteCreated(w, r, response, "Job created successfully")
@@ -429,9 +582,12 @@ func (h *Handler) fetchJobResponse(ctx context.Context, jobID string, organisati
 	var createdAt, startedAt, completedAt sql.NullTime
 	var durationSeconds sql.NullInt64
 	var avgTimePerTaskSeconds sql.NullFloat64
-	var statsJSON []byte
-	var schedulerID sql.NullString
+	var statsJSON, sitemapErrorsJSON, sitemapChangesJSON []byte
+	var schedulerID, parentJobID, baselineJobID sql.NullString
+	var crawlMode, purpose string
+	var notModifiedTasks int
 	var concurrency, maxPages, adaptiveDelaySeconds int
+	var crawlAssets, ignoreRobotsDirectives, checkExternalLinks bool
 	var sourceType sql.NullString
 	var crawlDelaySeconds sql.NullInt64
 
@@ -442,8 +598,13 @@ func (h *Handler) fetchJobResponse(ctx context.Context, jobID string, organisati
 		       CASE WHEN j.completed_tasks > 0 THEN
 		           EXTRACT(EPOCH FROM (j.completed_at - j.started_at)) / j.completed_tasks
 		       END as avg_time_per_task_seconds,
-		       j.stats, j.scheduler_id,
-		       j.concurrency, j.max_pages, j.source_type,
+		       j.stats, j.scheduler_id, j.parent_job_id,
+		       j.concurrency, j.max_pages, j.crawl_assets, j.source_type,
+		       j.ignore_robots_directives, j.check_external_links, j.sitemap_errors,
+		       j.crawl_mode, j.baseline_job_id, j.sitemap_changes, j.purpose,
+		       CASE WHEN j.purpose = 'integrity' THEN
+		           (SELECT COUNT(*) FROM tasks t WHERE t.job_id = j.id AND t.not_modified)
+		       ELSE 0 END as not_modified_tasks,
 		       d.crawl_delay_seconds, d.adaptive_delay_seconds
 		FROM jobs j
 		JOIN domains d ON j.domain_id = d.id
@@ -462,9 +623,14 @@ func (h *Handler) fetchJobResponse(ctx context.Context, jobID string, organisati
 		// Job info
 		&status, &domainID, &domain, &createdAt, &startedAt, &completedAt,
 		// Computed metrics
-		&durationSeconds, &avgTimePerTaskSeconds, &statsJSON, &schedulerID,
+		&durationSeconds, &avgTimePerTaskSeconds, &statsJSON, &schedulerID, &parentJobID,
 		// Job config
-		&concurrency, &maxPages, &sourceType,
+		&concurrency, &maxPages, &crawlAssets, &sourceType, &ignoreRobotsDirectives, &checkExternalLinks,
+		&sitemapErrorsJSON,
+		// Sitemap change tracking
+		&crawlMode, &baselineJobID, &sitemapChangesJSON,
+		// Conditional requests
+		&purpose, &notModifiedTasks,
 		// Domain delays
 		&crawlDelaySeconds, &adaptiveDelaySeconds,
 	)
@@ -489,7 +655,14 @@ func (h *Handler) fetchJobResponse(ctx context.Context, jobID string, organisati
 		Progress:             progress,
 		Concurrency:          concurrency,
 		MaxPages:             maxPages,
+		CrawlAssets:          crawlAssets,
 		AdaptiveDelaySeconds: adaptiveDelaySeconds,
+
+		IgnoreRobotsDirectives: ignoreRobotsDirectives,
+		CheckExternalLinks:     checkExternalLinks,
+		CrawlMode:              crawlMode,
+		Purpose:                purpose,
+		NotModifiedTasks:       notModifiedTasks,
 	}
 	if sourceType.Valid {
 		response.SourceType = &sourceType.String
@@ -501,6 +674,12 @@ func (h *Handler) fetchJobResponse(ctx context.Context, jobID string, organisati
 	if schedulerID.Valid {
 		response.SchedulerID = &schedulerID.String
 	}
+	if parentJobID.Valid {
+		response.ParentJobID = &parentJobID.String
+	}
+	if baselineJobID.Valid {
+		response.BaselineJobID = &baselineJobID.String
+	}
 
 	if durationSeconds.Valid {
 		duration := int(durationSeconds.Int64)
@@ -518,6 +697,20 @@ func (h *Handler) fetchJobResponse(ctx context.Context, jobID string, organisati
 		}
 	}
 
+	if len(sitemapErrorsJSON) > 0 {
+		var sitemapErrors []crawler.SitemapError
+		if err := json.Unmarshal(sitemapErrorsJSON, &sitemapErrors); err == nil {
+			response.SitemapErrors = sitemapErrors
+		}
+	}
+
+	if len(sitemapChangesJSON) > 0 {
+		var sitemapChanges map[string]int
+		if err := json.Unmarshal(sitemapChangesJSON, &sitemapChanges); err == nil {
+			response.SitemapChanges = sitemapChanges
+		}
+	}
+
 	if createdAt.Valid {
 		response.CreatedAt = createdAt.Time.Format(time.RFC3339)
 	} else {
@@ -575,15 +768,30 @@ func (h *Handler) updateJob(w http.ResponseWriter, r *http.Request, jobID string
 	_ = logger // logger available for future use
 
 	resultJobID := jobID
+	var message, conflict string
 	switch req.Action {
 	case "cancel":
 		err = h.JobsManager.CancelJob(r.Context(), jobID)
+		message = "Job cancelled successfully"
+		conflict = "Only pending, running or paused jobs can be cancelled"
+	case "pause":
+		err = h.JobsManager.PauseJob(r.Context(), jobID)
+		message = "Job paused successfully"
+		conflict = "Only running jobs can be paused"
+	case "resume":
+		err = h.JobsManager.ResumeJob(r.Context(), jobID)
+		message = "Job resumed successfully"
+		conflict = "Only paused jobs can be resumed"
 	default:
-		BadRequest(w, r, "Invalid action. Supported actions: cancel")
+		BadRequest(w, r, "Invalid action. Supported actions: cancel, pause, resume")
 		return
 	}
 
 	if err != nil {
+		if errors.Is(err, jobs.ErrInvalidJobTransition) {
+			WriteErrorMessage(w, r, conflict, http.StatusConflict, ErrCodeConflict)
+			return
+		}
 		logger.Error().Err(err).Str("job_id", jobID).Str("action", req.Action).Msg("Failed to perform job action")
 		InternalError(w, r, err)
 		return
@@ -596,7 +804,7 @@ func (h *Handler) updateJob(w http.ResponseWriter, r *http.Request, jobID string
 		return
 	}
 
-	WriteSuccess(w, r, response, "Job cancelled successfully")
+	WriteSuccess(w, r, response, message)
 }
 
 // cancelJob handles DELETE /v1/jobs/:id
@@ -629,6 +837,10 @@ func (h *Handler) cancelJob(w http.ResponseWriter, r *http.Request, jobID string
 
 	err = h.JobsManager.CancelJob(r.Context(), jobID)
 	if err != nil {
+		if errors.Is(err, jobs.ErrInvalidJobTransition) {
+			WriteErrorMessage(w, r, "Only pending, running or paused jobs can be cancelled", http.StatusConflict, ErrCodeConflict)
+			return
+		}
 		logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to cancel job")
 		InternalError(w, r, err)
 		return
@@ -637,6 +849,60 @@ func (h *Handler) cancelJob(w http.ResponseWriter, r *http.Request, jobID string
 	WriteSuccess(w, r, map[string]string{"id": jobID, "status": "cancelled"}, "Job cancelled successfully")
 }
 
+// retryFailedTasks handles POST /v1/jobs/:id/retry-failed
+func (h *Handler) retryFailedTasks(w http.ResponseWriter, r *http.Request, jobID string) {
+	logger := loggerWithRequest(r)
+
+	// Get active organisation (validates auth and membership)
+	activeOrgID := h.GetActiveOrganisation(w, r)
+	if activeOrgID == "" {
+		return // Error already written
+	}
+
+	// Verify job belongs to user's active organisation
+	var jobOrgID string
+	err := h.DB.GetDB().QueryRowContext(r.Context(), `
+		SELECT organisation_id FROM jobs WHERE id = $1
+	`, jobID).Scan(&jobOrgID)
+
+	if err != nil {
+		NotFound(w, r, "Job not found")
+		return
+	}
+
+	if activeOrgID != jobOrgID {
+		Unauthorised(w, r, "Job access denied")
+		return
+	}
+
+	job, err := h.JobsManager.RetryFailedTasks(r.Context(), jobID)
+	if err != nil {
+		if errors.Is(err, jobs.ErrJobNotFinished) {
+			BadRequest(w, r, "Job must be finished before retrying failed tasks")
+			return
+		}
+		if errors.Is(err, jobs.ErrNoFailedTasks) {
+			BadRequest(w, r, "Job has no failed tasks to retry")
+			return
+		}
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to retry failed tasks")
+		InternalError(w, r, err)
+		return
+	}
+
+	response, err := h.fetchJobResponse(r.Context(), job.ID, &activeOrgID)
+	if err != nil {
+		logger.Error().Err(err).Str("job_id", job.ID).Msg("Failed to fetch retry job")
+		InternalError(w, r, err)
+		return
+	}
+
+	WriteCreated(w, r, response, "Retry job created successfully")
+}
+
 // TaskQueryParams holds parameters for task listing queries
 type TaskQueryParams struct {
 	Limit       int
@@ -761,8 +1027,10 @@ type TaskQueryBuilder struct {
 func buildTaskQuery(jobID string, params TaskQueryParams) TaskQueryBuilder {
 	baseQuery := `
 		SELECT t.id, t.job_id, p.path, COALESCE(t.host, d.name) as host, d.name as domain, t.status, t.status_code, t.response_time,
-		       t.cache_status, t.second_response_time, t.second_cache_status, t.content_type, t.error, t.source_type, t.source_url,
+		       t.cache_status, t.second_response_time, t.second_cache_status, t.cdn_provider, t.content_type, t.error, t.source_type, t.source_url,
 		       t.created_at, t.started_at, t.completed_at, t.retry_count,
+		       t.redirect_url, t.redirect_chain, t.redirect_loop, t.redirect_limit_exceeded, t.cache_variants, t.seo, t.page_audit, t.structured_data, t.insecure_content, t.soft_404,
+		       t.etag, t.last_modified, t.not_modified,
 		       pa.page_views_7d, pa.page_views_28d, pa.page_views_180d
 		FROM tasks t
 		JOIN pages p ON t.page_id = p.id
@@ -828,12 +1096,17 @@ func formatTasksFromRows(rows *sql.Rows) ([]TaskResponse, error) {
 		var startedAt, completedAt, createdAt sql.NullTime
 		var statusCode, responseTime, secondResponseTime sql.NullInt32
 		var pageViews7d, pageViews28d, pageViews180d sql.NullInt64
-		var cacheStatus, secondCacheStatus, contentType, errorMsg, sourceType, sourceURL sql.NullString
+		var cacheStatus, secondCacheStatus, cdnProvider, contentType, errorMsg, sourceType, sourceURL, redirectURL, soft404 sql.NullString
+		var redirectChain, cacheVariants, seo, pageAudit, structuredData, insecureContent []byte
+		var etag, lastModified sql.NullString
+		var redirectLoop, redirectLimitHit, notModified bool
 
 		err := rows.Scan(
 			&task.ID, &task.JobID, &task.Path, &host, &domain, &task.Status,
-			&statusCode, &responseTime, &cacheStatus, &secondResponseTime, &secondCacheStatus, &contentType, &errorMsg, &sourceType, &sourceURL,
+			&statusCode, &responseTime, &cacheStatus, &secondResponseTime, &secondCacheStatus, &cdnProvider, &contentType, &errorMsg, &sourceType, &sourceURL,
 			&createdAt, &startedAt, &completedAt, &task.RetryCount,
+			&redirectURL, &redirectChain, &redirectLoop, &redirectLimitHit, &cacheVariants, &seo, &pageAudit, &structuredData, &insecureContent, &soft404,
+			&etag, &lastModified, &notModified,
 			&pageViews7d, &pageViews28d, &pageViews180d,
 		)
 		if err != nil {
@@ -866,6 +1139,9 @@ func formatTasksFromRows(rows *sql.Rows) ([]TaskResponse, error) {
 		if secondCacheStatus.Valid {
 			task.SecondCacheStatus = &secondCacheStatus.String
 		}
+		if cdnProvider.Valid {
+			task.CDNProvider = &cdnProvider.String
+		}
 		if contentType.Valid {
 			task.ContentType = &contentType.String
 		}
@@ -878,6 +1154,41 @@ func formatTasksFromRows(rows *sql.Rows) ([]TaskResponse, error) {
 		if sourceURL.Valid {
 			task.SourceURL = &sourceURL.String
 		}
+		if redirectURL.Valid && redirectURL.String != "" {
+			task.RedirectURL = &redirectURL.String
+		}
+		if soft404.Valid && soft404.String != "" {
+			task.Soft404 = &soft404.String
+		}
+		if etag.Valid {
+			task.ETag = &etag.String
+		}
+		if lastModified.Valid {
+			task.LastModified = &lastModified.String
+		}
+		task.NotModified = notModified
+		applyRedirectChain(&task, redirectChain, redirectLoop, redirectLimitHit)
+		if len(cacheVariants) > 0 {
+			if err := json.Unmarshal(cacheVariants, &task.CacheVariants); err != nil {
+				task.CacheVariants = nil
+			}
+		}
+		if len(seo) > 0 {
+			if err := json.Unmarshal(seo, &task.SEO); err != nil {
+				task.SEO = nil
+			}
+		}
+		if len(pageAudit) > 0 {
+			if err := json.Unmarshal(pageAudit, &task.PageAudit); err != nil {
+				task.PageAudit = nil
+			}
+		}
+		if len(structuredData) > 0 {
+			if err := json.Unmarshal(structuredData, &task.StructuredData); err != nil {
+				task.StructuredData = nil
+			}
+		}
+		applyInsecureContent(&task, insecureContent)
 		if startedAt.Valid {
 			sa := startedAt.Time.Format(time.RFC3339)
 			task.StartedAt = &sa
@@ -910,6 +1221,252 @@ func formatTasksFromRows(rows *sql.Rows) ([]TaskResponse, error) {
 	return tasks, nil
 }
 
+// applyRedirectChain decodes a task's stored redirect hops and derives the
+// summary fields used by the redirect-chains export
+func applyRedirectChain(task *TaskResponse, chainJSON []byte, loop, limitHit bool) {
+	if len(chainJSON) > 0 {
+		if err := json.Unmarshal(chainJSON, &task.RedirectChain); err != nil {
+			task.RedirectChain = nil
+		}
+	}
+	task.RedirectHops = len(task.RedirectChain)
+
+	var issue string
+	switch {
+	case loop:
+		issue = "loop"
+	case limitHit:
+		issue = "too_many_redirects"
+	case task.RedirectHops > 1:
+		issue = "chain"
+	}
+	if issue != "" {
+		task.RedirectIssue = &issue
+	}
+
+	if task.RedirectHops == 0 {
+		return
+	}
+
+	// e.g. "https://a.com/old (301) → https://a.com/interim (302) → https://a.com/new"
+	parts := make([]string, 0, task.RedirectHops+1)
+	for _, hop := range task.RedirectChain {
+		parts = append(parts, fmt.Sprintf("%s (%d)", hop.URL, hop.StatusCode))
+	}
+	last := task.RedirectChain[task.RedirectHops-1]
+	if destination := resolveRedirectLocation(last.URL, last.Location); destination != "" {
+		parts = append(parts, destination)
+	}
+	path := strings.Join(parts, " → ")
+	task.RedirectPath = &path
+}
+
+// applyInsecureContent decodes a task's stored insecure references and
+// derives the summary used by the mixed-content export
+func applyInsecureContent(task *TaskResponse, insecureJSON []byte) {
+	if len(insecureJSON) == 0 {
+		return
+	}
+	if err := json.Unmarshal(insecureJSON, &task.InsecureContent); err != nil || len(task.InsecureContent) == 0 {
+		task.InsecureContent = nil
+		return
+	}
+
+	// e.g. "img http://example.com/logo.png; a http://example.com/about"
+	parts := make([]string, 0, len(task.InsecureContent))
+	for _, ref := range task.InsecureContent {
+		parts = append(parts, ref.Element+" "+ref.URL)
+	}
+	summary := strings.Join(parts, "; ")
+	task.InsecureSummary = &summary
+}
+
+// applyTaskFindings attaches recorded audit findings to their tasks and
+// derives the flat columns used by the seo-audit export
+func applyTaskFindings(tasks []TaskResponse, findings []db.TaskFinding) {
+	byTask := make(map[string][]crawler.AuditFinding)
+	for _, f := range findings {
+		byTask[f.TaskID] = append(byTask[f.TaskID], crawler.AuditFinding{Code: f.Code, Severity: f.Severity, Detail: f.Detail})
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+		if task.PageAudit != nil && task.PageAudit.Title != "" {
+			title := task.PageAudit.Title
+			task.PageTitle = &title
+		}
+
+		task.Findings = byTask[task.ID]
+		if len(task.Findings) == 0 {
+			continue
+		}
+
+		// e.g. "missing_description; multiple_h1 (2 h1 elements)"
+		parts := make([]string, 0, len(task.Findings))
+		for _, f := range task.Findings {
+			if f.Detail != "" {
+				parts = append(parts, fmt.Sprintf("%s (%s)", f.Code, f.Detail))
+			} else {
+				parts = append(parts, f.Code)
+			}
+		}
+		summary := strings.Join(parts, "; ")
+		task.SEOFindings = &summary
+	}
+}
+
+// externalLinkTaskResponses converts a job's broken external links into rows
+// for the broken-links export, flagged so they stand apart from crawled pages
+func externalLinkTaskResponses(jobID string, links []db.JobExternalLink) []TaskResponse {
+	rows := make([]TaskResponse, 0, len(links))
+	for _, link := range links {
+		row := TaskResponse{
+			JobID:     jobID,
+			URL:       link.URL,
+			Status:    "failed",
+			CreatedAt: link.CreatedAt.Format(time.RFC3339),
+			External:  true,
+			Severity:  "error",
+		}
+		if parsed, err := url.Parse(link.URL); err == nil {
+			row.Path = parsed.RequestURI()
+		}
+		if link.Host != "" {
+			host := link.Host
+			row.Host = &host
+		}
+		if link.SourceURL != "" {
+			sourceURL := link.SourceURL
+			row.SourceURL = &sourceURL
+		}
+		if link.StatusCode > 0 {
+			statusCode := link.StatusCode
+			row.StatusCode = &statusCode
+		}
+		if link.ResponseTime > 0 {
+			responseTime := int(link.ResponseTime)
+			row.ResponseTime = &responseTime
+		}
+		if link.Error != "" {
+			errMsg := link.Error
+			row.Error = &errMsg
+		}
+		if link.CheckedAt != nil {
+			completedAt := link.CheckedAt.Format(time.RFC3339)
+			row.CompletedAt = &completedAt
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// missingAnchorTaskResponses converts fragment links with no matching anchor
+// into lower-severity rows for the broken-links export
+func missingAnchorTaskResponses(jobID string, missing []db.MissingAnchor) []TaskResponse {
+	rows := make([]TaskResponse, 0, len(missing))
+	for _, m := range missing {
+		row := TaskResponse{
+			JobID:     jobID,
+			URL:       m.URL,
+			Status:    "missing_anchor",
+			CreatedAt: m.CreatedAt.Format(time.RFC3339),
+			Severity:  "warning",
+		}
+		if parsed, err := url.Parse(m.URL); err == nil {
+			host := parsed.Hostname()
+			row.Host = &host
+			row.Path = parsed.Path
+		}
+		if m.SourceURL != "" {
+			sourceURL := m.SourceURL
+			row.SourceURL = &sourceURL
+		}
+		fragment := m.Fragment
+		row.MissingAnchor = &fragment
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// applySitemapChanges sets each task's sitemap change for the
+// sitemap-changes export, and adds rows for changed URLs with no task: pages
+// removed from the sitemap, which are reported rather than crawled, and any
+// the job did not reach
+func applySitemapChanges(jobID string, tasks []TaskResponse, changes []db.SitemapChange) []TaskResponse {
+	byPage := make(map[string]db.SitemapChange, len(changes))
+	for _, change := range changes {
+		byPage[change.Host+"|"+change.Path] = change
+	}
+
+	formatLastMod := func(t *time.Time) *string {
+		if t == nil {
+			return nil
+		}
+		formatted := t.Format(time.RFC3339)
+		return &formatted
+	}
+
+	matched := make(map[string]bool, len(tasks))
+	for i := range tasks {
+		if tasks[i].Host == nil {
+			continue
+		}
+		key := *tasks[i].Host + "|" + tasks[i].Path
+		change, ok := byPage[key]
+		if !ok {
+			continue
+		}
+		matched[key] = true
+		kind := change.Change
+		tasks[i].SitemapChange = &kind
+		tasks[i].LastMod = formatLastMod(change.LastMod)
+		tasks[i].PreviousLastMod = formatLastMod(change.PreviousLastMod)
+	}
+
+	for _, change := range changes {
+		key := change.Host + "|" + change.Path
+		if matched[key] {
+			continue
+		}
+		matched[key] = true
+
+		status := "not_crawled"
+		if change.Change == db.SitemapChangeRemoved {
+			status = "removed"
+		}
+		host := change.Host
+		kind := change.Change
+		tasks = append(tasks, TaskResponse{
+			JobID:           jobID,
+			Host:            &host,
+			Path:            change.Path,
+			URL:             util.ConstructURL(change.Host, change.Path),
+			Status:          status,
+			CreatedAt:       change.CreatedAt.Format(time.RFC3339),
+			SitemapChange:   &kind,
+			LastMod:         formatLastMod(change.LastMod),
+			PreviousLastMod: formatLastMod(change.PreviousLastMod),
+		})
+	}
+	return tasks
+}
+
+// resolveRedirectLocation resolves a (possibly relative) Location header against the hop URL
+func resolveRedirectLocation(hopURL, location string) string {
+	if location == "" {
+		return ""
+	}
+	base, err := url.Parse(hopURL)
+	if err != nil {
+		return location
+	}
+	ref, err := url.Parse(location)
+	if err != nil {
+		return location
+	}
+	return base.ResolveReference(ref).String()
+}
+
 func canonicalHostForComparison(host string) string {
 	host = strings.ToLower(strings.TrimSpace(host))
 	host = strings.TrimSuffix(host, ".")
@@ -929,6 +1486,7 @@ type TaskResponse struct {
 	CacheStatus        *string `json:"cache_status,omitempty"`
 	SecondResponseTime *int    `json:"second_response_time,omitempty"`
 	SecondCacheStatus  *string `json:"second_cache_status,omitempty"`
+	CDNProvider        *string `json:"cdn_provider,omitempty"`
 	ContentType        *string `json:"content_type,omitempty"`
 	Error              *string `json:"error,omitempty"`
 	SourceType         *string `json:"source_type,omitempty"`
@@ -937,9 +1495,64 @@ type TaskResponse struct {
 	StartedAt          *string `json:"started_at,omitempty"`
 	CompletedAt        *string `json:"completed_at,omitempty"`
 	RetryCount         int     `json:"retry_count"`
-	PageViews7d        *int    `json:"page_views_7d,omitempty"`
-	PageViews28d       *int    `json:"page_views_28d,omitempty"`
-	PageViews180d      *int    `json:"page_views_180d,omitempty"`
+	RedirectURL        *string `json:"redirect_url,omitempty"`
+	// Redirect chain details; RedirectIssue is "loop", "too_many_redirects" or "chain" (more than one hop)
+	RedirectChain []crawler.RedirectHop `json:"redirect_chain,omitempty"`
+	RedirectHops  int                   `json:"redirect_hops,omitempty"`
+	RedirectPath  *string               `json:"redirect_path,omitempty"`
+	RedirectIssue *string               `json:"redirect_issue,omitempty"`
+	PageViews7d   *int                  `json:"page_views_7d,omitempty"`
+	PageViews28d  *int                  `json:"page_views_28d,omitempty"`
+	PageViews180d *int                  `json:"page_views_180d,omitempty"`
+
+	// Per-variant cache results when the job has a variant matrix
+	CacheVariants []crawler.VariantResult `json:"cache_variants,omitempty"`
+
+	// Canonical, robots directives and hreflang alternates for HTML pages
+	SEO *crawler.SEOSignals `json:"seo,omitempty"`
+
+	// On-page audit signals for HTML pages. The seo-audit export also sets the
+	// page title, the task's recorded findings (page-level and job-wide) and
+	// a one-line summary of them.
+	PageAudit   *crawler.PageAudit     `json:"page_audit,omitempty"`
+	PageTitle   *string                `json:"page_title,omitempty"`
+	Findings    []crawler.AuditFinding `json:"findings,omitempty"`
+	SEOFindings *string                `json:"seo_findings,omitempty"`
+
+	// JSON-LD and microdata items and their validation issues for HTML pages
+	StructuredData *crawler.StructuredData `json:"structured_data,omitempty"`
+
+	// http:// subresources, form actions and internal links on HTTPS pages,
+	// with a one-line summary for the mixed-content export
+	InsecureContent []crawler.InsecureReference `json:"insecure_content,omitempty"`
+	InsecureSummary *string                     `json:"insecure_summary,omitempty"`
+
+	// Why a page served with a 200 was failed as a soft 404: "template_match",
+	// "not_found_title" or "not_found_text"
+	Soft404 *string `json:"soft_404,omitempty"`
+
+	// ETag and Last-Modified the page was served with; NotModified is set
+	// when an integrity check's conditional request got a 304
+	ETag         *string `json:"etag,omitempty"`
+	LastModified *string `json:"last_modified,omitempty"`
+	NotModified  bool    `json:"not_modified,omitempty"`
+
+	// Set on broken-links export rows for links to other sites, which are
+	// checked rather than crawled
+	External bool `json:"external,omitempty"`
+
+	// Broken-links export only: "error" for broken pages and links, "warning"
+	// for links to a section anchor missing from its page
+	Severity      string  `json:"severity,omitempty"`
+	MissingAnchor *string `json:"missing_anchor,omitempty"`
+
+	// Sitemap-changes export only: how the page's sitemap entry compares with
+	// the baseline job ("new", "changed", "no_lastmod", "not_warmed" or
+	// "removed") and its
+	// lastmod in each
+	SitemapChange   *string `json:"sitemap_change,omitempty"`
+	LastMod         *string `json:"lastmod,omitempty"`
+	PreviousLastMod *string `json:"previous_lastmod,omitempty"`
 }
 
 // ExportColumn describes a column in exported task datasets
@@ -957,6 +1570,10 @@ func taskExportColumns(exportType string, includeAnalytics bool) []ExportColumn
 			{Key: "status", Label: "Status"},
 			{Key: "created_at", Label: "Date"},
 			{Key: "source_type", Label: "Source Type"},
+			{Key: "external", Label: "External"},
+			{Key: "severity", Label: "Severity"},
+			{Key: "missing_anchor", Label: "Missing anchor"},
+			{Key: "soft_404", Label: "Soft 404"},
 		}
 		if includeAnalytics {
 			columns = append(columns,
@@ -983,6 +1600,89 @@ func taskExportColumns(exportType string, includeAnalytics bool) []ExportColumn
 			)
 		}
 		return columns
+	case "redirect-chains":
+		columns := []ExportColumn{
+			{Key: "source_url", Label: "Found on"},
+			{Key: "url", Label: "Requested URL"},
+			{Key: "redirect_issue", Label: "Issue"},
+			{Key: "redirect_hops", Label: "Hops"},
+			{Key: "redirect_path", Label: "Redirect chain"},
+			{Key: "status_code", Label: "Final Status"},
+			{Key: "created_at", Label: "Date"},
+		}
+		if includeAnalytics {
+			columns = append(columns,
+				ExportColumn{Key: "page_views_7d", Label: "Views (7d)"},
+				ExportColumn{Key: "page_views_28d", Label: "Views (28d)"},
+				ExportColumn{Key: "page_views_180d", Label: "Views (180d)"},
+			)
+		}
+		return columns
+	case "seo-audit":
+		columns := []ExportColumn{
+			{Key: "url", Label: "Page"},
+			{Key: "page_title", Label: "Title"},
+			{Key: "seo_findings", Label: "Findings"},
+			{Key: "status_code", Label: "Status Code"},
+			{Key: "created_at", Label: "Date"},
+		}
+		if includeAnalytics {
+			columns = append(columns,
+				ExportColumn{Key: "page_views_7d", Label: "Views (7d)"},
+				ExportColumn{Key: "page_views_28d", Label: "Views (28d)"},
+				ExportColumn{Key: "page_views_180d", Label: "Views (180d)"},
+			)
+		}
+		return columns
+	case "mixed-content":
+		columns := []ExportColumn{
+			{Key: "url", Label: "Page"},
+			{Key: "insecure_summary", Label: "Insecure references"},
+			{Key: "status_code", Label: "Status Code"},
+			{Key: "created_at", Label: "Date"},
+		}
+		if includeAnalytics {
+			columns = append(columns,
+				ExportColumn{Key: "page_views_7d", Label: "Views (7d)"},
+				ExportColumn{Key: "page_views_28d", Label: "Views (28d)"},
+				ExportColumn{Key: "page_views_180d", Label: "Views (180d)"},
+			)
+		}
+		return columns
+	case "missing-validators":
+		columns := []ExportColumn{
+			{Key: "url", Label: "Page"},
+			{Key: "content_type", Label: "Content Type"},
+			{Key: "cache_status", Label: "Cache Status"},
+			{Key: "status_code", Label: "Status Code"},
+			{Key: "created_at", Label: "Date"},
+		}
+		if includeAnalytics {
+			columns = append(columns,
+				ExportColumn{Key: "page_views_7d", Label: "Views (7d)"},
+				ExportColumn{Key: "page_views_28d", Label: "Views (28d)"},
+				ExportColumn{Key: "page_views_180d", Label: "Views (180d)"},
+			)
+		}
+		return columns
+	case "sitemap-changes":
+		columns := []ExportColumn{
+			{Key: "url", Label: "Page"},
+			{Key: "sitemap_change", Label: "Change"},
+			{Key: "lastmod", Label: "Lastmod"},
+			{Key: "previous_lastmod", Label: "Previous lastmod"},
+			{Key: "status", Label: "Status"},
+			{Key: "status_code", Label: "Status Code"},
+			{Key: "created_at", Label: "Date"},
+		}
+		if includeAnalytics {
+			columns = append(columns,
+				ExportColumn{Key: "page_views_7d", Label: "Views (7d)"},
+				ExportColumn{Key: "page_views_28d", Label: "Views (28d)"},
+				ExportColumn{Key: "page_views_180d", Label: "Views (180d)"},
+			)
+		}
+		return columns
 	default: // "job" (all tasks)
 		columns := []ExportColumn{
 			{Key: "id", Label: "Task ID"},
@@ -996,6 +1696,7 @@ func taskExportColumns(exportType string, includeAnalytics bool) []ExportColumn
 			{Key: "response_time", Label: "Load Time (ms)"},
 			{Key: "second_cache_status", Label: "Second Cache Status"},
 			{Key: "second_response_time", Label: "Load Response Time (ms)"},
+			{Key: "cdn_provider", Label: "CDN"},
 			{Key: "retry_count", Label: "Retry Count"},
 			{Key: "error", Label: "Error"},
 			{Key: "source_type", Label: "Source"},
@@ -1081,6 +1782,103 @@ func (h *Handler) getJobTasks(w http.ResponseWriter, r *http.Request, jobID stri
 	WriteSuccess(w, r, response, "Tasks retrieved successfully")
 }
 
+// VariantSummary aggregates one cache variant's warming results across a job
+type VariantSummary struct {
+	Device          string   `json:"device,omitempty"`
+	Encoding        string   `json:"encoding,omitempty"`
+	Language        string   `json:"language,omitempty"`
+	Label           string   `json:"label"`
+	Tasks           int      `json:"tasks"`
+	Warm            int      `json:"warm"`
+	Cold            int      `json:"cold"`
+	AvgResponseTime *float64 `json:"avg_response_time,omitempty"`
+	ColdPaths       []string `json:"cold_paths"`
+}
+
+// getJobVariants handles GET /v1/jobs/:id/variants, reporting per-variant
+// cache results so users can see which device/encoding/language combinations
+// stayed cold after warming
+func (h *Handler) getJobVariants(w http.ResponseWriter, r *http.Request, jobID string) {
+	logger := loggerWithRequest(r)
+
+	user := h.validateJobAccess(w, r, jobID)
+	if user == nil {
+		return // validateJobAccess already wrote the error response
+	}
+
+	// A variant is cold when it errored or its last request was still a miss
+	rows, err := h.DB.GetDB().QueryContext(r.Context(), `
+		WITH variants AS (
+			SELECT
+				t.path,
+				COALESCE(v->>'device', '') AS device,
+				COALESCE(v->>'encoding', '') AS encoding,
+				COALESCE(v->>'language', '') AS language,
+				(v->>'response_time')::numeric AS response_time,
+				(
+					COALESCE(v->>'error', '') <> ''
+					OR UPPER(COALESCE(NULLIF(v->>'second_cache_status', ''), v->>'cache_status', '')) IN ('MISS', 'EXPIRED')
+				) AS cold
+			FROM tasks t
+			CROSS JOIN LATERAL jsonb_array_elements(t.cache_variants) v
+			WHERE t.job_id = $1 AND t.cache_variants IS NOT NULL
+		)
+		SELECT
+			device, encoding, language,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE NOT cold),
+			COUNT(*) FILTER (WHERE cold),
+			AVG(response_time)::float8,
+			COALESCE((ARRAY_AGG(path ORDER BY path) FILTER (WHERE cold))[1:10], ARRAY[]::text[])
+		FROM variants
+		GROUP BY device, encoding, language
+		ORDER BY COUNT(*) FILTER (WHERE cold) DESC, device, encoding, language
+	`, jobID)
+	if err != nil {
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to get cache variants")
+		DatabaseError(w, r, err)
+		return
+	}
+	defer rows.Close()
+
+	summaries := make([]VariantSummary, 0)
+	for rows.Next() {
+		var summary VariantSummary
+		var avg sql.NullFloat64
+		if err := rows.Scan(
+			&summary.Device, &summary.Encoding, &summary.Language,
+			&summary.Tasks, &summary.Warm, &summary.Cold,
+			&avg, pq.Array(&summary.ColdPaths),
+		); err != nil {
+			logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to scan cache variant")
+			DatabaseError(w, r, err)
+			return
+		}
+		if avg.Valid {
+			summary.AvgResponseTime = &avg.Float64
+		}
+		summary.Label = crawler.WarmVariant{
+			Device:   summary.Device,
+			Encoding: summary.Encoding,
+			Language: summary.Language,
+		}.String()
+		summaries = append(summaries, summary)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to iterate cache variants")
+		DatabaseError(w, r, err)
+		return
+	}
+
+	WriteSuccess(w, r, map[string]any{
+		"job_id":   jobID,
+		"variants": summaries,
+	}, "Cache variants retrieved successfully")
+}
+
 // exportJobTasks handles GET /v1/jobs/:id/export
 func (h *Handler) exportJobTasks(w http.ResponseWriter, r *http.Request, jobID string) {
 	h.serveJobExport(w, r, jobID, true)
@@ -1110,6 +1908,23 @@ func (h *Handler) serveJobExport(w http.ResponseWriter, r *http.Request, jobID s
 	case "slow-pages":
 		// Use second_response_time (cache HIT) when available, fallback to response_time
 		whereClause = " AND COALESCE(t.second_response_time, t.response_time) > 3000"
+	case "redirect-chains":
+		// Multi-hop chains, loops and chains cut off at the hop limit
+		whereClause = " AND (jsonb_array_length(COALESCE(t.redirect_chain, '[]'::jsonb)) > 1 OR t.redirect_loop OR t.redirect_limit_exceeded)"
+	case "seo-audit":
+		// Pages with at least one page-level or duplicate finding
+		whereClause = " AND (EXISTS (SELECT 1 FROM task_findings f WHERE f.task_id = t.id) OR t.id IN (SELECT task_id FROM job_duplicate_findings($1)))"
+	case "mixed-content":
+		// HTTPS pages with http:// subresources, form actions or internal links
+		whereClause = " AND jsonb_array_length(COALESCE(t.insecure_content, '[]'::jsonb)) > 0"
+	case "missing-validators":
+		// Pages that have never been served with an ETag or Last-Modified,
+		// so can neither be revalidated nor cached efficiently by clients
+		whereClause = " AND t.status = 'completed' AND p.validators_checked_at IS NOT NULL AND p.validators_seen_at IS NULL"
+	case "sitemap-changes":
+		// Pages whose sitemap entry differs from the baseline job; removed
+		// URLs have no task and are added after the query
+		whereClause = " AND EXISTS (SELECT 1 FROM job_sitemap_entries e WHERE e.job_id = t.job_id AND e.page_id = t.page_id AND e.change IS NOT NULL AND e.change <> 'unchanged')"
 	case "job":
 		// Export all tasks
 		whereClause = ""
@@ -1123,9 +1938,11 @@ func (h *Handler) serveJobExport(w http.ResponseWriter, r *http.Request, jobID s
 		SELECT
 			t.id, t.job_id, p.path, COALESCE(t.host, d.name) as host, d.name as domain,
 			t.status, t.status_code, t.response_time, t.cache_status,
-			t.second_response_time, t.second_cache_status,
+			t.second_response_time, t.second_cache_status, t.cdn_provider,
 			t.content_type, t.error, t.source_type, t.source_url,
 			t.created_at, t.started_at, t.completed_at, t.retry_count,
+			t.redirect_url, t.redirect_chain, t.redirect_loop, t.redirect_limit_exceeded, t.cache_variants, t.seo, t.page_audit, t.structured_data, t.insecure_content, t.soft_404,
+			t.etag, t.last_modified, t.not_modified,
 			pa.page_views_7d, pa.page_views_28d, pa.page_views_180d
 		FROM tasks t
 		JOIN pages p ON t.page_id = p.id
@@ -1155,6 +1972,48 @@ func (h *Handler) serveJobExport(w http.ResponseWriter, r *http.Request, jobID s
 		return
 	}
 
+	if exportType == "seo-audit" {
+		findings, err := h.DB.ListJobFindings(r.Context(), jobID)
+		if err != nil {
+			logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to list audit findings for export")
+			DatabaseError(w, r, err)
+			return
+		}
+		applyTaskFindings(tasks, findings)
+	}
+
+	if exportType == "broken-links" {
+		for i := range tasks {
+			tasks[i].Severity = "error"
+		}
+
+		externalLinks, err := h.DB.ListJobExternalLinks(r.Context(), jobID, true, 10000)
+		if err != nil {
+			logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to list broken external links for export")
+			DatabaseError(w, r, err)
+			return
+		}
+		tasks = append(tasks, externalLinkTaskResponses(jobID, externalLinks)...)
+
+		missingAnchors, err := h.DB.ListJobMissingAnchors(r.Context(), jobID, 10000)
+		if err != nil {
+			logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to list missing anchors for export")
+			DatabaseError(w, r, err)
+			return
+		}
+		tasks = append(tasks, missingAnchorTaskResponses(jobID, missingAnchors)...)
+	}
+
+	if exportType == "sitemap-changes" {
+		changes, err := h.DB.ListJobSitemapChanges(r.Context(), jobID, 10000)
+		if err != nil {
+			logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to list sitemap changes for export")
+			DatabaseError(w, r, err)
+			return
+		}
+		tasks = applySitemapChanges(jobID, tasks, changes)
+	}
+
 	// Get job details
 	var domain, status string
 	var createdAt time.Time
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Harvey-AU/adapt/internal/crawler"
+	"github.com/Harvey-AU/adapt/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTaskFindings(t *testing.T) {
+	tasks := []TaskResponse{
+		{ID: "task-1", PageAudit: &crawler.PageAudit{Title: "Blue widgets"}},
+		{ID: "task-2", PageAudit: &crawler.PageAudit{}},
+	}
+	findings := []db.TaskFinding{
+		{TaskID: "task-1", Code: "missing_description", Severity: "warning"},
+		{TaskID: "task-1", Code: "duplicate_title", Severity: "warning", Detail: "shared with 2 other page(s)"},
+	}
+
+	applyTaskFindings(tasks, findings)
+
+	require.NotNil(t, tasks[0].PageTitle)
+	assert.Equal(t, "Blue widgets", *tasks[0].PageTitle)
+	assert.Len(t, tasks[0].Findings, 2)
+	require.NotNil(t, tasks[0].SEOFindings)
+	assert.Equal(t, "missing_description; duplicate_title (shared with 2 other page(s))", *tasks[0].SEOFindings)
+
+	assert.Nil(t, tasks[1].PageTitle)
+	assert.Empty(t, tasks[1].Findings)
+	assert.Nil(t, tasks[1].SEOFindings)
+}
+
+func TestTaskExportColumnsSEOAudit(t *testing.T) {
+	keys := func(columns []ExportColumn) []string {
+		out := make([]string, 0, len(columns))
+		for _, c := range columns {
+			out = append(out, c.Key)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"url", "page_title", "seo_findings", "status_code", "created_at"}, keys(taskExportColumns("seo-audit", false)))
+	assert.Contains(t, keys(taskExportColumns("seo-audit", true)), "page_views_28d")
+}
+
+func TestApplyInsecureContent(t *testing.T) {
+	var task TaskResponse
+	applyInsecureContent(&task, []byte(`[
+		{"url": "http://cdn.example.com/site.css", "element": "stylesheet", "kind": "mixed_content"},
+		{"url": "http://example.com/about", "element": "a", "kind": "insecure_link"}
+	]`))
+
+	require.Len(t, task.InsecureContent, 2)
+	require.NotNil(t, task.InsecureSummary)
+	assert.Equal(t, "stylesheet http://cdn.example.com/site.css; a http://example.com/about", *task.InsecureSummary)
+
+	var clean TaskResponse
+	applyInsecureContent(&clean, []byte(`[]`))
+	assert.Nil(t, clean.InsecureContent)
+	assert.Nil(t, clean.InsecureSummary)
+}
+
+func TestExternalLinkTaskResponses(t *testing.T) {
+	checkedAt := time.Date(2026, 3, 17, 9, 0, 0, 0, time.UTC)
+	rows := externalLinkTaskResponses("job-1", []db.JobExternalLink{
+		{URL: "https://other.net/gone?x=1", Host: "other.net", SourceURL: "https://example.com/", StatusCode: 404, CheckedAt: &checkedAt, CreatedAt: checkedAt},
+		{URL: "https://down.example.org/", Host: "down.example.org", Error: "connection refused", CreatedAt: checkedAt},
+	})
+
+	require.Len(t, rows, 2)
+	assert.True(t, rows[0].External)
+	assert.Equal(t, "failed", rows[0].Status)
+	assert.Equal(t, "/gone?x=1", rows[0].Path)
+	require.NotNil(t, rows[0].SourceURL)
+	assert.Equal(t, "https://example.com/", *rows[0].SourceURL)
+	require.NotNil(t, rows[0].StatusCode)
+	assert.Equal(t, 404, *rows[0].StatusCode)
+	assert.Nil(t, rows[0].Error)
+
+	assert.Nil(t, rows[1].StatusCode)
+	require.NotNil(t, rows[1].Error)
+	assert.Equal(t, "connection refused", *rows[1].Error)
+	assert.Nil(t, rows[1].CompletedAt)
+
+	assert.Contains(t, taskExportColumns("broken-links", false), ExportColumn{Key: "external", Label: "External"})
+}
+
+func TestMissingAnchorTaskResponses(t *testing.T) {
+	rows := missingAnchorTaskResponses("job-1", []db.MissingAnchor{
+		{URL: "https://example.com/pricing#enterprise", SourceURL: "https://example.com/", Fragment: "enterprise"},
+	})
+
+	require.Len(t, rows, 1)
+	assert.Equal(t, "warning", rows[0].Severity)
+	assert.Equal(t, "missing_anchor", rows[0].Status)
+	assert.Equal(t, "/pricing", rows[0].Path)
+	assert.False(t, rows[0].External)
+	require.NotNil(t, rows[0].MissingAnchor)
+	assert.Equal(t, "enterprise", *rows[0].MissingAnchor)
+	require.NotNil(t, rows[0].SourceURL)
+	assert.Equal(t, "https://example.com/", *rows[0].SourceURL)
+}
+
+func TestApplySitemapChanges(t *testing.T) {
+	host := "example.com"
+	lastMod := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	previous := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []TaskResponse{{ID: "task-1", Host: &host, Path: "/pricing", Status: "completed"}}
+
+	rows := applySitemapChanges("job-1", tasks, []db.SitemapChange{
+		{Host: "example.com", Path: "/pricing", Change: db.SitemapChangeChanged, LastMod: &lastMod, PreviousLastMod: &previous},
+		{Host: "example.com", Path: "/old", Change: db.SitemapChangeRemoved, PreviousLastMod: &previous, CreatedAt: lastMod},
+	})
+
+	require.Len(t, rows, 2)
+	require.NotNil(t, rows[0].SitemapChange)
+	assert.Equal(t, "changed", *rows[0].SitemapChange)
+	assert.Equal(t, "completed", rows[0].Status)
+	require.NotNil(t, rows[0].LastMod)
+	assert.Equal(t, "2026-03-20T00:00:00Z", *rows[0].LastMod)
+
+	assert.Equal(t, "removed", rows[1].Status)
+	assert.Equal(t, "https://example.com/old", rows[1].URL)
+	assert.Nil(t, rows[1].LastMod)
+	require.NotNil(t, rows[1].PreviousLastMod)
+	assert.Equal(t, "2026-03-01T00:00:00Z", *rows[1].PreviousLastMod)
+
+	assert.Contains(t, taskExportColumns("sitemap-changes", false), ExportColumn{Key: "sitemap_change", Label: "Change"})
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Harvey-AU/adapt/internal/jobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jobActionDB serves the job ownership lookup from sqlmock on top of the
+// user and organisation stubs in cdnTestDB
+type jobActionDB struct {
+	cdnTestDB
+	db *sql.DB
+}
+
+func (s *jobActionDB) GetDB() *sql.DB {
+	return s.db
+}
+
+// jobActionManager returns a fixed error from each job action
+type jobActionManager struct {
+	jobs.JobManagerInterface
+	err error
+}
+
+func (m *jobActionManager) CancelJob(context.Context, string) error { return m.err }
+func (m *jobActionManager) PauseJob(context.Context, string) error  { return m.err }
+func (m *jobActionManager) ResumeJob(context.Context, string) error { return m.err }
+
+func TestUpdateJobActionErrors(t *testing.T) {
+	invalid := fmt.Errorf("%w: job cannot be paused: completed", jobs.ErrInvalidJobTransition)
+
+	tests := []struct {
+		name       string
+		action     string
+		err        error
+		wantStatus int
+		wantCode   ErrorCode
+	}{
+		{name: "pause finished job", action: "pause", err: invalid, wantStatus: http.StatusConflict, wantCode: ErrCodeConflict},
+		{name: "resume running job", action: "resume", err: invalid, wantStatus: http.StatusConflict, wantCode: ErrCodeConflict},
+		{name: "cancel finished job", action: "cancel", err: invalid, wantStatus: http.StatusConflict, wantCode: ErrCodeConflict},
+		{name: "unknown action", action: "restart", wantStatus: http.StatusBadRequest, wantCode: ErrCodeBadRequest},
+		{name: "unexpected failure", action: "pause", err: errors.New("connection reset"), wantStatus: http.StatusInternalServerError, wantCode: ErrCodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			mock.ExpectQuery("SELECT organisation_id FROM jobs").
+				WithArgs("job-1").
+				WillReturnRows(sqlmock.NewRows([]string{"organisation_id"}).AddRow("org-1"))
+
+			h := &Handler{DB: &jobActionDB{db: mockDB}, JobsManager: &jobActionManager{err: tt.err}}
+			rec := httptest.NewRecorder()
+			h.updateJob(rec, cdnRequest(http.MethodPut, "/v1/jobs/job-1", `{"action":"`+tt.action+`"}`), "job-1")
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), string(tt.wantCode))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/Harvey-AU/adapt/internal/auth"
+	"github.com/Harvey-AU/adapt/internal/crawler"
 	"github.com/Harvey-AU/adapt/internal/db"
 	"github.com/Harvey-AU/adapt/internal/loops"
 	"github.com/Harvey-AU/adapt/internal/util"
@@ -739,6 +740,90 @@ func (h *Handler) OrganisationPlanHandler(w http.ResponseWriter, r *http.Request
 	}, "Organisation plan updated successfully")
 }
 
+// OrganisationCacheMappingsHandler handles GET and PUT /v1/organisations/cache-mappings.
+// Mappings teach the crawler how to read cache status headers from CDNs the
+// built-in detectors don't recognise. Any member can read them; only admins
+// can replace them.
+func (h *Handler) OrganisationCacheMappingsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut {
+		MethodNotAllowed(w, r)
+		return
+	}
+
+	orgID := h.GetActiveOrganisation(w, r)
+	if orgID == "" {
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		raw, err := h.DB.GetOrganisationCacheHeaderMappings(r.Context(), orgID)
+		if err != nil {
+			if HandlePoolSaturation(w, r, err) {
+				return
+			}
+			DatabaseError(w, r, err)
+			return
+		}
+
+		mappings := []crawler.HeaderMapping{}
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &mappings); err != nil {
+				log.Warn().Err(err).Str("organisation_id", orgID).Msg("Invalid cache header mappings stored for organisation")
+				mappings = []crawler.HeaderMapping{}
+			}
+		}
+
+		WriteSuccess(w, r, map[string]any{
+			"mappings": mappings,
+		}, "Cache header mappings retrieved successfully")
+		return
+	}
+
+	userClaims, ok := auth.GetUserFromContext(r.Context())
+	if !ok {
+		Unauthorised(w, r, "User information not found")
+		return
+	}
+
+	if ok := h.requireOrganisationAdmin(w, r, orgID, userClaims.UserID); !ok {
+		return
+	}
+
+	var req struct {
+		Mappings []crawler.HeaderMapping `json:"mappings"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		BadRequest(w, r, "Invalid JSON request body")
+		return
+	}
+	if req.Mappings == nil {
+		req.Mappings = []crawler.HeaderMapping{}
+	}
+
+	if err := crawler.ValidateHeaderMappings(req.Mappings); err != nil {
+		BadRequest(w, r, "Invalid cache header mappings: "+err.Error())
+		return
+	}
+
+	raw, err := json.Marshal(req.Mappings)
+	if err != nil {
+		InternalError(w, r, err)
+		return
+	}
+
+	if err := h.DB.SetOrganisationCacheHeaderMappings(r.Context(), orgID, raw); err != nil {
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		DatabaseError(w, r, err)
+		return
+	}
+
+	WriteSuccess(w, r, map[string]any{
+		"mappings": req.Mappings,
+	}, "Cache header mappings updated successfully")
+}
+
 // UsageHistoryHandler handles GET /v1/usage/history
 func (h *Handler) UsageHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
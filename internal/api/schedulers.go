@@ -15,16 +15,24 @@ import (
 	"github.com/google/uuid"
 )
 
+// defaultFullCrawlEvery is how often a delta scheduler forces a full crawl
+const defaultFullCrawlEvery = 7
+
 // SchedulerRequest represents the request body for creating/updating a scheduler
 type SchedulerRequest struct {
 	Domain                string   `json:"domain"`                            // Only used for creation, not update
 	ScheduleIntervalHours *int     `json:"schedule_interval_hours,omitempty"` // Pointer for explicit optional updates
+	CronExpression        *string  `json:"cron_expression,omitempty"`         // Alternative to schedule_interval_hours
+	Timezone              *string  `json:"timezone,omitempty"`                // IANA zone for cron_expression, defaults to UTC
 	Concurrency           *int     `json:"concurrency,omitempty"`
 	FindLinks             *bool    `json:"find_links,omitempty"`
 	MaxPages              *int     `json:"max_pages,omitempty"`
 	IncludePaths          []string `json:"include_paths,omitempty"`
 	ExcludePaths          []string `json:"exclude_paths,omitempty"`
 	IsEnabled             *bool    `json:"is_enabled,omitempty"`
+	KeepWarm              *bool    `json:"keep_warm,omitempty"`           // Re-warm pages before their edge TTL expires
+	DeltaMode             *bool    `json:"delta_mode,omitempty"`          // Only warm sitemap URLs changed since the last run
+	FullCrawlEvery        *int     `json:"full_crawl_every,omitempty"`    // Force a full crawl every N runs in delta mode
 	ExpectedIsEnabled     *bool    `json:"expected_is_enabled,omitempty"` // Optional optimistic concurrency hint
 }
 
@@ -33,6 +41,8 @@ type SchedulerResponse struct {
 	ID                    string   `json:"id"`
 	Domain                string   `json:"domain"`
 	ScheduleIntervalHours int      `json:"schedule_interval_hours"`
+	CronExpression        string   `json:"cron_expression,omitempty"`
+	Timezone              string   `json:"timezone"`
 	NextRunAt             string   `json:"next_run_at"`
 	IsEnabled             bool     `json:"is_enabled"`
 	Concurrency           int      `json:"concurrency"`
@@ -40,6 +50,9 @@ type SchedulerResponse struct {
 	MaxPages              int      `json:"max_pages"`
 	IncludePaths          []string `json:"include_paths,omitempty"`
 	ExcludePaths          []string `json:"exclude_paths,omitempty"`
+	KeepWarm              bool     `json:"keep_warm"`
+	DeltaMode             bool     `json:"delta_mode"`
+	FullCrawlEvery        int      `json:"full_crawl_every"`
 	CreatedAt             string   `json:"created_at"`
 	UpdatedAt             string   `json:"updated_at"`
 }
@@ -123,11 +136,16 @@ func (h *Handler) createScheduler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.ScheduleIntervalHours == nil {
-		BadRequest(w, r, "schedule_interval_hours is required")
+	hasCron := req.CronExpression != nil && strings.TrimSpace(*req.CronExpression) != ""
+	if req.ScheduleIntervalHours == nil && !hasCron {
+		BadRequest(w, r, "schedule_interval_hours or cron_expression is required")
+		return
+	}
+	if req.ScheduleIntervalHours != nil && hasCron {
+		BadRequest(w, r, "Specify either schedule_interval_hours or cron_expression, not both")
 		return
 	}
-	if *req.ScheduleIntervalHours != 6 && *req.ScheduleIntervalHours != 12 &&
+	if req.ScheduleIntervalHours != nil && *req.ScheduleIntervalHours != 6 && *req.ScheduleIntervalHours != 12 &&
 		*req.ScheduleIntervalHours != 24 && *req.ScheduleIntervalHours != 48 {
 		BadRequest(w, r, "schedule_interval_hours must be 6, 12, 24, or 48")
 		return
@@ -188,24 +206,54 @@ func (h *Handler) createScheduler(w http.ResponseWriter, r *http.Request) {
 		isEnabled = *req.IsEnabled
 	}
 
+	keepWarm := req.KeepWarm != nil && *req.KeepWarm
+	deltaMode := req.DeltaMode != nil && *req.DeltaMode
+
+	fullCrawlEvery := defaultFullCrawlEvery
+	if req.FullCrawlEvery != nil {
+		fullCrawlEvery = *req.FullCrawlEvery
+		if fullCrawlEvery < 0 {
+			BadRequest(w, r, "full_crawl_every cannot be negative")
+			return
+		}
+	}
+
 	now := time.Now().UTC()
 	scheduler := &db.Scheduler{
-		ID:                    uuid.New().String(),
-		DomainID:              domainID,
-		OrganisationID:        orgID,
-		ScheduleIntervalHours: *req.ScheduleIntervalHours,
-		NextRunAt:             now.Add(time.Duration(*req.ScheduleIntervalHours) * time.Hour),
-		IsEnabled:             isEnabled,
-		Concurrency:           concurrency,
-		FindLinks:             findLinks,
-		MaxPages:              maxPages,
-		IncludePaths:          req.IncludePaths,
-		ExcludePaths:          req.ExcludePaths,
-		RequiredWorkers:       1,
-		CreatedAt:             now,
-		UpdatedAt:             now,
+		ID:              uuid.New().String(),
+		DomainID:        domainID,
+		OrganisationID:  orgID,
+		Timezone:        "UTC",
+		IsEnabled:       isEnabled,
+		Concurrency:     concurrency,
+		FindLinks:       findLinks,
+		MaxPages:        maxPages,
+		IncludePaths:    req.IncludePaths,
+		ExcludePaths:    req.ExcludePaths,
+		RequiredWorkers: 1,
+		KeepWarm:        keepWarm,
+		DeltaMode:       deltaMode,
+		FullCrawlEvery:  fullCrawlEvery,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if req.ScheduleIntervalHours != nil {
+		scheduler.ScheduleIntervalHours = *req.ScheduleIntervalHours
+	} else {
+		scheduler.CronExpression = strings.TrimSpace(*req.CronExpression)
+	}
+	if req.Timezone != nil && *req.Timezone != "" {
+		scheduler.Timezone = *req.Timezone
 	}
 
+	nextRun, err := validateSchedule(scheduler, now)
+	if err != nil {
+		BadRequest(w, r, err.Error())
+		return
+	}
+	scheduler.NextRunAt = nextRun
+
 	if err := h.DB.CreateScheduler(r.Context(), scheduler); err != nil {
 		logger.Error().Err(err).Str("domain", normalisedDomain).Msg("Failed to create scheduler")
 		InternalError(w, r, err)
@@ -338,14 +386,61 @@ func (h *Handler) updateScheduler(w http.ResponseWriter, r *http.Request, schedu
 		return
 	}
 
-	// Update fields if provided
+	// Update fields if provided. Setting an interval clears any cron expression
+	// and vice versa; the next run is recalculated when the schedule changes.
+	scheduleChanged := false
+	if req.ScheduleIntervalHours != nil && req.CronExpression != nil && *req.CronExpression != "" {
+		BadRequest(w, r, "Specify either schedule_interval_hours or cron_expression, not both")
+		return
+	}
+
 	if req.ScheduleIntervalHours != nil {
 		if *req.ScheduleIntervalHours != 6 && *req.ScheduleIntervalHours != 12 &&
 			*req.ScheduleIntervalHours != 24 && *req.ScheduleIntervalHours != 48 {
 			BadRequest(w, r, "schedule_interval_hours must be 6, 12, 24, or 48")
 			return
 		}
+		scheduleChanged = scheduleChanged || scheduler.ScheduleIntervalHours != *req.ScheduleIntervalHours
 		scheduler.ScheduleIntervalHours = *req.ScheduleIntervalHours
+		scheduler.CronExpression = ""
+	}
+
+	if req.CronExpression != nil && req.ScheduleIntervalHours == nil {
+		cronExpression := strings.TrimSpace(*req.CronExpression)
+		if cronExpression == "" {
+			BadRequest(w, r, "cron_expression cannot be empty; set schedule_interval_hours to switch to an interval")
+			return
+		}
+		scheduleChanged = scheduleChanged || scheduler.CronExpression != cronExpression
+		scheduler.CronExpression = cronExpression
+		scheduler.ScheduleIntervalHours = 0
+	}
+
+	timezoneChanged := false
+	if req.Timezone != nil && *req.Timezone != "" {
+		timezoneChanged = scheduler.Timezone != *req.Timezone
+		scheduler.Timezone = *req.Timezone
+	}
+
+	// Interval schedules run a fixed time after the last run, so only a cron
+	// schedule's next run depends on its timezone
+	if timezoneChanged && scheduler.CronExpression != "" {
+		scheduleChanged = true
+	}
+
+	if scheduleChanged {
+		scheduler.NextRunAt = time.Time{}
+		nextRun, err := validateSchedule(scheduler, time.Now().UTC())
+		if err != nil {
+			BadRequest(w, r, err.Error())
+			return
+		}
+		scheduler.NextRunAt = nextRun
+	} else if timezoneChanged {
+		if _, err := validateSchedule(scheduler, time.Now().UTC()); err != nil {
+			BadRequest(w, r, err.Error())
+			return
+		}
 	}
 
 	if req.Concurrency != nil {
@@ -383,6 +478,22 @@ func (h *Handler) updateScheduler(w http.ResponseWriter, r *http.Request, schedu
 		scheduler.IsEnabled = *req.IsEnabled
 	}
 
+	if req.KeepWarm != nil {
+		scheduler.KeepWarm = *req.KeepWarm
+	}
+
+	if req.DeltaMode != nil {
+		scheduler.DeltaMode = *req.DeltaMode
+	}
+
+	if req.FullCrawlEvery != nil {
+		if *req.FullCrawlEvery < 0 {
+			BadRequest(w, r, "full_crawl_every cannot be negative")
+			return
+		}
+		scheduler.FullCrawlEvery = *req.FullCrawlEvery
+	}
+
 	if err := h.DB.UpdateScheduler(r.Context(), schedulerID, scheduler, req.ExpectedIsEnabled); err != nil {
 		if errors.Is(err, db.ErrSchedulerNotFound) {
 			NotFound(w, r, "Scheduler not found")
@@ -565,6 +676,8 @@ func schedulerToResponse(scheduler *db.Scheduler, domainName string) SchedulerRe
 		ID:                    scheduler.ID,
 		Domain:                domainName,
 		ScheduleIntervalHours: scheduler.ScheduleIntervalHours,
+		CronExpression:        scheduler.CronExpression,
+		Timezone:              scheduler.Timezone,
 		NextRunAt:             scheduler.NextRunAt.Format(time.RFC3339),
 		IsEnabled:             scheduler.IsEnabled,
 		Concurrency:           scheduler.Concurrency,
@@ -572,7 +685,29 @@ func schedulerToResponse(scheduler *db.Scheduler, domainName string) SchedulerRe
 		MaxPages:              scheduler.MaxPages,
 		IncludePaths:          scheduler.IncludePaths,
 		ExcludePaths:          scheduler.ExcludePaths,
+		KeepWarm:              scheduler.KeepWarm,
+		DeltaMode:             scheduler.DeltaMode,
+		FullCrawlEvery:        scheduler.FullCrawlEvery,
 		CreatedAt:             scheduler.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:             scheduler.UpdatedAt.Format(time.RFC3339),
 	}
 }
+
+// validateSchedule checks the scheduler's cron expression and time zone and
+// returns its first run time after now
+func validateSchedule(scheduler *db.Scheduler, now time.Time) (time.Time, error) {
+	if _, err := scheduler.Location(); err != nil {
+		return time.Time{}, fmt.Errorf("timezone must be a valid IANA time zone (e.g. Australia/Melbourne)")
+	}
+
+	if scheduler.CronExpression != "" {
+		if _, err := util.ParseCron(scheduler.CronExpression); err != nil {
+			return time.Time{}, fmt.Errorf("cron_expression is invalid: %s", err.Error())
+		}
+		if err := scheduler.CheckCronFrequency(now, db.MinScheduleInterval); err != nil {
+			return time.Time{}, fmt.Errorf("cron_expression must not run more often than every %d hours", int(db.MinScheduleInterval.Hours()))
+		}
+	}
+
+	return scheduler.NextRunAfter(now)
+}
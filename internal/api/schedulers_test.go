@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Harvey-AU/adapt/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schedulerTestDB serves a single scheduler and records the last update
+type schedulerTestDB struct {
+	cdnTestDB
+	scheduler db.Scheduler
+	updated   *db.Scheduler
+}
+
+func (s *schedulerTestDB) GetScheduler(_ context.Context, schedulerID string) (*db.Scheduler, error) {
+	scheduler := s.scheduler
+	return &scheduler, nil
+}
+
+func (s *schedulerTestDB) UpdateScheduler(_ context.Context, schedulerID string, updates *db.Scheduler, expectedIsEnabled *bool) error {
+	s.updated = updates
+	return nil
+}
+
+func (s *schedulerTestDB) GetDomainNameByID(_ context.Context, domainID int) (string, error) {
+	return "example.com", nil
+}
+
+func TestUpdateSchedulerTimezoneNextRun(t *testing.T) {
+	nextRun := time.Now().UTC().Add(3 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name        string
+		scheduler   db.Scheduler
+		body        string
+		wantStatus  int
+		wantNextRun bool // Whether next_run_at is kept as it was
+	}{
+		{
+			name:        "interval schedule keeps its next run",
+			scheduler:   db.Scheduler{ScheduleIntervalHours: 24, Timezone: "UTC"},
+			body:        `{"timezone":"Australia/Melbourne"}`,
+			wantStatus:  http.StatusOK,
+			wantNextRun: true,
+		},
+		{
+			name:       "cron schedule is recalculated",
+			scheduler:  db.Scheduler{CronExpression: "0 3 * * *", Timezone: "UTC"},
+			body:       `{"timezone":"Australia/Melbourne"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "unchanged timezone keeps the next run",
+			scheduler:   db.Scheduler{CronExpression: "0 3 * * *", Timezone: "Australia/Melbourne"},
+			body:        `{"timezone":"Australia/Melbourne","find_links":false}`,
+			wantStatus:  http.StatusOK,
+			wantNextRun: true,
+		},
+		{
+			name:       "invalid timezone on an interval schedule",
+			scheduler:  db.Scheduler{ScheduleIntervalHours: 24, Timezone: "UTC"},
+			body:       `{"timezone":"Mars/Olympus"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.scheduler.ID = "sched-1"
+			tt.scheduler.OrganisationID = "org-1"
+			tt.scheduler.NextRunAt = nextRun
+			stub := &schedulerTestDB{scheduler: tt.scheduler}
+			h := &Handler{DB: stub}
+
+			rec := httptest.NewRecorder()
+			h.updateScheduler(rec, cdnRequest(http.MethodPut, "/v1/schedulers/sched-1", tt.body), "sched-1")
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				assert.Nil(t, stub.updated)
+				return
+			}
+			require.NotNil(t, stub.updated)
+			if tt.wantNextRun {
+				assert.Equal(t, nextRun, stub.updated.NextRunAt)
+			} else {
+				assert.NotEqual(t, nextRun, stub.updated.NextRunAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Harvey-AU/adapt/internal/crawler"
+)
+
+// maxSecurityReportPages caps the per-page list in a security report response
+const maxSecurityReportPages = 500
+
+// SecurityReportSummary aggregates security header grades across a set of pages
+type SecurityReportSummary struct {
+	Pages        int                       `json:"pages"`
+	AverageScore int                       `json:"average_score"`
+	Grade        string                    `json:"grade"`   // Grade of the average score
+	Grades       map[string]int            `json:"grades"`  // Pages per grade
+	Headers      map[string]map[string]int `json:"headers"` // Header -> status -> pages
+
+	totalScore int
+}
+
+// SecurityReportHost is the security header summary for one host
+type SecurityReportHost struct {
+	Host string `json:"host"`
+	*SecurityReportSummary
+}
+
+// SecurityReportCertificate is the TLS state captured for a host
+type SecurityReportCertificate struct {
+	Host          string    `json:"host"`
+	TLSVersion    string    `json:"tls_version"`
+	CipherSuite   string    `json:"cipher_suite"`
+	Subject       string    `json:"subject"`
+	Issuer        string    `json:"issuer"`
+	DNSNames      []string  `json:"dns_names"`
+	NotAfter      time.Time `json:"not_after"`
+	DaysRemaining int       `json:"days_remaining"`
+}
+
+// SecurityReportPage is the security header grade of a single page
+type SecurityReportPage struct {
+	Host string `json:"host"`
+	Path string `json:"path"`
+	crawler.SecurityHeaderGrade
+}
+
+func newSecurityReportSummary() *SecurityReportSummary {
+	return &SecurityReportSummary{Grades: map[string]int{}, Headers: map[string]map[string]int{}}
+}
+
+func (s *SecurityReportSummary) add(grade crawler.SecurityHeaderGrade) {
+	s.Pages++
+	s.totalScore += grade.Score
+	s.Grades[grade.Grade]++
+	for _, check := range grade.Checks {
+		if s.Headers[check.Header] == nil {
+			s.Headers[check.Header] = map[string]int{}
+		}
+		s.Headers[check.Header][check.Status]++
+	}
+}
+
+func (s *SecurityReportSummary) finish() {
+	if s.Pages > 0 {
+		s.AverageScore = s.totalScore / s.Pages
+	}
+	s.Grade = crawler.SecurityGradeForScore(s.AverageScore)
+}
+
+// hasSecurityIssues reports whether any of a page's checks did not pass
+func hasSecurityIssues(grade crawler.SecurityHeaderGrade) bool {
+	for _, check := range grade.Checks {
+		if check.Status != crawler.CheckPass {
+			return true
+		}
+	}
+	return false
+}
+
+// getJobSecurityReport handles GET /v1/jobs/:id/security-report. It grades
+// the security headers stored for every completed page, per host and for the
+// whole job, alongside the TLS certificate captured for each host.
+func (h *Handler) getJobSecurityReport(w http.ResponseWriter, r *http.Request, jobID string) {
+	logger := loggerWithRequest(r)
+
+	user := h.validateJobAccess(w, r, jobID)
+	if user == nil {
+		return // validateJobAccess already wrote the error response
+	}
+
+	pagesFilter := r.URL.Query().Get("pages")
+	switch pagesFilter {
+	case "":
+		pagesFilter = "issues"
+	case "issues", "all", "none":
+	default:
+		BadRequest(w, r, "pages must be one of: issues, all, none")
+		return
+	}
+
+	hostTLS, err := h.DB.ListJobHostTLS(r.Context(), jobID)
+	if err != nil {
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to get host TLS for security report")
+		DatabaseError(w, r, err)
+		return
+	}
+
+	now := time.Now()
+	certificates := make([]SecurityReportCertificate, 0, len(hostTLS))
+	for _, info := range hostTLS {
+		leaf := crawler.CertificateInfo{NotAfter: info.NotAfter}
+		certificates = append(certificates, SecurityReportCertificate{
+			Host:          info.Host,
+			TLSVersion:    info.Version,
+			CipherSuite:   info.CipherSuite,
+			Subject:       info.Subject,
+			Issuer:        info.Issuer,
+			DNSNames:      info.DNSNames,
+			NotAfter:      info.NotAfter,
+			DaysRemaining: leaf.ExpiresInDays(now),
+		})
+	}
+
+	rows, err := h.DB.GetDB().QueryContext(r.Context(), `
+		SELECT p.host, p.path, t.headers
+		FROM tasks t
+		JOIN pages p ON t.page_id = p.id
+		WHERE t.job_id = $1
+		  AND t.status = 'completed'
+		  AND t.status_code BETWEEN 200 AND 299
+		  AND COALESCE(t.source_type, '') <> 'asset'
+		ORDER BY p.host, p.path
+	`, jobID)
+	if err != nil {
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to get tasks for security report")
+		DatabaseError(w, r, err)
+		return
+	}
+	defer rows.Close()
+
+	summary := newSecurityReportSummary()
+	hosts := make(map[string]*SecurityReportSummary)
+	pages := make([]SecurityReportPage, 0)
+	truncated := false
+
+	for rows.Next() {
+		var page SecurityReportPage
+		var rawHeaders []byte
+		if err := rows.Scan(&page.Host, &page.Path, &rawHeaders); err != nil {
+			logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to scan task for security report")
+			DatabaseError(w, r, err)
+			return
+		}
+
+		headers := http.Header{}
+		if len(rawHeaders) > 0 {
+			if err := json.Unmarshal(rawHeaders, &headers); err != nil {
+				headers = http.Header{}
+			}
+		}
+
+		page.SecurityHeaderGrade = crawler.GradeSecurityHeaders(headers)
+		summary.add(page.SecurityHeaderGrade)
+		if hosts[page.Host] == nil {
+			hosts[page.Host] = newSecurityReportSummary()
+		}
+		hosts[page.Host].add(page.SecurityHeaderGrade)
+
+		if pagesFilter == "all" || (pagesFilter == "issues" && hasSecurityIssues(page.SecurityHeaderGrade)) {
+			if len(pages) < maxSecurityReportPages {
+				pages = append(pages, page)
+			} else {
+				truncated = true
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to iterate tasks for security report")
+		DatabaseError(w, r, err)
+		return
+	}
+
+	summary.finish()
+	hostList := make([]SecurityReportHost, 0, len(hosts))
+	for host, hostSummary := range hosts {
+		hostSummary.finish()
+		hostList = append(hostList, SecurityReportHost{Host: host, SecurityReportSummary: hostSummary})
+	}
+	// Lowest scoring hosts first
+	sort.Slice(hostList, func(i, j int) bool {
+		if hostList[i].AverageScore != hostList[j].AverageScore {
+			return hostList[i].AverageScore < hostList[j].AverageScore
+		}
+		return hostList[i].Host < hostList[j].Host
+	})
+
+	response := map[string]any{
+		"job_id":       jobID,
+		"summary":      summary,
+		"hosts":        hostList,
+		"certificates": certificates,
+	}
+	if pagesFilter != "none" {
+		response["pages"] = pages
+		response["pages_truncated"] = truncated
+	}
+
+	WriteSuccess(w, r, response, "Security report generated successfully")
+}
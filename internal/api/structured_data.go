@@ -0,0 +1,209 @@
+package api
+
+import (
+	"cmp"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/Harvey-AU/adapt/internal/crawler"
+	"github.com/Harvey-AU/adapt/internal/util"
+)
+
+// maxStructuredDataPages caps the per-page list in a structured data summary
+const maxStructuredDataPages = 500
+
+// StructuredDataTypeCount counts the items of one schema.org type
+type StructuredDataTypeCount struct {
+	Type  string `json:"type"`
+	Items int    `json:"items"`
+	Pages int    `json:"pages"`
+}
+
+// StructuredDataIssueCount counts one kind of structured data issue
+type StructuredDataIssueCount struct {
+	Code     string `json:"code"`
+	Type     string `json:"type,omitempty"`
+	Property string `json:"property,omitempty"`
+	Count    int    `json:"count"`
+}
+
+// StructuredDataPage lists a page's structured data issues
+type StructuredDataPage struct {
+	URL    string                        `json:"url"`
+	Issues []crawler.StructuredDataIssue `json:"issues"`
+}
+
+// StructuredDataSummary aggregates the structured data found across a job
+type StructuredDataSummary struct {
+	PagesChecked            int                        `json:"pages_checked"`
+	PagesWithStructuredData int                        `json:"pages_with_structured_data"`
+	PagesWithIssues         int                        `json:"pages_with_issues"`
+	Types                   []StructuredDataTypeCount  `json:"types"`
+	Issues                  []StructuredDataIssueCount `json:"issues"`
+}
+
+// structuredDataPage is the stored crawl data the summary is built from
+type structuredDataPage struct {
+	URL  string
+	Data crawler.StructuredData
+}
+
+// buildStructuredDataSummary counts item types and issues across pages and
+// lists the pages with issues
+func buildStructuredDataSummary(pages []structuredDataPage) (StructuredDataSummary, []StructuredDataPage) {
+	summary := StructuredDataSummary{}
+	typeCounts := make(map[string]*StructuredDataTypeCount)
+	issueCounts := make(map[StructuredDataIssueCount]int)
+	issuePages := make([]StructuredDataPage, 0)
+
+	for _, page := range pages {
+		summary.PagesChecked++
+		if len(page.Data.Items) > 0 {
+			summary.PagesWithStructuredData++
+		}
+
+		seen := make(map[string]bool)
+		for _, item := range page.Data.Items {
+			count, ok := typeCounts[item.Type]
+			if !ok {
+				count = &StructuredDataTypeCount{Type: item.Type}
+				typeCounts[item.Type] = count
+			}
+			count.Items++
+			if !seen[item.Type] {
+				seen[item.Type] = true
+				count.Pages++
+			}
+		}
+
+		if len(page.Data.Issues) == 0 {
+			continue
+		}
+		summary.PagesWithIssues++
+		for _, issue := range page.Data.Issues {
+			issueCounts[StructuredDataIssueCount{Code: issue.Code, Type: issue.Type, Property: issue.Property}]++
+		}
+		issuePages = append(issuePages, StructuredDataPage{URL: page.URL, Issues: page.Data.Issues})
+	}
+
+	summary.Types = make([]StructuredDataTypeCount, 0, len(typeCounts))
+	for _, count := range typeCounts {
+		summary.Types = append(summary.Types, *count)
+	}
+	slices.SortFunc(summary.Types, func(a, b StructuredDataTypeCount) int {
+		return cmp.Or(cmp.Compare(b.Items, a.Items), cmp.Compare(a.Type, b.Type))
+	})
+
+	summary.Issues = make([]StructuredDataIssueCount, 0, len(issueCounts))
+	for key, count := range issueCounts {
+		key.Count = count
+		summary.Issues = append(summary.Issues, key)
+	}
+	slices.SortFunc(summary.Issues, func(a, b StructuredDataIssueCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Code, b.Code),
+			cmp.Compare(a.Type, b.Type), cmp.Compare(a.Property, b.Property))
+	})
+
+	return summary, issuePages
+}
+
+// getDomainStructuredData handles GET /v1/domains/:domain/structured-data. It
+// summarises the JSON-LD and microdata found by the organisation's most recent
+// completed job for the domain.
+func (h *Handler) getDomainStructuredData(w http.ResponseWriter, r *http.Request, domain string) {
+	logger := loggerWithRequest(r)
+
+	orgID := h.GetActiveOrganisation(w, r)
+	if orgID == "" {
+		return
+	}
+
+	domain = util.NormaliseDomain(domain)
+	if err := util.ValidateDomain(domain); err != nil {
+		BadRequest(w, r, err.Error())
+		return
+	}
+
+	var jobID string
+	err := h.DB.GetDB().QueryRowContext(r.Context(), `
+		SELECT j.id
+		FROM jobs j
+		JOIN domains d ON j.domain_id = d.id
+		WHERE j.organisation_id = $1
+		  AND d.name = $2
+		  AND j.status = 'completed'
+		ORDER BY j.completed_at DESC NULLS LAST
+		LIMIT 1
+	`, orgID, domain).Scan(&jobID)
+	if errors.Is(err, sql.ErrNoRows) {
+		NotFound(w, r, "No completed job found for this domain")
+		return
+	}
+	if err != nil {
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		logger.Error().Err(err).Str("domain", domain).Msg("Failed to find job for structured data summary")
+		DatabaseError(w, r, err)
+		return
+	}
+
+	rows, err := h.DB.GetDB().QueryContext(r.Context(), `
+		SELECT p.host, p.path, t.structured_data
+		FROM tasks t
+		JOIN pages p ON t.page_id = p.id
+		WHERE t.job_id = $1
+		  AND t.status = 'completed'
+		  AND t.structured_data IS NOT NULL
+		ORDER BY p.path
+	`, jobID)
+	if err != nil {
+		if HandlePoolSaturation(w, r, err) {
+			return
+		}
+		logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to get tasks for structured data summary")
+		DatabaseError(w, r, err)
+		return
+	}
+	defer rows.Close()
+
+	pages := make([]structuredDataPage, 0)
+	for rows.Next() {
+		var page structuredDataPage
+		var host, path string
+		var raw []byte
+		if err := rows.Scan(&host, &path, &raw); err != nil {
+			logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to scan task for structured data summary")
+			DatabaseError(w, r, err)
+			return
+		}
+		page.URL = util.ConstructURL(host, path)
+		if err := json.Unmarshal(raw, &page.Data); err != nil {
+			continue // Skip unreadable rows rather than failing the summary
+		}
+		pages = append(pages, page)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error().Err(err).Str("job_id", jobID).Msg("Failed to iterate tasks for structured data summary")
+		DatabaseError(w, r, err)
+		return
+	}
+
+	summary, issuePages := buildStructuredDataSummary(pages)
+
+	truncated := len(issuePages) > maxStructuredDataPages
+	if truncated {
+		issuePages = issuePages[:maxStructuredDataPages]
+	}
+
+	WriteSuccess(w, r, map[string]any{
+		"domain":          domain,
+		"job_id":          jobID,
+		"summary":         summary,
+		"pages":           issuePages,
+		"pages_truncated": truncated,
+	}, "Structured data summary generated successfully")
+}
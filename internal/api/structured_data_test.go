@@ -0,0 +1,50 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/Harvey-AU/adapt/internal/crawler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildStructuredDataSummary(t *testing.T) {
+	missingOffers := crawler.StructuredDataIssue{Format: crawler.FormatJSONLD, Type: "Product", Code: crawler.SchemaIssueMissingProperty, Property: "offers or review or aggregateRating"}
+	invalidJSON := crawler.StructuredDataIssue{Format: crawler.FormatJSONLD, Code: crawler.SchemaIssueInvalidJSON, Detail: "block 1: unexpected end of JSON input"}
+
+	pages := []structuredDataPage{
+		{URL: "https://example.com/", Data: crawler.StructuredData{Items: []crawler.StructuredDataItem{
+			{Format: crawler.FormatJSONLD, Type: "Organization"},
+			{Format: crawler.FormatJSONLD, Type: "BreadcrumbList"},
+		}}},
+		{URL: "https://example.com/a", Data: crawler.StructuredData{
+			Items: []crawler.StructuredDataItem{
+				{Format: crawler.FormatJSONLD, Type: "Product"},
+				{Format: crawler.FormatMicrodata, Type: "Product"},
+				{Format: crawler.FormatJSONLD, Type: "BreadcrumbList"},
+			},
+			Issues: []crawler.StructuredDataIssue{missingOffers, missingOffers},
+		}},
+		{URL: "https://example.com/b", Data: crawler.StructuredData{Issues: []crawler.StructuredDataIssue{invalidJSON}}},
+		{URL: "https://example.com/c"},
+	}
+
+	summary, issuePages := buildStructuredDataSummary(pages)
+
+	assert.Equal(t, 4, summary.PagesChecked)
+	assert.Equal(t, 2, summary.PagesWithStructuredData)
+	assert.Equal(t, 2, summary.PagesWithIssues)
+	assert.Equal(t, []StructuredDataTypeCount{
+		{Type: "BreadcrumbList", Items: 2, Pages: 2},
+		{Type: "Product", Items: 2, Pages: 1},
+		{Type: "Organization", Items: 1, Pages: 1},
+	}, summary.Types)
+	assert.Equal(t, []StructuredDataIssueCount{
+		{Code: crawler.SchemaIssueMissingProperty, Type: "Product", Property: "offers or review or aggregateRating", Count: 2},
+		{Code: crawler.SchemaIssueInvalidJSON, Count: 1},
+	}, summary.Issues)
+
+	require.Len(t, issuePages, 2)
+	assert.Equal(t, "https://example.com/a", issuePages[0].URL)
+	assert.Equal(t, []crawler.StructuredDataIssue{invalidJSON}, issuePages[1].Issues)
+}
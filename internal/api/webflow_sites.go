@@ -368,7 +368,9 @@ func (h *Handler) updateSiteSchedule(w http.ResponseWriter, r *http.Request, sit
 
 		if existingScheduler != nil {
 			// Update existing scheduler
+			// Webflow schedules are interval-based, replacing any cron expression
 			existingScheduler.ScheduleIntervalHours = *req.ScheduleIntervalHours
+			existingScheduler.CronExpression = ""
 			existingScheduler.NextRunAt = time.Now().Add(time.Duration(*req.ScheduleIntervalHours) * time.Hour)
 			if err := h.DB.UpdateScheduler(ctx, existingScheduler.ID, existingScheduler, nil); err != nil {
 				logger.Error().Err(err).Str("scheduler_id", existingScheduler.ID).Msg("Failed to update scheduler")
@@ -483,7 +485,7 @@ func (h *Handler) updateSiteSchedule(w http.ResponseWriter, r *http.Request, sit
 				SourceInfo:   &sourceInfo,
 			}
 
-			_, err = h.createJobFromRequest(ctx, user, jobReq, loggerWithRequest(r))
+			_, err = h.createJobFromRequest(ctx, user, jobReq, nil, loggerWithRequest(r))
 			if err != nil {
 				logger.Warn().Err(err).Msg("Failed to create immediate job, user can trigger manually")
 				// Don't fail the entire request - scheduler is configured successfully
@@ -665,7 +667,7 @@ func (h *Handler) toggleSiteAutoPublish(w http.ResponseWriter, r *http.Request,
 				SourceInfo:   &sourceInfo,
 			}
 
-			_, err = h.createJobFromRequest(ctx, user, jobReq, loggerWithRequest(r))
+			_, err = h.createJobFromRequest(ctx, user, jobReq, nil, loggerWithRequest(r))
 			if err != nil {
 				logger.Warn().Err(err).Msg("Failed to create immediate job, user can trigger manually")
 				// Don't fail the entire request - webhook is registered successfully
@@ -0,0 +1,155 @@
+// Package cdn purges cached content from CDN providers so warming jobs fetch
+// fresh copies rather than re-confirming stale ones.
+package cdn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// Supported providers
+const (
+	ProviderCloudflare = "cloudflare"
+	ProviderFastly     = "fastly"
+)
+
+// Purge scopes
+const (
+	ScopeURLs       = "urls"
+	ScopeTags       = "tags"
+	ScopeEverything = "everything"
+)
+
+// MaxPurgeItems caps the URLs or tags accepted in a single purge request
+const MaxPurgeItems = 500
+
+// ErrUnsupportedProvider is returned for providers without a purge client
+var ErrUnsupportedProvider = errors.New("unsupported CDN provider")
+
+// ErrURLOutsideDomain is returned when a URL to purge is not on the
+// connection's domain or one of its subdomains
+var ErrURLOutsideDomain = errors.New("URL is outside the domain")
+
+// PurgeRequest describes what to purge. URLs and Tags are only used with
+// their matching scope.
+type PurgeRequest struct {
+	Scope string   `json:"scope"`
+	URLs  []string `json:"urls,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// Validate checks the scope and that the matching list is set
+func (p *PurgeRequest) Validate() error {
+	if p == nil {
+		return nil
+	}
+	var items []string
+	switch p.Scope {
+	case ScopeURLs:
+		items = p.URLs
+	case ScopeTags:
+		items = p.Tags
+	case ScopeEverything:
+		return nil
+	default:
+		return fmt.Errorf("scope must be one of: %s, %s, %s", ScopeURLs, ScopeTags, ScopeEverything)
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("at least one entry is required for scope %q", p.Scope)
+	}
+	if len(items) > MaxPurgeItems {
+		return fmt.Errorf("too many entries (%d); the maximum is %d", len(items), MaxPurgeItems)
+	}
+	for _, item := range items {
+		if strings.TrimSpace(item) == "" {
+			return fmt.Errorf("entries must not be empty")
+		}
+		if p.Scope == ScopeURLs && !strings.HasPrefix(item, "http://") && !strings.HasPrefix(item, "https://") {
+			return fmt.Errorf("invalid URL %q: must be absolute", item)
+		}
+	}
+	return nil
+}
+
+// CheckDomain checks that every URL to purge is on domain or one of its
+// subdomains, so a domain's CDN credentials cannot purge other hosts
+func (p *PurgeRequest) CheckDomain(domain string) error {
+	if p == nil || p.Scope != ScopeURLs {
+		return nil
+	}
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	for _, item := range p.URLs {
+		parsed, err := url.Parse(strings.TrimSpace(item))
+		if err != nil {
+			return fmt.Errorf("invalid URL %q: %w", item, err)
+		}
+		host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+		if host == "" || (host != domain && !strings.HasSuffix(host, "."+domain)) {
+			return fmt.Errorf("%w %s: %s", ErrURLOutsideDomain, domain, item)
+		}
+	}
+	return nil
+}
+
+// PurgeResult is the provider's confirmation of a purge
+type PurgeResult struct {
+	Provider string   `json:"provider"`
+	Scope    string   `json:"scope"`
+	Purged   int      `json:"purged"`             // URLs or tags purged; 0 for everything
+	IDs      []string `json:"ids,omitempty"`      // Provider purge request IDs
+	Duration int64    `json:"duration_ms"`        // Time taken to confirm the purge
+	Warnings []string `json:"warnings,omitempty"` // Non-fatal provider messages
+}
+
+// Provider purges content from a CDN. Purge returns only once the provider
+// has accepted every purge call, so warming can start straight after.
+type Provider interface {
+	Name() string
+	Purge(ctx context.Context, req PurgeRequest) (*PurgeResult, error)
+}
+
+// New creates a purge client for a provider. zoneID is the Cloudflare zone
+// ID or the Fastly service ID. baseURL overrides the provider's API address
+// and is empty in production.
+func New(provider, zoneID, token, baseURL string) (Provider, error) {
+	client := &http.Client{Timeout: defaultTimeout}
+	switch provider {
+	case ProviderCloudflare:
+		if baseURL == "" {
+			baseURL = cloudflareBaseURL
+		}
+		return &Cloudflare{baseURL: strings.TrimSuffix(baseURL, "/"), zoneID: zoneID, token: token, httpClient: client}, nil
+	case ProviderFastly:
+		if baseURL == "" {
+			baseURL = fastlyBaseURL
+		}
+		return &Fastly{baseURL: strings.TrimSuffix(baseURL, "/"), serviceID: zoneID, token: token, httpClient: client}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedProvider, provider)
+	}
+}
+
+// SupportedProvider reports whether provider has a purge client
+func SupportedProvider(provider string) bool {
+	return provider == ProviderCloudflare || provider == ProviderFastly
+}
+
+// batches splits items into chunks of at most size
+func batches(items []string, size int) [][]string {
+	var chunks [][]string
+	for len(items) > size {
+		chunks = append(chunks, items[:size])
+		items = items[size:]
+	}
+	if len(items) > 0 {
+		chunks = append(chunks, items)
+	}
+	return chunks
+}
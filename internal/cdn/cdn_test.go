@@ -0,0 +1,181 @@
+package cdn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *PurgeRequest
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"everything", &PurgeRequest{Scope: ScopeEverything}, false},
+		{"urls", &PurgeRequest{Scope: ScopeURLs, URLs: []string{"https://example.com/"}}, false},
+		{"tags", &PurgeRequest{Scope: ScopeTags, Tags: []string{"blog"}}, false},
+		{"unknown scope", &PurgeRequest{Scope: "site"}, true},
+		{"no urls", &PurgeRequest{Scope: ScopeURLs}, true},
+		{"relative url", &PurgeRequest{Scope: ScopeURLs, URLs: []string{"/about"}}, true},
+		{"empty tag", &PurgeRequest{Scope: ScopeTags, Tags: []string{" "}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPurgeRequestCheckDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *PurgeRequest
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"everything", &PurgeRequest{Scope: ScopeEverything}, false},
+		{"domain", &PurgeRequest{Scope: ScopeURLs, URLs: []string{"https://example.com/about"}}, false},
+		{"subdomain", &PurgeRequest{Scope: ScopeURLs, URLs: []string{"https://www.Example.com/", "http://shop.example.com:8080/cart"}}, false},
+		{"other host", &PurgeRequest{Scope: ScopeURLs, URLs: []string{"https://example.com/", "https://other.org/"}}, true},
+		{"suffix trick", &PurgeRequest{Scope: ScopeURLs, URLs: []string{"https://notexample.com/"}}, true},
+		{"domain as subdomain", &PurgeRequest{Scope: ScopeURLs, URLs: []string{"https://example.com.evil.test/"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.CheckDomain("example.com")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrURLOutsideDomain)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewUnsupportedProvider(t *testing.T) {
+	_, err := New("akamai", "zone", "token", "")
+	assert.ErrorIs(t, err, ErrUnsupportedProvider)
+}
+
+func TestCloudflarePurgeURLsInBatches(t *testing.T) {
+	var mu sync.Mutex
+	var batchSizes []int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/zones/zone-1/purge_cache", r.URL.Path)
+		assert.Equal(t, "Bearer cf-token", r.Header.Get("Authorization"))
+
+		var body struct {
+			Files []string `json:"files"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		mu.Lock()
+		batchSizes = append(batchSizes, len(body.Files))
+		id := fmt.Sprintf("purge-%d", len(batchSizes))
+		mu.Unlock()
+
+		fmt.Fprintf(w, `{"success":true,"errors":[],"messages":[],"result":{"id":%q}}`, id)
+	}))
+	defer server.Close()
+
+	provider, err := New(ProviderCloudflare, "zone-1", "cf-token", server.URL)
+	require.NoError(t, err)
+
+	urls := make([]string, 45)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://example.com/page-%d", i)
+	}
+
+	result, err := provider.Purge(context.Background(), PurgeRequest{Scope: ScopeURLs, URLs: urls})
+	require.NoError(t, err)
+	assert.Equal(t, []int{30, 15}, batchSizes)
+	assert.Equal(t, 45, result.Purged)
+	assert.Equal(t, []string{"purge-1", "purge-2"}, result.IDs)
+}
+
+func TestCloudflarePurgeFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":10000,"message":"Authentication error"}]}`)
+	}))
+	defer server.Close()
+
+	provider, err := New(ProviderCloudflare, "zone-1", "bad-token", server.URL)
+	require.NoError(t, err)
+
+	_, err = provider.Purge(context.Background(), PurgeRequest{Scope: ScopeEverything})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Authentication error")
+}
+
+func TestFastlyPurge(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	var surrogateKeys string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "fastly-token", r.Header.Get("Fastly-Key"))
+
+		mu.Lock()
+		calls = append(calls, r.URL.Path)
+		if key := r.Header.Get("Surrogate-Key"); key != "" {
+			surrogateKeys = key
+		}
+		mu.Unlock()
+
+		if r.URL.Path == "/service/svc-1/purge" {
+			fmt.Fprint(w, `{"blog":"108-1391560174-974124","news":"108-1391560174-974125"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"ok","id":"108-1391560174-974123"}`)
+	}))
+	defer server.Close()
+
+	provider, err := New(ProviderFastly, "svc-1", "fastly-token", server.URL)
+	require.NoError(t, err)
+
+	result, err := provider.Purge(context.Background(), PurgeRequest{Scope: ScopeURLs, URLs: []string{"https://example.com/blog/post?page=2"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"108-1391560174-974123"}, result.IDs)
+
+	_, err = provider.Purge(context.Background(), PurgeRequest{Scope: ScopeTags, Tags: []string{"blog", "news"}})
+	require.NoError(t, err)
+	assert.Equal(t, "blog news", surrogateKeys)
+
+	_, err = provider.Purge(context.Background(), PurgeRequest{Scope: ScopeEverything})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/purge/example.com/blog/post", "/service/svc-1/purge", "/service/svc-1/purge_all"}, calls)
+}
+
+func TestFastlyPurgeFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"msg":"Provided credentials are missing or invalid"}`)
+	}))
+	defer server.Close()
+
+	provider, err := New(ProviderFastly, "svc-1", "bad-token", server.URL)
+	require.NoError(t, err)
+
+	_, err = provider.Purge(context.Background(), PurgeRequest{Scope: ScopeEverything})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "credentials are missing")
+}
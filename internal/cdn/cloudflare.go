@@ -0,0 +1,126 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	cloudflareBaseURL = "https://api.cloudflare.com/client/v4"
+
+	// cloudflarePurgeBatch is the most files or tags Cloudflare accepts per
+	// purge call on every plan
+	cloudflarePurgeBatch = 30
+)
+
+// Cloudflare purges a zone's cache through the Cloudflare API.
+// See https://developers.cloudflare.com/api/resources/cache/methods/purge/
+type Cloudflare struct {
+	baseURL    string
+	zoneID     string
+	token      string
+	httpClient *http.Client
+}
+
+type cloudflareResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Messages []struct {
+		Message string `json:"message"`
+	} `json:"messages"`
+	Result struct {
+		ID string `json:"id"`
+	} `json:"result"`
+}
+
+// Name implements Provider
+func (c *Cloudflare) Name() string {
+	return ProviderCloudflare
+}
+
+// Purge implements Provider. URLs and tags are purged in batches of 30.
+func (c *Cloudflare) Purge(ctx context.Context, req PurgeRequest) (*PurgeResult, error) {
+	start := time.Now()
+	result := &PurgeResult{Provider: ProviderCloudflare, Scope: req.Scope}
+
+	var bodies []map[string]any
+	switch req.Scope {
+	case ScopeEverything:
+		bodies = append(bodies, map[string]any{"purge_everything": true})
+	case ScopeURLs:
+		for _, batch := range batches(req.URLs, cloudflarePurgeBatch) {
+			bodies = append(bodies, map[string]any{"files": batch})
+		}
+		result.Purged = len(req.URLs)
+	case ScopeTags:
+		for _, batch := range batches(req.Tags, cloudflarePurgeBatch) {
+			bodies = append(bodies, map[string]any{"tags": batch})
+		}
+		result.Purged = len(req.Tags)
+	default:
+		return nil, fmt.Errorf("cloudflare: unsupported purge scope %q", req.Scope)
+	}
+
+	for _, body := range bodies {
+		resp, err := c.purge(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Result.ID != "" {
+			result.IDs = append(result.IDs, resp.Result.ID)
+		}
+		for _, message := range resp.Messages {
+			result.Warnings = append(result.Warnings, message.Message)
+		}
+	}
+
+	result.Duration = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+func (c *Cloudflare) purge(ctx context.Context, body map[string]any) (*cloudflareResponse, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("cloudflare: failed to marshal request: %w", err)
+	}
+
+	reqURL := fmt.Sprintf("%s/zones/%s/purge_cache", c.baseURL, c.zoneID)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("cloudflare: failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("cloudflare: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	var parsed cloudflareResponse
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("cloudflare: unexpected response (status %d): %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	if resp.StatusCode >= 300 || !parsed.Success {
+		messages := make([]string, 0, len(parsed.Errors))
+		for _, e := range parsed.Errors {
+			messages = append(messages, fmt.Sprintf("%d: %s", e.Code, e.Message))
+		}
+		return nil, fmt.Errorf("cloudflare: purge failed (status %d): %s", resp.StatusCode, strings.Join(messages, "; "))
+	}
+
+	return &parsed, nil
+}
@@ -0,0 +1,124 @@
+package cdn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	fastlyBaseURL = "https://api.fastly.com"
+
+	// fastlyPurgeBatch is the most surrogate keys Fastly accepts per call
+	fastlyPurgeBatch = 256
+)
+
+// Fastly purges a service's cache through the Fastly API.
+// See https://www.fastly.com/documentation/reference/api/purging/
+type Fastly struct {
+	baseURL    string
+	serviceID  string
+	token      string
+	httpClient *http.Client
+}
+
+// fastlyResponse covers single purges ({"status":"ok","id":...}) and errors
+type fastlyResponse struct {
+	Status string `json:"status"`
+	ID     string `json:"id"`
+	Msg    string `json:"msg"`
+	Detail string `json:"detail"`
+}
+
+// Name implements Provider
+func (f *Fastly) Name() string {
+	return ProviderFastly
+}
+
+// Purge implements Provider. URLs are purged one at a time; surrogate keys
+// (tags) in batches of 256.
+func (f *Fastly) Purge(ctx context.Context, req PurgeRequest) (*PurgeResult, error) {
+	start := time.Now()
+	result := &PurgeResult{Provider: ProviderFastly, Scope: req.Scope}
+
+	switch req.Scope {
+	case ScopeEverything:
+		if _, err := f.do(ctx, fmt.Sprintf("%s/service/%s/purge_all", f.baseURL, f.serviceID), nil); err != nil {
+			return nil, err
+		}
+	case ScopeURLs:
+		for _, rawURL := range req.URLs {
+			u, err := url.Parse(rawURL)
+			if err != nil || u.Host == "" {
+				return nil, fmt.Errorf("fastly: invalid URL %q", rawURL)
+			}
+			// Single-URL purges address the cached object by host and path
+			id, err := f.do(ctx, fmt.Sprintf("%s/purge/%s%s", f.baseURL, u.Host, u.RequestURI()), nil)
+			if err != nil {
+				return nil, err
+			}
+			if id != "" {
+				result.IDs = append(result.IDs, id)
+			}
+		}
+		result.Purged = len(req.URLs)
+	case ScopeTags:
+		for _, batch := range batches(req.Tags, fastlyPurgeBatch) {
+			headers := http.Header{"Surrogate-Key": []string{strings.Join(batch, " ")}}
+			if _, err := f.do(ctx, fmt.Sprintf("%s/service/%s/purge", f.baseURL, f.serviceID), headers); err != nil {
+				return nil, err
+			}
+		}
+		result.Purged = len(req.Tags)
+	default:
+		return nil, fmt.Errorf("fastly: unsupported purge scope %q", req.Scope)
+	}
+
+	result.Duration = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// do sends a purge call and returns the purge ID, if the response has one
+func (f *Fastly) do(ctx context.Context, reqURL string, headers http.Header) (string, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("fastly: failed to create request: %w", err)
+	}
+	for name, values := range headers {
+		for _, value := range values {
+			httpReq.Header.Add(name, value)
+		}
+	}
+	httpReq.Header.Set("Fastly-Key", f.token)
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := f.httpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("fastly: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode >= 300 {
+		var parsed fastlyResponse
+		message := strings.TrimSpace(string(raw))
+		if json.Unmarshal(raw, &parsed) == nil && parsed.Msg != "" {
+			message = strings.TrimSpace(parsed.Msg + " " + parsed.Detail)
+		}
+		return "", fmt.Errorf("fastly: purge failed (status %d): %s", resp.StatusCode, message)
+	}
+
+	// Surrogate key batches return a map of key to purge ID; only single
+	// purges carry a top-level ID
+	var parsed fastlyResponse
+	if err := json.Unmarshal(raw, &parsed); err == nil && parsed.Status != "" && parsed.Status != "ok" {
+		return "", fmt.Errorf("fastly: purge not confirmed: status %q", parsed.Status)
+	}
+	return parsed.ID, nil
+}
@@ -0,0 +1,53 @@
+package crawler
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// maxPageAnchors caps the anchors recorded per page
+const maxPageAnchors = 2000
+
+// setupAnchorExtraction configures Colly HTML handler for recording the
+// fragment targets on a page: every id attribute and named <a> element. It
+// only runs when link extraction is enabled, since anchors are only needed to
+// validate fragment links, and must be registered before link extraction
+// strips the header and footer.
+func setupAnchorExtraction(collyClone *colly.Collector) {
+	collyClone.OnHTML("html", func(e *colly.HTMLElement) {
+		if findLinks, ok := e.Request.Ctx.GetAny("find_links").(bool); ok && !findLinks {
+			return
+		}
+
+		result, ok := e.Request.Ctx.GetAny("result").(*CrawlResult)
+		if !ok {
+			return
+		}
+
+		seen := make(map[string]bool)
+		anchors := make([]string, 0)
+		record := func(anchor string) {
+			if anchor == "" || seen[anchor] || len(anchors) >= maxPageAnchors {
+				return
+			}
+			seen[anchor] = true
+			anchors = append(anchors, anchor)
+		}
+
+		e.DOM.Find("[id]").Each(func(i int, s *goquery.Selection) {
+			record(strings.TrimSpace(s.AttrOr("id", "")))
+		})
+		e.DOM.Find("a[name]").Each(func(i int, s *goquery.Selection) {
+			record(strings.TrimSpace(s.AttrOr("name", "")))
+		})
+		result.Anchors = anchors
+
+		log.Debug().
+			Str("url", e.Request.URL.String()).
+			Int("anchors", len(anchors)).
+			Msg("Recorded page anchors")
+	})
+}
@@ -0,0 +1,57 @@
+package crawler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gocolly/colly/v2"
+)
+
+func TestAnchorExtraction(t *testing.T) {
+	page := `<html><body>
+		<header id="top"><a href="#main">Skip</a></header>
+		<main id="main">
+			<h2 id="pricing">Pricing</h2>
+			<section id=" enterprise ">Enterprise</section>
+			<a name="legacy"></a>
+			<div id="pricing">Duplicate</div>
+		</main>
+		<footer id="footer"></footer>
+	</body></html>`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(page))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		findLinks bool
+		want      []string
+	}{
+		{"find links", true, []string{"top", "main", "pricing", "enterprise", "footer", "legacy"}},
+		{"links disabled", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &CrawlResult{}
+			collector := colly.NewCollector()
+			setupAnchorExtraction(collector)
+			collector.OnRequest(func(r *colly.Request) {
+				r.Ctx.Put("result", result)
+				r.Ctx.Put("find_links", tt.findLinks)
+			})
+			if err := collector.Visit(ts.URL + "/"); err != nil {
+				t.Fatalf("Visit failed: %v", err)
+			}
+
+			if !slices.Equal(result.Anchors, tt.want) {
+				t.Errorf("Expected anchors %v, got %v", tt.want, result.Anchors)
+			}
+		})
+	}
+}
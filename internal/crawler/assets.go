@@ -0,0 +1,236 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type assetExtractionKey struct{}
+
+// WithAssetExtraction returns a context that makes WarmURL also collect the
+// images, stylesheets, scripts, media and fonts referenced by the page
+func WithAssetExtraction(ctx context.Context) context.Context {
+	return context.WithValue(ctx, assetExtractionKey{}, true)
+}
+
+func assetExtractionEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(assetExtractionKey{}).(bool)
+	return enabled
+}
+
+var (
+	fontFaceRule = regexp.MustCompile(`(?is)@font-face\s*\{[^}]*\}`)
+	cssURLRef    = regexp.MustCompile(`(?i)url\(\s*['"]?([^'")\s]+)['"]?\s*\)`)
+)
+
+// assetHeaders are sent with asset requests in place of the page Accept header
+var assetHeaders = http.Header{"Accept": []string{"*/*"}}
+
+// assetLinkRels are the <link rel> tokens that reference a fetchable asset
+var assetLinkRels = map[string]bool{
+	"stylesheet": true,
+	"preload":    true,
+	"icon":       true,
+}
+
+// setupAssetExtraction configures Colly HTML handler for collecting asset URLs
+func setupAssetExtraction(collyClone *colly.Collector) {
+	collyClone.OnHTML("html", func(e *colly.HTMLElement) {
+		if findAssets, ok := e.Request.Ctx.GetAny("find_assets").(bool); !ok || !findAssets {
+			return
+		}
+
+		result, ok := e.Request.Ctx.GetAny("result").(*CrawlResult)
+		if !ok {
+			return
+		}
+
+		seen := make(map[string]bool)
+		add := func(ref string) {
+			ref = strings.TrimSpace(ref)
+			if !isFetchableAssetRef(ref) {
+				return
+			}
+			u := e.Request.AbsoluteURL(ref)
+			if u == "" || seen[u] {
+				return
+			}
+			seen[u] = true
+			result.Assets = append(result.Assets, u)
+		}
+
+		e.DOM.Find("img, source").Each(func(i int, s *goquery.Selection) {
+			add(s.AttrOr("src", ""))
+			for _, candidate := range parseSrcset(s.AttrOr("srcset", "")) {
+				add(candidate)
+			}
+		})
+
+		e.DOM.Find("link[href]").Each(func(i int, s *goquery.Selection) {
+			for rel := range strings.FieldsSeq(strings.ToLower(s.AttrOr("rel", ""))) {
+				if assetLinkRels[rel] {
+					add(s.AttrOr("href", ""))
+					return
+				}
+			}
+		})
+
+		e.DOM.Find("script[src]").Each(func(i int, s *goquery.Selection) {
+			add(s.AttrOr("src", ""))
+		})
+
+		e.DOM.Find("video").Each(func(i int, s *goquery.Selection) {
+			add(s.AttrOr("src", ""))
+			add(s.AttrOr("poster", ""))
+		})
+
+		// Inline @font-face rules resolve against the page URL
+		e.DOM.Find("style").Each(func(i int, s *goquery.Selection) {
+			for _, ref := range extractFontFaceURLs(s.Text()) {
+				add(ref)
+			}
+		})
+
+		log.Debug().
+			Str("url", e.Request.URL.String()).
+			Int("assets", len(result.Assets)).
+			Msg("Collected asset URLs from page")
+	})
+}
+
+// isFetchableAssetRef filters out empty, inline and non-HTTP references
+func isFetchableAssetRef(ref string) bool {
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return false
+	}
+	lower := strings.ToLower(ref)
+	for _, prefix := range []string{"data:", "blob:", "javascript:", "about:", "mailto:"} {
+		if strings.HasPrefix(lower, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSrcset returns the URLs from a srcset attribute, dropping width and
+// density descriptors (e.g. "a.jpg 1x, b.jpg 2x")
+func parseSrcset(srcset string) []string {
+	var urls []string
+	for candidate := range strings.SplitSeq(srcset, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) > 0 {
+			urls = append(urls, fields[0])
+		}
+	}
+	return urls
+}
+
+// extractFontFaceURLs returns the url() references inside @font-face rules
+func extractFontFaceURLs(css string) []string {
+	var urls []string
+	for _, rule := range fontFaceRule.FindAllString(css, -1) {
+		for _, match := range cssURLRef.FindAllStringSubmatch(rule, -1) {
+			urls = append(urls, match[1])
+		}
+	}
+	return urls
+}
+
+// isStylesheet reports whether a response content type is CSS
+func isStylesheet(contentType string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(contentType)), "text/css")
+}
+
+// WarmAsset checks and warms a single asset (image, stylesheet, script, font).
+// Unlike WarmURL it never extracts links: it sends a HEAD request and only
+// follows up with a GET when the asset needs warming, the server does not
+// support HEAD, or the asset is a stylesheet whose @font-face URLs are needed.
+// Fonts found in stylesheets are returned in Assets.
+func (c *Crawler) WarmAsset(ctx context.Context, targetURL string) (*CrawlResult, error) {
+	if _, err := validateCrawlRequest(ctx, targetURL, c.config.SkipSSRFCheck); err != nil {
+		res := &CrawlResult{URL: targetURL, Timestamp: time.Now().Unix(), Error: err.Error()}
+		return res, err
+	}
+
+	res, err := c.fetchWithHeaders(ctx, http.MethodHead, targetURL, assetHeaders)
+	if err != nil {
+		return res, err
+	}
+
+	// Some origins reject HEAD outright; treat the GET as the primary request
+	if res.StatusCode == http.StatusMethodNotAllowed || res.StatusCode == http.StatusNotImplemented {
+		res, err = c.fetchWithHeaders(ctx, http.MethodGet, targetURL, assetHeaders)
+		if err != nil {
+			return res, err
+		}
+		return res, c.finishAsset(targetURL, res, res)
+	}
+
+	if res.Error == "" && (shouldMakeSecondRequest(res.CacheStatus) || isStylesheet(res.ContentType)) {
+		warm, warmErr := c.fetchWithHeaders(ctx, http.MethodGet, targetURL, assetHeaders)
+		if warmErr != nil {
+			log.Debug().
+				Err(warmErr).
+				Str("url", targetURL).
+				Msg("Asset warming request failed")
+		} else if warm.Error == "" {
+			res.SecondResponseTime = warm.ResponseTime
+			res.SecondCacheStatus = warm.CacheStatus
+			res.SecondContentLength = warm.ContentLength
+			res.SecondHeaders = warm.Headers
+			res.SecondPerformance = &warm.Performance
+			if res.ContentLength == 0 {
+				res.ContentLength = warm.ContentLength
+			}
+		}
+		return res, c.finishAsset(targetURL, res, warm)
+	}
+
+	return res, c.finishAsset(targetURL, res, nil)
+}
+
+// finishAsset collects fonts from a fetched stylesheet body, drops the body
+// (assets are never stored or fingerprinted) and reports non-2xx statuses
+func (c *Crawler) finishAsset(targetURL string, res, fetched *CrawlResult) error {
+	if fetched != nil && isStylesheet(fetched.ContentType) && len(fetched.Body) > 0 {
+		base, err := url.Parse(fetched.RedirectURL)
+		if err != nil || fetched.RedirectURL == "" {
+			base, _ = url.Parse(targetURL)
+		}
+		for _, ref := range extractFontFaceURLs(string(fetched.Body)) {
+			if !isFetchableAssetRef(ref) {
+				continue
+			}
+			if refURL, err := url.Parse(ref); err == nil && base != nil {
+				res.Assets = append(res.Assets, base.ResolveReference(refURL).String())
+			}
+		}
+	}
+
+	for _, r := range []*CrawlResult{res, fetched} {
+		if r != nil {
+			r.Body = nil
+			r.BodySample = nil
+		}
+	}
+
+	if res.Error != "" {
+		log.Debug().
+			Int("status", res.StatusCode).
+			Str("url", targetURL).
+			Str("error", res.Error).
+			Msg("Asset check returned non-success status")
+		return fmt.Errorf("%s", res.Error)
+	}
+
+	return nil
+}
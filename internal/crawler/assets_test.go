@@ -0,0 +1,132 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestWarmURLCollectsAssetsWhenEnabled(t *testing.T) {
+	page := `<html><head>
+		<link rel="stylesheet" href="/css/site.css">
+		<link rel="preload" href="/fonts/inter.woff2" as="font">
+		<link rel="shortcut icon" href="/favicon.ico">
+		<link rel="canonical" href="/page">
+		<script src="/js/app.js"></script>
+		<style>@font-face { font-family: Brand; src: url('/fonts/brand.woff2') format('woff2'); }</style>
+	</head><body>
+		<img src="/img/hero.jpg" srcset="/img/hero-2x.jpg 2x, /img/hero.jpg 1x">
+		<img src="data:image/png;base64,AAAA">
+		<video src="/media/intro.mp4" poster="/img/poster.jpg"><source src="/media/intro.webm"></video>
+		<a href="/about">About</a>
+	</body></html>`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(page))
+	}))
+	defer ts.Close()
+
+	crawler := New(testConfig())
+
+	result, err := crawler.WarmURL(context.Background(), ts.URL+"/", true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Assets) != 0 {
+		t.Fatalf("Expected no assets without asset extraction, got %v", result.Assets)
+	}
+
+	result, err = crawler.WarmURL(WithAssetExtraction(context.Background()), ts.URL+"/", true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"/css/site.css", "/fonts/inter.woff2", "/favicon.ico", "/js/app.js", "/fonts/brand.woff2",
+		"/img/hero.jpg", "/img/hero-2x.jpg", "/media/intro.mp4", "/img/poster.jpg", "/media/intro.webm",
+	}
+	for _, path := range expected {
+		if !slices.Contains(result.Assets, ts.URL+path) {
+			t.Errorf("Expected asset %s in %v", path, result.Assets)
+		}
+	}
+	if len(result.Assets) != len(expected) {
+		t.Errorf("Expected %d unique assets, got %d: %v", len(expected), len(result.Assets), result.Assets)
+	}
+	if len(result.Links["body"]) != 1 {
+		t.Errorf("Expected link extraction to still run, got %v", result.Links)
+	}
+}
+
+func TestWarmAssetWarmsOnMissAndCollectsFonts(t *testing.T) {
+	var methods []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Header().Set("Content-Type", "text/css")
+		if r.Method == http.MethodHead {
+			w.Header().Set("CF-Cache-Status", "MISS")
+			return
+		}
+		w.Header().Set("CF-Cache-Status", "HIT")
+		_, _ = w.Write([]byte(`body { color: red } @font-face { src: url("../fonts/a.woff2"), url(data:font/woff2;base64,AA); }`))
+	}))
+	defer ts.Close()
+
+	crawler := New(testConfig())
+	result, err := crawler.WarmAsset(context.Background(), ts.URL+"/css/site.css")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !slices.Equal(methods, []string{http.MethodHead, http.MethodGet}) {
+		t.Errorf("Expected HEAD then GET, got %v", methods)
+	}
+	if result.CacheStatus != "MISS" || result.SecondCacheStatus != "HIT" {
+		t.Errorf("Expected MISS then HIT, got %q then %q", result.CacheStatus, result.SecondCacheStatus)
+	}
+	if !slices.Equal(result.Assets, []string{ts.URL + "/fonts/a.woff2"}) {
+		t.Errorf("Expected font resolved against the stylesheet URL, got %v", result.Assets)
+	}
+	if result.Body != nil || result.BodySample != nil {
+		t.Error("Expected asset body to be dropped")
+	}
+}
+
+func TestWarmAssetFallsBackToGetWhenHeadUnsupported(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("CF-Cache-Status", "HIT")
+		_, _ = w.Write([]byte("png"))
+	}))
+	defer ts.Close()
+
+	crawler := New(testConfig())
+	result, err := crawler.WarmAsset(context.Background(), ts.URL+"/logo.png")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.StatusCode != http.StatusOK || result.CacheStatus != "HIT" {
+		t.Errorf("Expected GET result to be primary, got status %d cache %q", result.StatusCode, result.CacheStatus)
+	}
+}
+
+func TestWarmAssetReportsBrokenAsset(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	crawler := New(testConfig())
+	result, err := crawler.WarmAsset(context.Background(), ts.URL+"/missing.js")
+	if err == nil {
+		t.Fatal("Expected error for missing asset")
+	}
+	if result.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", result.StatusCode)
+	}
+}
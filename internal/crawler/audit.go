@@ -0,0 +1,188 @@
+package crawler
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// Page audit thresholds
+const (
+	maxTitleLength       = 60  // Characters before search results truncate the title
+	maxDescriptionLength = 160 // Characters before search results truncate the description
+	minWordCount         = 200 // Fewer visible words than this is reported as thin content
+)
+
+// Finding severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityNotice  = "notice"
+)
+
+// Page-level audit finding codes. Job-wide codes (duplicate_title,
+// duplicate_description) are added when a job's findings are refreshed.
+const (
+	FindingMissingTitle          = "missing_title"
+	FindingTitleTooLong          = "title_too_long"
+	FindingMissingDescription    = "missing_description"
+	FindingDescriptionTooLong    = "description_too_long"
+	FindingMissingH1             = "missing_h1"
+	FindingMultipleH1            = "multiple_h1"
+	FindingThinContent           = "thin_content"
+	FindingImagesMissingAlt      = "images_missing_alt"
+	FindingMissingOpenGraph      = "missing_open_graph"
+	FindingIncompleteOpenGraph   = "incomplete_open_graph"
+	FindingMissingStructuredData = "missing_structured_data"
+)
+
+// requiredOpenGraph are the properties every shareable page should declare
+var requiredOpenGraph = []string{"og:title", "og:description", "og:image"}
+
+// PageAudit holds the on-page content signals of an HTML page
+type PageAudit struct {
+	Title            string            `json:"title,omitempty"`
+	MetaDescription  string            `json:"meta_description,omitempty"`
+	H1Count          int               `json:"h1_count"`
+	WordCount        int               `json:"word_count"`
+	ImageCount       int               `json:"image_count"`
+	ImagesMissingAlt int               `json:"images_missing_alt"` // <img> without an alt attribute; alt="" is decorative
+	OpenGraph        map[string]string `json:"open_graph,omitempty"`
+	JSONLDCount      int               `json:"json_ld_count"` // <script type="application/ld+json"> blocks
+	Findings         []AuditFinding    `json:"findings,omitempty"`
+}
+
+// AuditFinding is a content issue found on a page
+type AuditFinding struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// auditPageFindings checks a page's signals against the audit thresholds
+func auditPageFindings(audit *PageAudit) []AuditFinding {
+	var findings []AuditFinding
+	add := func(code, severity, detail string) {
+		findings = append(findings, AuditFinding{Code: code, Severity: severity, Detail: detail})
+	}
+
+	if audit.Title == "" {
+		add(FindingMissingTitle, SeverityError, "")
+	} else if n := utf8.RuneCountInString(audit.Title); n > maxTitleLength {
+		add(FindingTitleTooLong, SeverityWarning, fmt.Sprintf("%d characters (max %d)", n, maxTitleLength))
+	}
+
+	if audit.MetaDescription == "" {
+		add(FindingMissingDescription, SeverityWarning, "")
+	} else if n := utf8.RuneCountInString(audit.MetaDescription); n > maxDescriptionLength {
+		add(FindingDescriptionTooLong, SeverityNotice, fmt.Sprintf("%d characters (max %d)", n, maxDescriptionLength))
+	}
+
+	switch {
+	case audit.H1Count == 0:
+		add(FindingMissingH1, SeverityWarning, "")
+	case audit.H1Count > 1:
+		add(FindingMultipleH1, SeverityWarning, fmt.Sprintf("%d h1 elements", audit.H1Count))
+	}
+
+	if audit.WordCount < minWordCount {
+		add(FindingThinContent, SeverityNotice, fmt.Sprintf("%d words (min %d)", audit.WordCount, minWordCount))
+	}
+
+	if audit.ImagesMissingAlt > 0 {
+		add(FindingImagesMissingAlt, SeverityWarning, fmt.Sprintf("%d of %d images", audit.ImagesMissingAlt, audit.ImageCount))
+	}
+
+	if len(audit.OpenGraph) == 0 {
+		add(FindingMissingOpenGraph, SeverityNotice, "")
+	} else {
+		var missing []string
+		for _, property := range requiredOpenGraph {
+			if audit.OpenGraph[property] == "" {
+				missing = append(missing, property)
+			}
+		}
+		if len(missing) > 0 {
+			add(FindingIncompleteOpenGraph, SeverityNotice, "missing "+strings.Join(missing, ", "))
+		}
+	}
+
+	if audit.JSONLDCount == 0 {
+		add(FindingMissingStructuredData, SeverityNotice, "")
+	}
+
+	return findings
+}
+
+// visibleWordCount counts the words in a selection's text, ignoring scripts,
+// styles and templates
+func visibleWordCount(selection *goquery.Selection) int {
+	clone := selection.Clone()
+	clone.Find("script, style, noscript, template").Remove()
+	return len(strings.Fields(clone.Text()))
+}
+
+// setupPageAudit configures Colly HTML handler for the on-page SEO audit. It
+// must be registered before link extraction, which strips the header and
+// footer from the parsed document.
+func setupPageAudit(collyClone *colly.Collector) {
+	collyClone.OnHTML("html", func(e *colly.HTMLElement) {
+		result, ok := e.Request.Ctx.GetAny("result").(*CrawlResult)
+		if !ok {
+			return
+		}
+
+		audit := &PageAudit{
+			Title:     strings.Join(strings.Fields(e.DOM.Find("title").First().Text()), " "),
+			H1Count:   e.DOM.Find("h1").Length(),
+			WordCount: visibleWordCount(e.DOM.Find("body")),
+		}
+
+		e.DOM.Find("meta").Each(func(i int, s *goquery.Selection) {
+			content := strings.TrimSpace(s.AttrOr("content", ""))
+			if strings.EqualFold(strings.TrimSpace(s.AttrOr("name", "")), "description") {
+				if audit.MetaDescription == "" {
+					audit.MetaDescription = content
+				}
+				return
+			}
+			// Open Graph uses property=, but name= is common enough to accept
+			property := strings.ToLower(strings.TrimSpace(s.AttrOr("property", s.AttrOr("name", ""))))
+			if strings.HasPrefix(property, "og:") && content != "" {
+				if audit.OpenGraph == nil {
+					audit.OpenGraph = make(map[string]string)
+				}
+				if _, exists := audit.OpenGraph[property]; !exists {
+					audit.OpenGraph[property] = content
+				}
+			}
+		})
+
+		e.DOM.Find("img").Each(func(i int, s *goquery.Selection) {
+			audit.ImageCount++
+			if _, hasAlt := s.Attr("alt"); !hasAlt {
+				audit.ImagesMissingAlt++
+			}
+		})
+
+		e.DOM.Find("script[type]").Each(func(i int, s *goquery.Selection) {
+			if strings.EqualFold(strings.TrimSpace(s.AttrOr("type", "")), "application/ld+json") {
+				audit.JSONLDCount++
+			}
+		})
+
+		audit.Findings = auditPageFindings(audit)
+		result.Audit = audit
+
+		log.Debug().
+			Str("url", e.Request.URL.String()).
+			Int("h1_count", audit.H1Count).
+			Int("word_count", audit.WordCount).
+			Int("findings", len(audit.Findings)).
+			Msg("Audited page content")
+	})
+}
@@ -0,0 +1,114 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func findingCodes(findings []AuditFinding) []string {
+	codes := make([]string, 0, len(findings))
+	for _, f := range findings {
+		codes = append(codes, f.Code)
+	}
+	return codes
+}
+
+func TestWarmURLAuditsPage(t *testing.T) {
+	page := `<html><head>
+		<title>
+			Blue Widgets | Example
+		</title>
+		<meta name="Description" content="Hand-made blue widgets.">
+		<meta property="og:title" content="Blue Widgets">
+		<meta property="og:image" content="/widget.png">
+		<script type="application/ld+json">{"@type": "Product"}</script>
+		<script>var ignored = "these words are not counted";</script>
+	</head><body>
+		<header><h1>Example</h1></header>
+		<h1>Blue widgets</h1>
+		<p>Our widgets are blue.</p>
+		<img src="/a.png" alt="A widget">
+		<img src="/b.png" alt="">
+		<img src="/c.png">
+	</body></html>`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(page))
+	}))
+	defer ts.Close()
+
+	result, err := New(testConfig()).WarmURL(context.Background(), ts.URL+"/", true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	audit := result.Audit
+	if audit == nil {
+		t.Fatal("Expected page audit")
+	}
+	if audit.Title != "Blue Widgets | Example" {
+		t.Errorf("Expected whitespace-collapsed title, got %q", audit.Title)
+	}
+	if audit.MetaDescription != "Hand-made blue widgets." {
+		t.Errorf("Expected meta description, got %q", audit.MetaDescription)
+	}
+	if audit.H1Count != 2 {
+		t.Errorf("Expected h1 in header to be counted, got %d", audit.H1Count)
+	}
+	if audit.WordCount != 7 {
+		t.Errorf("Expected 7 visible words, got %d", audit.WordCount)
+	}
+	if audit.ImageCount != 3 || audit.ImagesMissingAlt != 1 {
+		t.Errorf("Expected 1 of 3 images missing alt, got %d of %d", audit.ImagesMissingAlt, audit.ImageCount)
+	}
+	if audit.OpenGraph["og:title"] != "Blue Widgets" || len(audit.OpenGraph) != 2 {
+		t.Errorf("Expected two Open Graph properties, got %v", audit.OpenGraph)
+	}
+	if audit.JSONLDCount != 1 {
+		t.Errorf("Expected 1 JSON-LD block, got %d", audit.JSONLDCount)
+	}
+
+	want := "multiple_h1,thin_content,images_missing_alt,incomplete_open_graph"
+	if got := strings.Join(findingCodes(audit.Findings), ","); got != want {
+		t.Errorf("Expected findings %s, got %s", want, got)
+	}
+}
+
+func TestAuditPageFindings(t *testing.T) {
+	complete := PageAudit{
+		Title:           "Blue widgets",
+		MetaDescription: "Hand-made blue widgets.",
+		H1Count:         1,
+		WordCount:       450,
+		ImageCount:      2,
+		OpenGraph:       map[string]string{"og:title": "a", "og:description": "b", "og:image": "c"},
+		JSONLDCount:     1,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(a *PageAudit)
+		want   string
+	}{
+		{"complete page", func(a *PageAudit) {}, ""},
+		{"missing title and description", func(a *PageAudit) { a.Title, a.MetaDescription = "", "" }, "missing_title,missing_description"},
+		{"long title", func(a *PageAudit) { a.Title = strings.Repeat("t", 61) }, "title_too_long"},
+		{"long description", func(a *PageAudit) { a.MetaDescription = strings.Repeat("d", 161) }, "description_too_long"},
+		{"no h1", func(a *PageAudit) { a.H1Count = 0 }, "missing_h1"},
+		{"no open graph or structured data", func(a *PageAudit) { a.OpenGraph, a.JSONLDCount = nil, 0 }, "missing_open_graph,missing_structured_data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := complete
+			tt.modify(&audit)
+			if got := strings.Join(findingCodes(auditPageFindings(&audit)), ","); got != tt.want {
+				t.Errorf("auditPageFindings() = %s; want %s", got, tt.want)
+			}
+		})
+	}
+}
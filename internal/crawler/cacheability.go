@@ -0,0 +1,205 @@
+package crawler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reasons a response cannot be cached at the edge
+const (
+	ReasonNoStore           = "no-store"
+	ReasonPrivate           = "private"
+	ReasonNoCache           = "no-cache"
+	ReasonPragmaNoCache     = "pragma-no-cache"
+	ReasonMaxAgeZero        = "max-age-zero"
+	ReasonExpiresPast       = "expires-past"
+	ReasonSetCookie         = "set-cookie"
+	ReasonVaryStar          = "vary-star"
+	ReasonNoExplicitTTL     = "no-explicit-ttl"
+	ReasonUncacheableStatus = "uncacheable-status"
+	ReasonCDNBypass         = "cdn-bypass"
+)
+
+// Warnings for responses that are cacheable but likely to warm poorly
+const (
+	WarningShortTTL      = "short-ttl"
+	WarningVaryCookie    = "vary-cookie"
+	WarningVaryUserAgent = "vary-user-agent"
+)
+
+// ShortEdgeTTL is the TTL below which warmed objects expire before they help
+const ShortEdgeTTL = 5 * time.Minute
+
+// heuristicallyCacheableStatuses are the status codes shared caches may store
+// without explicit freshness information (RFC 9110 section 15.1)
+var heuristicallyCacheableStatuses = map[int]bool{
+	200: true, 203: true, 204: true, 206: true, 300: true, 301: true, 308: true,
+	404: true, 405: true, 410: true, 414: true, 501: true,
+}
+
+// Cacheability is the result of analysing a response's caching headers
+type Cacheability struct {
+	Cacheable bool     `json:"cacheable"`
+	Reasons   []string `json:"reasons,omitempty"`  // Why the response is not cacheable
+	Warnings  []string `json:"warnings,omitempty"` // Cacheable, but likely to warm poorly
+	EdgeTTL   *int64   `json:"edge_ttl,omitempty"` // Effective shared-cache lifetime in seconds
+	TTLSource string   `json:"ttl_source,omitempty"`
+}
+
+// AnalyseCacheability classifies a response as cacheable or not at the edge
+// from its Cache-Control, Surrogate-Control, CDN-Cache-Control, Expires, Vary
+// and Set-Cookie headers. cacheStatus is the CDN's observed status, if known;
+// a BYPASS or DYNAMIC result is reported even when the headers allow caching.
+func AnalyseCacheability(headers http.Header, statusCode int, contentType, cacheStatus string) Cacheability {
+	var result Cacheability
+	html := strings.Contains(strings.ToLower(contentType), "html")
+
+	cacheControl := parseCacheControl(headers.Values("Cache-Control"))
+
+	// Surrogate-Control and CDN-Cache-Control are read by CDNs in place of
+	// Cache-Control, so they decide edge caching when present
+	directives, targeted := cacheControl, ""
+	for _, name := range []string{"Surrogate-Control", "CDN-Cache-Control"} {
+		if values := headers.Values(name); len(values) > 0 {
+			directives, targeted = parseCacheControl(values), strings.ToLower(name)
+			break
+		}
+	}
+
+	if statusCode != 0 && !heuristicallyCacheableStatuses[statusCode] {
+		result.Reasons = append(result.Reasons, ReasonUncacheableStatus)
+	}
+	if _, ok := directives["no-store"]; ok {
+		result.Reasons = append(result.Reasons, ReasonNoStore)
+	}
+	if _, ok := cacheControl["private"]; ok && targeted == "" {
+		result.Reasons = append(result.Reasons, ReasonPrivate)
+	}
+	if _, ok := directives["no-cache"]; ok {
+		result.Reasons = append(result.Reasons, ReasonNoCache)
+	} else if len(cacheControl) == 0 && targeted == "" && strings.Contains(strings.ToLower(headers.Get("Pragma")), "no-cache") {
+		result.Reasons = append(result.Reasons, ReasonPragmaNoCache)
+	}
+
+	ttl, source := edgeFreshness(headers, directives, targeted)
+	if ttl != nil && *ttl == 0 {
+		if source == "expires" {
+			result.Reasons = append(result.Reasons, ReasonExpiresPast)
+		} else {
+			result.Reasons = append(result.Reasons, ReasonMaxAgeZero)
+		}
+	}
+
+	for _, vary := range varyFields(headers) {
+		switch vary {
+		case "*":
+			result.Reasons = append(result.Reasons, ReasonVaryStar)
+		case "cookie":
+			result.Warnings = append(result.Warnings, WarningVaryCookie)
+		case "user-agent":
+			result.Warnings = append(result.Warnings, WarningVaryUserAgent)
+		}
+	}
+
+	// Most CDNs refuse to cache HTML that sets cookies, and don't cache HTML
+	// at all without an explicit TTL
+	if html && len(headers.Values("Set-Cookie")) > 0 {
+		result.Reasons = append(result.Reasons, ReasonSetCookie)
+	}
+	if html && ttl == nil {
+		result.Reasons = append(result.Reasons, ReasonNoExplicitTTL)
+	}
+
+	switch strings.ToUpper(cacheStatus) {
+	case "BYPASS", "DYNAMIC", "PASS":
+		result.Reasons = append(result.Reasons, ReasonCDNBypass)
+	}
+
+	result.Cacheable = len(result.Reasons) == 0
+	if result.Cacheable && ttl != nil {
+		result.EdgeTTL = ttl
+		result.TTLSource = source
+		if time.Duration(*ttl)*time.Second < ShortEdgeTTL {
+			result.Warnings = append(result.Warnings, WarningShortTTL)
+		}
+	}
+
+	return result
+}
+
+// edgeFreshness returns the shared-cache freshness lifetime and where it came
+// from, in order of precedence: a CDN-targeted header's max-age, s-maxage,
+// max-age, then Expires relative to Date
+func edgeFreshness(headers http.Header, directives map[string]string, targeted string) (*int64, string) {
+	if targeted != "" {
+		if seconds, ok := directiveSeconds(directives, "max-age"); ok {
+			return &seconds, targeted
+		}
+		directives = parseCacheControl(headers.Values("Cache-Control"))
+	}
+	if seconds, ok := directiveSeconds(directives, "s-maxage"); ok {
+		return &seconds, "s-maxage"
+	}
+	if seconds, ok := directiveSeconds(directives, "max-age"); ok {
+		return &seconds, "max-age"
+	}
+
+	if raw := strings.TrimSpace(headers.Get("Expires")); raw != "" {
+		// Invalid dates such as "0" or "-1" mean already expired
+		var seconds int64
+		if expires, err := http.ParseTime(raw); err == nil {
+			date := time.Now()
+			if parsed, err := http.ParseTime(headers.Get("Date")); err == nil {
+				date = parsed
+			}
+			seconds = max(int64(expires.Sub(date).Seconds()), 0)
+		}
+		return &seconds, "expires"
+	}
+
+	return nil, ""
+}
+
+// parseCacheControl parses cache-control style header values into a map of
+// lower-cased directive names to their (unquoted) arguments
+func parseCacheControl(values []string) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range values {
+		for directive := range strings.SplitSeq(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			directives[name] = strings.Trim(strings.TrimSpace(arg), `"`)
+		}
+	}
+	return directives
+}
+
+func directiveSeconds(directives map[string]string, name string) (int64, bool) {
+	arg, ok := directives[name]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return seconds, true
+}
+
+// varyFields returns the lower-cased header names listed in Vary
+func varyFields(headers http.Header) []string {
+	var fields []string
+	for _, value := range headers.Values("Vary") {
+		for field := range strings.SplitSeq(value, ",") {
+			if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,142 @@
+package crawler
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestAnalyseCacheability(t *testing.T) {
+	tests := []struct {
+		name          string
+		headers       map[string]string
+		statusCode    int
+		contentType   string
+		cacheStatus   string
+		wantCacheable bool
+		wantReasons   []string
+		wantWarnings  []string
+		wantTTL       int64
+		wantSource    string
+	}{
+		{
+			name:          "s-maxage wins over max-age",
+			headers:       map[string]string{"Cache-Control": "public, max-age=60, s-maxage=86400"},
+			wantCacheable: true,
+			wantTTL:       86400,
+			wantSource:    "s-maxage",
+		},
+		{
+			name:          "surrogate-control overrides private cache-control",
+			headers:       map[string]string{"Cache-Control": "private, max-age=0", "Surrogate-Control": "max-age=3600"},
+			wantCacheable: true,
+			wantTTL:       3600,
+			wantSource:    "surrogate-control",
+		},
+		{
+			name:        "private and no-store",
+			headers:     map[string]string{"Cache-Control": "private, no-store"},
+			wantReasons: []string{ReasonNoStore, ReasonPrivate, ReasonNoExplicitTTL},
+		},
+		{
+			name:        "max-age zero",
+			headers:     map[string]string{"Cache-Control": "max-age=0"},
+			wantReasons: []string{ReasonMaxAgeZero},
+		},
+		{
+			name:        "set-cookie on html",
+			headers:     map[string]string{"Cache-Control": "max-age=600", "Set-Cookie": "session=abc"},
+			wantReasons: []string{ReasonSetCookie},
+		},
+		{
+			name:          "set-cookie on an image is fine",
+			headers:       map[string]string{"Cache-Control": "max-age=600", "Set-Cookie": "session=abc"},
+			contentType:   "image/png",
+			wantCacheable: true,
+			wantTTL:       600,
+			wantSource:    "max-age",
+		},
+		{
+			name:        "vary star",
+			headers:     map[string]string{"Cache-Control": "max-age=600", "Vary": "Accept-Encoding, *"},
+			wantReasons: []string{ReasonVaryStar},
+		},
+		{
+			name:          "short ttl and vary cookie are warnings",
+			headers:       map[string]string{"Cache-Control": "s-maxage=30", "Vary": "Cookie"},
+			wantCacheable: true,
+			wantWarnings:  []string{WarningVaryCookie, WarningShortTTL},
+			wantTTL:       30,
+			wantSource:    "s-maxage",
+		},
+		{
+			name:        "expires in the past",
+			headers:     map[string]string{"Expires": "0"},
+			wantReasons: []string{ReasonExpiresPast},
+		},
+		{
+			name:          "expires relative to date",
+			headers:       map[string]string{"Date": "Mon, 02 Mar 2026 10:00:00 GMT", "Expires": "Mon, 02 Mar 2026 11:00:00 GMT"},
+			wantCacheable: true,
+			wantTTL:       3600,
+			wantSource:    "expires",
+		},
+		{
+			name:        "html without explicit ttl",
+			headers:     map[string]string{},
+			wantReasons: []string{ReasonNoExplicitTTL},
+		},
+		{
+			name:        "pragma without cache-control",
+			headers:     map[string]string{"Pragma": "no-cache", "Cache-Control": ""},
+			wantReasons: []string{ReasonPragmaNoCache, ReasonNoExplicitTTL},
+		},
+		{
+			name:        "uncacheable status and cdn bypass",
+			headers:     map[string]string{"Cache-Control": "max-age=600"},
+			statusCode:  500,
+			cacheStatus: "BYPASS",
+			wantReasons: []string{ReasonUncacheableStatus, ReasonCDNBypass},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			for name, value := range tt.headers {
+				if value != "" {
+					headers.Set(name, value)
+				}
+			}
+			statusCode := tt.statusCode
+			if statusCode == 0 {
+				statusCode = http.StatusOK
+			}
+			contentType := tt.contentType
+			if contentType == "" {
+				contentType = "text/html; charset=utf-8"
+			}
+
+			result := AnalyseCacheability(headers, statusCode, contentType, tt.cacheStatus)
+
+			if result.Cacheable != tt.wantCacheable {
+				t.Errorf("Cacheable = %v, want %v (reasons %v)", result.Cacheable, tt.wantCacheable, result.Reasons)
+			}
+			if !slices.Equal(result.Reasons, tt.wantReasons) {
+				t.Errorf("Reasons = %v, want %v", result.Reasons, tt.wantReasons)
+			}
+			if !slices.Equal(result.Warnings, tt.wantWarnings) {
+				t.Errorf("Warnings = %v, want %v", result.Warnings, tt.wantWarnings)
+			}
+			if tt.wantSource == "" {
+				if result.EdgeTTL != nil {
+					t.Errorf("Expected no edge TTL, got %d", *result.EdgeTTL)
+				}
+				return
+			}
+			if result.EdgeTTL == nil || *result.EdgeTTL != tt.wantTTL || result.TTLSource != tt.wantSource {
+				t.Errorf("Edge TTL = %v from %q, want %d from %q", result.EdgeTTL, result.TTLSource, tt.wantTTL, tt.wantSource)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxCacheHeaderMappings caps the custom header mappings an organisation can define
+const MaxCacheHeaderMappings = 20
+
+// CacheDetection is the cache information a detector read from a response
+type CacheDetection struct {
+	Provider string `json:"provider,omitempty"` // e.g. "cloudflare", "fastly", "unknown"
+	Status   string `json:"status,omitempty"`   // Normalised HIT/MISS/BYPASS/...
+	Age      *int64 `json:"age,omitempty"`      // Seconds the object has been cached
+	TTL      *int64 `json:"ttl,omitempty"`      // Seconds until the cached object expires
+}
+
+// CacheDetector recognises a CDN's cache status headers. Detect returns false
+// when the response did not come through the detector's CDN.
+type CacheDetector interface {
+	Detect(headers http.Header) (CacheDetection, bool)
+}
+
+// DetectorRegistry runs cache detectors in priority order; the first detector
+// that recognises a response wins
+type DetectorRegistry struct {
+	detectors []CacheDetector
+}
+
+// NewDetectorRegistry creates a registry that tries detectors in the given order
+func NewDetectorRegistry(detectors ...CacheDetector) *DetectorRegistry {
+	return &DetectorRegistry{detectors: detectors}
+}
+
+// Register adds a detector at the lowest priority
+func (r *DetectorRegistry) Register(detector CacheDetector) {
+	r.detectors = append(r.detectors, detector)
+}
+
+// WithMappings returns a copy of the registry with custom header mappings
+// tried before the existing detectors
+func (r *DetectorRegistry) WithMappings(mappings []HeaderMapping) *DetectorRegistry {
+	detectors := make([]CacheDetector, 0, len(mappings)+len(r.detectors))
+	for _, mapping := range mappings {
+		detectors = append(detectors, mapping)
+	}
+	detectors = append(detectors, r.detectors...)
+	return &DetectorRegistry{detectors: detectors}
+}
+
+// Detect returns the first detector match. When no CDN is recognised the
+// result has no provider or status but still reports Age and TTL.
+func (r *DetectorRegistry) Detect(headers http.Header) CacheDetection {
+	for _, detector := range r.detectors {
+		if detection, ok := detector.Detect(headers); ok {
+			return detection
+		}
+	}
+	age, ttl := cacheLifetime(headers, "")
+	return CacheDetection{Age: age, TTL: ttl}
+}
+
+// DefaultDetectors returns a registry of the built-in CDN detectors.
+// Provider-specific detectors come before the generic X-Cache and Cache-Status
+// fallbacks so a shared header is attributed to the CDN that sent it.
+func DefaultDetectors() *DetectorRegistry {
+	return NewDetectorRegistry(
+		headerDetector{provider: "cloudflare", header: "CF-Cache-Status", controlHeader: "Cloudflare-CDN-Cache-Control"},
+		headerDetector{provider: "bunny", header: "CDN-Cache"},
+		headerDetector{provider: "cloudfront", header: "X-Cache", identify: func(h http.Header) bool {
+			return h.Get("X-Amz-Cf-Id") != "" || strings.Contains(strings.ToLower(h.Get("X-Cache")), "cloudfront")
+		}},
+		headerDetector{provider: "fastly", header: "X-Cache", controlHeader: "Surrogate-Control", identify: func(h http.Header) bool {
+			return h.Get("X-Fastly-Request-ID") != "" || strings.HasPrefix(h.Get("X-Served-By"), "cache-")
+		}},
+		headerDetector{provider: "azure_front_door", header: "X-Cache", identify: func(h http.Header) bool {
+			return h.Get("X-Azure-Ref") != ""
+		}},
+		headerDetector{provider: "keycdn", header: "X-Cache", identify: func(h http.Header) bool {
+			return strings.HasPrefix(strings.ToLower(h.Get("Server")), "keycdn")
+		}},
+		headerDetector{provider: "akamai", header: "X-Cache", controlHeader: "Edge-Control", identify: func(h http.Header) bool {
+			return h.Get("X-Cache-Remote") != "" || strings.Contains(strings.ToLower(h.Get("X-Cache")), "akamai")
+		}},
+		headerDetector{provider: "unknown", header: "X-Cache"},
+		headerDetector{provider: "akamai", header: "X-Cache-Remote", controlHeader: "Edge-Control"},
+		headerDetector{provider: "vercel", header: "X-Vercel-Cache", controlHeader: "Vercel-CDN-Cache-Control"},
+		headerDetector{provider: "netlify", header: "Cache-Status", controlHeader: "Netlify-CDN-Cache-Control", identify: func(h http.Header) bool {
+			return h.Get("X-Nf-Request-Id") != "" || strings.Contains(strings.ToLower(h.Get("Cache-Status")), "netlify")
+		}},
+		headerDetector{provider: "fly", header: "Cache-Status", identify: func(h http.Header) bool {
+			return h.Get("Fly-Request-Id") != ""
+		}},
+		headerDetector{provider: "unknown", header: "Cache-Status"},
+		headerDetector{provider: "varnish", header: "X-Varnish", parse: func(value string) string {
+			// X-Varnish holds the request ID, plus the cached object's ID on a hit
+			if strings.Contains(strings.TrimSpace(value), " ") {
+				return "HIT"
+			}
+			return "MISS"
+		}},
+	)
+}
+
+var defaultDetectors = DefaultDetectors()
+
+// headerDetector reads a CDN's cache status from a single response header
+type headerDetector struct {
+	provider      string
+	header        string
+	controlHeader string                    // Targeted Cache-Control header (RFC 9213) for the TTL, if any
+	identify      func(http.Header) bool    // Extra check that the response came from this CDN
+	parse         func(value string) string // Defaults to normaliseCacheStatus
+}
+
+// Detect implements CacheDetector
+func (d headerDetector) Detect(headers http.Header) (CacheDetection, bool) {
+	value := headers.Get(d.header)
+	if value == "" || (d.identify != nil && !d.identify(headers)) {
+		return CacheDetection{}, false
+	}
+
+	parse := d.parse
+	if parse == nil {
+		parse = normaliseCacheStatus
+	}
+	status := parse(value)
+	if status == "" {
+		return CacheDetection{}, false
+	}
+
+	age, ttl := cacheLifetime(headers, d.controlHeader)
+	return CacheDetection{Provider: d.provider, Status: status, Age: age, TTL: ttl}, true
+}
+
+// HeaderMapping is an organisation-defined detector for CDNs the built-in
+// detectors don't know. Values maps case-insensitive substrings of the header
+// value to a status; unmatched values fall back to the standard normalisation.
+type HeaderMapping struct {
+	Header   string            `json:"header"`
+	Provider string            `json:"provider"`
+	Values   map[string]string `json:"values,omitempty"`
+}
+
+// Detect implements CacheDetector
+func (m HeaderMapping) Detect(headers http.Header) (CacheDetection, bool) {
+	value := strings.TrimSpace(headers.Get(m.Header))
+	if value == "" {
+		return CacheDetection{}, false
+	}
+
+	status := m.mappedStatus(value)
+	if status == "" {
+		status = normaliseCacheStatus(value)
+	}
+
+	age, ttl := cacheLifetime(headers, "")
+	return CacheDetection{Provider: m.Provider, Status: status, Age: age, TTL: ttl}, true
+}
+
+// mappedStatus matches the longest configured value first so that, for
+// example, "refresh_hit" wins over "hit"
+func (m HeaderMapping) mappedStatus(value string) string {
+	keys := make([]string, 0, len(m.Values))
+	for key := range m.Values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	lower := strings.ToLower(value)
+	for _, key := range keys {
+		if strings.Contains(lower, strings.ToLower(key)) {
+			return strings.ToUpper(m.Values[key])
+		}
+	}
+	return ""
+}
+
+var (
+	headerNameToken = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+
+	// mappableCacheStatuses are the statuses a custom mapping may produce
+	mappableCacheStatuses = map[string]bool{
+		"HIT": true, "MISS": true, "EXPIRED": true, "STALE": true, "REVALIDATED": true,
+		"UPDATING": true, "BYPASS": true, "DYNAMIC": true, "PASS": true,
+	}
+)
+
+// ValidateHeaderMappings checks an organisation's custom header mappings
+func ValidateHeaderMappings(mappings []HeaderMapping) error {
+	if len(mappings) > MaxCacheHeaderMappings {
+		return fmt.Errorf("too many mappings (%d); the maximum is %d", len(mappings), MaxCacheHeaderMappings)
+	}
+	for i, mapping := range mappings {
+		if !headerNameToken.MatchString(mapping.Header) {
+			return fmt.Errorf("mapping %d: invalid header name %q", i+1, mapping.Header)
+		}
+		if strings.TrimSpace(mapping.Provider) == "" || len(mapping.Provider) > 64 {
+			return fmt.Errorf("mapping %d: provider must be 1-64 characters", i+1)
+		}
+		for value, status := range mapping.Values {
+			if strings.TrimSpace(value) == "" {
+				return fmt.Errorf("mapping %d: header values must not be empty", i+1)
+			}
+			if !mappableCacheStatuses[strings.ToUpper(status)] {
+				return fmt.Errorf("mapping %d: unsupported status %q for value %q", i+1, status, value)
+			}
+		}
+	}
+	return nil
+}
+
+type cacheHeaderMappingsKey struct{}
+
+// WithCacheHeaderMappings returns a context that makes the crawler try the
+// given custom mappings before the built-in CDN detectors
+func WithCacheHeaderMappings(ctx context.Context, mappings []HeaderMapping) context.Context {
+	return context.WithValue(ctx, cacheHeaderMappingsKey{}, mappings)
+}
+
+func cacheDetectorsFrom(ctx context.Context) *DetectorRegistry {
+	if mappings, _ := ctx.Value(cacheHeaderMappingsKey{}).([]HeaderMapping); len(mappings) > 0 {
+		return defaultDetectors.WithMappings(mappings)
+	}
+	return defaultDetectors
+}
+
+// cacheLifetime reads the object's age from the Age header and its remaining
+// TTL from the most specific cache-control header present: a CDN's own
+// header, then CDN-Cache-Control (RFC 9213), then Cache-Control. Shared-cache
+// directives (s-maxage) take precedence over max-age.
+func cacheLifetime(headers http.Header, controlHeader string) (age, ttl *int64) {
+	if raw := strings.TrimSpace(headers.Get("Age")); raw != "" {
+		if seconds, err := strconv.ParseInt(raw, 10, 64); err == nil && seconds >= 0 {
+			age = &seconds
+		}
+	}
+
+	for _, name := range []string{controlHeader, "CDN-Cache-Control", "Cache-Control"} {
+		if name == "" {
+			continue
+		}
+		value := headers.Get(name)
+		if value == "" {
+			continue
+		}
+		maxAge, ok := parseMaxAge(value)
+		if !ok {
+			continue
+		}
+		remaining := maxAge
+		if age != nil {
+			remaining -= *age
+		}
+		remaining = max(remaining, 0)
+		return age, &remaining
+	}
+
+	return age, nil
+}
+
+// parseMaxAge returns the freshness lifetime from a cache-control value.
+// no-store and private responses are never cached by a CDN, so their TTL is 0.
+func parseMaxAge(value string) (int64, bool) {
+	directives := parseCacheControl([]string{value})
+	_, noStore := directives["no-store"]
+	_, private := directives["private"]
+	if noStore || private {
+		return 0, true
+	}
+	if seconds, ok := directiveSeconds(directives, "s-maxage"); ok {
+		return seconds, true
+	}
+	return directiveSeconds(directives, "max-age")
+}
@@ -0,0 +1,148 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDefaultDetectorsIdentifyProvider(t *testing.T) {
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		wantProvider string
+		wantStatus   string
+	}{
+		{name: "cloudflare", headers: map[string]string{"CF-Cache-Status": "HIT"}, wantProvider: "cloudflare", wantStatus: "HIT"},
+		{name: "bunny", headers: map[string]string{"CDN-Cache": "MISS", "Server": "BunnyCDN-SYD1-123"}, wantProvider: "bunny", wantStatus: "MISS"},
+		{name: "cloudfront", headers: map[string]string{"X-Cache": "Hit from cloudfront"}, wantProvider: "cloudfront", wantStatus: "HIT"},
+		{name: "fastly", headers: map[string]string{"X-Cache": "MISS, HIT", "X-Served-By": "cache-syd10120-SYD"}, wantProvider: "fastly", wantStatus: "HIT"},
+		{name: "azure front door", headers: map[string]string{"X-Cache": "TCP_MISS", "X-Azure-Ref": "0abc"}, wantProvider: "azure_front_door", wantStatus: "MISS"},
+		{name: "keycdn", headers: map[string]string{"X-Cache": "HIT", "Server": "keycdn-engine"}, wantProvider: "keycdn", wantStatus: "HIT"},
+		{name: "akamai remote", headers: map[string]string{"X-Cache-Remote": "TCP_MISS from a23-1-2-3"}, wantProvider: "akamai", wantStatus: "MISS"},
+		{name: "unknown x-cache", headers: map[string]string{"X-Cache": "HIT"}, wantProvider: "unknown", wantStatus: "HIT"},
+		{name: "vercel", headers: map[string]string{"X-Vercel-Cache": "STALE"}, wantProvider: "vercel", wantStatus: "STALE"},
+		{name: "netlify", headers: map[string]string{"Cache-Status": `"Netlify Edge"; hit`}, wantProvider: "netlify", wantStatus: "HIT"},
+		{name: "fly", headers: map[string]string{"Cache-Status": "MISS", "Fly-Request-Id": "01H"}, wantProvider: "fly", wantStatus: "MISS"},
+		{name: "varnish hit", headers: map[string]string{"X-Varnish": "123 456"}, wantProvider: "varnish", wantStatus: "HIT"},
+		{name: "no cdn", headers: map[string]string{"Server": "nginx"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			for name, value := range tt.headers {
+				headers.Set(name, value)
+			}
+			detection := DefaultDetectors().Detect(headers)
+			if detection.Provider != tt.wantProvider || detection.Status != tt.wantStatus {
+				t.Errorf("Expected %s/%s, got %s/%s", tt.wantProvider, tt.wantStatus, detection.Provider, detection.Status)
+			}
+		})
+	}
+}
+
+func TestDetectorRegistryAgeAndTTL(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("CF-Cache-Status", "HIT")
+	headers.Set("Age", "120")
+	headers.Set("Cache-Control", "public, max-age=60, s-maxage=3600")
+
+	detection := DefaultDetectors().Detect(headers)
+	if detection.Age == nil || *detection.Age != 120 {
+		t.Fatalf("Expected age 120, got %v", detection.Age)
+	}
+	if detection.TTL == nil || *detection.TTL != 3480 {
+		t.Fatalf("Expected s-maxage to give TTL 3480, got %v", detection.TTL)
+	}
+
+	// A CDN's own cache-control header overrides Cache-Control
+	headers.Set("Cloudflare-CDN-Cache-Control", "max-age=600")
+	if detection := DefaultDetectors().Detect(headers); detection.TTL == nil || *detection.TTL != 480 {
+		t.Fatalf("Expected targeted header to give TTL 480, got %v", detection.TTL)
+	}
+
+	noStore := http.Header{}
+	noStore.Set("Cache-Control", "no-store")
+	if detection := DefaultDetectors().Detect(noStore); detection.TTL == nil || *detection.TTL != 0 {
+		t.Fatalf("Expected no-store to give TTL 0, got %v", detection.TTL)
+	}
+}
+
+func TestHeaderMappingsTakePrecedence(t *testing.T) {
+	mappings := []HeaderMapping{{
+		Header:   "X-Edge-Result",
+		Provider: "acme-cdn",
+		Values:   map[string]string{"served-from-edge": "hit", "origin": "MISS"},
+	}}
+	registry := DefaultDetectors().WithMappings(mappings)
+
+	headers := http.Header{}
+	headers.Set("X-Edge-Result", "Served-From-Edge (syd)")
+	headers.Set("X-Cache", "MISS")
+	detection := registry.Detect(headers)
+	if detection.Provider != "acme-cdn" || detection.Status != "HIT" {
+		t.Fatalf("Expected custom mapping to win, got %s/%s", detection.Provider, detection.Status)
+	}
+
+	// The base registry is left unchanged
+	if detection := DefaultDetectors().Detect(headers); detection.Provider != "unknown" {
+		t.Fatalf("Expected default registry to ignore custom header, got %s", detection.Provider)
+	}
+}
+
+func TestValidateHeaderMappings(t *testing.T) {
+	valid := []HeaderMapping{{Header: "X-Edge-Result", Provider: "acme", Values: map[string]string{"edge": "HIT"}}}
+	if err := ValidateHeaderMappings(valid); err != nil {
+		t.Fatalf("Expected valid mappings, got %v", err)
+	}
+
+	invalid := map[string][]HeaderMapping{
+		"invalid header name": {{Header: "X Edge", Provider: "acme"}},
+		"provider must be":    {{Header: "X-Edge", Provider: " "}},
+		"unsupported status":  {{Header: "X-Edge", Provider: "acme", Values: map[string]string{"edge": "WARM"}}},
+	}
+	for want, mappings := range invalid {
+		if err := ValidateHeaderMappings(mappings); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing %q, got %v", want, err)
+		}
+	}
+}
+
+func TestWarmURLUsesCacheHeaderMappings(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("X-Edge-Result", "edge-hit")
+		w.Header().Set("Age", "30")
+		_, _ = w.Write([]byte("<html><body>ok</body></html>"))
+	}))
+	defer ts.Close()
+
+	crawler := New(testConfig())
+
+	result, err := crawler.WarmURL(context.Background(), ts.URL+"/", false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.CDNProvider != "" || result.CacheStatus != "" {
+		t.Fatalf("Expected no CDN detected without mappings, got %s/%s", result.CDNProvider, result.CacheStatus)
+	}
+
+	ctx := WithCacheHeaderMappings(context.Background(), []HeaderMapping{{
+		Header:   "X-Edge-Result",
+		Provider: "acme-cdn",
+		Values:   map[string]string{"edge-hit": "HIT"},
+	}})
+	result, err = crawler.WarmURL(ctx, ts.URL+"/", false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.CDNProvider != "acme-cdn" || result.CacheStatus != "HIT" {
+		t.Errorf("Expected acme-cdn/HIT, got %s/%s", result.CDNProvider, result.CacheStatus)
+	}
+	if result.CacheAge == nil || *result.CacheAge != 30 {
+		t.Errorf("Expected cache age 30, got %v", result.CacheAge)
+	}
+}
@@ -0,0 +1,99 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// Validators are the ETag and Last-Modified values a page was served with,
+// sent back as If-None-Match and If-Modified-Since on conditional requests
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// IsZero reports whether there are no validators to send
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// responseValidators reads the validators from a response's headers
+func responseValidators(headers http.Header) Validators {
+	if headers == nil {
+		return Validators{}
+	}
+	return Validators{
+		ETag:         headers.Get("ETag"),
+		LastModified: headers.Get("Last-Modified"),
+	}
+}
+
+type conditionalRequestKey struct{}
+
+// WithConditionalRequest returns a context that makes WarmURL send the page's
+// stored validators. A 304 response is returned as a successful result with
+// NotModified set, and is not followed by cache validation or variant warming.
+func WithConditionalRequest(ctx context.Context, validators Validators) context.Context {
+	return context.WithValue(ctx, conditionalRequestKey{}, validators)
+}
+
+func conditionalRequestFrom(ctx context.Context) Validators {
+	validators, _ := ctx.Value(conditionalRequestKey{}).(Validators)
+	return validators
+}
+
+// setConditionalHeaders adds If-None-Match and If-Modified-Since for the
+// validators that are set
+func setConditionalHeaders(headers *http.Header, validators Validators) {
+	if validators.ETag != "" {
+		headers.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		headers.Set("If-Modified-Since", validators.LastModified)
+	}
+}
+
+// setupNotModifiedHandling treats a 304 response to a conditional request as
+// an unchanged, healthy page. Colly reports 304s as errors, so this runs
+// after the generic error handler and clears the error it recorded.
+func (c *Crawler) setupNotModifiedHandling(collyClone *colly.Collector, sent Validators) {
+	collyClone.OnError(func(r *colly.Response, _ error) {
+		if r == nil || r.Ctx == nil || r.StatusCode != http.StatusNotModified {
+			return
+		}
+		result, ok := r.Ctx.GetAny("result").(*CrawlResult)
+		if !ok {
+			return
+		}
+
+		if metricsVal, ok := c.metricsMap.LoadAndDelete(r.Request.URL.String()); ok {
+			result.Performance = *metricsVal.(*PerformanceMetrics)
+			result.TLS = result.Performance.tls
+		}
+
+		result.Error = ""
+		result.NotModified = true
+		if r.Headers != nil {
+			result.Headers = r.Headers.Clone()
+			detectors, ok := r.Ctx.GetAny("cache_detectors").(*DetectorRegistry)
+			if !ok {
+				detectors = defaultDetectors
+			}
+			detection := detectors.Detect(*r.Headers)
+			result.CacheStatus = detection.Status
+			result.CDNProvider = detection.Provider
+			result.CacheAge = detection.Age
+			result.CacheTTL = detection.TTL
+		}
+
+		// A 304 need not repeat the validators; the ones sent still apply
+		validators := responseValidators(result.Headers)
+		if validators.IsZero() {
+			validators = sent
+		}
+		result.ETag = validators.ETag
+		result.LastModified = validators.LastModified
+	})
+}
@@ -0,0 +1,89 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWarmURLConditionalRequest(t *testing.T) {
+	const etag = `"v1"`
+	const lastModified = "Mon, 02 Mar 2026 10:00:00 GMT"
+
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html><title>Home</title></html>"))
+	}))
+	defer ts.Close()
+
+	crawler := New(testConfig())
+
+	t.Run("full GET records validators", func(t *testing.T) {
+		result, err := crawler.WarmURL(context.Background(), ts.URL, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.NotModified {
+			t.Error("Expected a full response, got not modified")
+		}
+		if result.ETag != etag || result.LastModified != lastModified {
+			t.Errorf("Expected validators %q and %q, got %q and %q", etag, lastModified, result.ETag, result.LastModified)
+		}
+	})
+
+	t.Run("unchanged page returns 304", func(t *testing.T) {
+		requests = 0
+		ctx := WithConditionalRequest(context.Background(), Validators{ETag: etag, LastModified: lastModified})
+		result, err := crawler.WarmURL(ctx, ts.URL, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !result.NotModified || result.StatusCode != http.StatusNotModified {
+			t.Errorf("Expected not modified 304, got %d (not_modified=%v)", result.StatusCode, result.NotModified)
+		}
+		if result.Error != "" {
+			t.Errorf("Expected no error on result, got %q", result.Error)
+		}
+		if result.ETag != etag || result.LastModified != lastModified {
+			t.Errorf("Expected sent validators to carry over, got %q and %q", result.ETag, result.LastModified)
+		}
+		if requests != 1 {
+			t.Errorf("Expected a single request, got %d", requests)
+		}
+	})
+
+	t.Run("changed page returns full response", func(t *testing.T) {
+		ctx := WithConditionalRequest(context.Background(), Validators{ETag: `"v0"`})
+		result, err := crawler.WarmURL(ctx, ts.URL, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.NotModified || result.StatusCode != http.StatusOK {
+			t.Errorf("Expected full 200 response, got %d (not_modified=%v)", result.StatusCode, result.NotModified)
+		}
+		if result.ETag != etag {
+			t.Errorf("Expected new ETag %q, got %q", etag, result.ETag)
+		}
+	})
+}
+
+func TestSetConditionalHeaders(t *testing.T) {
+	headers := http.Header{}
+	setConditionalHeaders(&headers, Validators{LastModified: "Mon, 02 Mar 2026 10:00:00 GMT"})
+
+	if got := headers.Get("If-None-Match"); got != "" {
+		t.Errorf("Expected no If-None-Match without an ETag, got %q", got)
+	}
+	if got := headers.Get("If-Modified-Since"); got != "Mon, 02 Mar 2026 10:00:00 GMT" {
+		t.Errorf("Expected If-Modified-Since, got %q", got)
+	}
+}
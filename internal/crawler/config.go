@@ -21,6 +21,7 @@ type Config struct {
 	SentryDSN      string        // Sentry DSN for error tracking
 	FindLinks      bool          // Whether to extract links (e.g. PDFs/docs) from pages
 	SkipSSRFCheck  bool          // Skip SSRF protection (for tests only, never enable in production)
+	MaxRedirects   int           // Redirect hops to follow before flagging the chain as too long
 }
 
 // DefaultConfig returns a Config instance with default values
@@ -34,5 +35,6 @@ func DefaultConfig() *Config {
 		RetryDelay:     500 * time.Millisecond,
 		SkipCachedURLs: false, // Default to crawling all URLs
 		FindLinks:      false,
+		MaxRedirects:   10, // Matches net/http's default redirect limit
 	}
 }
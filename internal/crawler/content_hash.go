@@ -0,0 +1,193 @@
+package crawler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+	"golang.org/x/net/html"
+)
+
+// contentMainTextLimit caps the main content text kept for diffs, in bytes
+const contentMainTextLimit = 256 * 1024
+
+// contentHashAttributes are the attributes whose values count towards the
+// content hash as they are; these identify the published version of a page
+var contentHashAttributes = []string{"data-wf-page", "data-wf-site", "lang"}
+
+// contentReferenceAttributes are the attributes of body elements whose masked
+// values count towards the content hash, so a changed link target, image or
+// alt text is a change. Query strings are dropped from src, where they are
+// usually cache busters. Other attributes, such as nonces, are ignored.
+var contentReferenceAttributes = []string{"href", "src", "alt"}
+
+// volatileTimePattern matches machine timestamps rendered into page text (ISO
+// 8601 dates and times, and Unix timestamps), which can change on every
+// request without the content changing. Dates written for people are content.
+var volatileTimePattern = regexp.MustCompile(`(?i)` +
+	`\b\d{4}-\d{2}-\d{2}[t ]\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:z|[+-]\d{2}:?\d{2})?` +
+	`|\b1[5-9]\d{8}(?:\d{3})?\b`)
+
+// volatileTokenPattern matches long runs that may be nonces, session IDs or
+// hashes; only those mixing letters and digits are replaced
+var volatileTokenPattern = regexp.MustCompile(`[A-Za-z0-9+/_-]{20,}={0,2}`)
+
+// contentBlockElements start a new line in normalised text
+var contentBlockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true,
+	"caption": true, "dd": true, "div": true, "dl": true, "dt": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "table": true,
+	"td": true, "th": true, "tr": true, "ul": true,
+}
+
+// ContentSnapshot is the normalised content of an HTML page, used to tell
+// whether it changed between crawls
+type ContentSnapshot struct {
+	Hash     string // Hex SHA-256 of the normalised page
+	MainText string // Normalised text of the main content, one block per line
+}
+
+// snapshotContent normalises a parsed HTML document and hashes it. Scripts,
+// styles, <time> elements and attributes outside contentHashAttributes and
+// contentReferenceAttributes are dropped, and machine timestamps and
+// token-like strings are masked, so only changes a visitor would notice alter
+// the hash.
+func snapshotContent(doc *goquery.Selection) *ContentSnapshot {
+	var canonical strings.Builder
+
+	title := strings.Join(strings.Fields(doc.Find("title").First().Text()), " ")
+	canonical.WriteString("title:" + maskVolatile(title) + "\n")
+
+	for _, attr := range contentHashAttributes {
+		doc.Find("html, body").Each(func(_ int, s *goquery.Selection) {
+			if value, ok := s.Attr(attr); ok {
+				canonical.WriteString(attr + ":" + strings.TrimSpace(value) + "\n")
+			}
+		})
+		doc.Find("[" + attr + "]").Not("html, body").Each(func(_ int, s *goquery.Selection) {
+			canonical.WriteString(attr + ":" + strings.TrimSpace(s.AttrOr(attr, "")) + "\n")
+		})
+	}
+
+	body := doc.Find("body").First().Clone()
+	body.Find("script, style, noscript, template, svg, iframe, time").Remove()
+	for _, line := range textLines(body) {
+		canonical.WriteString(line + "\n")
+	}
+	for _, attr := range contentReferenceAttributes {
+		body.Find("[" + attr + "]").Each(func(_ int, s *goquery.Selection) {
+			value := strings.TrimSpace(s.AttrOr(attr, ""))
+			if attr == "src" {
+				value, _, _ = strings.Cut(value, "?")
+			}
+			canonical.WriteString(attr + ":" + maskVolatile(value) + "\n")
+		})
+	}
+
+	sum := sha256.Sum256([]byte(canonical.String()))
+	return &ContentSnapshot{
+		Hash:     hex.EncodeToString(sum[:]),
+		MainText: mainContentText(body),
+	}
+}
+
+// mainContentText returns the normalised text of a page's main content: its
+// <main> or sole <article> when it has one, otherwise the body without the
+// header, navigation, footer and sidebars
+func mainContentText(body *goquery.Selection) string {
+	main := body.Find("main, [role=main]").First()
+	if main.Length() == 0 {
+		if articles := body.Find("article"); articles.Length() == 1 {
+			main = articles
+		}
+	}
+	if main.Length() == 0 {
+		main = body.Clone()
+		main.Find("header, nav, footer, aside, [role=banner], [role=navigation], [role=contentinfo]").Remove()
+	}
+
+	text := strings.Join(textLines(main), "\n")
+	if len(text) > contentMainTextLimit {
+		text = strings.ToValidUTF8(text[:contentMainTextLimit], "")
+	}
+	return text
+}
+
+// textLines returns the masked, whitespace-collapsed text of a selection,
+// split into a line per block element
+func textLines(selection *goquery.Selection) []string {
+	var lines []string
+	var current strings.Builder
+	flush := func() {
+		if line := maskVolatile(strings.Join(strings.Fields(current.String()), " ")); line != "" {
+			lines = append(lines, line)
+		}
+		current.Reset()
+	}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			current.WriteString(n.Data)
+			current.WriteByte(' ')
+			return
+		case html.CommentNode:
+			return
+		}
+		block := n.Type == html.ElementNode && contentBlockElements[n.Data]
+		if block {
+			flush()
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if block {
+			flush()
+		}
+	}
+	for _, node := range selection.Nodes {
+		walk(node)
+	}
+	flush()
+	return lines
+}
+
+// maskVolatile replaces machine timestamps and token-like strings in text
+// with fixed placeholders
+func maskVolatile(text string) string {
+	text = volatileTimePattern.ReplaceAllString(text, "{time}")
+	return volatileTokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		var letters, digits bool
+		for _, r := range token {
+			letters = letters || unicode.IsLetter(r)
+			digits = digits || unicode.IsDigit(r)
+		}
+		if letters && digits {
+			return "{token}"
+		}
+		return token
+	})
+}
+
+// setupContentHash configures Colly HTML handler for hashing page content. It
+// must be registered before link extraction, which strips the header and
+// footer from the parsed document.
+func setupContentHash(collyClone *colly.Collector) {
+	collyClone.OnHTML("html", func(e *colly.HTMLElement) {
+		result, ok := e.Request.Ctx.GetAny("result").(*CrawlResult)
+		if !ok {
+			return
+		}
+		snapshot := snapshotContent(e.DOM)
+		result.ContentHash = snapshot.Hash
+		result.MainText = snapshot.MainText
+	})
+}
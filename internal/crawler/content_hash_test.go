@@ -0,0 +1,117 @@
+package crawler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func snapshotHTML(t *testing.T, page string) *ContentSnapshot {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("Failed to parse HTML: %v", err)
+	}
+	return snapshotContent(doc.Selection)
+}
+
+func TestSnapshotContentIgnoresVolatileMarkup(t *testing.T) {
+	first := snapshotHTML(t, `<html data-wf-page="abc"><head><title>Pricing</title>
+		<script nonce="r4nd0mN0nce1234567890">var t = 1700000000;</script></head>
+		<body><main><h1>Pricing</h1><p>Plans from $10</p><img src="/hero.png?v=101" alt="Plans">
+		<p>Updated 2026-03-02T10:00:00Z</p><time>2 hours ago</time></main>
+		<footer nonce="r4nd0mN0nce1234567890">Build 9f8e7d6c5b4a39281706</footer></body></html>`)
+	second := snapshotHTML(t, `<html data-wf-page="abc"><head><title>Pricing</title>
+		<script nonce="0therN0nce0987654321">var t = 1700000999;</script></head>
+		<body class="loaded"><main><h1>Pricing</h1><p>Plans   from $10</p><img src="/hero.png?v=102" alt="Plans">
+		<p>Updated 2026-03-05T16:30:12Z</p><time>5 minutes ago</time></main>
+		<footer nonce="0therN0nce0987654321">Build 1a2b3c4d5e6f70819203</footer></body></html>`)
+
+	if first.Hash != second.Hash {
+		t.Errorf("Expected identical hashes for pages differing only in volatile content, got %s and %s", first.Hash, second.Hash)
+	}
+}
+
+func TestSnapshotContentDetectsChanges(t *testing.T) {
+	base := snapshotHTML(t, `<html data-wf-page="abc"><body><main><p>Plans from $10</p>
+		<p>Offer ends 31 March 2026</p><a href="/signup">Sign up</a><img src="/hero.png" alt="Plans"></main></body></html>`)
+
+	tests := []struct {
+		name string
+		page string
+	}{
+		{"text", `<html data-wf-page="abc"><body><main><p>Plans from $12</p>
+			<p>Offer ends 31 March 2026</p><a href="/signup">Sign up</a><img src="/hero.png" alt="Plans"></main></body></html>`},
+		{"published version", `<html data-wf-page="def"><body><main><p>Plans from $10</p>
+			<p>Offer ends 31 March 2026</p><a href="/signup">Sign up</a><img src="/hero.png" alt="Plans"></main></body></html>`},
+		{"new block", `<html data-wf-page="abc"><body><main><p>Plans from $10</p><p>Now with support</p>
+			<p>Offer ends 31 March 2026</p><a href="/signup">Sign up</a><img src="/hero.png" alt="Plans"></main></body></html>`},
+		{"prose date", `<html data-wf-page="abc"><body><main><p>Plans from $10</p>
+			<p>Offer ends 30 April 2026</p><a href="/signup">Sign up</a><img src="/hero.png" alt="Plans"></main></body></html>`},
+		{"link target", `<html data-wf-page="abc"><body><main><p>Plans from $10</p>
+			<p>Offer ends 31 March 2026</p><a href="/register">Sign up</a><img src="/hero.png" alt="Plans"></main></body></html>`},
+		{"image", `<html data-wf-page="abc"><body><main><p>Plans from $10</p>
+			<p>Offer ends 31 March 2026</p><a href="/signup">Sign up</a><img src="/hero-2.png" alt="Plans"></main></body></html>`},
+		{"alt text", `<html data-wf-page="abc"><body><main><p>Plans from $10</p>
+			<p>Offer ends 31 March 2026</p><a href="/signup">Sign up</a><img src="/hero.png" alt="Pricing plans"></main></body></html>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snapshotHTML(t, tt.page); got.Hash == base.Hash {
+				t.Errorf("Expected hash to change")
+			}
+		})
+	}
+}
+
+func TestMainContentText(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		want string
+	}{
+		{
+			name: "main element",
+			page: `<body><nav>Home</nav><main><h1>Title</h1><p>First <b>para</b></p></main><footer>Contact</footer></body>`,
+			want: "Title\nFirst para",
+		},
+		{
+			name: "single article",
+			page: `<body><header>Site</header><article><p>Story</p></article></body>`,
+			want: "Story",
+		},
+		{
+			name: "body without chrome",
+			page: `<body><header>Site</header><div>Body<br>text</div><aside>Related</aside><footer>Contact</footer></body>`,
+			want: "Body\ntext",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snapshotHTML(t, tt.page).MainText; got != tt.want {
+				t.Errorf("Expected main text %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMaskVolatile(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Generated 2026-10-17T09:30:00Z", "Generated {time}"},
+		{"Rendered at 1792222200", "Rendered at {time}"},
+		{"Posted 17 October 2026 at 9:30am", "Posted 17 October 2026 at 9:30am"},
+		{"Due 17/10/2026", "Due 17/10/2026"},
+		{"Session a1b2c3d4e5f6a7b8c9d0e1", "Session {token}"},
+		{"internationalisationisms stay", "internationalisationisms stay"},
+		{"Call 0400 123 456", "Call 0400 123 456"},
+	}
+	for _, tt := range tests {
+		if got := maskVolatile(tt.in); got != tt.want {
+			t.Errorf("maskVolatile(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -179,11 +179,21 @@ func (t *tracingRoundTripper) RoundTrip(req *http.Request) (*http.Response, erro
 	// Store metrics for this URL (will be retrieved in OnResponse)
 	t.metricsMap.Store(req.URL.String(), metrics)
 
+	// Mark the start of this hop when the request is part of a tracked redirect chain
+	if rec := redirectRecorderFrom(req.Context()); rec != nil {
+		rec.startHop()
+	}
+
 	// Attach trace to request context
 	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
 
-	// Perform the request
-	return t.transport.RoundTrip(req)
+	// Perform the request, keeping the negotiated TLS state; unlike the
+	// handshake trace it is also available on reused connections
+	resp, err := t.transport.RoundTrip(req)
+	if err == nil && resp != nil {
+		metrics.tls = summariseTLS(resp.TLS)
+	}
+	return resp, err
 }
 
 // New creates a new Crawler instance with the given configuration and optional ID
@@ -275,12 +285,18 @@ func New(config *Config, id ...string) *Crawler {
 
 	// Note: OnHTML handler will be registered on the clone in WarmURL to ensure proper context access
 
-	return &Crawler{
+	crawler := &Crawler{
 		config:     config,
 		colly:      c,
 		id:         crawlerID,
 		metricsMap: metricsMap,
 	}
+
+	// Record every redirect hop and detect loops/over-long chains.
+	// Must be set after SetClient, which replaces the client's CheckRedirect.
+	c.SetRedirectHandler(crawler.checkRedirect)
+
+	return crawler
 }
 
 // isPrivateOrLocalIP checks if an IP address is in a private, loopback, or link-local range.
@@ -393,6 +409,7 @@ func (c *Crawler) setupResponseHandlers(collyClone *colly.Collector, result *Cra
 				performanceMetrics.ContentTransferTime = time.Since(startTime).Milliseconds() - performanceMetrics.TTFB
 			}
 			result.Performance = *performanceMetrics
+			result.TLS = performanceMetrics.tls
 		}
 
 		// Calculate response time
@@ -402,6 +419,9 @@ func (c *Crawler) setupResponseHandlers(collyClone *colly.Collector, result *Cra
 		result.ContentLength = int64(len(r.Body))
 		result.Headers = r.Headers.Clone()
 		result.RedirectURL = r.Request.URL.String()
+		validators := responseValidators(result.Headers)
+		result.ETag = validators.ETag
+		result.LastModified = validators.LastModified
 
 		// Store body for tech detection and storage upload
 		// BodySample is truncated for wappalyzer detection, Body is the full content
@@ -431,34 +451,15 @@ func (c *Crawler) setupResponseHandlers(collyClone *colly.Collector, result *Cra
 
 		// Check for cache status headers from different CDNs
 		// Normalise all values to standard HIT/MISS/BYPASS format
-		// Cloudflare
-		if cacheStatus := r.Headers.Get("CF-Cache-Status"); cacheStatus != "" {
-			result.CacheStatus = normaliseCacheStatus(cacheStatus)
-		}
-		// CloudFront/Fastly (X-Cache: "Miss from cloudfront", "HIT", etc.)
-		if cacheStatus := r.Headers.Get("X-Cache"); cacheStatus != "" && result.CacheStatus == "" {
-			result.CacheStatus = normaliseCacheStatus(cacheStatus)
-		}
-		// Akamai (X-Cache-Remote: "TCP_HIT", "TCP_MISS", etc.)
-		if cacheStatus := r.Headers.Get("X-Cache-Remote"); cacheStatus != "" && result.CacheStatus == "" {
-			result.CacheStatus = normaliseCacheStatus(cacheStatus)
-		}
-		// Vercel
-		if cacheStatus := r.Headers.Get("x-vercel-cache"); cacheStatus != "" && result.CacheStatus == "" {
-			result.CacheStatus = normaliseCacheStatus(cacheStatus)
-		}
-		// Standard Cache-Status header (newer standardised approach)
-		if cacheStatus := r.Headers.Get("Cache-Status"); cacheStatus != "" && result.CacheStatus == "" {
-			result.CacheStatus = normaliseCacheStatus(cacheStatus)
-		}
-		// Varnish (the presence of X-Varnish indicates it was processed by Varnish)
-		if varnishID := r.Headers.Get("X-Varnish"); varnishID != "" && result.CacheStatus == "" {
-			if strings.Contains(varnishID, " ") {
-				result.CacheStatus = "HIT" // Multiple IDs indicate a cache hit
-			} else {
-				result.CacheStatus = "MISS" // Single ID indicates a cache miss
-			}
+		detectors, ok := r.Ctx.GetAny("cache_detectors").(*DetectorRegistry)
+		if !ok {
+			detectors = defaultDetectors
 		}
+		detection := detectors.Detect(*r.Headers)
+		result.CacheStatus = detection.Status
+		result.CDNProvider = detection.Provider
+		result.CacheAge = detection.Age
+		result.CacheTTL = detection.TTL
 
 		// Set error for non-2xx status codes (to match test expectations)
 		if r.StatusCode < 200 || r.StatusCode >= 300 {
@@ -683,6 +684,12 @@ func setupLinkExtraction(collyClone *colly.Collector) {
 				}
 
 				result.Links[category] = append(result.Links[category], u)
+				if result.SEO != nil && hasRelToken(s.AttrOr("rel", ""), "nofollow") {
+					if result.SEO.NofollowLinks == nil {
+						result.SEO.NofollowLinks = make(map[string][]string)
+					}
+					result.SEO.NofollowLinks[category] = append(result.SEO.NofollowLinks[category], u)
+				}
 			})
 		}
 
@@ -707,13 +714,13 @@ func setupLinkExtraction(collyClone *colly.Collector) {
 }
 
 // executeCollyRequest performs the HTTP request using Colly with context cancellation support
-func executeCollyRequest(ctx context.Context, collyClone *colly.Collector, targetURL string, res *CrawlResult) error {
+func executeCollyRequest(ctx context.Context, collyClone *colly.Collector, method, targetURL string, res *CrawlResult) error {
 	// Set up context cancellation handling
 	done := make(chan error, 1)
 
 	// Visit the URL with Colly in a goroutine to support context cancellation
 	go func() {
-		visitErr := collyClone.Visit(targetURL)
+		visitErr := collyClone.Request(method, targetURL, nil, nil, nil)
 		if visitErr != nil {
 			done <- visitErr
 			return
@@ -747,6 +754,48 @@ func executeCollyRequest(ctx context.Context, collyClone *colly.Collector, targe
 	}
 }
 
+// fetchWithHeaders performs a single lightweight request with the given
+// method and headers, recording status, cache headers, timing and redirects.
+// No links are extracted and no cache validation is performed.
+func (c *Crawler) fetchWithHeaders(ctx context.Context, method, targetURL string, headers http.Header) (*CrawlResult, error) {
+	start := time.Now()
+	res := &CrawlResult{
+		URL:       targetURL,
+		Timestamp: start.Unix(),
+	}
+
+	collyClone := c.colly.Clone()
+
+	redirects := &redirectRecorder{}
+	collyClone.Context = withRedirectRecorder(collyClone.Context, redirects)
+	detectors := cacheDetectorsFrom(ctx)
+
+	collyClone.OnRequest(func(r *colly.Request) {
+		r.Ctx.Put("result", res)
+		r.Ctx.Put("start_time", start)
+		r.Ctx.Put("cache_detectors", detectors)
+		for name, values := range headers {
+			(*r.Headers)[name] = values
+		}
+	})
+
+	c.setupResponseHandlers(collyClone, res, start, targetURL)
+
+	err := executeCollyRequest(ctx, collyClone, method, targetURL, res)
+	redirects.applyTo(res)
+	if err != nil && res.StatusCode == 0 {
+		return res, err
+	}
+
+	if res.RedirectLoop {
+		res.Error = fmt.Sprintf("redirect loop detected after %d hops", len(res.RedirectChain))
+	} else if res.RedirectLimitHit {
+		res.Error = fmt.Sprintf("redirect limit of %d hops exceeded", len(res.RedirectChain))
+	}
+
+	return res, nil
+}
+
 // WarmURL performs a crawl of the specified URL and returns the result.
 // It respects context cancellation, enforces timeout, and treats non-2xx statuses as errors.
 func (c *Crawler) WarmURL(ctx context.Context, targetURL string, findLinks bool) (*CrawlResult, error) {
@@ -773,24 +822,56 @@ func (c *Crawler) WarmURL(ctx context.Context, targetURL string, findLinks bool)
 	// Use Colly for everything - single request handles cache warming and link extraction
 	collyClone := c.colly.Clone()
 
-	// Set up link extraction
+	// Track redirect hops for this request via its context
+	redirects := &redirectRecorder{}
+	collyClone.Context = withRedirectRecorder(collyClone.Context, redirects)
+
+	// Set up asset, SEO, page audit, structured data, insecure content, anchor,
+	// soft-404 fingerprint and link extraction. Everything else is collected first because link
+	// extraction strips the header and footer from the parsed document.
+	findAssets := assetExtractionEnabled(ctx)
+	setupAssetExtraction(collyClone)
+	setupSEOExtraction(collyClone)
+	setupPageAudit(collyClone)
+	setupStructuredDataExtraction(collyClone)
+	setupInsecureContentDetection(collyClone)
+	setupAnchorExtraction(collyClone)
+	setupSoft404Fingerprint(collyClone)
+	setupContentHash(collyClone)
 	setupLinkExtraction(collyClone)
+	detectors := cacheDetectorsFrom(ctx)
+	validators := conditionalRequestFrom(ctx)
 
 	// Set up timing and result collection
 	collyClone.OnRequest(func(r *colly.Request) {
 		r.Ctx.Put("result", res)
 		r.Ctx.Put("start_time", start)
 		r.Ctx.Put("find_links", findLinks)
+		r.Ctx.Put("find_assets", findAssets)
+		r.Ctx.Put("cache_detectors", detectors)
+		setConditionalHeaders(r.Headers, validators)
 	})
 
 	// Set up response and error handlers
 	c.setupResponseHandlers(collyClone, res, start, targetURL)
+	if !validators.IsZero() {
+		c.setupNotModifiedHandling(collyClone, validators)
+	}
 
 	// Execute the HTTP request
-	if err := executeCollyRequest(ctx, collyClone, targetURL, res); err != nil {
+	err = executeCollyRequest(ctx, collyClone, http.MethodGet, targetURL, res)
+	redirects.applyTo(res)
+	if err != nil {
 		return res, err
 	}
 
+	// Replace the generic 3xx status error with the reason we stopped following
+	if res.RedirectLoop {
+		res.Error = fmt.Sprintf("redirect loop detected after %d hops", len(res.RedirectChain))
+	} else if res.RedirectLimitHit {
+		res.Error = fmt.Sprintf("redirect limit of %d hops exceeded", len(res.RedirectChain))
+	}
+
 	// Log results and return error if needed
 	if res.Error != "" {
 		if res.StatusCode < 200 || res.StatusCode >= 300 {
@@ -810,11 +891,26 @@ func (c *Crawler) WarmURL(ctx context.Context, targetURL string, findLinks bool)
 		return res, fmt.Errorf("%s", res.Error)
 	}
 
+	// An unchanged page is already cached by the client; there is nothing
+	// to validate or warm
+	if res.NotModified {
+		log.Debug().
+			Str("url", targetURL).
+			Dur("duration_ms", time.Duration(res.ResponseTime)*time.Millisecond).
+			Msg("URL not modified since last crawl")
+		return res, nil
+	}
+
 	// Perform cache validation and warming
 	if err := c.performCacheValidation(ctx, targetURL, res); err != nil {
 		return res, err
 	}
 
+	// Warm any additional device/encoding/language variants requested by the job
+	if variants := warmVariantsFrom(ctx); len(variants) > 0 {
+		res.Variants = c.warmVariants(ctx, targetURL, variants)
+	}
+
 	return res, nil
 }
 
@@ -833,7 +929,12 @@ func shouldMakeSecondRequest(cacheStatus string) bool {
 // makeSecondRequest performs a second request to verify cache warming
 // Reuses the main WarmURL logic but disables link extraction
 func (c *Crawler) makeSecondRequest(ctx context.Context, targetURL string) (*CrawlResult, error) {
-	// Reuse the main WarmURL method but disable link extraction
+	// Reuse the main WarmURL method but disable link and asset extraction,
+	// variant warming and conditional requests, which only apply to the
+	// primary request
+	ctx = context.WithValue(ctx, assetExtractionKey{}, false)
+	ctx = context.WithValue(ctx, warmVariantsKey{}, []WarmVariant(nil))
+	ctx = context.WithValue(ctx, conditionalRequestKey{}, Validators{})
 	return c.WarmURL(ctx, targetURL, false)
 }
 
@@ -868,7 +969,7 @@ func (c *Crawler) CheckCacheStatus(ctx context.Context, targetURL string) (strin
 	}
 	defer resp.Body.Close()
 
-	return resp.Header.Get("CF-Cache-Status"), nil
+	return cacheDetectorsFrom(ctx).Detect(resp.Header).Status, nil
 }
 
 // CreateHTTPClient returns a configured HTTP client with SSRF protection
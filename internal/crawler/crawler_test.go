@@ -168,6 +168,120 @@ func TestWarmURLWithDifferentStatuses(t *testing.T) {
 	}
 }
 
+func TestWarmURLRecordsRedirectChain(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/interim", http.StatusMovedPermanently)
+		case "/interim":
+			http.Redirect(w, r, "/new", http.StatusFound)
+		default:
+			w.Header().Set("CF-Cache-Status", "HIT")
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer ts.Close()
+
+	crawler := New(testConfig())
+	result, err := crawler.WarmURL(context.Background(), ts.URL+"/old", false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.RedirectChain) != 2 {
+		t.Fatalf("Expected 2 redirect hops, got %d", len(result.RedirectChain))
+	}
+	first, second := result.RedirectChain[0], result.RedirectChain[1]
+	if first.URL != ts.URL+"/old" || first.StatusCode != http.StatusMovedPermanently || first.Location != "/interim" {
+		t.Errorf("Unexpected first hop: %+v", first)
+	}
+	if second.URL != ts.URL+"/interim" || second.StatusCode != http.StatusFound || second.Location != "/new" {
+		t.Errorf("Unexpected second hop: %+v", second)
+	}
+	if result.RedirectURL != ts.URL+"/new" {
+		t.Errorf("Expected final URL %s, got %s", ts.URL+"/new", result.RedirectURL)
+	}
+	if result.RedirectLoop || result.RedirectLimitHit {
+		t.Errorf("Expected no loop or limit flags, got loop=%v limit=%v", result.RedirectLoop, result.RedirectLimitHit)
+	}
+}
+
+func TestWarmURLDetectsRedirectLoop(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/a" {
+			http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+			return
+		}
+		http.Redirect(w, r, "/a", http.StatusMovedPermanently)
+	}))
+	defer ts.Close()
+
+	crawler := New(testConfig())
+	result, err := crawler.WarmURL(context.Background(), ts.URL+"/a", false)
+	if err == nil {
+		t.Fatal("Expected error for redirect loop")
+	}
+
+	if !result.RedirectLoop {
+		t.Error("Expected redirect loop to be flagged")
+	}
+	if len(result.RedirectChain) != 2 {
+		t.Errorf("Expected 2 hops before the loop was detected, got %d", len(result.RedirectChain))
+	}
+	if !strings.Contains(err.Error(), "redirect loop") {
+		t.Errorf("Expected redirect loop error, got %v", err)
+	}
+}
+
+func TestWarmURLFlagsRedirectLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every hop goes somewhere new, so only the limit stops it
+		http.Redirect(w, r, r.URL.Path+"x", http.StatusMovedPermanently)
+	}))
+	defer ts.Close()
+
+	cfg := testConfig()
+	cfg.MaxRedirects = 3
+	crawler := New(cfg)
+	result, err := crawler.WarmURL(context.Background(), ts.URL+"/", false)
+	if err == nil {
+		t.Fatal("Expected error when redirect limit is exceeded")
+	}
+
+	if !result.RedirectLimitHit {
+		t.Error("Expected redirect limit to be flagged")
+	}
+	if len(result.RedirectChain) != 3 {
+		t.Errorf("Expected 3 recorded hops, got %d", len(result.RedirectChain))
+	}
+	if result.StatusCode != http.StatusMovedPermanently {
+		t.Errorf("Expected last redirect status to be reported, got %d", result.StatusCode)
+	}
+}
+
+func TestCheckRedirectEnforcesDomainFilters(t *testing.T) {
+	crawler := New(testConfig())
+	crawler.colly.AllowedDomains = []string{"example.com", "www.example.com"}
+	crawler.colly.DisallowedDomains = []string{"www.example.com"}
+
+	via := []*http.Request{httptest.NewRequest(http.MethodGet, "https://example.com/old", nil)}
+	tests := []struct {
+		target  string
+		allowed bool
+	}{
+		{"https://example.com/new", true},
+		{"https://www.example.com/new", false},
+		{"https://evil.test/new", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		err := crawler.checkRedirect(req, via)
+		if (err == nil) != tt.allowed {
+			t.Errorf("redirect to %s: expected allowed=%v, got err=%v", tt.target, tt.allowed, err)
+		}
+	}
+}
+
 func TestWarmURLContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	crawler := New(testConfig())
@@ -0,0 +1,45 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// linkCheckHeaders are sent when checking an external link
+var linkCheckHeaders = http.Header{"Accept": []string{"text/html,*/*;q=0.8"}}
+
+// CheckLink checks that a link to another site resolves, without warming it
+// or extracting anything from it. It sends a HEAD request and only falls back
+// to a single GET when HEAD fails or reports an error status, as many origins
+// reject or mishandle HEAD. Returns an error when the link is broken.
+func (c *Crawler) CheckLink(ctx context.Context, targetURL string) (*CrawlResult, error) {
+	if _, err := validateCrawlRequest(ctx, targetURL, c.config.SkipSSRFCheck); err != nil {
+		res := &CrawlResult{URL: targetURL, Timestamp: time.Now().Unix(), Error: err.Error()}
+		return res, err
+	}
+
+	res, err := c.fetchWithHeaders(ctx, http.MethodHead, targetURL, linkCheckHeaders)
+	if err != nil || res.StatusCode >= http.StatusBadRequest || res.StatusCode == 0 {
+		getRes, getErr := c.fetchWithHeaders(ctx, http.MethodGet, targetURL, linkCheckHeaders)
+		if getErr != nil {
+			return getRes, getErr
+		}
+		res = getRes
+	}
+	res.Body = nil
+	res.BodySample = nil
+
+	if res.Error != "" {
+		log.Debug().
+			Int("status", res.StatusCode).
+			Str("url", targetURL).
+			Str("error", res.Error).
+			Msg("External link check returned non-success status")
+		return res, fmt.Errorf("%s", res.Error)
+	}
+	return res, nil
+}
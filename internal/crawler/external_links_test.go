@@ -0,0 +1,51 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckLinkFallsBackToGetWhenHeadRejected(t *testing.T) {
+	var methods []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer ts.Close()
+
+	crawler := New(testConfig())
+	result, err := crawler.CheckLink(context.Background(), ts.URL+"/page")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", result.StatusCode)
+	}
+	if len(result.Body) != 0 {
+		t.Error("Expected body to be discarded")
+	}
+	if len(methods) != 2 || methods[0] != http.MethodHead || methods[1] != http.MethodGet {
+		t.Errorf("Expected HEAD then GET, got %v", methods)
+	}
+}
+
+func TestCheckLinkReportsBrokenLink(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	crawler := New(testConfig())
+	result, err := crawler.CheckLink(context.Background(), ts.URL+"/missing")
+	if err == nil {
+		t.Fatal("Expected error for broken link")
+	}
+	if result.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", result.StatusCode)
+	}
+}
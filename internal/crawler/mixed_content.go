@@ -0,0 +1,130 @@
+package crawler
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// MaxInsecureReferences caps the insecure references stored per page. It is
+// applied by the worker once links to other sites have been dropped, so the
+// crawler records every distinct reference.
+const MaxInsecureReferences = 200
+
+// Kinds of insecure reference found on an HTTPS page
+const (
+	InsecureMixedContent = "mixed_content" // http:// subresource or form action
+	InsecureLink         = "insecure_link" // <a> pointing at an http:// URL
+)
+
+// InsecureReference is an http:// URL referenced from an HTTPS page
+type InsecureReference struct {
+	URL     string `json:"url"`
+	Element string `json:"element"` // e.g. "img", "script", "stylesheet", "form", "a"
+	Kind    string `json:"kind"`
+}
+
+// mixedContentSelectors maps subresource elements to the attributes that load
+// them; srcset attributes hold several candidate URLs
+var mixedContentSelectors = []struct {
+	selector string
+	attrs    []string
+}{
+	{"img", []string{"src", "srcset"}},
+	{"source", []string{"src", "srcset"}},
+	{"script[src]", []string{"src"}},
+	{"iframe[src]", []string{"src"}},
+	{"video", []string{"src", "poster"}},
+	{"audio[src]", []string{"src"}},
+	{"track[src]", []string{"src"}},
+	{"embed[src]", []string{"src"}},
+	{"object[data]", []string{"data"}},
+	{"link[href]", []string{"href"}},
+	{"form[action]", []string{"action"}},
+}
+
+// mixedContentElement names the element type recorded for a reference; link
+// elements are named by their rel so stylesheets stand out from icons
+func mixedContentElement(s *goquery.Selection) string {
+	name := goquery.NodeName(s)
+	if name != "link" {
+		return name
+	}
+	for rel := range strings.FieldsSeq(strings.ToLower(s.AttrOr("rel", ""))) {
+		if assetLinkRels[rel] {
+			return rel
+		}
+	}
+	return ""
+}
+
+// isInsecureURL reports whether a resolved URL is loaded over plain HTTP
+func isInsecureURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && strings.EqualFold(u.Scheme, "http")
+}
+
+// setupInsecureContentDetection configures Colly HTML handler for flagging
+// http:// subresources, form actions and links on HTTPS pages. Links are
+// recorded whatever their host; the worker keeps only those internal to the
+// job. It must be registered before link extraction, which strips the header
+// and footer from the parsed document.
+func setupInsecureContentDetection(collyClone *colly.Collector) {
+	collyClone.OnHTML("html", func(e *colly.HTMLElement) {
+		if !strings.EqualFold(e.Request.URL.Scheme, "https") {
+			return
+		}
+
+		result, ok := e.Request.Ctx.GetAny("result").(*CrawlResult)
+		if !ok {
+			return
+		}
+
+		seen := make(map[InsecureReference]bool)
+		record := func(ref, element, kind string) {
+			ref = strings.TrimSpace(ref)
+			if element == "" || !isFetchableAssetRef(ref) {
+				return
+			}
+			u := e.Request.AbsoluteURL(ref)
+			if !isInsecureURL(u) {
+				return
+			}
+			item := InsecureReference{URL: u, Element: element, Kind: kind}
+			if seen[item] {
+				return
+			}
+			seen[item] = true
+			result.InsecureContent = append(result.InsecureContent, item)
+		}
+
+		for _, sel := range mixedContentSelectors {
+			e.DOM.Find(sel.selector).Each(func(i int, s *goquery.Selection) {
+				element := mixedContentElement(s)
+				for _, attr := range sel.attrs {
+					if attr == "srcset" {
+						for _, candidate := range parseSrcset(s.AttrOr(attr, "")) {
+							record(candidate, element, InsecureMixedContent)
+						}
+						continue
+					}
+					record(s.AttrOr(attr, ""), element, InsecureMixedContent)
+				}
+			})
+		}
+
+		e.DOM.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+			record(s.AttrOr("href", ""), "a", InsecureLink)
+		})
+
+		if len(result.InsecureContent) > 0 {
+			log.Debug().
+				Str("url", e.Request.URL.String()).
+				Int("insecure_references", len(result.InsecureContent)).
+				Msg("Found insecure references on HTTPS page")
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package crawler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gocolly/colly/v2"
+)
+
+func TestInsecureContentDetection(t *testing.T) {
+	page := `<html><head>
+		<link rel="stylesheet" href="http://cdn.example.com/site.css">
+		<link rel="canonical" href="http://example.com/">
+		<script src="//cdn.example.com/app.js"></script>
+	</head><body>
+		<header><a href="http://example.com/about">About</a></header>
+		<img src="/logo.png" srcset="http://example.com/logo@2x.png 2x, /logo@3x.png 3x">
+		<iframe src="http://video.example.net/embed/1"></iframe>
+		<form action="http://example.com/subscribe"></form>
+		<a href="https://example.com/secure">Secure</a>
+		<a href="http://example.com/about">About again</a>
+		<a href="http://other.example.org/">Elsewhere</a>
+	</body></html>`
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(page))
+	}))
+	defer ts.Close()
+
+	result := &CrawlResult{}
+	collector := colly.NewCollector()
+	collector.WithTransport(ts.Client().Transport)
+	setupInsecureContentDetection(collector)
+	collector.OnRequest(func(r *colly.Request) {
+		r.Ctx.Put("result", result)
+	})
+	if err := collector.Visit(ts.URL + "/"); err != nil {
+		t.Fatalf("Visit failed: %v", err)
+	}
+
+	want := []InsecureReference{
+		{URL: "http://example.com/logo@2x.png", Element: "img", Kind: InsecureMixedContent},
+		{URL: "http://video.example.net/embed/1", Element: "iframe", Kind: InsecureMixedContent},
+		{URL: "http://cdn.example.com/site.css", Element: "stylesheet", Kind: InsecureMixedContent},
+		{URL: "http://example.com/subscribe", Element: "form", Kind: InsecureMixedContent},
+		{URL: "http://example.com/about", Element: "a", Kind: InsecureLink},
+		{URL: "http://other.example.org/", Element: "a", Kind: InsecureLink},
+	}
+	if !slices.Equal(result.InsecureContent, want) {
+		t.Errorf("Unexpected insecure references:\n got %+v\nwant %+v", result.InsecureContent, want)
+	}
+}
+
+func TestInsecureContentDetectionSkipsPlainHTTPPages(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><body><img src="http://example.com/a.png"></body></html>`))
+	}))
+	defer ts.Close()
+
+	result := &CrawlResult{}
+	collector := colly.NewCollector()
+	setupInsecureContentDetection(collector)
+	collector.OnRequest(func(r *colly.Request) {
+		r.Ctx.Put("result", result)
+	})
+	if err := collector.Visit(ts.URL + "/"); err != nil {
+		t.Fatalf("Visit failed: %v", err)
+	}
+
+	if len(result.InsecureContent) != 0 {
+		t.Errorf("Expected no findings on an HTTP page, got %+v", result.InsecureContent)
+	}
+}
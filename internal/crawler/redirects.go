@@ -0,0 +1,167 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// defaultMaxRedirects mirrors net/http's limit when Config.MaxRedirects is unset
+const defaultMaxRedirects = 10
+
+type redirectRecorderKey struct{}
+
+// redirectRecorder collects the redirect hops followed by a single WarmURL call.
+// It travels on the request context so concurrent crawls sharing the
+// collector's HTTP client each record their own chain.
+type redirectRecorder struct {
+	mu       sync.Mutex
+	hops     []RedirectHop
+	hopStart time.Time
+	loop     bool
+	limitHit bool
+}
+
+func withRedirectRecorder(ctx context.Context, rec *redirectRecorder) context.Context {
+	return context.WithValue(ctx, redirectRecorderKey{}, rec)
+}
+
+func redirectRecorderFrom(ctx context.Context) *redirectRecorder {
+	rec, _ := ctx.Value(redirectRecorderKey{}).(*redirectRecorder)
+	return rec
+}
+
+// startHop marks the start of a request in the chain; called from the transport
+func (r *redirectRecorder) startHop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hopStart = time.Now()
+}
+
+func (r *redirectRecorder) recordHop(resp *http.Response) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hop := RedirectHop{
+		StatusCode: resp.StatusCode,
+		Location:   resp.Header.Get("Location"),
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		hop.URL = resp.Request.URL.String()
+	}
+	if !r.hopStart.IsZero() {
+		hop.Duration = time.Since(r.hopStart).Milliseconds()
+	}
+	r.hops = append(r.hops, hop)
+}
+
+// applyTo copies the recorded chain onto a crawl result
+func (r *redirectRecorder) applyTo(res *CrawlResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.hops) > 0 {
+		res.RedirectChain = append([]RedirectHop(nil), r.hops...)
+	}
+	res.RedirectLoop = r.loop
+	res.RedirectLimitHit = r.limitHit
+}
+
+// checkRedirect is the collector's redirect policy. It records each hop,
+// refuses hops the collector's domain and URL filters do not allow, stops at
+// the first revisited URL (a loop) and stops once the chain reaches
+// MaxRedirects. Stopping returns the last redirect response as the result so
+// the chain is still reported rather than surfacing a generic client error.
+func (c *Crawler) checkRedirect(req *http.Request, via []*http.Request) error {
+	rec := redirectRecorderFrom(req.Context())
+
+	if req.Response != nil {
+		if rec != nil {
+			rec.recordHop(req.Response)
+		}
+		// Intermediate hops never reach OnResponse, so drop their trace metrics here
+		if req.Response.Request != nil {
+			c.metricsMap.Delete(req.Response.Request.URL.String())
+		}
+	}
+
+	// Replacing colly's redirect policy must not let a redirect leave the
+	// domains the collector is allowed to crawl
+	if err := c.checkRedirectFilters(req.URL); err != nil {
+		return fmt.Errorf("not following redirect to %q: %w", req.URL, err)
+	}
+
+	for _, prev := range via {
+		if sameRedirectTarget(prev.URL, req.URL) {
+			if rec != nil {
+				rec.mu.Lock()
+				rec.loop = true
+				rec.mu.Unlock()
+			}
+			return http.ErrUseLastResponse
+		}
+	}
+
+	maxRedirects := c.config.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = defaultMaxRedirects
+	}
+	if len(via) >= maxRedirects {
+		if rec != nil {
+			rec.mu.Lock()
+			rec.limitHit = true
+			rec.mu.Unlock()
+		}
+		return http.ErrUseLastResponse
+	}
+
+	// Don't forward credentials to a different host
+	if req.URL.Host != via[len(via)-1].URL.Host {
+		req.Header.Del("Authorization")
+	}
+
+	return nil
+}
+
+// checkRedirectFilters applies the collector's AllowedDomains,
+// DisallowedDomains, URLFilters and DisallowedURLFilters to a redirect target
+func (c *Crawler) checkRedirectFilters(target *url.URL) error {
+	if c.colly == nil || target == nil {
+		return nil
+	}
+	raw := []byte(target.String())
+	if slices.ContainsFunc(c.colly.DisallowedURLFilters, func(re *regexp.Regexp) bool { return re.Match(raw) }) {
+		return colly.ErrForbiddenURL
+	}
+	if len(c.colly.URLFilters) > 0 && !slices.ContainsFunc(c.colly.URLFilters, func(re *regexp.Regexp) bool { return re.Match(raw) }) {
+		return colly.ErrNoURLFiltersMatch
+	}
+
+	host := target.Hostname()
+	if slices.Contains(c.colly.DisallowedDomains, host) {
+		return colly.ErrForbiddenDomain
+	}
+	if len(c.colly.AllowedDomains) > 0 && !slices.Contains(c.colly.AllowedDomains, host) {
+		return colly.ErrForbiddenDomain
+	}
+	return nil
+}
+
+// sameRedirectTarget compares two URLs ignoring fragments, which are never
+// sent to the server and so cannot break a loop
+func sameRedirectTarget(a, b *url.URL) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	ac, bc := *a, *b
+	ac.Fragment, bc.Fragment = "", ""
+	ac.RawFragment, bc.RawFragment = "", ""
+	return ac.String() == bc.String()
+}
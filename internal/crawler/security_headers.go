@@ -0,0 +1,186 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// minHSTSMaxAge is the shortest HSTS max-age graded as a pass (180 days)
+const minHSTSMaxAge = 180 * 24 * 60 * 60
+
+// Security header check outcomes
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// SecurityHeaderCheck is the outcome of checking one security header
+type SecurityHeaderCheck struct {
+	Header string `json:"header"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// SecurityHeaderGrade grades a response's security headers. Each of the five
+// checks scores 20 for a pass and 10 for a warning.
+type SecurityHeaderGrade struct {
+	Grade  string                `json:"grade"` // A to F
+	Score  int                   `json:"score"` // 0 to 100
+	Checks []SecurityHeaderCheck `json:"checks"`
+}
+
+// SecurityGradeForScore maps a 0-100 score to a letter grade
+func SecurityGradeForScore(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 70:
+		return "B"
+	case score >= 50:
+		return "C"
+	case score >= 30:
+		return "D"
+	}
+	return "F"
+}
+
+// cspDirective returns the value of a directive in a Content-Security-Policy
+func cspDirective(policy, name string) (string, bool) {
+	for directive := range strings.SplitSeq(policy, ";") {
+		fields := strings.Fields(directive)
+		if len(fields) > 0 && strings.EqualFold(fields[0], name) {
+			return strings.Join(fields[1:], " "), true
+		}
+	}
+	return "", false
+}
+
+func checkHSTS(headers http.Header) SecurityHeaderCheck {
+	check := SecurityHeaderCheck{Header: "Strict-Transport-Security"}
+	value := headers.Get(check.Header)
+	if value == "" {
+		check.Status, check.Detail = CheckFail, "missing"
+		return check
+	}
+
+	maxAge := -1
+	for directive := range strings.SplitSeq(value, ";") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if strings.EqualFold(name, "max-age") {
+			if n, err := strconv.Atoi(strings.Trim(arg, `"`)); err == nil {
+				maxAge = n
+			}
+		}
+	}
+
+	switch {
+	case maxAge < 0:
+		check.Status, check.Detail = CheckFail, "no valid max-age"
+	case maxAge < minHSTSMaxAge:
+		check.Status, check.Detail = CheckWarn, fmt.Sprintf("max-age %d is under 180 days", maxAge)
+	default:
+		check.Status = CheckPass
+	}
+	return check
+}
+
+func checkCSP(headers http.Header) SecurityHeaderCheck {
+	check := SecurityHeaderCheck{Header: "Content-Security-Policy"}
+	policy := headers.Get(check.Header)
+	if policy == "" {
+		if headers.Get("Content-Security-Policy-Report-Only") != "" {
+			check.Status, check.Detail = CheckWarn, "report-only"
+		} else {
+			check.Status, check.Detail = CheckFail, "missing"
+		}
+		return check
+	}
+
+	scripts, ok := cspDirective(policy, "script-src")
+	if !ok {
+		scripts, ok = cspDirective(policy, "default-src")
+	}
+	switch {
+	case !ok:
+		check.Status, check.Detail = CheckWarn, "no script-src or default-src"
+	case strings.Contains(scripts, "'unsafe-inline'") || strings.Contains(scripts, "'unsafe-eval'"):
+		check.Status, check.Detail = CheckWarn, "allows unsafe-inline or unsafe-eval scripts"
+	default:
+		check.Status = CheckPass
+	}
+	return check
+}
+
+func checkFrameOptions(headers http.Header) SecurityHeaderCheck {
+	check := SecurityHeaderCheck{Header: "X-Frame-Options"}
+	if _, ok := cspDirective(headers.Get("Content-Security-Policy"), "frame-ancestors"); ok {
+		check.Status, check.Detail = CheckPass, "covered by CSP frame-ancestors"
+		return check
+	}
+
+	switch value := strings.ToUpper(strings.TrimSpace(headers.Get(check.Header))); value {
+	case "DENY", "SAMEORIGIN":
+		check.Status = CheckPass
+	case "":
+		check.Status, check.Detail = CheckFail, "missing"
+	default:
+		check.Status, check.Detail = CheckWarn, "unsupported value "+value
+	}
+	return check
+}
+
+func checkReferrerPolicy(headers http.Header) SecurityHeaderCheck {
+	check := SecurityHeaderCheck{Header: "Referrer-Policy"}
+	values := strings.Split(headers.Get(check.Header), ",")
+	// Browsers apply the last policy they recognise
+	policy := strings.ToLower(strings.TrimSpace(values[len(values)-1]))
+
+	switch policy {
+	case "":
+		check.Status, check.Detail = CheckFail, "missing"
+	case "unsafe-url", "no-referrer-when-downgrade":
+		check.Status, check.Detail = CheckWarn, policy+" leaks full URLs to other sites"
+	default:
+		check.Status = CheckPass
+	}
+	return check
+}
+
+func checkPermissionsPolicy(headers http.Header) SecurityHeaderCheck {
+	check := SecurityHeaderCheck{Header: "Permissions-Policy"}
+	switch {
+	case headers.Get(check.Header) != "":
+		check.Status = CheckPass
+	case headers.Get("Feature-Policy") != "":
+		check.Status, check.Detail = CheckWarn, "only the deprecated Feature-Policy is set"
+	default:
+		check.Status, check.Detail = CheckFail, "missing"
+	}
+	return check
+}
+
+// GradeSecurityHeaders checks a response's HSTS, Content-Security-Policy,
+// X-Frame-Options, Referrer-Policy and Permissions-Policy headers
+func GradeSecurityHeaders(headers http.Header) SecurityHeaderGrade {
+	grade := SecurityHeaderGrade{Checks: []SecurityHeaderCheck{
+		checkHSTS(headers),
+		checkCSP(headers),
+		checkFrameOptions(headers),
+		checkReferrerPolicy(headers),
+		checkPermissionsPolicy(headers),
+	}}
+
+	for _, check := range grade.Checks {
+		switch check.Status {
+		case CheckPass:
+			grade.Score += 20
+		case CheckWarn:
+			grade.Score += 10
+		}
+	}
+	grade.Grade = SecurityGradeForScore(grade.Score)
+	return grade
+}
@@ -0,0 +1,103 @@
+package crawler
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGradeSecurityHeaders(t *testing.T) {
+	tests := []struct {
+		name      string
+		headers   map[string]string
+		wantGrade string
+		wantScore int
+		want      map[string]string
+	}{
+		{
+			name:      "none set",
+			headers:   map[string]string{},
+			wantGrade: "F",
+			wantScore: 0,
+			want: map[string]string{
+				"Strict-Transport-Security": CheckFail, "Content-Security-Policy": CheckFail,
+				"X-Frame-Options": CheckFail, "Referrer-Policy": CheckFail, "Permissions-Policy": CheckFail,
+			},
+		},
+		{
+			name: "all strong",
+			headers: map[string]string{
+				"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+				"Content-Security-Policy":   "default-src 'self'; frame-ancestors 'none'",
+				"Referrer-Policy":           "strict-origin-when-cross-origin",
+				"Permissions-Policy":        "camera=()",
+			},
+			wantGrade: "A",
+			wantScore: 100,
+			want: map[string]string{
+				"Strict-Transport-Security": CheckPass, "Content-Security-Policy": CheckPass,
+				"X-Frame-Options": CheckPass, "Referrer-Policy": CheckPass, "Permissions-Policy": CheckPass,
+			},
+		},
+		{
+			name: "weak values",
+			headers: map[string]string{
+				"Strict-Transport-Security": "max-age=86400",
+				"Content-Security-Policy":   "script-src 'self' 'unsafe-inline'",
+				"X-Frame-Options":           "ALLOW-FROM https://example.com",
+				"Referrer-Policy":           "no-referrer, unsafe-url",
+				"Feature-Policy":            "camera 'none'",
+			},
+			wantGrade: "C",
+			wantScore: 50,
+			want: map[string]string{
+				"Strict-Transport-Security": CheckWarn, "Content-Security-Policy": CheckWarn,
+				"X-Frame-Options": CheckWarn, "Referrer-Policy": CheckWarn, "Permissions-Policy": CheckWarn,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			for k, v := range tt.headers {
+				headers.Set(k, v)
+			}
+
+			grade := GradeSecurityHeaders(headers)
+			if grade.Grade != tt.wantGrade || grade.Score != tt.wantScore {
+				t.Errorf("Expected grade %s (%d), got %s (%d)", tt.wantGrade, tt.wantScore, grade.Grade, grade.Score)
+			}
+			for _, check := range grade.Checks {
+				if check.Status != tt.want[check.Header] {
+					t.Errorf("%s: expected %s, got %s (%s)", check.Header, tt.want[check.Header], check.Status, check.Detail)
+				}
+			}
+		})
+	}
+}
+
+func TestSummariseTLS(t *testing.T) {
+	if summariseTLS(nil) != nil {
+		t.Error("Expected nil for a plain HTTP response")
+	}
+
+	info := summariseTLS(&tls.ConnectionState{Version: tls.VersionTLS13, CipherSuite: tls.TLS_AES_128_GCM_SHA256})
+	if info.Version != "TLS 1.3" || info.CipherSuite != "TLS_AES_128_GCM_SHA256" {
+		t.Errorf("Unexpected protocol summary: %+v", info)
+	}
+	if info.Leaf() != nil {
+		t.Error("Expected no leaf when no certificates were served")
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cert := CertificateInfo{NotAfter: now.Add(36 * time.Hour)}
+	if days := cert.ExpiresInDays(now); days != 1 {
+		t.Errorf("Expected 1 day remaining, got %d", days)
+	}
+	cert.NotAfter = now.Add(-time.Hour)
+	if days := cert.ExpiresInDays(now); days != -1 {
+		t.Errorf("Expected expired certificate to report -1, got %d", days)
+	}
+}
@@ -0,0 +1,142 @@
+package crawler
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// SEOSignals holds the indexing directives and alternates declared by a page
+type SEOSignals struct {
+	Canonical  string          `json:"canonical,omitempty"`    // Absolute URL from <link rel="canonical">
+	MetaRobots string          `json:"meta_robots,omitempty"`  // Content of <meta name="robots">
+	XRobotsTag string          `json:"x_robots_tag,omitempty"` // X-Robots-Tag response header(s)
+	NoIndex    bool            `json:"noindex,omitempty"`
+	NoFollow   bool            `json:"nofollow,omitempty"`
+	Hreflang   []HreflangEntry `json:"hreflang,omitempty"`
+
+	// Links from anchors with rel="nofollow", by link category; they also
+	// appear in CrawlResult.Links so the job decides whether to follow them
+	NofollowLinks map[string][]string `json:"-"`
+}
+
+// HreflangEntry is a <link rel="alternate" hreflang> alternate
+type HreflangEntry struct {
+	Lang string `json:"lang"`
+	URL  string `json:"url"`
+}
+
+// robotsDirectivesWithValues take an argument after a colon, so a colon alone
+// does not mean an X-Robots-Tag value is scoped to a user agent
+var robotsDirectivesWithValues = map[string]bool{
+	"max-snippet":       true,
+	"max-image-preview": true,
+	"max-video-preview": true,
+	"unavailable_after": true,
+}
+
+// parseRobotsDirectives reports whether a comma-separated directive list
+// contains noindex or nofollow ("none" means both)
+func parseRobotsDirectives(value string) (noindex, nofollow bool) {
+	for directive := range strings.SplitSeq(strings.ToLower(value), ",") {
+		switch strings.TrimSpace(directive) {
+		case "noindex":
+			noindex = true
+		case "nofollow":
+			nofollow = true
+		case "none":
+			noindex = true
+			nofollow = true
+		}
+	}
+	return noindex, nofollow
+}
+
+// parseXRobotsTag applies parseRobotsDirectives to each X-Robots-Tag value.
+// Values scoped to a named crawler, e.g. "googlebot: noindex", are ignored.
+func parseXRobotsTag(values []string) (noindex, nofollow bool) {
+	for _, value := range values {
+		if agent, _, found := strings.Cut(value, ":"); found && !strings.Contains(agent, ",") &&
+			!robotsDirectivesWithValues[strings.ToLower(strings.TrimSpace(agent))] {
+			continue
+		}
+		n, f := parseRobotsDirectives(value)
+		noindex = noindex || n
+		nofollow = nofollow || f
+	}
+	return noindex, nofollow
+}
+
+// hasRelToken reports whether a space-separated rel attribute contains token
+func hasRelToken(rel, token string) bool {
+	for t := range strings.FieldsSeq(strings.ToLower(rel)) {
+		if t == token {
+			return true
+		}
+	}
+	return false
+}
+
+// setupSEOExtraction configures Colly HTML handler for canonical, robots and
+// hreflang extraction. It must be registered before link extraction, which
+// strips the header and footer from the parsed document.
+func setupSEOExtraction(collyClone *colly.Collector) {
+	collyClone.OnHTML("html", func(e *colly.HTMLElement) {
+		result, ok := e.Request.Ctx.GetAny("result").(*CrawlResult)
+		if !ok {
+			return
+		}
+
+		seo := &SEOSignals{}
+
+		e.DOM.Find("link[href][rel]").Each(func(i int, s *goquery.Selection) {
+			rel := s.AttrOr("rel", "")
+			href := strings.TrimSpace(s.AttrOr("href", ""))
+			if href == "" {
+				return
+			}
+			switch {
+			case hasRelToken(rel, "canonical"):
+				// Search engines ignore conflicting canonicals; keep the first
+				if seo.Canonical == "" {
+					seo.Canonical = e.Request.AbsoluteURL(href)
+				}
+			case hasRelToken(rel, "alternate"):
+				if lang := strings.TrimSpace(s.AttrOr("hreflang", "")); lang != "" {
+					seo.Hreflang = append(seo.Hreflang, HreflangEntry{Lang: lang, URL: e.Request.AbsoluteURL(href)})
+				}
+			}
+		})
+
+		var metaRobots []string
+		e.DOM.Find("meta[name]").Each(func(i int, s *goquery.Selection) {
+			if strings.EqualFold(strings.TrimSpace(s.AttrOr("name", "")), "robots") {
+				metaRobots = append(metaRobots, strings.TrimSpace(s.AttrOr("content", "")))
+			}
+		})
+		seo.MetaRobots = strings.Join(metaRobots, ", ")
+
+		var headerRobots []string
+		if e.Response != nil && e.Response.Headers != nil {
+			headerRobots = e.Response.Headers.Values("X-Robots-Tag")
+		}
+		seo.XRobotsTag = strings.Join(headerRobots, ", ")
+
+		metaNoIndex, metaNoFollow := parseRobotsDirectives(seo.MetaRobots)
+		headerNoIndex, headerNoFollow := parseXRobotsTag(headerRobots)
+		seo.NoIndex = metaNoIndex || headerNoIndex
+		seo.NoFollow = metaNoFollow || headerNoFollow
+
+		result.SEO = seo
+
+		log.Debug().
+			Str("url", e.Request.URL.String()).
+			Str("canonical", seo.Canonical).
+			Bool("noindex", seo.NoIndex).
+			Bool("nofollow", seo.NoFollow).
+			Int("hreflang", len(seo.Hreflang)).
+			Msg("Extracted SEO signals from page")
+	})
+}
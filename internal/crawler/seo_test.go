@@ -0,0 +1,83 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestWarmURLExtractsSEOSignals(t *testing.T) {
+	page := `<html><head>
+		<link rel="canonical" href="/en/page">
+		<link rel="canonical" href="/ignored">
+		<link rel="alternate" hreflang="en-AU" href="/en/page">
+		<link rel="alternate" hreflang="x-default" href="https://example.com/page">
+		<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+		<meta name="ROBOTS" content="index, nofollow">
+	</head><body>
+		<a href="/about">About</a>
+		<a href="/login" rel="nofollow noopener">Log in</a>
+	</body></html>`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Add("X-Robots-Tag", "googlebot: noindex")
+		_, _ = w.Write([]byte(page))
+	}))
+	defer ts.Close()
+
+	result, err := New(testConfig()).WarmURL(context.Background(), ts.URL+"/", true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	seo := result.SEO
+	if seo == nil {
+		t.Fatal("Expected SEO signals")
+	}
+	if seo.Canonical != ts.URL+"/en/page" {
+		t.Errorf("Expected first canonical resolved against the page, got %q", seo.Canonical)
+	}
+	if !seo.NoFollow || seo.NoIndex {
+		t.Errorf("Expected nofollow only (googlebot-scoped noindex ignored), got noindex=%v nofollow=%v", seo.NoIndex, seo.NoFollow)
+	}
+	if seo.XRobotsTag != "googlebot: noindex" {
+		t.Errorf("Expected raw X-Robots-Tag to be kept, got %q", seo.XRobotsTag)
+	}
+	want := []HreflangEntry{{Lang: "en-AU", URL: ts.URL + "/en/page"}, {Lang: "x-default", URL: "https://example.com/page"}}
+	if !slices.Equal(seo.Hreflang, want) {
+		t.Errorf("Expected hreflang %v, got %v", want, seo.Hreflang)
+	}
+	if !slices.Equal(seo.NofollowLinks["body"], []string{ts.URL + "/login"}) {
+		t.Errorf("Expected rel=nofollow anchor to be recorded, got %v", seo.NofollowLinks)
+	}
+	if len(result.Links["body"]) != 2 {
+		t.Errorf("Expected nofollow anchors to stay in links, got %v", result.Links["body"])
+	}
+}
+
+func TestParseXRobotsTag(t *testing.T) {
+	tests := []struct {
+		name         string
+		values       []string
+		wantNoIndex  bool
+		wantNoFollow bool
+	}{
+		{"none", []string{"none"}, true, true},
+		{"list", []string{"noindex, noarchive"}, true, false},
+		{"scoped to other crawler", []string{"bingbot: noindex, nofollow"}, false, false},
+		{"directive with value", []string{"unavailable_after: 25 Jun 2030 15:00:00 PST"}, false, false},
+		{"multiple headers", []string{"noarchive", "NOFOLLOW"}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			noindex, nofollow := parseXRobotsTag(tt.values)
+			if noindex != tt.wantNoIndex || nofollow != tt.wantNoFollow {
+				t.Errorf("parseXRobotsTag(%v) = %v, %v; want %v, %v", tt.values, noindex, nofollow, tt.wantNoIndex, tt.wantNoFollow)
+			}
+		})
+	}
+}
@@ -1,13 +1,15 @@
 package crawler
 
 import (
-	"bytes"
+	"bufio"
 	"compress/gzip"
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,22 +17,6 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// decompressGzip decompresses gzip-encoded data
-func decompressGzip(data []byte) ([]byte, error) {
-	reader, err := gzip.NewReader(bytes.NewReader(data))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
-	}
-	defer reader.Close()
-
-	decompressed, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decompress gzip data: %w", err)
-	}
-
-	return decompressed, nil
-}
-
 // isGzipContent checks if the response is gzip-encoded based on headers or URL
 func isGzipContent(contentEncoding, url string) bool {
 	// Check Content-Encoding header
@@ -167,179 +153,514 @@ func (c *Crawler) DiscoverSitemaps(ctx context.Context, domain string) ([]string
 	return result.Sitemaps, nil
 }
 
-// Create proper sitemap structs
-type SitemapIndex struct {
-	XMLName  xml.Name  `xml:"sitemapindex"`
-	Sitemaps []Sitemap `xml:"sitemap"`
+// Sitemap change frequencies defined by the sitemaps.org protocol
+var sitemapChangeFreqs = map[string]bool{
+	"always": true, "hourly": true, "daily": true, "weekly": true,
+	"monthly": true, "yearly": true, "never": true,
 }
 
-type Sitemap struct {
-	XMLName xml.Name `xml:"sitemap"`
-	Loc     string   `xml:"loc"`
+// sitemapTimeLayouts are the W3C datetime forms allowed in sitemaps
+var sitemapTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006-01",
+	"2006",
 }
 
-type URLSet struct {
-	XMLName xml.Name `xml:"urlset"`
-	URLs    []URL    `xml:"url"`
+// errSitemapWalkStopped ends a walk early, when the URL limit is reached or
+// emit fails
+var errSitemapWalkStopped = errors.New("sitemap walk stopped")
+
+// SitemapLimits bounds the work done reading a sitemap and its children
+type SitemapLimits struct {
+	MaxDepth             int   // Levels of nested sitemap indexes followed below the root
+	MaxChildSitemaps     int   // Child sitemaps fetched from indexes in total
+	MaxURLs              int   // URL entries emitted in total
+	MaxDecompressedBytes int64 // Size of each sitemap once decompressed
+
+	// IdleTimeout is the longest a fetch may wait for a response or for the
+	// next part of its body. A download that keeps making progress is not
+	// cut off however long it takes.
+	IdleTimeout time.Duration
 }
 
-type URL struct {
-	XMLName xml.Name `xml:"url"`
-	Loc     string   `xml:"loc"`
+// DefaultSitemapLimits returns the limits used for job discovery. The URL and
+// size limits are ten times and equal to the protocol's per-file limits.
+func DefaultSitemapLimits() SitemapLimits {
+	return SitemapLimits{
+		MaxDepth:             3,
+		MaxChildSitemaps:     1000,
+		MaxURLs:              500000,
+		MaxDecompressedBytes: 50 << 20,
+		IdleTimeout:          30 * time.Second,
+	}
 }
 
-// ParseSitemap extracts URLs from a sitemap
-func (c *Crawler) ParseSitemap(ctx context.Context, sitemapURL string) ([]string, error) {
-	var urls []string
+// SitemapEntry is a <url> entry from a sitemap, with the optional fields and
+// extensions it declared
+type SitemapEntry struct {
+	Loc        string
+	LastMod    *time.Time
+	ChangeFreq string   // One of always, hourly, daily, weekly, monthly, yearly, never
+	Priority   *float64 // 0.0 to 1.0
+	Images     []SitemapImage
+	Videos     []SitemapVideo
+	News       *SitemapNews
+	Alternates []SitemapAlternate // xhtml:link rel="alternate" language versions
+	Sitemap    string             // Sitemap the entry was read from
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", sitemapURL, nil)
-	if err != nil {
-		return nil, err
-	}
+// SitemapImage is an image:image extension entry
+type SitemapImage struct {
+	Loc     string
+	Title   string
+	Caption string
+}
 
-	// Request gzip encoding if server supports it
-	req.Header.Set("Accept-Encoding", "gzip")
+// SitemapVideo is a video:video extension entry
+type SitemapVideo struct {
+	ThumbnailLoc    string
+	Title           string
+	Description     string
+	ContentLoc      string
+	PlayerLoc       string
+	Duration        int // Seconds
+	PublicationDate *time.Time
+}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// SitemapNews is a news:news extension entry
+type SitemapNews struct {
+	PublicationName     string
+	PublicationLanguage string
+	PublicationDate     *time.Time
+	Title               string
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch sitemap: %d", resp.StatusCode)
+// SitemapAlternate is a language version of an entry
+type SitemapAlternate struct {
+	Hreflang string
+	Href     string
+}
+
+// SitemapError is a sitemap that could not be fetched or read in full
+type SitemapError struct {
+	URL   string `json:"url"`
+	Error string `json:"error"`
+}
+
+// SitemapStats summarises a sitemap walk
+type SitemapStats struct {
+	Sitemaps  int            // Sitemaps fetched, including the root
+	URLs      int            // Entries emitted
+	Errors    []SitemapError // Sitemaps skipped or only partly read
+	Truncated bool           // A limit stopped the walk early
+}
+
+// Raw XML forms of sitemap elements. Tags match on local name so that the
+// usual namespace prefixes (image:, video:, news:, xhtml:) and their absence
+// are both accepted.
+type sitemapURLElement struct {
+	Loc        string                `xml:"loc"`
+	LastMod    string                `xml:"lastmod"`
+	ChangeFreq string                `xml:"changefreq"`
+	Priority   string                `xml:"priority"`
+	Images     []sitemapImageElement `xml:"image"`
+	Videos     []sitemapVideoElement `xml:"video"`
+	News       *sitemapNewsElement   `xml:"news"`
+	Links      []sitemapLinkElement  `xml:"link"`
+}
+
+type sitemapImageElement struct {
+	Loc     string `xml:"loc"`
+	Title   string `xml:"title"`
+	Caption string `xml:"caption"`
+}
+
+type sitemapVideoElement struct {
+	ThumbnailLoc    string `xml:"thumbnail_loc"`
+	Title           string `xml:"title"`
+	Description     string `xml:"description"`
+	ContentLoc      string `xml:"content_loc"`
+	PlayerLoc       string `xml:"player_loc"`
+	Duration        string `xml:"duration"`
+	PublicationDate string `xml:"publication_date"`
+}
+
+type sitemapNewsElement struct {
+	Name            string `xml:"publication>name"`
+	Language        string `xml:"publication>language"`
+	PublicationDate string `xml:"publication_date"`
+	Title           string `xml:"title"`
+}
+
+type sitemapLinkElement struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+type sitemapRefElement struct {
+	Loc string `xml:"loc"`
+}
+
+// parseSitemapTime parses a W3C datetime, returning nil if it is not one
+func parseSitemapTime(raw string) *time.Time {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	for _, layout := range sitemapTimeLayouts {
+		if parsed, err := time.Parse(layout, raw); err == nil {
+			parsed = parsed.UTC()
+			return &parsed
+		}
 	}
+	return nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+// entry converts a decoded <url> element, returning false if it has no
+// usable location
+func (el *sitemapURLElement) entry(sitemapURL string) (SitemapEntry, bool) {
+	loc := util.NormaliseURL(el.Loc)
+	if loc == "" {
+		if strings.TrimSpace(el.Loc) != "" {
+			log.Debug().Str("invalid_url", el.Loc).Msg("Skipping invalid URL from sitemap")
+		}
+		return SitemapEntry{}, false
 	}
 
-	// Decompress if gzip-encoded (via header or .gz URL suffix)
-	contentEncoding := resp.Header.Get("Content-Encoding")
-	if isGzipContent(contentEncoding, sitemapURL) {
-		log.Debug().
-			Str("url", sitemapURL).
-			Str("content_encoding", contentEncoding).
-			Int("compressed_size", len(body)).
-			Msg("Decompressing gzip sitemap")
+	entry := SitemapEntry{
+		Loc:     loc,
+		LastMod: parseSitemapTime(el.LastMod),
+		Sitemap: sitemapURL,
+	}
+	if freq := strings.ToLower(strings.TrimSpace(el.ChangeFreq)); sitemapChangeFreqs[freq] {
+		entry.ChangeFreq = freq
+	}
+	if priority, err := strconv.ParseFloat(strings.TrimSpace(el.Priority), 64); err == nil {
+		priority = min(max(priority, 0), 1)
+		entry.Priority = &priority
+	}
 
-		body, err = decompressGzip(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decompress sitemap %s: %w", sitemapURL, err)
+	for _, img := range el.Images {
+		if imgLoc := strings.TrimSpace(img.Loc); imgLoc != "" {
+			entry.Images = append(entry.Images, SitemapImage{
+				Loc:     imgLoc,
+				Title:   strings.TrimSpace(img.Title),
+				Caption: strings.TrimSpace(img.Caption),
+			})
+		}
+	}
+	for _, vid := range el.Videos {
+		duration, _ := strconv.Atoi(strings.TrimSpace(vid.Duration))
+		entry.Videos = append(entry.Videos, SitemapVideo{
+			ThumbnailLoc:    strings.TrimSpace(vid.ThumbnailLoc),
+			Title:           strings.TrimSpace(vid.Title),
+			Description:     strings.TrimSpace(vid.Description),
+			ContentLoc:      strings.TrimSpace(vid.ContentLoc),
+			PlayerLoc:       strings.TrimSpace(vid.PlayerLoc),
+			Duration:        duration,
+			PublicationDate: parseSitemapTime(vid.PublicationDate),
+		})
+	}
+	if el.News != nil {
+		entry.News = &SitemapNews{
+			PublicationName:     strings.TrimSpace(el.News.Name),
+			PublicationLanguage: strings.TrimSpace(el.News.Language),
+			PublicationDate:     parseSitemapTime(el.News.PublicationDate),
+			Title:               strings.TrimSpace(el.News.Title),
+		}
+	}
+	for _, link := range el.Links {
+		if !strings.EqualFold(strings.TrimSpace(link.Rel), "alternate") {
+			continue
 		}
+		if href := util.NormaliseURL(link.Href); href != "" {
+			entry.Alternates = append(entry.Alternates, SitemapAlternate{
+				Hreflang: strings.TrimSpace(link.Hreflang),
+				Href:     href,
+			})
+		}
+	}
 
-		log.Debug().
-			Str("url", sitemapURL).
-			Int("decompressed_size", len(body)).
-			Msg("Sitemap decompressed successfully")
+	return entry, true
+}
+
+// sizeLimitedReader fails once more than limit bytes have been read, rather
+// than reporting a clean EOF that would hide the truncation
+type sizeLimitedReader struct {
+	reader io.Reader
+	limit  int64
+	read   int64
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if r.read > r.limit {
+		return n, fmt.Errorf("sitemap exceeds %d bytes decompressed", r.limit)
 	}
+	return n, err
+}
 
-	content := string(body)
+// errSitemapStalled cancels a sitemap fetch that has gone quiet
+var errSitemapStalled = errors.New("sitemap download stalled")
+
+// idleTimeoutReader cancels its request when a single read waits longer than
+// timeout. Time spent between reads, such as emitting entries, is not
+// counted.
+type idleTimeoutReader struct {
+	reader  io.Reader
+	timer   *time.Timer
+	timeout time.Duration
+	ctx     context.Context
+}
 
-	// Log the content for debugging
-	log.Debug().
-		Str("url", sitemapURL).
-		Int("content_length", len(content)).
-		Str("content_sample", content[:min(100, len(content))]).
-		Msg("Sitemap content received")
-
-	// Check if it's a sitemap index
-	if strings.Contains(content, "<sitemapindex") {
-		// Extract sitemap URLs
-		sitemapURLs := extractURLsFromXML(content, "<sitemap>", "</sitemap>", "<loc>", "</loc>")
-
-		// Process each sitemap in the index
-		for _, childSitemapURL := range sitemapURLs {
-			// Validate and normalise the child sitemap URL
-			childSitemapURL = util.NormaliseURL(childSitemapURL)
-			if childSitemapURL == "" {
-				log.Warn().Str("url", childSitemapURL).Msg("Invalid child sitemap URL, skipping")
-				continue
-			}
+func (r *idleTimeoutReader) Read(p []byte) (int, error) {
+	r.timer.Reset(r.timeout)
+	n, err := r.reader.Read(p)
+	r.timer.Stop()
+	if err != nil && errors.Is(context.Cause(r.ctx), errSitemapStalled) {
+		err = fmt.Errorf("%w: no data for %s", errSitemapStalled, r.timeout)
+	}
+	return n, err
+}
 
-			childURLs, err := c.ParseSitemap(ctx, childSitemapURL)
-			if err != nil {
-				log.Warn().Err(err).Str("url", childSitemapURL).Msg("Failed to parse child sitemap")
-				continue
-			}
-			urls = append(urls, childURLs...)
-		}
-	} else {
-		// It's a regular sitemap
-		extractedURLs := extractURLsFromXML(content, "<url>", "</url>", "<loc>", "</loc>")
-
-		// Validate and normalise all extracted URLs
-		var validURLs []string
-		for _, extractedURL := range extractedURLs {
-			validURL := util.NormaliseURL(extractedURL)
-			if validURL != "" {
-				validURLs = append(validURLs, validURL)
-			} else {
-				log.Debug().Str("invalid_url", extractedURL).Msg("Skipping invalid URL from sitemap")
-			}
-		}
+// sitemapWalk is the state of one StreamSitemap call
+type sitemapWalk struct {
+	crawler  *Crawler
+	client   *http.Client
+	limits   SitemapLimits
+	emit     func(SitemapEntry) error
+	stats    *SitemapStats
+	seen     map[string]bool
+	children int
+	emitErr  error // Returned by emit, ending the walk
+}
 
-		log.Debug().
-			Str("sitemap_url", sitemapURL).
-			Int("url_count", len(validURLs)).
-			Msg("Extracted valid URLs from regular sitemap")
-		urls = append(urls, validURLs...)
+// StreamSitemap reads a sitemap or sitemap index with a streaming XML
+// decoder, calling emit for each <url> entry as it is read so large sitemaps
+// are never held in memory. Child sitemaps of an index are followed up to
+// the given limits. Only a failure to fetch the root sitemap, or an error
+// returned by emit, is returned; problems with child sitemaps and partly
+// read files are collected in the stats.
+func (c *Crawler) StreamSitemap(ctx context.Context, sitemapURL string, limits SitemapLimits, emit func(SitemapEntry) error) (*SitemapStats, error) {
+	// Large sitemaps are read as they download, so rather than a total
+	// timeout each fetch is bounded by ctx and the idle timeout in limits
+	client := &http.Client{}
+	return c.streamSitemap(ctx, client, sitemapURL, limits, emit)
+}
+
+func (c *Crawler) streamSitemap(ctx context.Context, client *http.Client, sitemapURL string, limits SitemapLimits, emit func(SitemapEntry) error) (*SitemapStats, error) {
+	walk := &sitemapWalk{
+		crawler: c,
+		client:  client,
+		limits:  limits,
+		emit:    emit,
+		stats:   &SitemapStats{},
+		seen:    map[string]bool{sitemapURL: true},
+	}
+
+	err := walk.walk(ctx, sitemapURL, 0)
+	if errors.Is(err, errSitemapWalkStopped) {
+		err = walk.emitErr
 	}
 
 	log.Debug().
 		Str("sitemap_url", sitemapURL).
-		Int("total_url_count", len(urls)).
-		Msg("Finished parsing sitemap")
+		Int("sitemaps", walk.stats.Sitemaps).
+		Int("url_count", walk.stats.URLs).
+		Int("errors", len(walk.stats.Errors)).
+		Bool("truncated", walk.stats.Truncated).
+		Msg("Finished streaming sitemap")
 
-	return urls, nil
+	return walk.stats, err
 }
 
-// Helper function to extract URLs from XML content
-func extractURLsFromXML(content, startTag, endTag, locStartTag, locEndTag string) []string {
-	var urls []string
+// walk reads one sitemap, emitting its entries, then follows the children
+// it listed if it was an index
+func (w *sitemapWalk) walk(ctx context.Context, sitemapURL string, depth int) error {
+	children, err := w.read(ctx, sitemapURL, depth)
+	if errors.Is(err, errSitemapWalkStopped) {
+		return err
+	}
+	if err != nil {
+		// Nothing can be read when the root itself is unavailable
+		if depth == 0 && w.stats.Sitemaps == 0 {
+			return err
+		}
+		w.stats.Errors = append(w.stats.Errors, SitemapError{URL: sitemapURL, Error: err.Error()})
+		log.Warn().Err(err).Str("url", sitemapURL).Msg("Failed to read sitemap")
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
 
-	// Find all instances of the outer tag
-	startIdx := 0
-	for {
-		startTagIdx := strings.Index(content[startIdx:], startTag)
-		if startTagIdx == -1 {
-			break
+	for _, child := range children {
+		if err := w.walk(ctx, child, depth+1); err != nil {
+			return err
 		}
+	}
+	return nil
+}
+
+// read fetches and decodes one sitemap, returning the child sitemaps it
+// lists. A fetch failure is returned without counting the sitemap as read;
+// a decode failure part way through keeps the entries already emitted.
+func (w *sitemapWalk) read(ctx context.Context, sitemapURL string, depth int) ([]string, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
 
-		startTagIdx += startIdx
-		endTagIdx := strings.Index(content[startTagIdx:], endTag)
-		if endTagIdx == -1 {
-			break
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sitemapURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Request gzip encoding if server supports it
+	req.Header.Set("Accept-Encoding", "gzip")
+	if w.crawler.config != nil && w.crawler.config.UserAgent != "" {
+		req.Header.Set("User-Agent", w.crawler.config.UserAgent)
+	}
+
+	var idle *time.Timer
+	if w.limits.IdleTimeout > 0 {
+		idle = time.AfterFunc(w.limits.IdleTimeout, func() { cancel(errSitemapStalled) })
+		defer idle.Stop()
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		if errors.Is(context.Cause(ctx), errSitemapStalled) {
+			return nil, fmt.Errorf("%w: no response for %s", errSitemapStalled, w.limits.IdleTimeout)
 		}
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-		endTagIdx += startTagIdx
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch sitemap: %d", resp.StatusCode)
+	}
+	w.stats.Sitemaps++
 
-		// Extract the section between tags
-		section := content[startTagIdx : endTagIdx+len(endTag)]
+	var rawBody io.Reader = resp.Body
+	if idle != nil {
+		idle.Stop()
+		rawBody = &idleTimeoutReader{reader: resp.Body, timer: idle, timeout: w.limits.IdleTimeout, ctx: ctx}
+	}
 
-		// Find the URL in the section
-		locStartIdx := strings.Index(section, locStartTag)
-		if locStartIdx != -1 {
-			locEndIdx := strings.Index(section[locStartIdx:], locEndTag)
-			if locEndIdx != -1 {
-				locEndIdx += locStartIdx
+	// Decompress by content rather than trusting headers, as servers often
+	// decompress .gz files or mislabel compressed ones
+	body := bufio.NewReader(rawBody)
+	var content io.Reader = body
+	if magic, _ := body.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress sitemap %s: %w", sitemapURL, err)
+		}
+		defer gz.Close()
+		content = gz
+	} else if isGzipContent(resp.Header.Get("Content-Encoding"), sitemapURL) {
+		log.Debug().Str("url", sitemapURL).Msg("Sitemap labelled as gzip is not compressed, reading as XML")
+	}
 
-				// Extract the URL
-				url := section[locStartIdx+len(locStartTag) : locEndIdx]
-				url = strings.TrimSpace(url)
+	decoder := xml.NewDecoder(&sizeLimitedReader{reader: content, limit: w.limits.MaxDecompressedBytes})
+	// Treat any declared charset as UTF-8 rather than rejecting the file
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
 
-				if url != "" {
-					urls = append(urls, url)
+	var children []string
+	nestedTooDeep := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return children, nil
+		}
+		if err != nil {
+			return children, fmt.Errorf("failed to read sitemap: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "url":
+			var el sitemapURLElement
+			if err := decoder.DecodeElement(&el, &start); err != nil {
+				return children, fmt.Errorf("failed to read sitemap: %w", err)
+			}
+			entry, ok := el.entry(sitemapURL)
+			if !ok {
+				continue
+			}
+			if w.stats.URLs >= w.limits.MaxURLs {
+				w.stats.Truncated = true
+				log.Warn().
+					Str("url", sitemapURL).
+					Int("limit", w.limits.MaxURLs).
+					Msg("Sitemap URL limit reached, skipping remaining URLs")
+				return nil, errSitemapWalkStopped
+			}
+			if err := w.emit(entry); err != nil {
+				w.emitErr = err
+				return nil, errSitemapWalkStopped
+			}
+			w.stats.URLs++
+		case "sitemap":
+			var el sitemapRefElement
+			if err := decoder.DecodeElement(&el, &start); err != nil {
+				return children, fmt.Errorf("failed to read sitemap index: %w", err)
+			}
+			child := util.NormaliseURL(el.Loc)
+			if child == "" {
+				log.Warn().Str("url", el.Loc).Msg("Invalid child sitemap URL, skipping")
+				continue
+			}
+			if w.seen[child] {
+				continue
+			}
+			if depth >= w.limits.MaxDepth {
+				if !nestedTooDeep {
+					w.stats.Truncated = true
+					w.stats.Errors = append(w.stats.Errors, SitemapError{
+						URL:   sitemapURL,
+						Error: fmt.Sprintf("sitemap index nested more than %d levels deep", w.limits.MaxDepth),
+					})
+				}
+				nestedTooDeep = true
+				continue
+			}
+			if w.children >= w.limits.MaxChildSitemaps {
+				if !w.stats.Truncated {
+					log.Warn().
+						Str("url", sitemapURL).
+						Int("limit", w.limits.MaxChildSitemaps).
+						Msg("Child sitemap limit reached, skipping remaining sitemaps")
 				}
+				w.stats.Truncated = true
+				continue
 			}
+			w.seen[child] = true
+			w.children++
+			children = append(children, child)
 		}
-
-		startIdx = endTagIdx + len(endTag)
 	}
+}
 
-	return urls
+// ParseSitemap extracts the URLs from a sitemap, following sitemap indexes
+// within the default limits
+func (c *Crawler) ParseSitemap(ctx context.Context, sitemapURL string) ([]string, error) {
+	var urls []string
+	_, err := c.StreamSitemap(ctx, sitemapURL, DefaultSitemapLimits(), func(entry SitemapEntry) error {
+		urls = append(urls, entry.Loc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return urls, nil
 }
 
 // FilterURLs filters URLs based on include/exclude patterns
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -336,43 +338,6 @@ func TestIsGzipContent(t *testing.T) {
 	}
 }
 
-func TestDecompressGzip(t *testing.T) {
-	t.Run("valid_gzip_data", func(t *testing.T) {
-		original := []byte("<?xml version=\"1.0\"?><urlset><url><loc>https://example.com/</loc></url></urlset>")
-
-		// Compress the data
-		var buf bytes.Buffer
-		gzWriter := gzip.NewWriter(&buf)
-		_, err := gzWriter.Write(original)
-		require.NoError(t, err)
-		err = gzWriter.Close()
-		require.NoError(t, err)
-
-		// Decompress and verify
-		decompressed, err := decompressGzip(buf.Bytes())
-		require.NoError(t, err)
-		assert.Equal(t, original, decompressed)
-	})
-
-	t.Run("invalid_gzip_data", func(t *testing.T) {
-		invalidData := []byte("not gzip data")
-		_, err := decompressGzip(invalidData)
-		assert.Error(t, err)
-	})
-
-	t.Run("empty_gzip_data", func(t *testing.T) {
-		// Create empty gzip
-		var buf bytes.Buffer
-		gzWriter := gzip.NewWriter(&buf)
-		err := gzWriter.Close()
-		require.NoError(t, err)
-
-		decompressed, err := decompressGzip(buf.Bytes())
-		require.NoError(t, err)
-		assert.Empty(t, decompressed)
-	})
-}
-
 func TestParseSitemapGzip(t *testing.T) {
 	// Helper to gzip content
 	gzipContent := func(content []byte) []byte {
@@ -460,3 +425,237 @@ func TestParseSitemapGzip(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamSitemapEntries(t *testing.T) {
+	sitemapXML := `<?xml version="1.0" encoding="ISO-8859-1"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
+	xmlns:image="http://www.google.com/schemas/sitemap-image/1.1"
+	xmlns:video="http://www.google.com/schemas/sitemap-video/1.1"
+	xmlns:news="http://www.google.com/schemas/sitemap-news/0.9"
+	xmlns:xhtml="http://www.w3.org/1999/xhtml">
+	<url>
+		<loc> https://example.com/article </loc>
+		<lastmod>2026-03-01T10:30:00+10:00</lastmod>
+		<changefreq>Weekly</changefreq>
+		<priority>1.5</priority>
+		<xhtml:link rel="alternate" hreflang="fr" href="https://example.com/fr/article"/>
+		<xhtml:link rel="canonical" href="https://example.com/other"/>
+		<image:image>
+			<image:loc>https://example.com/hero.jpg</image:loc>
+			<image:title>Hero</image:title>
+		</image:image>
+		<video:video>
+			<video:thumbnail_loc>https://example.com/thumb.jpg</video:thumbnail_loc>
+			<video:title>Launch</video:title>
+			<video:content_loc>https://example.com/launch.mp4</video:content_loc>
+			<video:duration>95</video:duration>
+		</video:video>
+		<news:news>
+			<news:publication>
+				<news:name>Example News</news:name>
+				<news:language>en</news:language>
+			</news:publication>
+			<news:publication_date>2026-03-01</news:publication_date>
+			<news:title>Launch day</news:title>
+		</news:news>
+	</url>
+	<url><loc>https://example.com/plain</loc><lastmod>not a date</lastmod><priority>high</priority></url>
+	<url><loc></loc></url>
+</urlset>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(sitemapXML))
+	}))
+	defer server.Close()
+
+	c := &Crawler{config: &Config{UserAgent: "TestBot/1.0"}}
+
+	var entries []SitemapEntry
+	stats, err := c.StreamSitemap(context.Background(), server.URL+"/sitemap.xml", DefaultSitemapLimits(), func(entry SitemapEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Sitemaps)
+	assert.Equal(t, 2, stats.URLs)
+	assert.Empty(t, stats.Errors)
+	require.Len(t, entries, 2)
+
+	article := entries[0]
+	assert.Equal(t, "https://example.com/article", article.Loc)
+	assert.Equal(t, server.URL+"/sitemap.xml", article.Sitemap)
+	require.NotNil(t, article.LastMod)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 30, 0, 0, time.UTC), *article.LastMod)
+	assert.Equal(t, "weekly", article.ChangeFreq)
+	require.NotNil(t, article.Priority)
+	assert.Equal(t, 1.0, *article.Priority)
+	assert.Equal(t, []SitemapAlternate{{Hreflang: "fr", Href: "https://example.com/fr/article"}}, article.Alternates)
+	assert.Equal(t, []SitemapImage{{Loc: "https://example.com/hero.jpg", Title: "Hero"}}, article.Images)
+	require.Len(t, article.Videos, 1)
+	assert.Equal(t, "Launch", article.Videos[0].Title)
+	assert.Equal(t, "https://example.com/launch.mp4", article.Videos[0].ContentLoc)
+	assert.Equal(t, 95, article.Videos[0].Duration)
+	require.NotNil(t, article.News)
+	assert.Equal(t, "Example News", article.News.PublicationName)
+	assert.Equal(t, "en", article.News.PublicationLanguage)
+	assert.Equal(t, "Launch day", article.News.Title)
+	require.NotNil(t, article.News.PublicationDate)
+
+	plain := entries[1]
+	assert.Equal(t, "https://example.com/plain", plain.Loc)
+	assert.Nil(t, plain.LastMod)
+	assert.Nil(t, plain.Priority)
+	assert.Empty(t, plain.ChangeFreq)
+}
+
+func TestStreamSitemapIndex(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.xml":
+			_, _ = w.Write([]byte(`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap><loc>` + server.URL + `/pages.xml</loc></sitemap>
+	<sitemap><loc>` + server.URL + `/missing.xml</loc></sitemap>
+	<sitemap><loc>` + server.URL + `/nested.xml</loc></sitemap>
+	<sitemap><loc>` + server.URL + `/index.xml</loc></sitemap>
+</sitemapindex>`))
+		case "/nested.xml":
+			_, _ = w.Write([]byte(`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap><loc>` + server.URL + `/posts.xml.gz</loc></sitemap>
+</sitemapindex>`))
+		case "/pages.xml":
+			_, _ = w.Write([]byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>https://example.com/</loc></url>
+	<url><loc>https://example.com/about</loc></url>
+</urlset>`))
+		case "/posts.xml.gz":
+			gz := gzip.NewWriter(w)
+			_, _ = gz.Write([]byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>https://example.com/posts/1</loc></url>
+</urlset>`))
+			_ = gz.Close()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := &Crawler{config: &Config{UserAgent: "TestBot/1.0"}}
+	collect := func(limits SitemapLimits) ([]string, *SitemapStats, error) {
+		var urls []string
+		stats, err := c.streamSitemap(context.Background(), server.Client(), server.URL+"/index.xml", limits, func(entry SitemapEntry) error {
+			urls = append(urls, entry.Loc)
+			return nil
+		})
+		return urls, stats, err
+	}
+
+	t.Run("follows_children_and_records_errors", func(t *testing.T) {
+		urls, stats, err := collect(DefaultSitemapLimits())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/", "https://example.com/about", "https://example.com/posts/1"}, urls)
+		assert.Equal(t, 4, stats.Sitemaps)
+		assert.False(t, stats.Truncated)
+		require.Len(t, stats.Errors, 1)
+		assert.Equal(t, server.URL+"/missing.xml", stats.Errors[0].URL)
+		assert.Contains(t, stats.Errors[0].Error, "404")
+	})
+
+	t.Run("depth_limit", func(t *testing.T) {
+		limits := DefaultSitemapLimits()
+		limits.MaxDepth = 1
+		urls, stats, err := collect(limits)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/", "https://example.com/about"}, urls)
+		assert.True(t, stats.Truncated)
+		assert.Len(t, stats.Errors, 2)
+	})
+
+	t.Run("child_sitemap_limit", func(t *testing.T) {
+		limits := DefaultSitemapLimits()
+		limits.MaxChildSitemaps = 1
+		urls, stats, err := collect(limits)
+		require.NoError(t, err)
+		assert.Len(t, urls, 2)
+		assert.Equal(t, 2, stats.Sitemaps)
+		assert.True(t, stats.Truncated)
+	})
+
+	t.Run("url_limit", func(t *testing.T) {
+		limits := DefaultSitemapLimits()
+		limits.MaxURLs = 1
+		urls, stats, err := collect(limits)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/"}, urls)
+		assert.True(t, stats.Truncated)
+	})
+
+	t.Run("size_limit", func(t *testing.T) {
+		limits := DefaultSitemapLimits()
+		limits.MaxDecompressedBytes = 64
+		_, stats, err := collect(limits)
+		require.NoError(t, err)
+		require.NotEmpty(t, stats.Errors)
+		assert.Contains(t, stats.Errors[0].Error, "exceeds 64 bytes")
+	})
+
+	t.Run("emit_error_stops_walk", func(t *testing.T) {
+		emitErr := errors.New("enqueue failed")
+		calls := 0
+		_, err := c.streamSitemap(context.Background(), server.Client(), server.URL+"/index.xml", DefaultSitemapLimits(), func(SitemapEntry) error {
+			calls++
+			return emitErr
+		})
+		assert.ErrorIs(t, err, emitErr)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestStreamSitemapIdleTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		switch r.URL.Path {
+		case "/slow.xml":
+			// Each chunk arrives within the idle timeout, though the whole
+			// download takes several times longer
+			_, _ = w.Write([]byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`))
+			flusher.Flush()
+			for i := range 4 {
+				time.Sleep(60 * time.Millisecond)
+				_, _ = w.Write([]byte(`<url><loc>https://example.com/` + string(rune('a'+i)) + `</loc></url>`))
+				flusher.Flush()
+			}
+			_, _ = w.Write([]byte(`</urlset>`))
+		case "/stalled.xml":
+			_, _ = w.Write([]byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>https://example.com/</loc></url>`))
+			flusher.Flush()
+			<-release
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := &Crawler{config: &Config{UserAgent: "TestBot/1.0"}}
+	limits := DefaultSitemapLimits()
+	limits.IdleTimeout = 150 * time.Millisecond
+
+	var urls []string
+	stats, err := c.streamSitemap(context.Background(), server.Client(), server.URL+"/slow.xml", limits, func(entry SitemapEntry) error {
+		urls = append(urls, entry.Loc)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, urls, 4)
+	assert.Empty(t, stats.Errors)
+
+	urls = nil
+	stats, err = c.streamSitemap(context.Background(), server.Client(), server.URL+"/stalled.xml", limits, func(entry SitemapEntry) error {
+		urls = append(urls, entry.Loc)
+		return nil
+	})
+	require.NoError(t, err, "a root sitemap that was partly read keeps its entries")
+	assert.Equal(t, []string{"https://example.com/"}, urls)
+	require.Len(t, stats.Errors, 1)
+	assert.Contains(t, stats.Errors[0].Error, "sitemap download stalled")
+}
@@ -0,0 +1,234 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// Reasons a 200 response is treated as a soft 404
+const (
+	Soft404TemplateMatch = "template_match"  // Near-identical to the host's not-found page
+	Soft404NotFoundTitle = "not_found_title" // Title says the page was not found
+	Soft404NotFoundText  = "not_found_text"  // Little content, which says the page was not found
+)
+
+const (
+	// soft404MaxDistance is the most simhash bits two pages can differ by
+	// and still be the same template
+	soft404MaxDistance = 4
+	// soft404TitleMatchDistance is the looser limit used when titles match
+	soft404TitleMatchDistance = 12
+	// soft404TinyText is the visible text size, in bytes, below which a page
+	// mentioning "not found" is treated as a not-found page
+	soft404TinyText = 512
+)
+
+// notFoundPattern matches the phrasing of typical not-found pages
+var notFoundPattern = regexp.MustCompile(`(?i)page not found|not be found|can'?t be found|cannot be found|could ?n[o']t be found|does ?n[o']t exist|no longer exists`)
+
+// statusCodePattern matches a bare 404, which real pages mention too (error
+// guides, route numbers, product codes), so it only counts alongside other
+// not-found signals
+var statusCodePattern = regexp.MustCompile(`\b404\b`)
+
+// notFoundTitlePattern matches a title with a 404 followed by "not found",
+// such as "404 Not Found" or "Error 404 - Not Found"
+var notFoundTitlePattern = regexp.MustCompile(`(?i)\b404\b.*\bnot found\b`)
+
+// PageFingerprint summarises an HTML page for comparison with a site's
+// not-found template
+type PageFingerprint struct {
+	Title        string `json:"title"`
+	SimHash      uint64 `json:"simhash"`        // Of the visible text
+	TextSize     int    `json:"text_size"`      // Bytes of visible text
+	NotFoundText bool   `json:"not_found_text"` // Visible text mentions the page was not found
+}
+
+// visibleText returns a selection's text without scripts, styles and
+// templates, with whitespace collapsed
+func visibleText(selection *goquery.Selection) string {
+	clone := selection.Clone()
+	clone.Find("script, style, noscript, template").Remove()
+	return strings.Join(strings.Fields(clone.Text()), " ")
+}
+
+// simHash computes a 64-bit simhash over word pairs, so pages that differ by
+// a few words (such as the requested path echoed back) hash within a few bits
+func simHash(text string) uint64 {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	add := func(feature string) {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(feature))
+		sum := h.Sum64()
+		for bit := range 64 {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+	if len(words) == 1 {
+		add(words[0])
+	}
+	for i := 0; i+1 < len(words); i++ {
+		add(words[i] + " " + words[i+1])
+	}
+
+	var hash uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			hash |= 1 << bit
+		}
+	}
+	return hash
+}
+
+// fingerprintDocument fingerprints a parsed HTML document
+func fingerprintDocument(doc *goquery.Selection) *PageFingerprint {
+	text := visibleText(doc.Find("body"))
+	return &PageFingerprint{
+		Title:        strings.Join(strings.Fields(doc.Find("title").First().Text()), " "),
+		SimHash:      simHash(text),
+		TextSize:     len(text),
+		NotFoundText: notFoundPattern.MatchString(text) || statusCodePattern.MatchString(text),
+	}
+}
+
+// SimilarTo reports whether two fingerprints look like the same page template
+func (fp *PageFingerprint) SimilarTo(other *PageFingerprint) bool {
+	if fp == nil || other == nil {
+		return false
+	}
+	distance := bits.OnesCount64(fp.SimHash ^ other.SimHash)
+	if distance <= soft404MaxDistance {
+		return true
+	}
+	return fp.Title != "" && fp.Title == other.Title && distance <= soft404TitleMatchDistance
+}
+
+// Soft404Reason returns why a page served with a 200 looks like a not-found
+// page, or "" if it does not. template is the fingerprint of the host's
+// response to a URL that cannot exist, or nil if the host returns real 404s.
+func (fp *PageFingerprint) Soft404Reason(template *PageFingerprint) string {
+	if fp == nil {
+		return ""
+	}
+	switch {
+	case fp.SimilarTo(template):
+		return Soft404TemplateMatch
+	case notFoundPattern.MatchString(fp.Title), notFoundTitlePattern.MatchString(fp.Title):
+		return Soft404NotFoundTitle
+	case statusCodePattern.MatchString(fp.Title) && fp.TextSize < soft404TinyText:
+		// A bare 404 title only counts on a page with almost no content
+		return Soft404NotFoundTitle
+	case fp.NotFoundText && fp.TextSize < soft404TinyText:
+		return Soft404NotFoundText
+	}
+	return ""
+}
+
+// setupSoft404Fingerprint configures Colly HTML handler for fingerprinting
+// pages for soft-404 detection. It must be registered before link
+// extraction, which strips the header and footer from the parsed document.
+func setupSoft404Fingerprint(collyClone *colly.Collector) {
+	collyClone.OnHTML("html", func(e *colly.HTMLElement) {
+		result, ok := e.Request.Ctx.GetAny("result").(*CrawlResult)
+		if !ok {
+			return
+		}
+		result.Fingerprint = fingerprintDocument(e.DOM)
+	})
+}
+
+// ProbeNotFound requests a URL on pageURL's host that cannot exist, to learn
+// what the site serves for missing pages. It returns the fingerprint of that
+// response when the host answers with a 200 HTML page (a soft-404 template),
+// or nil when it returns a proper error status. When there is a template the
+// homepage is fetched too: if it matches, every route serves the same
+// document (e.g. a single-page app shell) and nil is returned, since the
+// template cannot tell missing pages apart.
+func (c *Crawler) ProbeNotFound(ctx context.Context, pageURL string) (*PageFingerprint, error) {
+	parsed, err := url.Parse(pageURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid page URL: %s", pageURL)
+	}
+
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate probe path: %w", err)
+	}
+	probeURL := (&url.URL{
+		Scheme: parsed.Scheme,
+		Host:   parsed.Host,
+		Path:   "/adapt-404-probe-" + hex.EncodeToString(token),
+	}).String()
+
+	// Hosts that redirect missing pages (usually to the homepage) surface as
+	// redirects instead, so only a 200 served at the probe URL is a template
+	fingerprint, err := c.fingerprintURL(ctx, probeURL, false)
+	if err != nil || fingerprint == nil {
+		return nil, err
+	}
+
+	homeURL := (&url.URL{Scheme: parsed.Scheme, Host: parsed.Host, Path: "/"}).String()
+	home, err := c.fingerprintURL(ctx, homeURL, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch homepage: %w", err)
+	}
+	if home.SimilarTo(fingerprint) {
+		log.Info().
+			Str("url", homeURL).
+			Msg("Homepage matches not-found template, not using it for soft-404 detection")
+		return nil, nil
+	}
+
+	log.Debug().
+		Str("url", probeURL).
+		Str("title", fingerprint.Title).
+		Int("text_size", fingerprint.TextSize).
+		Msg("Host serves missing pages with a 200 response")
+
+	return fingerprint, nil
+}
+
+// fingerprintURL fetches a URL and fingerprints it, returning nil when the
+// response is not a 200 HTML page or, unless followRedirects, was redirected
+func (c *Crawler) fingerprintURL(ctx context.Context, target string, followRedirects bool) (*PageFingerprint, error) {
+	if _, err := validateCrawlRequest(ctx, target, c.config.SkipSSRFCheck); err != nil {
+		return nil, err
+	}
+
+	res, err := c.fetchWithHeaders(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK || (!followRedirects && len(res.RedirectChain) > 0) ||
+		!strings.Contains(strings.ToLower(res.ContentType), "html") {
+		return nil, nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(res.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return fingerprintDocument(doc.Selection), nil
+}
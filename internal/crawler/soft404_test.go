@@ -0,0 +1,129 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const soft404Template = `<html><head><title>Acme Widgets</title></head><body>
+	<header><nav>Home Products About Contact</nav></header>
+	<main><h1>Sorry, we couldn't find that</h1>
+	<p>The page you were looking for has moved or never existed. Try searching
+	the catalogue or head back to the homepage to browse our range of widgets,
+	gadgets and accessories.</p></main>
+	<footer>Copyright Acme Widgets. All rights reserved.</footer>
+</body></html>`
+
+func fingerprintHTML(t *testing.T, html string) *PageFingerprint {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("Failed to parse HTML: %v", err)
+	}
+	return fingerprintDocument(doc.Selection)
+}
+
+func TestSoft404Reason(t *testing.T) {
+	template := fingerprintHTML(t, soft404Template)
+
+	tests := []struct {
+		name     string
+		html     string
+		template *PageFingerprint
+		want     string
+	}{
+		{"template page", soft404Template, template, Soft404TemplateMatch},
+		{"template with path echoed", strings.Replace(soft404Template, "never existed.", "never existed: /old-page.", 1), template, Soft404TemplateMatch},
+		{"real page", `<html><head><title>Blue widgets | Acme Widgets</title></head><body>
+			<header><nav>Home Products About Contact</nav></header>
+			<main><h1>Blue widgets</h1><p>Our blue widgets come in three sizes and ship
+			worldwide. Each one is tested by hand before it leaves the workshop, and
+			comes with a five year guarantee covering parts and labour.</p></main>
+			<footer>Copyright Acme Widgets. All rights reserved.</footer></body></html>`, template, ""},
+		{"not found title", `<html><head><title>Page Not Found</title></head><body><p>Lots of other content here.</p></body></html>`, nil, Soft404NotFoundTitle},
+		{"404 not found title", `<html><head><title>404 Not Found</title></head><body><p>` +
+			strings.Repeat("Browse our other products instead. ", 20) + `</p></body></html>`, nil, Soft404NotFoundTitle},
+		{"bare 404 title on tiny page", `<html><head><title>404</title></head><body><p>Oops.</p></body></html>`, nil, Soft404NotFoundTitle},
+		{"article about 404 errors", `<html><head><title>Fixing 404 errors in Next.js</title></head><body><p>` +
+			strings.Repeat("Dynamic routes need a fallback so missing pages return the right status. ", 10) + `</p></body></html>`, nil, ""},
+		{"route number", `<html><head><title>Route 404 | City Buses</title></head><body><p>` +
+			strings.Repeat("Departs every fifteen minutes from the central station. ", 10) + `</p></body></html>`, nil, ""},
+		{"product code", `<html><head><title>Bracket SKU 404-B | Acme</title></head><body><p>` +
+			strings.Repeat("Powder coated steel bracket rated to forty kilograms. ", 10) + `</p></body></html>`, nil, ""},
+		{"tiny not found body", `<html><head><title>Acme</title></head><body><p>This page does not exist.</p></body></html>`, nil, Soft404NotFoundText},
+		{"long page mentioning not found", `<html><head><title>Help</title></head><body><p>` +
+			strings.Repeat("Troubleshooting guide for common errors. ", 20) + `If you see page not found, check the URL.</p></body></html>`, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fingerprintHTML(t, tt.html).Soft404Reason(tt.template); got != tt.want {
+				t.Errorf("Expected reason %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProbeNotFound(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    bool
+	}{
+		{"soft 404 template", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			if r.URL.Path == "/" {
+				_, _ = w.Write([]byte(`<html><head><title>Acme Widgets</title></head><body>
+					<main><h1>Welcome to Acme</h1><p>Browse our full range of widgets, gadgets
+					and accessories, built by hand and shipped worldwide.</p></main></body></html>`))
+				return
+			}
+			_, _ = w.Write([]byte(soft404Template))
+		}, true},
+		{"real 404", func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		}, false},
+		{"single-page app shell", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(soft404Template))
+		}, false},
+		{"redirect to homepage", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/" {
+				http.Redirect(w, r, "/", http.StatusFound)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html><title>Home</title></html>"))
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var probedPath string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if probedPath == "" {
+					probedPath = r.URL.Path
+				}
+				tt.handler(w, r)
+			}))
+			defer ts.Close()
+
+			crawler := New(testConfig())
+			fingerprint, err := crawler.ProbeNotFound(context.Background(), ts.URL+"/products/blue")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if (fingerprint != nil) != tt.want {
+				t.Errorf("Expected fingerprint %v, got %+v", tt.want, fingerprint)
+			}
+			if !strings.HasPrefix(probedPath, "/adapt-404-probe-") {
+				t.Errorf("Expected probe path, got %q", probedPath)
+			}
+		})
+	}
+}
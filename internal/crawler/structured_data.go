@@ -0,0 +1,344 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// maxStructuredDataItems caps the items recorded per page
+const maxStructuredDataItems = 50
+
+// Structured data formats
+const (
+	FormatJSONLD    = "json-ld"
+	FormatMicrodata = "microdata"
+)
+
+// Structured data issue codes
+const (
+	SchemaIssueInvalidJSON     = "invalid_json"     // A JSON-LD block does not parse
+	SchemaIssueMissingType     = "missing_type"     // An item declares no @type/itemtype
+	SchemaIssueMissingProperty = "missing_property" // A required property is absent or empty
+)
+
+// StructuredData holds the schema.org items declared by a page and the
+// problems found validating them
+type StructuredData struct {
+	Items  []StructuredDataItem  `json:"items,omitempty"`
+	Issues []StructuredDataIssue `json:"issues,omitempty"`
+}
+
+// StructuredDataItem is a top-level JSON-LD entity or microdata item
+type StructuredDataItem struct {
+	Format     string   `json:"format"`
+	Type       string   `json:"type,omitempty"`       // e.g. "Product", without the schema.org prefix
+	Properties []string `json:"properties,omitempty"` // Declared property names, sorted
+}
+
+// StructuredDataIssue is a structured data problem found on a page
+type StructuredDataIssue struct {
+	Format   string `json:"format"`
+	Type     string `json:"type,omitempty"`
+	Code     string `json:"code"`
+	Property string `json:"property,omitempty"` // Alternatives are joined with " or "
+	Detail   string `json:"detail,omitempty"`
+}
+
+// schemaRule lists the properties a schema.org type needs for rich results
+type schemaRule struct {
+	required [][]string        // Each group needs at least one of its properties
+	children map[string]string // Property whose nested items are checked against another rule
+}
+
+// schemaRules is the bundled subset of schema.org types we validate. Types
+// not listed here are recorded but not checked.
+var schemaRules = map[string]schemaRule{
+	"Article": {required: [][]string{{"headline"}, {"author"}, {"datePublished"}}},
+	"Product": {required: [][]string{{"name"}, {"offers", "review", "aggregateRating"}}},
+	"BreadcrumbList": {
+		required: [][]string{{"itemListElement"}},
+		children: map[string]string{"itemListElement": "ListItem"},
+	},
+	"ListItem":     {required: [][]string{{"position"}, {"name", "item"}}},
+	"Organization": {required: [][]string{{"name"}, {"url"}}},
+	"FAQPage": {
+		required: [][]string{{"mainEntity"}},
+		children: map[string]string{"mainEntity": "Question"},
+	},
+	"Question": {required: [][]string{{"name"}, {"acceptedAnswer"}}},
+}
+
+// schemaSubtypes maps common subtypes to the rule of their parent type
+var schemaSubtypes = map[string]string{
+	"NewsArticle":             "Article",
+	"BlogPosting":             "Article",
+	"TechArticle":             "Article",
+	"ScholarlyArticle":        "Article",
+	"Report":                  "Article",
+	"Corporation":             "Organization",
+	"NGO":                     "Organization",
+	"EducationalOrganization": "Organization",
+	"GovernmentOrganization":  "Organization",
+}
+
+// schemaType returns the first declared type of an item, without the
+// schema.org prefix
+func schemaType(value any) string {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []any:
+		if len(v) > 0 {
+			raw, _ = v[0].(string)
+		}
+	}
+	raw = strings.TrimSpace(raw)
+	for _, prefix := range []string{"https://schema.org/", "http://schema.org/", "schema:"} {
+		if len(raw) > len(prefix) && strings.EqualFold(raw[:len(prefix)], prefix) {
+			return raw[len(prefix):]
+		}
+	}
+	return raw
+}
+
+// hasSchemaValue reports whether a property value is present and non-empty
+func hasSchemaValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return strings.TrimSpace(v) != ""
+	case []any:
+		return slices.ContainsFunc(v, hasSchemaValue)
+	case map[string]any:
+		return len(v) > 0
+	}
+	return true
+}
+
+// schemaChildren returns the nested items held by a property value
+func schemaChildren(value any) []map[string]any {
+	switch v := value.(type) {
+	case map[string]any:
+		return []map[string]any{v}
+	case []any:
+		children := make([]map[string]any, 0, len(v))
+		for _, element := range v {
+			if child, ok := element.(map[string]any); ok {
+				children = append(children, child)
+			}
+		}
+		return children
+	}
+	return nil
+}
+
+// checkSchemaRule reports the required properties an item is missing, and
+// those missing from its nested items
+func checkSchemaRule(format, itemType string, rule schemaRule, item map[string]any, detail string) []StructuredDataIssue {
+	var issues []StructuredDataIssue
+	for _, group := range rule.required {
+		if !slices.ContainsFunc(group, func(property string) bool { return hasSchemaValue(item[property]) }) {
+			issues = append(issues, StructuredDataIssue{
+				Format:   format,
+				Type:     itemType,
+				Code:     SchemaIssueMissingProperty,
+				Property: strings.Join(group, " or "),
+				Detail:   detail,
+			})
+		}
+	}
+
+	for property, childType := range rule.children {
+		for i, child := range schemaChildren(item[property]) {
+			issues = append(issues, checkSchemaRule(format, childType, schemaRules[childType], child,
+				fmt.Sprintf("%s item %d", property, i+1))...)
+		}
+	}
+	return issues
+}
+
+// validateSchemaItem summarises a top-level item and checks it against the
+// bundled rules
+func validateSchemaItem(format string, item map[string]any) (StructuredDataItem, []StructuredDataIssue) {
+	summary := StructuredDataItem{Format: format, Type: schemaType(item["@type"])}
+	for property := range item {
+		if !strings.HasPrefix(property, "@") {
+			summary.Properties = append(summary.Properties, property)
+		}
+	}
+	slices.Sort(summary.Properties)
+
+	if summary.Type == "" {
+		return summary, []StructuredDataIssue{{Format: format, Code: SchemaIssueMissingType}}
+	}
+
+	ruleName := summary.Type
+	if parent, ok := schemaSubtypes[ruleName]; ok {
+		ruleName = parent
+	}
+	rule, ok := schemaRules[ruleName]
+	if !ok {
+		return summary, nil
+	}
+	return summary, checkSchemaRule(format, summary.Type, rule, item, "")
+}
+
+// parseJSONLD returns the top-level entities of a JSON-LD block, expanding
+// arrays and @graph
+func parseJSONLD(block string) ([]map[string]any, error) {
+	var doc any
+	if err := json.Unmarshal([]byte(block), &doc); err != nil {
+		return nil, err
+	}
+
+	var entities []map[string]any
+	var collect func(value any)
+	collect = func(value any) {
+		switch v := value.(type) {
+		case []any:
+			for _, element := range v {
+				collect(element)
+			}
+		case map[string]any:
+			if graph, ok := v["@graph"]; ok {
+				collect(graph)
+				if _, typed := v["@type"]; !typed {
+					return
+				}
+			}
+			entities = append(entities, v)
+		}
+	}
+	collect(doc)
+
+	if entities == nil {
+		return nil, fmt.Errorf("no JSON-LD objects found")
+	}
+	return entities, nil
+}
+
+// microdataValue returns an itemprop element's value per the HTML microdata rules
+func microdataValue(s *goquery.Selection) string {
+	var attr string
+	switch goquery.NodeName(s) {
+	case "meta":
+		attr = "content"
+	case "a", "area", "link":
+		attr = "href"
+	case "audio", "embed", "iframe", "img", "source", "track", "video":
+		attr = "src"
+	case "object":
+		attr = "data"
+	case "time":
+		attr = "datetime"
+	case "data", "meter":
+		attr = "value"
+	}
+	if attr != "" {
+		if value, ok := s.Attr(attr); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
+
+// parseMicrodataItem converts an itemscope element into the same shape as a
+// JSON-LD entity so both formats share validation
+func parseMicrodataItem(scope *goquery.Selection) map[string]any {
+	item := map[string]any{}
+	if types := strings.Fields(scope.AttrOr("itemtype", "")); len(types) > 0 {
+		item["@type"] = types[0]
+	}
+
+	scope.Find("[itemprop]").Each(func(i int, s *goquery.Selection) {
+		// Properties belong to their nearest enclosing item
+		if !s.ParentsFiltered("[itemscope]").First().IsSelection(scope) {
+			return
+		}
+
+		var value any
+		if _, nested := s.Attr("itemscope"); nested {
+			value = parseMicrodataItem(s)
+		} else {
+			value = microdataValue(s)
+		}
+
+		for name := range strings.FieldsSeq(s.AttrOr("itemprop", "")) {
+			switch existing := item[name].(type) {
+			case nil:
+				item[name] = value
+			case []any:
+				item[name] = append(existing, value)
+			default:
+				item[name] = []any{existing, value}
+			}
+		}
+	})
+	return item
+}
+
+// setupStructuredDataExtraction configures Colly HTML handler for JSON-LD and
+// microdata extraction and validation. It must be registered before link
+// extraction, which strips the header and footer from the parsed document.
+func setupStructuredDataExtraction(collyClone *colly.Collector) {
+	collyClone.OnHTML("html", func(e *colly.HTMLElement) {
+		result, ok := e.Request.Ctx.GetAny("result").(*CrawlResult)
+		if !ok {
+			return
+		}
+
+		data := &StructuredData{}
+		record := func(format string, entity map[string]any) {
+			if len(data.Items) >= maxStructuredDataItems {
+				return
+			}
+			item, issues := validateSchemaItem(format, entity)
+			data.Items = append(data.Items, item)
+			data.Issues = append(data.Issues, issues...)
+		}
+
+		blocks := 0
+		e.DOM.Find("script[type]").Each(func(i int, s *goquery.Selection) {
+			if !strings.EqualFold(strings.TrimSpace(s.AttrOr("type", "")), "application/ld+json") {
+				return
+			}
+			blocks++
+			block := strings.TrimSpace(s.Text())
+			if block == "" {
+				return
+			}
+			entities, err := parseJSONLD(block)
+			if err != nil {
+				data.Issues = append(data.Issues, StructuredDataIssue{
+					Format: FormatJSONLD,
+					Code:   SchemaIssueInvalidJSON,
+					Detail: fmt.Sprintf("block %d: %s", blocks, err.Error()),
+				})
+				return
+			}
+			for _, entity := range entities {
+				record(FormatJSONLD, entity)
+			}
+		})
+
+		e.DOM.Find("[itemscope]:not([itemprop])").Each(func(i int, s *goquery.Selection) {
+			record(FormatMicrodata, parseMicrodataItem(s))
+		})
+
+		result.StructuredData = data
+
+		log.Debug().
+			Str("url", e.Request.URL.String()).
+			Int("items", len(data.Items)).
+			Int("issues", len(data.Issues)).
+			Msg("Extracted structured data from page")
+	})
+}
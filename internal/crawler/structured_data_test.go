@@ -0,0 +1,102 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestWarmURLExtractsStructuredData(t *testing.T) {
+	page := `<html><head>
+		<script type="application/ld+json">
+		{"@context": "https://schema.org", "@graph": [
+			{"@type": "Organization", "name": "Example", "url": "https://example.com"},
+			{"@type": "BreadcrumbList", "itemListElement": [
+				{"@type": "ListItem", "position": 1, "name": "Home", "item": "https://example.com/"},
+				{"@type": "ListItem", "name": "Widgets"}
+			]}
+		]}
+		</script>
+		<script type="application/ld+json">{"@type": "Product", "name": "Widget",}</script>
+	</head><body>
+		<footer>
+			<div itemscope itemtype="https://schema.org/Product">
+				<span itemprop="name">Blue widget</span>
+				<div itemprop="brand" itemscope itemtype="https://schema.org/Brand">
+					<span itemprop="name">Acme</span>
+				</div>
+			</div>
+		</footer>
+	</body></html>`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(page))
+	}))
+	defer ts.Close()
+
+	result, err := New(testConfig()).WarmURL(context.Background(), ts.URL+"/", true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data := result.StructuredData
+	if data == nil {
+		t.Fatal("Expected structured data")
+	}
+
+	var types []string
+	for _, item := range data.Items {
+		types = append(types, item.Format+":"+item.Type)
+	}
+	wantTypes := []string{"json-ld:Organization", "json-ld:BreadcrumbList", "microdata:Product"}
+	if !slices.Equal(types, wantTypes) {
+		t.Errorf("Expected items %v, got %v", wantTypes, types)
+	}
+	if props := data.Items[2].Properties; !slices.Equal(props, []string{"brand", "name"}) {
+		t.Errorf("Expected nested item's properties to stay on the nested item, got %v", props)
+	}
+
+	want := []StructuredDataIssue{
+		{Format: FormatJSONLD, Type: "ListItem", Code: SchemaIssueMissingProperty, Property: "position", Detail: "itemListElement item 2"},
+		{Format: FormatJSONLD, Code: SchemaIssueInvalidJSON, Detail: "block 2: invalid character '}' looking for beginning of object key string"},
+		{Format: FormatMicrodata, Type: "Product", Code: SchemaIssueMissingProperty, Property: "offers or review or aggregateRating"},
+	}
+	if !slices.Equal(data.Issues, want) {
+		t.Errorf("Expected issues %+v, got %+v", want, data.Issues)
+	}
+}
+
+func TestValidateSchemaItem(t *testing.T) {
+	tests := []struct {
+		name       string
+		item       map[string]any
+		wantType   string
+		wantIssues []string
+	}{
+		{"complete article subtype", map[string]any{"@type": "BlogPosting", "headline": "Hi", "author": map[string]any{"name": "Sam"}, "datePublished": "2026-01-01"}, "BlogPosting", nil},
+		{"empty values count as missing", map[string]any{"@type": "Article", "headline": " ", "author": []any{}, "datePublished": "2026-01-01"}, "Article", []string{"headline", "author"}},
+		{"prefixed type", map[string]any{"@type": "http://schema.org/Organization", "name": "Example"}, "Organization", []string{"url"}},
+		{"faq questions", map[string]any{"@type": "FAQPage", "mainEntity": map[string]any{"@type": "Question", "name": "Why?"}}, "FAQPage", []string{"acceptedAnswer"}},
+		{"unvalidated type", map[string]any{"@type": []any{"Event"}}, "Event", nil},
+		{"no type", map[string]any{"name": "Example"}, "", []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, issues := validateSchemaItem(FormatJSONLD, tt.item)
+			if item.Type != tt.wantType {
+				t.Errorf("Expected type %q, got %q", tt.wantType, item.Type)
+			}
+			var properties []string
+			for _, issue := range issues {
+				properties = append(properties, issue.Property)
+			}
+			if !slices.Equal(properties, tt.wantIssues) {
+				t.Errorf("Expected missing %v, got %+v", tt.wantIssues, issues)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package crawler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"math"
+	"time"
+)
+
+// TLSInfo is the negotiated TLS state of an HTTPS response
+type TLSInfo struct {
+	Version      string            `json:"version"`      // e.g. "TLS 1.3"
+	CipherSuite  string            `json:"cipher_suite"` // e.g. "TLS_AES_128_GCM_SHA256"
+	Certificates []CertificateInfo `json:"certificates"` // Chain as served, leaf first
+}
+
+// CertificateInfo summarises one certificate in a served chain
+type CertificateInfo struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	DNSNames  []string  `json:"dns_names,omitempty"` // Subject alternative names
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+}
+
+// Leaf returns the server's own certificate, or nil if none was served
+func (t *TLSInfo) Leaf() *CertificateInfo {
+	if t == nil || len(t.Certificates) == 0 {
+		return nil
+	}
+	return &t.Certificates[0]
+}
+
+// ExpiresInDays returns the whole days until the certificate expires,
+// negative once it has expired
+func (c *CertificateInfo) ExpiresInDays(now time.Time) int {
+	return int(math.Floor(c.NotAfter.Sub(now).Hours() / 24))
+}
+
+// certificateName prefers a certificate name's common name, falling back to
+// the full distinguished name
+func certificateName(cn, full string) string {
+	if cn != "" {
+		return cn
+	}
+	return full
+}
+
+// summariseTLS converts a connection state into a TLSInfo, or nil for a
+// plain HTTP response
+func summariseTLS(state *tls.ConnectionState) *TLSInfo {
+	if state == nil {
+		return nil
+	}
+
+	info := &TLSInfo{
+		Version:      tls.VersionName(state.Version),
+		CipherSuite:  tls.CipherSuiteName(state.CipherSuite),
+		Certificates: make([]CertificateInfo, 0, len(state.PeerCertificates)),
+	}
+	for _, cert := range state.PeerCertificates {
+		info.Certificates = append(info.Certificates, certificateInfo(cert))
+	}
+	return info
+}
+
+func certificateInfo(cert *x509.Certificate) CertificateInfo {
+	return CertificateInfo{
+		Subject:   certificateName(cert.Subject.CommonName, cert.Subject.String()),
+		Issuer:    certificateName(cert.Issuer.CommonName, cert.Issuer.String()),
+		DNSNames:  cert.DNSNames,
+		NotBefore: cert.NotBefore.UTC(),
+		NotAfter:  cert.NotAfter.UTC(),
+	}
+}
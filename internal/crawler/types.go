@@ -16,6 +16,16 @@ type PerformanceMetrics struct {
 	TLSHandshakeTime    int64 `json:"tls_handshake_time"`
 	TTFB                int64 `json:"ttfb"`
 	ContentTransferTime int64 `json:"content_transfer_time"`
+
+	tls *TLSInfo // Set by the tracing transport once the response arrives
+}
+
+// RedirectHop records a single redirect response in a redirect chain.
+type RedirectHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	Location   string `json:"location"`
+	Duration   int64  `json:"duration_ms"`
 }
 
 // MaxBodySampleSize is the maximum size of body sample stored for tech detection (50KB)
@@ -29,23 +39,44 @@ type CrawlResult struct {
 	Error               string              `json:"error,omitempty"`
 	Warning             string              `json:"warning,omitempty"`
 	CacheStatus         string              `json:"cache_status"`
+	CDNProvider         string              `json:"cdn_provider,omitempty"`
+	CacheAge            *int64              `json:"cache_age,omitempty"` // Seconds, from the Age header
+	CacheTTL            *int64              `json:"cache_ttl,omitempty"` // Seconds until the cached object expires
 	ContentType         string              `json:"content_type"`
 	ContentLength       int64               `json:"content_length"`
 	Headers             http.Header         `json:"headers"`
 	RedirectURL         string              `json:"redirect_url"`
+	RedirectChain       []RedirectHop       `json:"redirect_chain,omitempty"`
+	RedirectLoop        bool                `json:"redirect_loop,omitempty"`
+	RedirectLimitHit    bool                `json:"redirect_limit_hit,omitempty"`
 	Performance         PerformanceMetrics  `json:"performance"`
 	Timestamp           int64               `json:"timestamp"`
 	RetryCount          int                 `json:"retry_count"`
 	SkippedCrawl        bool                `json:"skipped_crawl,omitempty"`
 	Links               map[string][]string `json:"links,omitempty"`
+	Assets              []string            `json:"assets,omitempty"`
 	SecondResponseTime  int64               `json:"second_response_time,omitempty"`
 	SecondCacheStatus   string              `json:"second_cache_status,omitempty"`
 	SecondContentLength int64               `json:"second_content_length,omitempty"`
 	SecondHeaders       http.Header         `json:"second_headers,omitempty"`
 	SecondPerformance   *PerformanceMetrics `json:"second_performance,omitempty"`
 	CacheCheckAttempts  []CacheCheckAttempt `json:"cache_check_attempts,omitempty"`
-	BodySample          []byte              `json:"-"` // Truncated body for tech detection (not serialised)
-	Body                []byte              `json:"-"` // Full body for storage upload (not serialised)
+	Variants            []VariantResult     `json:"variants,omitempty"`
+	Audit               *PageAudit          `json:"audit,omitempty"`
+	StructuredData      *StructuredData     `json:"structured_data,omitempty"`
+	TLS                 *TLSInfo            `json:"tls,omitempty"`
+	InsecureContent     []InsecureReference `json:"insecure_content,omitempty"`
+	Anchors             []string            `json:"anchors,omitempty"`
+	Fingerprint         *PageFingerprint    `json:"fingerprint,omitempty"`
+	Soft404             string              `json:"soft_404,omitempty"`
+	ETag                string              `json:"etag,omitempty"`
+	LastModified        string              `json:"last_modified,omitempty"`
+	NotModified         bool                `json:"not_modified,omitempty"` // 304 to a conditional request
+	ContentHash         string              `json:"content_hash,omitempty"` // Of the normalised page, for change tracking
+	MainText            string              `json:"-"`                      // Normalised main content text for diffs (not serialised)
+	SEO                 *SEOSignals         `json:"seo,omitempty"`          // Nil for non-HTML responses
+	BodySample          []byte              `json:"-"`                      // Truncated body for tech detection (not serialised)
+	Body                []byte              `json:"-"`                      // Full body for storage upload (not serialised)
 }
 
 // CrawlOptions defines configuration options for a crawl operation
@@ -0,0 +1,273 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MaxWarmVariants caps the number of variants (devices × encodings × languages)
+// warmed per URL, since each variant costs at least one extra request
+const MaxWarmVariants = 12
+
+const (
+	// maxVariantTime bounds the requests for one variant, so a slow variant
+	// cannot use up the time left for the others
+	maxVariantTime = 15 * time.Second
+
+	// variantDeadlineReserve is left before the caller's deadline for
+	// recording the result once variants are warmed
+	variantDeadlineReserve = 10 * time.Second
+)
+
+// Device user agents sent for device variants. The crawler's own user agent
+// is appended so requests stay identifiable in origin logs.
+var deviceUserAgents = map[string]string{
+	"desktop": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+	"mobile":  "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+	"tablet":  "Mozilla/5.0 (iPad; CPU OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+}
+
+var warmEncodings = map[string]bool{
+	"br":       true,
+	"gzip":     true,
+	"deflate":  true,
+	"zstd":     true,
+	"identity": true,
+}
+
+var languageTag = regexp.MustCompile(`^[A-Za-z]{1,8}(-[A-Za-z0-9]{1,8})*$`)
+
+// VariantMatrix is a job's cache variant configuration. Every combination of
+// its dimensions is warmed; an empty dimension uses the crawler's default.
+type VariantMatrix struct {
+	Devices   []string `json:"devices,omitempty"`   // "desktop", "mobile", "tablet"
+	Encodings []string `json:"encodings,omitempty"` // Accept-Encoding, e.g. "br", "gzip"
+	Languages []string `json:"languages,omitempty"` // Accept-Language, e.g. "en-AU", "fr"
+}
+
+// WarmVariant is a single combination of request headers to warm
+type WarmVariant struct {
+	Device   string `json:"device,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+// String returns a compact label such as "mobile/br/en-AU"
+func (v WarmVariant) String() string {
+	label := func(value string) string {
+		if value == "" {
+			return "default"
+		}
+		return value
+	}
+	return label(v.Device) + "/" + label(v.Encoding) + "/" + label(v.Language)
+}
+
+// VariantResult records the cache status and timing of one warmed variant
+type VariantResult struct {
+	WarmVariant
+	StatusCode         int    `json:"status_code"`
+	CacheStatus        string `json:"cache_status"`
+	ResponseTime       int64  `json:"response_time"`
+	SecondCacheStatus  string `json:"second_cache_status,omitempty"`
+	SecondResponseTime int64  `json:"second_response_time,omitempty"`
+	Error              string `json:"error,omitempty"`
+}
+
+// Validate checks dimension values and the total variant count
+func (m *VariantMatrix) Validate() error {
+	if m == nil {
+		return nil
+	}
+	for _, device := range m.Devices {
+		if _, ok := deviceUserAgents[device]; !ok {
+			return fmt.Errorf("unknown device %q (expected desktop, mobile or tablet)", device)
+		}
+	}
+	for _, encoding := range m.Encodings {
+		if !warmEncodings[encoding] {
+			return fmt.Errorf("unsupported encoding %q (expected br, gzip, deflate, zstd or identity)", encoding)
+		}
+	}
+	for _, language := range m.Languages {
+		if !languageTag.MatchString(language) {
+			return fmt.Errorf("invalid language tag %q", language)
+		}
+	}
+	if count := len(m.Expand()); count > MaxWarmVariants {
+		return fmt.Errorf("variant matrix has %d combinations; the maximum is %d", count, MaxWarmVariants)
+	}
+	return nil
+}
+
+// Expand returns every combination of the matrix dimensions, with duplicate
+// values removed. A matrix with no dimensions set expands to nothing.
+func (m *VariantMatrix) Expand() []WarmVariant {
+	if m == nil || (len(m.Devices) == 0 && len(m.Encodings) == 0 && len(m.Languages) == 0) {
+		return nil
+	}
+
+	var variants []WarmVariant
+	for _, device := range dimensionValues(m.Devices) {
+		for _, encoding := range dimensionValues(m.Encodings) {
+			for _, language := range dimensionValues(m.Languages) {
+				variants = append(variants, WarmVariant{Device: device, Encoding: encoding, Language: language})
+			}
+		}
+	}
+	return variants
+}
+
+func dimensionValues(values []string) []string {
+	if len(values) == 0 {
+		return []string{""}
+	}
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+type warmVariantsKey struct{}
+
+// WithWarmVariants returns a context that makes WarmURL also warm each of the
+// given variants once the primary request succeeds
+func WithWarmVariants(ctx context.Context, variants []WarmVariant) context.Context {
+	return context.WithValue(ctx, warmVariantsKey{}, variants)
+}
+
+func warmVariantsFrom(ctx context.Context) []WarmVariant {
+	variants, _ := ctx.Value(warmVariantsKey{}).([]WarmVariant)
+	return variants
+}
+
+// variantHeaders builds the request headers for a variant, falling back to
+// the crawler defaults for unset dimensions
+func (c *Crawler) variantHeaders(v WarmVariant) http.Header {
+	headers := http.Header{}
+	headers.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
+
+	if ua, ok := deviceUserAgents[v.Device]; ok {
+		headers.Set("User-Agent", ua+" "+c.config.UserAgent)
+	}
+
+	encoding := v.Encoding
+	if encoding == "" {
+		encoding = "gzip, deflate, br"
+	}
+	headers.Set("Accept-Encoding", encoding)
+
+	language := "en-US,en;q=0.9"
+	if v.Language != "" {
+		language = v.Language
+	}
+	headers.Set("Accept-Language", language)
+
+	return headers
+}
+
+// variantBudget returns how long the next of remaining variants may take: an
+// even share of the time left before ctx's deadline, less a reserve, and no
+// more than maxVariantTime
+func variantBudget(ctx context.Context, remaining int) time.Duration {
+	budget := maxVariantTime
+	if deadline, ok := ctx.Deadline(); ok {
+		budget = min(budget, (time.Until(deadline)-variantDeadlineReserve)/time.Duration(remaining))
+	}
+	return budget
+}
+
+// warmVariants requests each variant in turn, repeating cache misses once to
+// confirm the variant was warmed. Variants are sequential so the extra load
+// on the origin stays within the task's own request budget, and each has its
+// own deadline so the matrix finishes within the caller's.
+func (c *Crawler) warmVariants(ctx context.Context, targetURL string, variants []WarmVariant) []VariantResult {
+	results := make([]VariantResult, 0, len(variants))
+
+	for i, variant := range variants {
+		budget := variantBudget(ctx, len(variants)-i)
+		if ctx.Err() != nil || budget <= 0 {
+			log.Debug().
+				Str("url", targetURL).
+				Int("skipped_variants", len(variants)-i).
+				Msg("No time left to warm remaining cache variants")
+			break
+		}
+
+		results = append(results, c.warmVariant(ctx, targetURL, variant, budget))
+	}
+
+	cold := 0
+	for _, r := range results {
+		if r.IsCold() {
+			cold++
+		}
+	}
+	log.Debug().
+		Str("url", targetURL).
+		Int("variants", len(results)).
+		Int("cold_variants", cold).
+		Msg("Warmed cache variants")
+
+	return results
+}
+
+// warmVariant requests one variant within budget, repeating a cache miss
+// once to confirm it was warmed
+func (c *Crawler) warmVariant(ctx context.Context, targetURL string, variant WarmVariant, budget time.Duration) VariantResult {
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	result := VariantResult{WarmVariant: variant}
+	headers := c.variantHeaders(variant)
+
+	first, err := c.fetchWithHeaders(ctx, http.MethodGet, targetURL, headers)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.StatusCode = first.StatusCode
+	result.CacheStatus = first.CacheStatus
+	result.ResponseTime = first.ResponseTime
+	result.Error = first.Error
+
+	if first.Error == "" && shouldMakeSecondRequest(first.CacheStatus) {
+		// Give the CDN a moment to store the object before re-checking
+		select {
+		case <-time.After(500 * time.Millisecond):
+		case <-ctx.Done():
+			return result
+		}
+
+		if second, err := c.fetchWithHeaders(ctx, http.MethodGet, targetURL, headers); err == nil {
+			result.SecondCacheStatus = second.CacheStatus
+			result.SecondResponseTime = second.ResponseTime
+		}
+	}
+
+	return result
+}
+
+// IsCold reports whether the variant was still a cache miss after warming,
+// or could not be fetched. Uncacheable responses (BYPASS, DYNAMIC) are not
+// counted as cold since warming cannot help them.
+func (r VariantResult) IsCold() bool {
+	if r.Error != "" {
+		return true
+	}
+	last := r.CacheStatus
+	if r.SecondCacheStatus != "" {
+		last = r.SecondCacheStatus
+	}
+	return shouldMakeSecondRequest(last)
+}
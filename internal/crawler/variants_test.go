@@ -0,0 +1,171 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVariantMatrixExpand(t *testing.T) {
+	var nilMatrix *VariantMatrix
+	if variants := nilMatrix.Expand(); variants != nil {
+		t.Errorf("Expected nil matrix to expand to nothing, got %v", variants)
+	}
+	if variants := (&VariantMatrix{}).Expand(); variants != nil {
+		t.Errorf("Expected empty matrix to expand to nothing, got %v", variants)
+	}
+
+	matrix := &VariantMatrix{
+		Devices:   []string{"mobile", "desktop", "mobile"},
+		Encodings: []string{"br", "gzip"},
+	}
+	variants := matrix.Expand()
+	if len(variants) != 4 {
+		t.Fatalf("Expected 4 variants, got %d: %v", len(variants), variants)
+	}
+	if variants[0] != (WarmVariant{Device: "mobile", Encoding: "br"}) {
+		t.Errorf("Unexpected first variant %+v", variants[0])
+	}
+	if got := variants[0].String(); got != "mobile/br/default" {
+		t.Errorf("Expected label mobile/br/default, got %q", got)
+	}
+}
+
+func TestVariantMatrixValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		matrix  *VariantMatrix
+		wantErr string
+	}{
+		{name: "nil", matrix: nil},
+		{name: "valid", matrix: &VariantMatrix{Devices: []string{"mobile"}, Encodings: []string{"br"}, Languages: []string{"en-AU", "fr"}}},
+		{name: "unknown device", matrix: &VariantMatrix{Devices: []string{"watch"}}, wantErr: "unknown device"},
+		{name: "unknown encoding", matrix: &VariantMatrix{Encodings: []string{"lzma"}}, wantErr: "unsupported encoding"},
+		{name: "bad language", matrix: &VariantMatrix{Languages: []string{"en AU"}}, wantErr: "invalid language"},
+		{
+			name: "too many combinations",
+			matrix: &VariantMatrix{
+				Devices:   []string{"desktop", "mobile", "tablet"},
+				Encodings: []string{"br", "gzip"},
+				Languages: []string{"en", "fr", "de"},
+			},
+			wantErr: "maximum is 12",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.matrix.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWarmURLWarmsEachVariant(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[string]int)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Accept-Encoding") + "|" + r.Header.Get("Accept-Language")
+		if strings.Contains(r.Header.Get("User-Agent"), "iPhone") {
+			key = "mobile|" + key
+		}
+
+		mu.Lock()
+		seen[key]++
+		count := seen[key]
+		mu.Unlock()
+
+		// The gzip variant never caches, every other variant warms on the second request
+		status := "HIT"
+		if count == 1 || strings.HasSuffix(key, "gzip|fr") {
+			status = "MISS"
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("CF-Cache-Status", status)
+		_, _ = w.Write([]byte("<html><body>ok</body></html>"))
+	}))
+	defer ts.Close()
+
+	matrix := &VariantMatrix{
+		Devices:   []string{"mobile"},
+		Encodings: []string{"br", "gzip"},
+		Languages: []string{"fr"},
+	}
+
+	crawler := New(testConfig())
+	result, err := crawler.WarmURL(WithWarmVariants(context.Background(), matrix.Expand()), ts.URL+"/", false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.Variants) != 2 {
+		t.Fatalf("Expected 2 variant results, got %d: %+v", len(result.Variants), result.Variants)
+	}
+
+	warm, cold := result.Variants[0], result.Variants[1]
+	if warm.Encoding != "br" || warm.CacheStatus != "MISS" || warm.SecondCacheStatus != "HIT" || warm.IsCold() {
+		t.Errorf("Expected br variant to warm on the second request, got %+v", warm)
+	}
+	if cold.Encoding != "gzip" || cold.SecondCacheStatus != "MISS" || !cold.IsCold() {
+		t.Errorf("Expected gzip variant to stay cold, got %+v", cold)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if seen["mobile|br|fr"] != 2 || seen["mobile|gzip|fr"] != 2 {
+		t.Errorf("Expected each variant to be requested twice with its headers, got %v", seen)
+	}
+}
+
+func TestVariantBudget(t *testing.T) {
+	if got := variantBudget(context.Background(), 12); got != maxVariantTime {
+		t.Errorf("Expected %s without a deadline, got %s", maxVariantTime, got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), variantDeadlineReserve+60*time.Second)
+	defer cancel()
+	if got := variantBudget(ctx, 12); got > 5*time.Second || got < 4*time.Second {
+		t.Errorf("Expected about 5s each for 12 variants in 60s, got %s", got)
+	}
+	if got := variantBudget(ctx, 1); got != maxVariantTime {
+		t.Errorf("Expected one variant to be capped at %s, got %s", maxVariantTime, got)
+	}
+
+	short, cancelShort := context.WithTimeout(context.Background(), variantDeadlineReserve/2)
+	defer cancelShort()
+	if got := variantBudget(short, 1); got > 0 {
+		t.Errorf("Expected no time for variants inside the reserve, got %s", got)
+	}
+}
+
+func TestWarmVariantsSkipsWhenOutOfTime(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html><body>ok</body></html>"))
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), variantDeadlineReserve/2)
+	defer cancel()
+
+	crawler := New(testConfig())
+	results := crawler.warmVariants(ctx, ts.URL+"/", []WarmVariant{{Device: "mobile"}, {Device: "tablet"}})
+	if len(results) != 0 || requests != 0 {
+		t.Errorf("Expected no variants warmed inside the deadline reserve, got %d results and %d requests", len(results), requests)
+	}
+}
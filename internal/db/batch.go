@@ -671,6 +671,20 @@ func (bm *BatchManager) batchUpdateCompleted(ctx context.Context, tx *sql.Tx, ta
 	secondContentTransferTimes := make([]int64, len(tasks))
 	retryCounts := make([]int, len(tasks))
 	cacheCheckAttempts := make([]string, len(tasks))
+	redirectChains := make([]string, len(tasks))
+	redirectLoops := make([]bool, len(tasks))
+	redirectLimitHits := make([]bool, len(tasks))
+	cacheVariants := make([]string, len(tasks))
+	cdnProviders := make([]string, len(tasks))
+	cacheExpiresAts := make([]string, len(tasks))
+	seo := make([]string, len(tasks))
+	pageAudits := make([]string, len(tasks))
+	structuredData := make([]string, len(tasks))
+	insecureContent := make([]string, len(tasks))
+	etags := make([]string, len(tasks))
+	lastModifieds := make([]string, len(tasks))
+	notModifieds := make([]bool, len(tasks))
+	contentHashes := make([]string, len(tasks))
 
 	for i, task := range tasks {
 		ids[i] = task.ID
@@ -716,6 +730,22 @@ func (bm *BatchManager) batchUpdateCompleted(ctx context.Context, tx *sql.Tx, ta
 		} else {
 			cacheCheckAttempts[i] = string(task.CacheCheckAttempts)
 		}
+
+		// Empty string becomes NULL via NULLIF in the query
+		redirectChains[i] = string(task.RedirectChain)
+		redirectLoops[i] = task.RedirectLoop
+		redirectLimitHits[i] = task.RedirectLimitHit
+		cacheVariants[i] = string(task.CacheVariants)
+		cdnProviders[i] = task.CDNProvider
+		cacheExpiresAts[i] = formatNullableTime(task.CacheExpiresAt)
+		seo[i] = string(task.SEO)
+		pageAudits[i] = string(task.PageAudit)
+		structuredData[i] = string(task.StructuredData)
+		insecureContent[i] = string(task.InsecureContent)
+		etags[i] = task.ETag
+		lastModifieds[i] = task.LastModified
+		notModifieds[i] = task.NotModified
+		contentHashes[i] = task.ContentHash
 	}
 
 	// Single UPDATE statement using unnest to batch update all tasks
@@ -746,7 +776,21 @@ func (bm *BatchManager) batchUpdateCompleted(ctx context.Context, tx *sql.Tx, ta
 			second_ttfb = updates.second_ttfb,
 			second_content_transfer_time = updates.second_content_transfer_time,
 			retry_count = updates.retry_count,
-			cache_check_attempts = updates.cache_check_attempts::jsonb
+			cache_check_attempts = updates.cache_check_attempts::jsonb,
+			redirect_chain = NULLIF(updates.redirect_chain, '')::jsonb,
+			redirect_loop = updates.redirect_loop,
+			redirect_limit_exceeded = updates.redirect_limit_exceeded,
+			cache_variants = NULLIF(updates.cache_variants, '')::jsonb,
+			cdn_provider = NULLIF(updates.cdn_provider, ''),
+			cache_expires_at = NULLIF(updates.cache_expires_at, '')::timestamptz,
+			seo = NULLIF(updates.seo, '')::jsonb,
+			page_audit = NULLIF(updates.page_audit, '')::jsonb,
+			structured_data = NULLIF(updates.structured_data, '')::jsonb,
+			insecure_content = NULLIF(updates.insecure_content, '')::jsonb,
+			etag = NULLIF(updates.etag, ''),
+			last_modified = NULLIF(updates.last_modified, ''),
+			not_modified = updates.not_modified,
+			content_hash = NULLIF(updates.content_hash, '')
 		FROM (
 			SELECT
 				unnest($1::text[]) AS id,
@@ -773,7 +817,21 @@ func (bm *BatchManager) batchUpdateCompleted(ctx context.Context, tx *sql.Tx, ta
 				unnest($22::bigint[]) AS second_ttfb,
 				unnest($23::bigint[]) AS second_content_transfer_time,
 				unnest($24::integer[]) AS retry_count,
-				unnest($25::text[]) AS cache_check_attempts
+				unnest($25::text[]) AS cache_check_attempts,
+				unnest($26::text[]) AS redirect_chain,
+				unnest($27::boolean[]) AS redirect_loop,
+				unnest($28::boolean[]) AS redirect_limit_exceeded,
+				unnest($29::text[]) AS cache_variants,
+				unnest($30::text[]) AS cdn_provider,
+				unnest($31::text[]) AS cache_expires_at,
+				unnest($32::text[]) AS seo,
+				unnest($33::text[]) AS page_audit,
+				unnest($34::text[]) AS structured_data,
+				unnest($35::text[]) AS insecure_content,
+				unnest($36::text[]) AS etag,
+				unnest($37::text[]) AS last_modified,
+				unnest($38::boolean[]) AS not_modified,
+				unnest($39::text[]) AS content_hash
 		) AS updates
 		WHERE tasks.id = updates.id
 	`
@@ -804,12 +862,38 @@ func (bm *BatchManager) batchUpdateCompleted(ctx context.Context, tx *sql.Tx, ta
 		pq.Array(secondContentTransferTimes),
 		pq.Array(retryCounts),
 		pq.Array(cacheCheckAttempts),
+		pq.Array(redirectChains),
+		pq.Array(redirectLoops),
+		pq.Array(redirectLimitHits),
+		pq.Array(cacheVariants),
+		pq.Array(cdnProviders),
+		pq.Array(cacheExpiresAts),
+		pq.Array(seo),
+		pq.Array(pageAudits),
+		pq.Array(structuredData),
+		pq.Array(insecureContent),
+		pq.Array(etags),
+		pq.Array(lastModifieds),
+		pq.Array(notModifieds),
+		pq.Array(contentHashes),
 	)
 
 	if err != nil {
 		return err
 	}
 
+	if err := updatePageValidators(ctx, tx, tasks); err != nil {
+		return err
+	}
+
+	if err := updatePageContentHashes(ctx, tx, tasks); err != nil {
+		return err
+	}
+
+	if err := recordTaskFindings(ctx, tx, tasks); err != nil {
+		return err
+	}
+
 	log.Debug().
 		Int("tasks_count", len(tasks)).
 		Msg("Batch updated completed tasks")
@@ -828,6 +912,10 @@ func (bm *BatchManager) batchUpdateFailed(ctx context.Context, tx *sql.Tx, tasks
 	errors := make([]string, len(tasks))
 	retryCounts := make([]int, len(tasks))
 	statuses := make([]string, len(tasks))
+	redirectChains := make([]string, len(tasks))
+	redirectLoops := make([]bool, len(tasks))
+	redirectLimitHits := make([]bool, len(tasks))
+	soft404s := make([]string, len(tasks))
 
 	for i, task := range tasks {
 		ids[i] = task.ID
@@ -835,6 +923,10 @@ func (bm *BatchManager) batchUpdateFailed(ctx context.Context, tx *sql.Tx, tasks
 		errors[i] = task.Error
 		retryCounts[i] = task.RetryCount
 		statuses[i] = task.Status // Could be "failed" or "blocked"
+		redirectChains[i] = string(task.RedirectChain)
+		redirectLoops[i] = task.RedirectLoop
+		redirectLimitHits[i] = task.RedirectLimitHit
+		soft404s[i] = task.Soft404
 	}
 
 	query := `
@@ -842,14 +934,22 @@ func (bm *BatchManager) batchUpdateFailed(ctx context.Context, tx *sql.Tx, tasks
 		SET status = updates.status,
 			completed_at = updates.completed_at,
 			error = updates.error,
-			retry_count = updates.retry_count
+			retry_count = updates.retry_count,
+			redirect_chain = NULLIF(updates.redirect_chain, '')::jsonb,
+			redirect_loop = updates.redirect_loop,
+			redirect_limit_exceeded = updates.redirect_limit_exceeded,
+			soft_404 = NULLIF(updates.soft_404, '')
 		FROM (
 			SELECT
 				unnest($1::text[]) AS id,
 				unnest($2::text[]) AS status,
 				unnest($3::timestamptz[]) AS completed_at,
 				unnest($4::text[]) AS error,
-				unnest($5::integer[]) AS retry_count
+				unnest($5::integer[]) AS retry_count,
+				unnest($6::text[]) AS redirect_chain,
+				unnest($7::boolean[]) AS redirect_loop,
+				unnest($8::boolean[]) AS redirect_limit_exceeded,
+				unnest($9::text[]) AS soft_404
 		) AS updates
 		WHERE tasks.id = updates.id
 	`
@@ -860,6 +960,10 @@ func (bm *BatchManager) batchUpdateFailed(ctx context.Context, tx *sql.Tx, tasks
 		pq.Array(completedAts),
 		pq.Array(errors),
 		pq.Array(retryCounts),
+		pq.Array(redirectChains),
+		pq.Array(redirectLoops),
+		pq.Array(redirectLimitHits),
+		pq.Array(soft404s),
 	)
 
 	if err != nil {
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrCDNConnectionNotFound is returned when a CDN connection is not found
+var ErrCDNConnectionNotFound = errors.New("cdn connection not found")
+
+// CDNConnection is an organisation's API access to a CDN for one domain.
+// The API token is stored in Supabase Vault, never in this table.
+type CDNConnection struct {
+	ID               string
+	OrganisationID   string
+	DomainID         int
+	Domain           string // Domain name, populated on read
+	Provider         string // "cloudflare" or "fastly"
+	ZoneID           string // Cloudflare zone ID or Fastly service ID
+	PurgeOnPublish   bool   // Purge everything before Webflow publish warming jobs
+	VaultSecretName  string // Name of the secret in Supabase Vault
+	InstallingUserID string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// CreateCDNConnection creates or replaces the organisation's connection for a
+// domain and provider, and reports whether a new connection was inserted.
+// Note: Use StoreCDNToken after creating the connection to store the API token in Vault
+func (db *DB) CreateCDNConnection(ctx context.Context, conn *CDNConnection) (bool, error) {
+	query := `
+		INSERT INTO cdn_connections (
+			id, organisation_id, domain_id, provider, zone_id, purge_on_publish,
+			installing_user_id, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::uuid, $8, $9)
+		ON CONFLICT (organisation_id, domain_id, provider)
+		DO UPDATE SET
+			zone_id = EXCLUDED.zone_id,
+			purge_on_publish = EXCLUDED.purge_on_publish,
+			installing_user_id = EXCLUDED.installing_user_id,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, (xmax = 0) AS inserted
+	`
+
+	var inserted bool
+	err := db.client.QueryRowContext(ctx, query,
+		conn.ID, conn.OrganisationID, conn.DomainID, conn.Provider, conn.ZoneID, conn.PurgeOnPublish,
+		conn.InstallingUserID, conn.CreatedAt, conn.UpdatedAt,
+	).Scan(&conn.ID, &inserted)
+	if err != nil {
+		log.Error().Err(err).Str("organisation_id", conn.OrganisationID).Str("provider", conn.Provider).Msg("Failed to create cdn connection")
+		return false, fmt.Errorf("failed to create cdn connection: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// StoreCDNToken stores a CDN API token in Supabase Vault
+func (db *DB) StoreCDNToken(ctx context.Context, connectionID, token string) error {
+	query := `SELECT store_cdn_token($1::uuid, $2)`
+
+	// Function returns secret name but we don't need it - just scan to consume the result
+	if err := db.client.QueryRowContext(ctx, query, connectionID, token).Scan(new(string)); err != nil {
+		log.Error().Err(err).Str("connection_id", connectionID).Msg("Failed to store cdn token in vault")
+		return fmt.Errorf("failed to store cdn token: %w", err)
+	}
+
+	return nil
+}
+
+// GetCDNToken retrieves a CDN API token from Supabase Vault
+func (db *DB) GetCDNToken(ctx context.Context, connectionID string) (string, error) {
+	query := `SELECT get_cdn_token($1::uuid)`
+
+	var token sql.NullString
+	err := db.client.QueryRowContext(ctx, query, connectionID).Scan(&token)
+	if err != nil {
+		log.Error().Err(err).Str("connection_id", connectionID).Msg("Failed to get cdn token from vault")
+		return "", fmt.Errorf("failed to get cdn token: %w", err)
+	}
+
+	if !token.Valid {
+		return "", fmt.Errorf("cdn token not found for connection %s", connectionID)
+	}
+
+	return token.String, nil
+}
+
+const cdnConnectionColumns = `
+	c.id, c.organisation_id, c.domain_id, d.name, c.provider, c.zone_id, c.purge_on_publish,
+	c.vault_secret_name, c.installing_user_id, c.created_at, c.updated_at
+`
+
+func scanCDNConnection(scanner interface{ Scan(...any) error }) (*CDNConnection, error) {
+	conn := &CDNConnection{}
+	var vaultSecretName, installingUserID sql.NullString
+
+	err := scanner.Scan(
+		&conn.ID, &conn.OrganisationID, &conn.DomainID, &conn.Domain, &conn.Provider, &conn.ZoneID, &conn.PurgeOnPublish,
+		&vaultSecretName, &installingUserID, &conn.CreatedAt, &conn.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.VaultSecretName = vaultSecretName.String
+	conn.InstallingUserID = installingUserID.String
+	return conn, nil
+}
+
+// GetCDNConnection retrieves a CDN connection by ID within an organisation
+func (db *DB) GetCDNConnection(ctx context.Context, connectionID, organisationID string) (*CDNConnection, error) {
+	query := `
+		SELECT ` + cdnConnectionColumns + `
+		FROM cdn_connections c
+		JOIN domains d ON d.id = c.domain_id
+		WHERE c.id = $1 AND c.organisation_id = $2
+	`
+
+	conn, err := scanCDNConnection(db.client.QueryRowContext(ctx, query, connectionID, organisationID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCDNConnectionNotFound
+		}
+		log.Error().Err(err).Str("connection_id", connectionID).Msg("Failed to get cdn connection")
+		return nil, fmt.Errorf("failed to get cdn connection: %w", err)
+	}
+
+	return conn, nil
+}
+
+// GetCDNConnectionForDomain retrieves the organisation's CDN connection for
+// a domain. Returns nil, nil when the domain has no connection.
+func (db *DB) GetCDNConnectionForDomain(ctx context.Context, organisationID, domain string) (*CDNConnection, error) {
+	query := `
+		SELECT ` + cdnConnectionColumns + `
+		FROM cdn_connections c
+		JOIN domains d ON d.id = c.domain_id
+		WHERE c.organisation_id = $1 AND d.name = $2
+		ORDER BY c.updated_at DESC
+		LIMIT 1
+	`
+
+	conn, err := scanCDNConnection(db.client.QueryRowContext(ctx, query, organisationID, domain))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Error().Err(err).Str("organisation_id", organisationID).Str("domain", domain).Msg("Failed to get cdn connection for domain")
+		return nil, fmt.Errorf("failed to get cdn connection for domain: %w", err)
+	}
+
+	return conn, nil
+}
+
+// ListCDNConnections lists all CDN connections for an organisation
+func (db *DB) ListCDNConnections(ctx context.Context, organisationID string) ([]*CDNConnection, error) {
+	query := `
+		SELECT ` + cdnConnectionColumns + `
+		FROM cdn_connections c
+		JOIN domains d ON d.id = c.domain_id
+		WHERE c.organisation_id = $1
+		ORDER BY c.created_at DESC
+	`
+
+	rows, err := db.client.QueryContext(ctx, query, organisationID)
+	if err != nil {
+		log.Error().Err(err).Str("organisation_id", organisationID).Msg("Failed to list cdn connections")
+		return nil, fmt.Errorf("failed to list cdn connections: %w", err)
+	}
+	defer rows.Close()
+
+	connections := make([]*CDNConnection, 0)
+	for rows.Next() {
+		conn, err := scanCDNConnection(rows)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to scan cdn connection row")
+			return nil, fmt.Errorf("failed to scan cdn connection: %w", err)
+		}
+		connections = append(connections, conn)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cdn connections: %w", err)
+	}
+
+	return connections, nil
+}
+
+// DeleteCDNConnection deletes a CDN connection; its Vault secret is removed by trigger
+func (db *DB) DeleteCDNConnection(ctx context.Context, connectionID, organisationID string) error {
+	query := `
+		DELETE FROM cdn_connections
+		WHERE id = $1 AND organisation_id = $2
+	`
+
+	result, err := db.client.ExecContext(ctx, query, connectionID, organisationID)
+	if err != nil {
+		log.Error().Err(err).Str("connection_id", connectionID).Msg("Failed to delete cdn connection")
+		return fmt.Errorf("failed to delete cdn connection: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrCDNConnectionNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// contentHashHistoryLimit caps the hashes kept in pages.content_hash_history
+const contentHashHistoryLimit = 20
+
+// How a page's content compares between two jobs
+const (
+	ContentChangeChanged = "changed" // Both jobs hashed the page, with different hashes
+	ContentChangeAdded   = "added"   // Only the later job crawled the page
+	ContentChangeRemoved = "removed" // Only the earlier job crawled the page
+)
+
+// ContentChange is a page whose content differs between two jobs
+type ContentChange struct {
+	Host         string
+	Path         string
+	Change       string
+	Hash         string // In the later job; empty when removed
+	PreviousHash string // In the earlier job; empty when added
+	DiffPath     string // Storage path of the main content diff from PreviousHash to Hash, if stored
+}
+
+// StorageKeysInUse reports which of keys the database still points at: a
+// page's current content text (stored at pages/{id}/content/{hash}.txt) or a
+// domain's latest HTML sample. Retention keeps these however old they are,
+// since the next crawl diffs against the former and the dashboard shows the
+// latter. Pages are looked up by the IDs in keys, so each call only reads
+// the rows for one batch.
+func (db *DB) StorageKeysInUse(ctx context.Context, keys []string) (map[string]bool, error) {
+	var pageIDs []int64
+	for _, key := range keys {
+		rest, ok := strings.CutPrefix(key, "pages/")
+		if !ok {
+			continue
+		}
+		id, _, _ := strings.Cut(rest, "/")
+		if pageID, err := strconv.ParseInt(id, 10, 64); err == nil {
+			pageIDs = append(pageIDs, pageID)
+		}
+	}
+
+	rows, err := db.client.QueryContext(ctx, `
+		SELECT key FROM (
+			SELECT 'pages/' || id || '/content/' || content_hash || '.txt' AS key
+			FROM pages
+			WHERE id = ANY($2) AND content_hash IS NOT NULL
+			UNION ALL
+			-- Stored as bucket/key
+			SELECT substring(tech_html_path FROM position('/' IN tech_html_path) + 1)
+			FROM domains
+			WHERE tech_html_path IS NOT NULL AND tech_html_path <> ''
+		) in_use
+		WHERE key = ANY($1)
+	`, pq.Array(keys), pq.Array(pageIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check storage keys in use: %w", err)
+	}
+	defer rows.Close()
+
+	inUse := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan storage key: %w", err)
+		}
+		inUse[key] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate storage keys: %w", err)
+	}
+	return inUse, nil
+}
+
+// SetTaskContentDiffPath records where a task's main content diff was stored
+// and the content hash it was diffed against
+func (db *DB) SetTaskContentDiffPath(ctx context.Context, taskID, path, baseHash string) error {
+	_, err := db.client.ExecContext(ctx, `
+		UPDATE tasks SET content_diff_path = $2, content_diff_base_hash = $3 WHERE id = $1
+	`, taskID, path, baseHash)
+	if err != nil {
+		return fmt.Errorf("failed to set task content diff path: %w", err)
+	}
+	return nil
+}
+
+// ListJobContentChanges returns the pages whose content hash differs between
+// a job and an earlier one, along with pages only one of them crawled. Pages
+// either job has no hash for (non-HTML, failed or 304 responses) are left
+// out rather than reported as changed. A task's stored diff is against the
+// page's previous crawl, so it is only returned when that crawl had the
+// earlier job's hash.
+func (db *DB) ListJobContentChanges(ctx context.Context, jobID, sinceJobID string, limit int) ([]ContentChange, error) {
+	rows, err := db.client.QueryContext(ctx, `
+		SELECT p.host, p.path,
+		       CASE
+		         WHEN prev.page_id IS NULL THEN 'added'
+		         WHEN cur.page_id IS NULL THEN 'removed'
+		         ELSE 'changed'
+		       END AS change,
+		       COALESCE(cur.content_hash, ''), COALESCE(prev.content_hash, ''),
+		       CASE
+		         WHEN cur.content_diff_base_hash = prev.content_hash THEN cur.content_diff_path
+		         ELSE ''
+		       END
+		FROM (
+			SELECT page_id, content_hash, content_diff_path, content_diff_base_hash FROM tasks WHERE job_id = $1
+		) cur
+		FULL OUTER JOIN (
+			SELECT page_id, content_hash FROM tasks WHERE job_id = $2
+		) prev ON prev.page_id = cur.page_id
+		JOIN pages p ON p.id = COALESCE(cur.page_id, prev.page_id)
+		WHERE (prev.page_id IS NULL AND cur.content_hash IS NOT NULL)
+		   OR (cur.page_id IS NULL AND prev.content_hash IS NOT NULL)
+		   OR cur.content_hash <> prev.content_hash
+		ORDER BY change, p.host, p.path
+		LIMIT $3
+	`, jobID, sinceJobID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list content changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := make([]ContentChange, 0)
+	for rows.Next() {
+		var change ContentChange
+		if err := rows.Scan(&change.Host, &change.Path, &change.Change, &change.Hash, &change.PreviousHash, &change.DiffPath); err != nil {
+			return nil, fmt.Errorf("failed to scan content change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate content changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// updatePageContentHashes stores the content hashes of completed tasks on
+// their pages. A hash that differs from the page's current one is prepended
+// to its history and moves content_changed_at; re-crawling unchanged content
+// leaves both alone.
+func updatePageContentHashes(ctx context.Context, tx *sql.Tx, tasks []*Task) error {
+	pageIDs := make([]int, 0, len(tasks))
+	jobIDs := make([]string, 0, len(tasks))
+	hashes := make([]string, 0, len(tasks))
+	seenAts := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if task.PageID == 0 || task.ContentHash == "" {
+			continue
+		}
+		pageIDs = append(pageIDs, task.PageID)
+		jobIDs = append(jobIDs, task.JobID)
+		hashes = append(hashes, task.ContentHash)
+		seenAts = append(seenAts, formatNullableTime(task.CompletedAt))
+	}
+	if len(pageIDs) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE pages
+		SET content_hash = updates.content_hash,
+			content_changed_at = updates.seen_at,
+			content_hash_history = (
+				SELECT COALESCE(jsonb_agg(entry ORDER BY position), '[]'::jsonb)
+				FROM jsonb_array_elements(
+					jsonb_build_array(jsonb_build_object(
+						'hash', updates.content_hash,
+						'job_id', updates.job_id,
+						'seen_at', updates.seen_at
+					)) || COALESCE(pages.content_hash_history, '[]'::jsonb)
+				) WITH ORDINALITY AS history(entry, position)
+				WHERE position <= $5
+			)
+		FROM (
+			SELECT DISTINCT ON (page_id) page_id, job_id, content_hash,
+				COALESCE(NULLIF(seen_at, '')::timestamptz, NOW()) AS seen_at
+			FROM (
+				SELECT
+					unnest($1::integer[]) AS page_id,
+					unnest($2::text[]) AS job_id,
+					unnest($3::text[]) AS content_hash,
+					unnest($4::text[]) AS seen_at
+			) AS batch
+			ORDER BY page_id, NULLIF(seen_at, '')::timestamptz DESC NULLS LAST
+		) AS updates
+		WHERE pages.id = updates.page_id
+		  AND pages.content_hash IS DISTINCT FROM updates.content_hash
+	`, pq.Array(pageIDs), pq.Array(jobIDs), pq.Array(hashes), pq.Array(seenAts), contentHashHistoryLimit)
+	if err != nil {
+		return fmt.Errorf("failed to update page content hashes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ExternalLinkCheck is the outcome of checking a link to another site
+type ExternalLinkCheck struct {
+	URL          string
+	StatusCode   int
+	Error        string
+	ResponseTime int64
+	CheckedAt    time.Time
+}
+
+// Broken reports whether the check failed or returned an error status
+func (c *ExternalLinkCheck) Broken() bool {
+	return c.Error != "" || c.StatusCode < 200 || c.StatusCode >= 400
+}
+
+// JobExternalLink is an external link found by a job, with its check result
+// once one has been recorded
+type JobExternalLink struct {
+	JobID        string
+	URL          string
+	Host         string
+	SourceURL    string
+	StatusCode   int
+	Error        string
+	ResponseTime int64
+	Cached       bool // Result reused from another job's recent check
+	CheckedAt    *time.Time
+	CreatedAt    time.Time
+}
+
+// AddJobExternalLinks stores external links found by a job, unchecked, so
+// they wait in the table until a checker claims them. Links the job already
+// has are left as they are.
+func (db *DB) AddJobExternalLinks(ctx context.Context, links []JobExternalLink) error {
+	if len(links) == 0 {
+		return nil
+	}
+
+	jobIDs := make([]string, len(links))
+	urls := make([]string, len(links))
+	hosts := make([]string, len(links))
+	sourceURLs := make([]string, len(links))
+	for i, link := range links {
+		jobIDs[i] = link.JobID
+		urls[i] = link.URL
+		hosts[i] = link.Host
+		sourceURLs[i] = link.SourceURL
+	}
+
+	if _, err := db.client.ExecContext(ctx, `
+		INSERT INTO job_external_links (job_id, url, host, source_url)
+		SELECT * FROM unnest($1::text[], $2::text[], $3::text[], $4::text[])
+		ON CONFLICT (job_id, url) DO NOTHING
+	`, pq.Array(jobIDs), pq.Array(urls), pq.Array(hosts), pq.Array(sourceURLs)); err != nil {
+		return fmt.Errorf("failed to add external links: %w", err)
+	}
+	return nil
+}
+
+// ClaimJobExternalLinks claims unchecked external links of running and
+// completed jobs, oldest first. A claimed link that has no result after
+// claimFor (e.g. its checker crashed) can be claimed again. Rows locked by
+// another instance's claim are skipped rather than waited on.
+func (db *DB) ClaimJobExternalLinks(ctx context.Context, limit int, claimFor time.Duration) ([]JobExternalLink, error) {
+	rows, err := db.client.QueryContext(ctx, `
+		WITH due AS (
+			SELECT l.id
+			FROM job_external_links l
+			JOIN jobs j ON j.id = l.job_id
+			WHERE l.checked_at IS NULL
+			  AND (l.claimed_at IS NULL OR l.claimed_at <= NOW() - make_interval(secs => $2))
+			  AND j.status IN ('running', 'completed')
+			ORDER BY l.id ASC
+			LIMIT $1
+			FOR UPDATE OF l SKIP LOCKED
+		)
+		UPDATE job_external_links l
+		SET claimed_at = NOW()
+		FROM due
+		WHERE l.id = due.id
+		RETURNING l.job_id, l.url, l.host, COALESCE(l.source_url, ''), l.created_at
+	`, limit, claimFor.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim external links: %w", err)
+	}
+	defer rows.Close()
+
+	links := make([]JobExternalLink, 0)
+	for rows.Next() {
+		var link JobExternalLink
+		if err := rows.Scan(&link.JobID, &link.URL, &link.Host, &link.SourceURL, &link.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan claimed external link: %w", err)
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate claimed external links: %w", err)
+	}
+
+	return links, nil
+}
+
+// GetExternalLinkCheck returns the most recent check of a link made within
+// maxAge by any job, or nil if there is none
+func (db *DB) GetExternalLinkCheck(ctx context.Context, linkURL string, maxAge time.Duration) (*ExternalLinkCheck, error) {
+	check := &ExternalLinkCheck{URL: linkURL}
+	err := db.client.QueryRowContext(ctx, `
+		SELECT COALESCE(status_code, 0), COALESCE(error, ''), COALESCE(response_time, 0), checked_at
+		FROM external_link_checks
+		WHERE url = $1
+		  AND checked_at > NOW() - make_interval(secs => $2)
+	`, linkURL, maxAge.Seconds()).Scan(&check.StatusCode, &check.Error, &check.ResponseTime, &check.CheckedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get external link check: %w", err)
+	}
+	return check, nil
+}
+
+// RecordExternalLinkCheck stores a check result against the job's link. Fresh
+// results (not cached) also update the cross-job cache.
+func (db *DB) RecordExternalLinkCheck(ctx context.Context, jobID string, check *ExternalLinkCheck, cached bool) error {
+	tx, err := db.client.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start external link transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE job_external_links
+		SET status_code = NULLIF($3, 0), error = NULLIF($4, ''), response_time = $5,
+		    cached = $6, checked_at = $7
+		WHERE job_id = $1 AND url = $2
+	`, jobID, check.URL, check.StatusCode, check.Error, check.ResponseTime, cached, check.CheckedAt); err != nil {
+		return fmt.Errorf("failed to record external link result: %w", err)
+	}
+
+	if !cached {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO external_link_checks (url, status_code, error, response_time, checked_at)
+			VALUES ($1, NULLIF($2, 0), NULLIF($3, ''), $4, $5)
+			ON CONFLICT (url) DO UPDATE SET
+				status_code = EXCLUDED.status_code,
+				error = EXCLUDED.error,
+				response_time = EXCLUDED.response_time,
+				checked_at = EXCLUDED.checked_at
+		`, check.URL, check.StatusCode, check.Error, check.ResponseTime, check.CheckedAt); err != nil {
+			return fmt.Errorf("failed to cache external link result: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit external link result: %w", err)
+	}
+	return nil
+}
+
+// ListJobExternalLinks returns a job's checked external links, optionally
+// only the broken ones, newest first
+func (db *DB) ListJobExternalLinks(ctx context.Context, jobID string, brokenOnly bool, limit int) ([]JobExternalLink, error) {
+	rows, err := db.client.QueryContext(ctx, `
+		SELECT job_id, url, host, COALESCE(source_url, ''), COALESCE(status_code, 0), COALESCE(error, ''),
+		       COALESCE(response_time, 0), cached, checked_at, created_at
+		FROM job_external_links
+		WHERE job_id = $1
+		  AND checked_at IS NOT NULL
+		  AND (NOT $2 OR error IS NOT NULL OR status_code IS NULL OR status_code < 200 OR status_code >= 400)
+		ORDER BY checked_at DESC
+		LIMIT $3
+	`, jobID, brokenOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list external links: %w", err)
+	}
+	defer rows.Close()
+
+	links := make([]JobExternalLink, 0)
+	for rows.Next() {
+		var link JobExternalLink
+		var checkedAt sql.NullTime
+		if err := rows.Scan(&link.JobID, &link.URL, &link.Host, &link.SourceURL, &link.StatusCode, &link.Error,
+			&link.ResponseTime, &link.Cached, &checkedAt, &link.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan external link: %w", err)
+		}
+		if checkedAt.Valid {
+			link.CheckedAt = &checkedAt.Time
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate external links: %w", err)
+	}
+
+	return links, nil
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// TaskFinding is an SEO audit finding recorded against a task
+type TaskFinding struct {
+	TaskID   string
+	Code     string
+	Severity string
+	Detail   string
+}
+
+// recordTaskFindings replaces the task_findings rows of completed tasks with
+// the page-level findings in their page_audit. Job-wide duplicate findings
+// are computed when findings are read, by job_duplicate_findings.
+func recordTaskFindings(ctx context.Context, tx *sql.Tx, tasks []*Task) error {
+	taskIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	// A retried task may already have findings from an earlier attempt
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_findings WHERE task_id = ANY($1::text[])`, pq.Array(taskIDs)); err != nil {
+		return fmt.Errorf("failed to clear task findings: %w", err)
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO task_findings (job_id, task_id, code, severity, detail)
+		SELECT t.job_id, t.id, f->>'code', f->>'severity', NULLIF(f->>'detail', '')
+		FROM tasks t
+		CROSS JOIN LATERAL jsonb_array_elements(COALESCE(t.page_audit->'findings', '[]'::jsonb)) AS f
+		WHERE t.id = ANY($1::text[])
+		  AND t.status = 'completed'
+	`, pq.Array(taskIDs))
+	if err != nil {
+		return fmt.Errorf("failed to record task findings: %w", err)
+	}
+
+	return nil
+}
+
+// ListJobFindings returns a job's page-level findings together with its
+// duplicate title and description findings, grouped by task in severity order
+func (db *DB) ListJobFindings(ctx context.Context, jobID string) ([]TaskFinding, error) {
+	rows, err := db.client.QueryContext(ctx, `
+		SELECT task_id, code, severity, detail
+		FROM (
+			SELECT task_id, code, severity, COALESCE(detail, '') AS detail
+			FROM task_findings
+			WHERE job_id = $1
+			UNION ALL
+			SELECT task_id, code, 'warning', detail
+			FROM job_duplicate_findings($1)
+		) findings
+		ORDER BY task_id,
+		         CASE severity WHEN 'error' THEN 0 WHEN 'warning' THEN 1 ELSE 2 END,
+		         code
+	`, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task findings: %w", err)
+	}
+	defer rows.Close()
+
+	findings := make([]TaskFinding, 0)
+	for rows.Next() {
+		var f TaskFinding
+		if err := rows.Scan(&f.TaskID, &f.Code, &f.Severity, &f.Detail); err != nil {
+			return nil, fmt.Errorf("failed to scan task finding: %w", err)
+		}
+		findings = append(findings, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate task findings: %w", err)
+	}
+
+	return findings, nil
+}
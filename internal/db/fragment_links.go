@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// FragmentLink is a link from a crawled page to a section of a page on the
+// same site, e.g. /pricing#enterprise
+type FragmentLink struct {
+	URL       string // As linked, including the fragment
+	TargetKey string // Host and path of the page the fragment points into
+	Fragment  string // Decoded, without the leading '#'
+	SourceURL string
+}
+
+// MissingAnchor is a fragment link whose target page was crawled but has no
+// element with a matching id or name
+type MissingAnchor struct {
+	URL       string
+	SourceURL string
+	Fragment  string
+	CreatedAt time.Time
+}
+
+// RecordPageFragments stores the anchors found on a page and the fragment
+// links it contains. Anchors are stored when non-nil; each fragment link is
+// kept once per job, with the first page found linking to it.
+func (db *DB) RecordPageFragments(ctx context.Context, jobID, pageKey string, anchors []string, links []FragmentLink) error {
+	tx, err := db.client.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start page fragments transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if anchors != nil {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO job_page_anchors (job_id, page_key, anchors)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (job_id, page_key) DO UPDATE SET anchors = EXCLUDED.anchors
+		`, jobID, pageKey, pq.Array(anchors)); err != nil {
+			return fmt.Errorf("failed to record page anchors: %w", err)
+		}
+	}
+
+	if len(links) > 0 {
+		urls := make([]string, len(links))
+		targetKeys := make([]string, len(links))
+		fragments := make([]string, len(links))
+		sourceURLs := make([]string, len(links))
+		for i, link := range links {
+			urls[i] = link.URL
+			targetKeys[i] = link.TargetKey
+			fragments[i] = link.Fragment
+			sourceURLs[i] = link.SourceURL
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO job_fragment_links (job_id, url, target_key, fragment, source_url)
+			SELECT $1, l.url, l.target_key, l.fragment, l.source_url
+			FROM unnest($2::text[], $3::text[], $4::text[], $5::text[]) AS l(url, target_key, fragment, source_url)
+			ON CONFLICT (job_id, target_key, fragment) DO NOTHING
+		`, jobID, pq.Array(urls), pq.Array(targetKeys), pq.Array(fragments), pq.Array(sourceURLs)); err != nil {
+			return fmt.Errorf("failed to record fragment links: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit page fragments: %w", err)
+	}
+	return nil
+}
+
+// ListJobMissingAnchors validates a job's fragment links against the anchors
+// recorded for their target pages, returning those with no matching anchor.
+// Links to pages the job did not crawl are not reported.
+func (db *DB) ListJobMissingAnchors(ctx context.Context, jobID string, limit int) ([]MissingAnchor, error) {
+	rows, err := db.client.QueryContext(ctx, `
+		SELECT fl.url, COALESCE(fl.source_url, ''), fl.fragment, fl.created_at
+		FROM job_fragment_links fl
+		JOIN job_page_anchors pa ON pa.job_id = fl.job_id AND pa.page_key = fl.target_key
+		WHERE fl.job_id = $1
+		  AND NOT (fl.fragment = ANY(pa.anchors))
+		ORDER BY fl.created_at DESC
+		LIMIT $2
+	`, jobID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list missing anchors: %w", err)
+	}
+	defer rows.Close()
+
+	missing := make([]MissingAnchor, 0)
+	for rows.Next() {
+		var m MissingAnchor
+		if err := rows.Scan(&m.URL, &m.SourceURL, &m.Fragment, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan missing anchor: %w", err)
+		}
+		missing = append(missing, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate missing anchors: %w", err)
+	}
+
+	return missing, nil
+}
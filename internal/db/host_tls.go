@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// HostTLS is the TLS state captured from the first HTTPS response for a host
+// in a job
+type HostTLS struct {
+	JobID       string
+	Host        string
+	Version     string // e.g. "TLS 1.3"
+	CipherSuite string
+	Subject     string // Leaf certificate
+	Issuer      string
+	DNSNames    []string
+	NotAfter    time.Time
+	Chain       []byte // JSONB, leaf first
+	CapturedAt  time.Time
+}
+
+// RecordHostTLS stores a job's TLS state for a host. Only the first capture
+// per job and host is kept; returns whether this call stored it.
+func (db *DB) RecordHostTLS(ctx context.Context, info *HostTLS) (bool, error) {
+	if len(info.Chain) > 0 && !json.Valid(info.Chain) {
+		return false, fmt.Errorf("certificate chain is not valid JSON")
+	}
+
+	result, err := db.client.ExecContext(ctx, `
+		INSERT INTO job_host_tls (
+			job_id, host, tls_version, cipher_suite, subject, issuer, dns_names,
+			not_after, certificate_chain, captured_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, '')::jsonb, $10)
+		ON CONFLICT (job_id, host) DO NOTHING
+	`, info.JobID, info.Host, info.Version, info.CipherSuite, info.Subject, info.Issuer,
+		pq.Array(info.DNSNames), info.NotAfter, string(info.Chain), info.CapturedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to record host tls: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to verify host tls insert: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// ListJobHostTLS returns the TLS state captured for each host in a job
+func (db *DB) ListJobHostTLS(ctx context.Context, jobID string) ([]*HostTLS, error) {
+	rows, err := db.client.QueryContext(ctx, `
+		SELECT job_id, host, tls_version, cipher_suite, subject, issuer, dns_names,
+		       not_after, COALESCE(certificate_chain, '[]'::jsonb), captured_at
+		FROM job_host_tls
+		WHERE job_id = $1
+		ORDER BY host
+	`, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list host tls: %w", err)
+	}
+	defer rows.Close()
+
+	hosts := make([]*HostTLS, 0)
+	for rows.Next() {
+		info := &HostTLS{}
+		if err := rows.Scan(&info.JobID, &info.Host, &info.Version, &info.CipherSuite, &info.Subject, &info.Issuer,
+			pq.Array(&info.DNSNames), &info.NotAfter, &info.Chain, &info.CapturedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan host tls: %w", err)
+		}
+		hosts = append(hosts, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate host tls: %w", err)
+	}
+
+	return hosts, nil
+}
+
+// CreateCertificateExpiryNotification notifies the job's organisation that a
+// host's certificate expires in daysLeft days. Each certificate (host and
+// expiry) is notified once per organisation, however often it is crawled;
+// returns whether a notification was created.
+func (db *DB) CreateCertificateExpiryNotification(ctx context.Context, info *HostTLS, daysLeft int) (bool, error) {
+	notAfter := info.NotAfter.UTC().Format(time.RFC3339)
+
+	subject := fmt.Sprintf("⚠️ Certificate for %s expires in %d days", info.Host, daysLeft)
+	switch {
+	case daysLeft < 0:
+		subject = fmt.Sprintf("🚨 Certificate for %s has expired", info.Host)
+	case daysLeft == 0:
+		subject = fmt.Sprintf("🚨 Certificate for %s expires today", info.Host)
+	case daysLeft == 1:
+		subject = fmt.Sprintf("⚠️ Certificate for %s expires tomorrow", info.Host)
+	}
+	preview := fmt.Sprintf("Expires %s, issued by %s", info.NotAfter.UTC().Format("2 Jan 2006 15:04 MST"), info.Issuer)
+
+	data, err := json.Marshal(map[string]any{
+		"job_id":         info.JobID,
+		"host":           info.Host,
+		"issuer":         info.Issuer,
+		"not_after":      notAfter,
+		"days_remaining": daysLeft,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal notification data: %w", err)
+	}
+
+	result, err := db.client.ExecContext(ctx, `
+		INSERT INTO notifications (organisation_id, type, subject, preview, link, data)
+		SELECT j.organisation_id, $2, $3, $4, '/jobs/' || j.id, $5::jsonb
+		FROM jobs j
+		WHERE j.id = $1
+		  AND j.organisation_id IS NOT NULL
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM notifications n
+			WHERE n.organisation_id = j.organisation_id
+			  AND n.type = $2
+			  AND n.data->>'host' = $6
+			  AND n.data->>'not_after' = $7
+		  )
+	`, info.JobID, string(NotificationCertificateExpiring), subject, preview, string(data), info.Host, notAfter)
+	if err != nil {
+		return false, fmt.Errorf("failed to create certificate expiry notification: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to verify notification insert: %w", err)
+	}
+	return rows > 0, nil
+}
@@ -0,0 +1,214 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SchedulerLeaderLease is the lease name held by the instance running the
+// job scheduler and health monitoring loops
+const SchedulerLeaderLease = "background-scheduler"
+
+const (
+	defaultLeaderLeaseTTL   = 90 * time.Second
+	defaultLeaderRenewEvery = 30 * time.Second
+)
+
+// LeaderLease describes the current holder of a named leadership lease
+type LeaderLease struct {
+	Name       string    `json:"name"`
+	HolderID   string    `json:"holder_id"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	RenewedAt  time.Time `json:"renewed_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+var (
+	instanceIDOnce sync.Once
+	instanceID     string
+)
+
+// InstanceID identifies this process for leader election. It prefers the
+// Fly.io machine ID and falls back to the hostname, suffixed with the PID so
+// two processes on one host never share an identity.
+func InstanceID() string {
+	instanceIDOnce.Do(func() {
+		host := sanitiseAppName(os.Getenv("FLY_MACHINE_ID"))
+		if host == "" {
+			if name, err := os.Hostname(); err == nil {
+				host = sanitiseAppName(name)
+			}
+		}
+		if host == "" {
+			host = "unknown"
+		}
+		instanceID = fmt.Sprintf("%s:%d", host, os.Getpid())
+	})
+	return instanceID
+}
+
+// TryAcquireLeaderLease acquires or renews the named lease for holderID.
+// It succeeds when the lease is unheld, expired, or already held by holderID.
+// Lease times use the database clock so instances with skewed clocks agree.
+//
+// A lease row is used rather than a session advisory lock because the app
+// connects through Supabase's transaction-mode pooler, where session state
+// does not stick to a single backend connection.
+func (db *DB) TryAcquireLeaderLease(ctx context.Context, name, holderID string, ttl time.Duration) (bool, error) {
+	query := `
+		INSERT INTO leader_leases (name, holder_id, acquired_at, renewed_at, expires_at)
+		VALUES ($1, $2, NOW(), NOW(), NOW() + make_interval(secs => $3))
+		ON CONFLICT (name) DO UPDATE SET
+			holder_id = EXCLUDED.holder_id,
+			acquired_at = CASE
+				WHEN leader_leases.holder_id = EXCLUDED.holder_id THEN leader_leases.acquired_at
+				ELSE NOW()
+			END,
+			renewed_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE leader_leases.holder_id = EXCLUDED.holder_id
+		   OR leader_leases.expires_at < NOW()
+		RETURNING holder_id
+	`
+
+	var holder string
+	err := db.client.QueryRowContext(ctx, query, name, holderID, ttl.Seconds()).Scan(&holder)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire leader lease: %w", err)
+	}
+
+	return holder == holderID, nil
+}
+
+// ReleaseLeaderLease gives up the named lease if holderID still holds it
+func (db *DB) ReleaseLeaderLease(ctx context.Context, name, holderID string) error {
+	_, err := db.client.ExecContext(ctx, `
+		DELETE FROM leader_leases WHERE name = $1 AND holder_id = $2
+	`, name, holderID)
+	if err != nil {
+		return fmt.Errorf("failed to release leader lease: %w", err)
+	}
+	return nil
+}
+
+// GetLeaderLease returns the current unexpired holder of the named lease,
+// or nil if no instance holds it
+func (db *DB) GetLeaderLease(ctx context.Context, name string) (*LeaderLease, error) {
+	lease := &LeaderLease{}
+	err := db.client.QueryRowContext(ctx, `
+		SELECT name, holder_id, acquired_at, renewed_at, expires_at
+		FROM leader_leases
+		WHERE name = $1 AND expires_at >= NOW()
+	`, name).Scan(&lease.Name, &lease.HolderID, &lease.AcquiredAt, &lease.RenewedAt, &lease.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leader lease: %w", err)
+	}
+	return lease, nil
+}
+
+// LeaderElector keeps a leader lease acquired and renewed in the background.
+// Exactly one instance holds the lease at a time; others retry each renewal
+// interval and take over once the holder stops renewing.
+type LeaderElector struct {
+	db         *DB
+	name       string
+	holderID   string
+	ttl        time.Duration
+	renewEvery time.Duration
+
+	mu        sync.RWMutex
+	leader    bool
+	renewedAt time.Time
+}
+
+// NewLeaderElector creates an elector for the named lease using this
+// process's InstanceID
+func NewLeaderElector(database *DB, name string) *LeaderElector {
+	return &LeaderElector{
+		db:         database,
+		name:       name,
+		holderID:   InstanceID(),
+		ttl:        defaultLeaderLeaseTTL,
+		renewEvery: defaultLeaderRenewEvery,
+	}
+}
+
+// Run acquires and renews the lease until ctx is cancelled, then releases it
+// so another instance can take over without waiting for expiry
+func (e *LeaderElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.renewEvery)
+	defer ticker.Stop()
+
+	e.tryAcquire(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			if e.IsLeader() {
+				releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := e.db.ReleaseLeaderLease(releaseCtx, e.name, e.holderID); err != nil {
+					log.Warn().Err(err).Str("lease", e.name).Msg("Failed to release leader lease")
+				}
+				cancel()
+			}
+			e.setLeader(false)
+			return
+		case <-ticker.C:
+			e.tryAcquire(ctx)
+		}
+	}
+}
+
+// IsLeader reports whether this instance currently holds the lease. A lease
+// that has not been renewed within its TTL is treated as lost.
+func (e *LeaderElector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader && time.Since(e.renewedAt) < e.ttl
+}
+
+// HolderID returns the identity this elector acquires the lease under
+func (e *LeaderElector) HolderID() string {
+	return e.holderID
+}
+
+func (e *LeaderElector) tryAcquire(ctx context.Context) {
+	acquired, err := e.db.TryAcquireLeaderLease(ctx, e.name, e.holderID, e.ttl)
+	if err != nil {
+		// Step down on errors; we can no longer prove we hold the lease
+		if ctx.Err() == nil {
+			log.Error().Err(err).Str("lease", e.name).Msg("Failed to renew leader lease")
+		}
+		acquired = false
+	}
+
+	wasLeader := e.IsLeader()
+	e.setLeader(acquired)
+
+	if acquired && !wasLeader {
+		log.Info().Str("lease", e.name).Str("holder_id", e.holderID).Msg("Acquired leadership")
+	} else if !acquired && wasLeader {
+		log.Warn().Str("lease", e.name).Str("holder_id", e.holderID).Msg("Lost leadership")
+	}
+}
+
+func (e *LeaderElector) setLeader(leader bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.leader = leader
+	if leader {
+		e.renewedAt = time.Now()
+	}
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestLeaderElectorTryAcquire(t *testing.T) {
+	client, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer client.Close()
+
+	elector := NewLeaderElector(&DB{client: client}, SchedulerLeaderLease)
+	ctx := context.Background()
+
+	// Lease is free
+	mock.ExpectQuery("INSERT INTO leader_leases").
+		WithArgs(SchedulerLeaderLease, elector.HolderID(), defaultLeaderLeaseTTL.Seconds()).
+		WillReturnRows(sqlmock.NewRows([]string{"holder_id"}).AddRow(elector.HolderID()))
+	elector.tryAcquire(ctx)
+	if !elector.IsLeader() {
+		t.Fatal("expected to acquire leadership when lease is free")
+	}
+
+	// Renewal fails: step down rather than assume we still hold the lease
+	mock.ExpectQuery("INSERT INTO leader_leases").
+		WillReturnError(errors.New("connection reset"))
+	elector.tryAcquire(ctx)
+	if elector.IsLeader() {
+		t.Fatal("expected to step down when renewal fails")
+	}
+
+	// Another instance took over in the meantime: conflict update matches no rows
+	mock.ExpectQuery("INSERT INTO leader_leases").
+		WillReturnRows(sqlmock.NewRows([]string{"holder_id"}))
+	elector.tryAcquire(ctx)
+	if elector.IsLeader() {
+		t.Fatal("expected not to be leader while another instance holds the lease")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestLeaderElectorExpiresWithoutRenewal(t *testing.T) {
+	elector := &LeaderElector{ttl: time.Minute}
+	elector.setLeader(true)
+	if !elector.IsLeader() {
+		t.Fatal("expected freshly renewed lease to be held")
+	}
+
+	elector.renewedAt = time.Now().Add(-2 * time.Minute)
+	if elector.IsLeader() {
+		t.Fatal("expected lease older than its TTL to be treated as lost")
+	}
+}
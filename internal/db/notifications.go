@@ -14,10 +14,11 @@ import (
 type NotificationType string
 
 const (
-	NotificationJobComplete    NotificationType = "job_complete"
-	NotificationJobFailed      NotificationType = "job_failed"
-	NotificationSchedulerRun   NotificationType = "scheduler_run"
-	NotificationSchedulerError NotificationType = "scheduler_error"
+	NotificationJobComplete         NotificationType = "job_complete"
+	NotificationJobFailed           NotificationType = "job_failed"
+	NotificationSchedulerRun        NotificationType = "scheduler_run"
+	NotificationSchedulerError      NotificationType = "scheduler_error"
+	NotificationCertificateExpiring NotificationType = "certificate_expiring" // A crawled host's TLS certificate is close to expiry
 )
 
 // Notification represents a notification record
@@ -424,6 +424,51 @@ func (db *DB) GetOrganisationPlanID(ctx context.Context, organisationID string)
 	return planID, nil
 }
 
+// GetOrganisationCacheHeaderMappings returns the organisation's custom CDN
+// cache header mappings as a JSON array.
+func (db *DB) GetOrganisationCacheHeaderMappings(ctx context.Context, organisationID string) ([]byte, error) {
+	query := `
+		SELECT cache_header_mappings
+		FROM organisations
+		WHERE id = $1
+	`
+
+	var mappings []byte
+	if err := db.client.QueryRowContext(ctx, query, organisationID).Scan(&mappings); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("organisation not found")
+		}
+		return nil, fmt.Errorf("failed to fetch cache header mappings: %w", err)
+	}
+
+	return mappings, nil
+}
+
+// SetOrganisationCacheHeaderMappings replaces the organisation's custom CDN
+// cache header mappings. The mappings are expected to be a JSON array.
+func (db *DB) SetOrganisationCacheHeaderMappings(ctx context.Context, organisationID string, mappings []byte) error {
+	query := `
+		UPDATE organisations
+		SET cache_header_mappings = $2::jsonb, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := db.client.ExecContext(ctx, query, organisationID, string(mappings))
+	if err != nil {
+		return fmt.Errorf("failed to update cache header mappings: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("organisation not found")
+	}
+
+	return nil
+}
+
 // ListDailyUsage returns daily usage rows for an organisation within a date range.
 func (db *DB) ListDailyUsage(ctx context.Context, organisationID string, startDate, endDate time.Time) ([]DailyUsageEntry, error) {
 	query := `
@@ -19,6 +19,8 @@ type Page struct {
 	Host     string
 	Path     string
 	Priority float64
+	// SourceURL overrides the batch source URL when set (e.g. re-enqueued tasks)
+	SourceURL string
 }
 
 // TransactionExecutor interface for types that can execute transactions
@@ -174,6 +176,12 @@ func ensurePageBatch(ctx context.Context, q TransactionExecutor, domainID int, b
 	})
 }
 
+// NormalisePageURL returns the host and path a URL is stored under in pages,
+// resolving relative URLs against the domain
+func NormalisePageURL(u string, domain string) (string, string, error) {
+	return normaliseURLPath(u, domain)
+}
+
 func normaliseURLPath(u string, domain string) (string, string, error) {
 	parsedURL, err := url.Parse(u)
 	if err != nil {
@@ -199,3 +207,53 @@ func normaliseURLPath(u string, domain string) (string, string, error) {
 	}
 	return host, path, nil
 }
+
+// updatePageValidators stores the validators completed tasks were served
+// with on their pages. validators_seen_at only moves when a response carried
+// at least one, so pages that never return them can be reported.
+func updatePageValidators(ctx context.Context, tx *sql.Tx, tasks []*Task) error {
+	pageIDs := make([]int, 0, len(tasks))
+	etags := make([]string, 0, len(tasks))
+	lastModifieds := make([]string, 0, len(tasks))
+	checkedAts := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if task.PageID == 0 {
+			continue
+		}
+		pageIDs = append(pageIDs, task.PageID)
+		etags = append(etags, task.ETag)
+		lastModifieds = append(lastModifieds, task.LastModified)
+		checkedAts = append(checkedAts, formatNullableTime(task.CompletedAt))
+	}
+	if len(pageIDs) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE pages
+		SET etag = NULLIF(updates.etag, ''),
+			last_modified = NULLIF(updates.last_modified, ''),
+			validators_checked_at = COALESCE(NULLIF(updates.checked_at, '')::timestamptz, NOW()),
+			validators_seen_at = CASE
+				WHEN updates.etag <> '' OR updates.last_modified <> ''
+				THEN COALESCE(NULLIF(updates.checked_at, '')::timestamptz, NOW())
+				ELSE pages.validators_seen_at
+			END
+		FROM (
+			SELECT DISTINCT ON (page_id) page_id, etag, last_modified, checked_at
+			FROM (
+				SELECT
+					unnest($1::integer[]) AS page_id,
+					unnest($2::text[]) AS etag,
+					unnest($3::text[]) AS last_modified,
+					unnest($4::text[]) AS checked_at
+			) AS batch
+			ORDER BY page_id, NULLIF(checked_at, '')::timestamptz DESC NULLS LAST
+		) AS updates
+		WHERE pages.id = updates.page_id
+	`, pq.Array(pageIDs), pq.Array(etags), pq.Array(lastModifieds), pq.Array(checkedAts))
+	if err != nil {
+		return fmt.Errorf("failed to update page validators: %w", err)
+	}
+	return nil
+}
@@ -68,6 +68,11 @@ const (
 
 	waitingReasonConcurrencyLimit = "concurrency_limit"
 	waitingReasonQuotaExhausted   = "quota_exhausted"
+
+	// staleSitemapProcessing is how old a job must be before its
+	// sitemap_processing flag is ignored, in case the instance reading its
+	// sitemaps stopped before clearing it
+	staleSitemapProcessing = time.Hour
 )
 
 // NewDbQueue creates a PostgreSQL job queue
@@ -842,10 +847,14 @@ type Task struct {
 	StatusCode          int
 	ResponseTime        int64
 	CacheStatus         string
+	CDNProvider         string
 	ContentType         string
 	ContentLength       int64
 	Headers             []byte // Stored as JSONB
 	RedirectURL         string
+	RedirectChain       []byte // Stored as JSONB, nil when the URL did not redirect
+	RedirectLoop        bool
+	RedirectLimitHit    bool
 	DNSLookupTime       int64
 	TCPConnectionTime   int64
 	TLSHandshakeTime    int64
@@ -863,11 +872,64 @@ type Task struct {
 	SecondTTFB                int64
 	SecondContentTransferTime int64
 	CacheCheckAttempts        []byte // Stored as JSONB
+	CacheVariants             []byte // Stored as JSONB, nil when the job has no variant matrix
+
+	// When the warmed object is expected to leave the edge cache; zero when
+	// the response had no usable TTL
+	CacheExpiresAt time.Time
+
+	// Canonical, robots directives and hreflang alternates; stored as JSONB,
+	// nil for non-HTML responses
+	SEO []byte
+
+	// On-page audit signals and page-level findings; stored as JSONB, nil for
+	// non-HTML responses
+	PageAudit []byte
+
+	// JSON-LD and microdata items with their validation issues; stored as
+	// JSONB, nil for non-HTML responses
+	StructuredData []byte
+
+	// http:// subresources, form actions and internal links found on an
+	// HTTPS page; stored as JSONB, nil when there are none
+	InsecureContent []byte
+
+	// Why a 200 response was failed as a soft 404 (e.g. "template_match");
+	// empty otherwise
+	Soft404 string
+
+	// ETag and Last-Modified the page was served with, and whether a
+	// conditional request was answered with a 304
+	ETag         string
+	LastModified string
+	NotModified  bool
+
+	// ETag and Last-Modified stored on the page when the task was claimed,
+	// for revalidating it
+	PageETag         string
+	PageLastModified string
+
+	// Content hash stored on the page when the task was claimed, for
+	// diffing against the new content
+	PageContentHash string
+
+	// Hash of the page's normalised content; empty for non-HTML and 304
+	// responses
+	ContentHash string
 
 	// Priority
 	PriorityScore float64
 }
 
+// formatNullableTime formats a timestamp as a string parameter for a nullable
+// timestamptz column; the zero time becomes an empty string, stored as NULL
+func formatNullableTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
 // GetNextTask gets a pending task using row-level locking
 // Uses FOR UPDATE SKIP LOCKED to prevent lock contention between workers
 // Combines SELECT and UPDATE in a CTE for atomic claiming
@@ -933,9 +995,11 @@ func (q *DbQueue) GetNextTask(ctx context.Context, jobID string) (*Task, error)
 				          tasks.source_url, tasks.priority_score,
 				          ju.running_tasks, ju.concurrency
 			)
-			SELECT id, job_id, page_id, host, path, created_at, retry_count, source_type, source_url, priority_score,
-			       running_tasks, concurrency
-			FROM task_update
+			SELECT tu.id, tu.job_id, tu.page_id, tu.host, tu.path, tu.created_at, tu.retry_count, tu.source_type,
+			       tu.source_url, tu.priority_score, tu.running_tasks, tu.concurrency,
+			       COALESCE(p.etag, ''), COALESCE(p.last_modified, ''), COALESCE(p.content_hash, '')
+			FROM task_update tu
+			LEFT JOIN pages p ON p.id = tu.page_id
 		`
 
 		// Execute the combined query
@@ -947,6 +1011,7 @@ func (q *DbQueue) GetNextTask(ctx context.Context, jobID string) (*Task, error)
 			&task.ID, &task.JobID, &task.PageID, &task.Host, &task.Path,
 			&task.CreatedAt, &task.RetryCount, &task.SourceType, &task.SourceURL,
 			&task.PriorityScore, &jobRunningTasks, &jobConcurrency,
+			&task.PageETag, &task.PageLastModified, &task.PageContentHash,
 		)
 		elapsed := time.Since(queryStart)
 
@@ -1331,7 +1396,9 @@ func (q *DbQueue) EnqueueURLs(ctx context.Context, jobID string, pages []Page, s
 			retryCounts = append(retryCounts, 0)
 			sourceTypes = append(sourceTypes, sourceType)
 			// Only store source_url for 'link' source type (pages discovered from other pages)
-			if sourceType == "link" {
+			if page.SourceURL != "" {
+				sourceURLs = append(sourceURLs, page.SourceURL)
+			} else if sourceType == "link" {
 				sourceURLs = append(sourceURLs, sourceURL)
 			} else {
 				sourceURLs = append(sourceURLs, "")
@@ -1421,7 +1488,8 @@ func (q *DbQueue) EnqueueURLs(ctx context.Context, jobID string, pages []Page, s
 }
 
 // CleanupStuckJobs finds and fixes jobs that are stuck in pending/running state
-// despite having all their tasks completed
+// despite having all their tasks completed. Jobs still reading their sitemaps
+// are left open.
 func (q *DbQueue) CleanupStuckJobs(ctx context.Context) error {
 	// Serialize cleanup operations to prevent prepared statement conflicts
 	q.cleanupMutex.Lock()
@@ -1445,7 +1513,8 @@ func (q *DbQueue) CleanupStuckJobs(ctx context.Context) error {
 		WHERE (status = $3 OR status = $4)
 		AND total_tasks > 0
 		AND total_tasks = completed_tasks + failed_tasks + skipped_tasks
-	`, JobStatusCompleted, time.Now().UTC(), JobStatusPending, JobStatusRunning)
+		AND (NOT sitemap_processing OR created_at < $5)
+	`, JobStatusCompleted, time.Now().UTC(), JobStatusPending, JobStatusRunning, time.Now().UTC().Add(-staleSitemapProcessing))
 
 	if err != nil {
 		span.SetTag("error", "true")
@@ -1541,7 +1610,14 @@ func (q *DbQueue) UpdateTaskStatus(ctx context.Context, task *Task) error {
 					second_dns_lookup_time = $19, second_tcp_connection_time = $20,
 					second_tls_handshake_time = $21, second_ttfb = $22,
 					second_content_transfer_time = $23,
-					retry_count = $24, cache_check_attempts = $25::jsonb
+					retry_count = $24, cache_check_attempts = $25::jsonb,
+					redirect_chain = NULLIF($27, '')::jsonb, redirect_loop = $28,
+					redirect_limit_exceeded = $29, cache_variants = NULLIF($30, '')::jsonb,
+					cdn_provider = NULLIF($31, ''), cache_expires_at = NULLIF($32, '')::timestamptz,
+					seo = NULLIF($33, '')::jsonb, page_audit = NULLIF($34, '')::jsonb,
+					structured_data = NULLIF($35, '')::jsonb, insecure_content = NULLIF($36, '')::jsonb,
+					etag = NULLIF($37, ''), last_modified = NULLIF($38, ''), not_modified = $39,
+					content_hash = NULLIF($40, '')
 				WHERE id = $26
 				RETURNING job_id
 			`, task.Status, task.CompletedAt, task.StatusCode,
@@ -1554,16 +1630,35 @@ func (q *DbQueue) UpdateTaskStatus(ctx context.Context, task *Task) error {
 				task.SecondDNSLookupTime, task.SecondTCPConnectionTime,
 				task.SecondTLSHandshakeTime, task.SecondTTFB,
 				task.SecondContentTransferTime,
-				task.RetryCount, string(cacheCheckAttempts), task.ID).Scan(&jobID)
+				task.RetryCount, string(cacheCheckAttempts), task.ID,
+				string(task.RedirectChain), task.RedirectLoop, task.RedirectLimitHit,
+				string(task.CacheVariants), task.CDNProvider,
+				formatNullableTime(task.CacheExpiresAt), string(task.SEO),
+				string(task.PageAudit), string(task.StructuredData),
+				string(task.InsecureContent), task.ETag, task.LastModified,
+				task.NotModified, task.ContentHash).Scan(&jobID)
+			if err == nil {
+				err = updatePageValidators(ctx, tx, []*Task{task})
+			}
+			if err == nil {
+				err = updatePageContentHashes(ctx, tx, []*Task{task})
+			}
+			if err == nil {
+				err = recordTaskFindings(ctx, tx, []*Task{task})
+			}
 
 		case "failed":
 			// Update task fields only (running_tasks decremented separately via DecrementRunningTasks)
 			err = tx.QueryRowContext(ctx, `
 				UPDATE tasks
-				SET status = $1, completed_at = $2, error = $3, retry_count = $4
+				SET status = $1, completed_at = $2, error = $3, retry_count = $4,
+					redirect_chain = NULLIF($6, '')::jsonb, redirect_loop = $7,
+					redirect_limit_exceeded = $8, soft_404 = NULLIF($9, '')
 				WHERE id = $5
 				RETURNING job_id
-			`, task.Status, task.CompletedAt, task.Error, task.RetryCount, task.ID).Scan(&jobID)
+			`, task.Status, task.CompletedAt, task.Error, task.RetryCount, task.ID,
+				string(task.RedirectChain), task.RedirectLoop, task.RedirectLimitHit,
+				task.Soft404).Scan(&jobID)
 
 		case "skipped":
 			// Update task fields only (running_tasks decremented separately via DecrementRunningTasks)
@@ -1741,3 +1836,75 @@ func (q *DbQueue) UpdateDomainTechnologies(ctx context.Context, domainID int, te
 	}
 	return q.db.UpdateDomainTechnologies(ctx, domainID, technologies, headers, htmlPath)
 }
+
+// RecordHostTLS stores a job's TLS state for a host.
+// Delegates to the underlying DB implementation.
+func (q *DbQueue) RecordHostTLS(ctx context.Context, info *HostTLS) (bool, error) {
+	if q == nil || q.db == nil {
+		return false, fmt.Errorf("queue not initialised")
+	}
+	return q.db.RecordHostTLS(ctx, info)
+}
+
+// CreateCertificateExpiryNotification notifies a job's organisation of an
+// expiring certificate. Delegates to the underlying DB implementation.
+func (q *DbQueue) CreateCertificateExpiryNotification(ctx context.Context, info *HostTLS, daysLeft int) (bool, error) {
+	if q == nil || q.db == nil {
+		return false, fmt.Errorf("queue not initialised")
+	}
+	return q.db.CreateCertificateExpiryNotification(ctx, info, daysLeft)
+}
+
+// AddJobExternalLinks stores unchecked external links found by a job.
+// Delegates to the underlying DB implementation.
+func (q *DbQueue) AddJobExternalLinks(ctx context.Context, links []JobExternalLink) error {
+	if q == nil || q.db == nil {
+		return fmt.Errorf("queue not initialised")
+	}
+	return q.db.AddJobExternalLinks(ctx, links)
+}
+
+// ClaimJobExternalLinks claims unchecked external links for checking.
+// Delegates to the underlying DB implementation.
+func (q *DbQueue) ClaimJobExternalLinks(ctx context.Context, limit int, claimFor time.Duration) ([]JobExternalLink, error) {
+	if q == nil || q.db == nil {
+		return nil, fmt.Errorf("queue not initialised")
+	}
+	return q.db.ClaimJobExternalLinks(ctx, limit, claimFor)
+}
+
+// GetExternalLinkCheck returns a recent cached check of a link.
+// Delegates to the underlying DB implementation.
+func (q *DbQueue) GetExternalLinkCheck(ctx context.Context, linkURL string, maxAge time.Duration) (*ExternalLinkCheck, error) {
+	if q == nil || q.db == nil {
+		return nil, fmt.Errorf("queue not initialised")
+	}
+	return q.db.GetExternalLinkCheck(ctx, linkURL, maxAge)
+}
+
+// RecordExternalLinkCheck stores an external link check result.
+// Delegates to the underlying DB implementation.
+func (q *DbQueue) RecordExternalLinkCheck(ctx context.Context, jobID string, check *ExternalLinkCheck, cached bool) error {
+	if q == nil || q.db == nil {
+		return fmt.Errorf("queue not initialised")
+	}
+	return q.db.RecordExternalLinkCheck(ctx, jobID, check, cached)
+}
+
+// SetTaskContentDiffPath records where a task's content diff was stored.
+// Delegates to the underlying DB implementation.
+func (q *DbQueue) SetTaskContentDiffPath(ctx context.Context, taskID, path, baseHash string) error {
+	if q == nil || q.db == nil {
+		return fmt.Errorf("queue not initialised")
+	}
+	return q.db.SetTaskContentDiffPath(ctx, taskID, path, baseHash)
+}
+
+// RecordPageFragments stores a page's anchors and fragment links.
+// Delegates to the underlying DB implementation.
+func (q *DbQueue) RecordPageFragments(ctx context.Context, jobID, pageKey string, anchors []string, links []FragmentLink) error {
+	if q == nil || q.db == nil {
+		return fmt.Errorf("queue not initialised")
+	}
+	return q.db.RecordPageFragments(ctx, jobID, pageKey, anchors, links)
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Harvey-AU/adapt/internal/util"
 	"github.com/rs/zerolog/log"
 )
 
@@ -15,12 +16,22 @@ import (
 var ErrSchedulerNotFound = errors.New("scheduler not found")
 var ErrSchedulerStateConflict = errors.New("scheduler state conflict")
 
+// MinScheduleInterval is the shortest time allowed between two runs of a
+// scheduler, matching the shortest schedule_interval_hours option
+const MinScheduleInterval = 6 * time.Hour
+
+// cronFrequencyHorizon is how far ahead CheckCronFrequency looks for runs that
+// are too close together, so date-specific bursts such as "* * 1 1 *" are seen
+const cronFrequencyHorizon = 366 * 24 * time.Hour
+
 // Scheduler represents a recurring job schedule
 type Scheduler struct {
 	ID                    string
 	DomainID              int
 	OrganisationID        string
-	ScheduleIntervalHours int
+	ScheduleIntervalHours int    // 0 when the scheduler uses a cron expression
+	CronExpression        string // empty when the scheduler uses an interval
+	Timezone              string // IANA zone the cron expression is evaluated in
 	NextRunAt             time.Time
 	IsEnabled             bool
 	Concurrency           int
@@ -29,26 +40,167 @@ type Scheduler struct {
 	IncludePaths          []string
 	ExcludePaths          []string
 	RequiredWorkers       int
+	KeepWarm              bool // Re-warm pages shortly before their edge TTL expires
+	DeltaMode             bool // Only warm sitemap URLs changed since the previous run
+	FullCrawlEvery        int  // Force a full crawl every N runs in delta mode; 0 never does
 	CreatedAt             time.Time
 	UpdatedAt             time.Time
 }
 
+// Location returns the scheduler's time zone, defaulting to UTC
+func (s *Scheduler) Location() (*time.Location, error) {
+	loc, err := time.LoadLocation(schedulerTimezone(s.Timezone))
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
+	}
+	return loc, nil
+}
+
+// NextRunAfter calculates the next run time strictly after now.
+// Cron schedulers are evaluated in their time zone. Interval schedulers step
+// forward from the previous next_run_at in whole intervals so run times do
+// not drift by the scheduler tick delay.
+func (s *Scheduler) NextRunAfter(now time.Time) (time.Time, error) {
+	if s.CronExpression != "" {
+		schedule, err := util.ParseCron(s.CronExpression)
+		if err != nil {
+			return time.Time{}, err
+		}
+		loc, err := s.Location()
+		if err != nil {
+			return time.Time{}, err
+		}
+		next := schedule.Next(now.In(loc))
+		if next.IsZero() {
+			return time.Time{}, fmt.Errorf("cron expression %q never matches", s.CronExpression)
+		}
+		return next.UTC(), nil
+	}
+
+	if s.ScheduleIntervalHours <= 0 {
+		return time.Time{}, fmt.Errorf("scheduler has no interval or cron expression")
+	}
+
+	interval := time.Duration(s.ScheduleIntervalHours) * time.Hour
+	if s.NextRunAt.IsZero() || s.NextRunAt.After(now) {
+		return now.Add(interval).UTC(), nil
+	}
+
+	missed := now.Sub(s.NextRunAt)/interval + 1
+	return s.NextRunAt.Add(missed * interval).UTC(), nil
+}
+
+// MinRunGap returns the minimum time allowed between two jobs from this
+// scheduler, used to guard against duplicate runs. It is half the interval,
+// or half the gap between the next two cron matches, and never less than half
+// of MinScheduleInterval.
+func (s *Scheduler) MinRunGap(now time.Time) time.Duration {
+	if s.CronExpression == "" {
+		return max(time.Duration(s.ScheduleIntervalHours)*time.Hour, MinScheduleInterval) / 2
+	}
+
+	first, err := s.NextRunAfter(now)
+	if err != nil {
+		return MinScheduleInterval / 2
+	}
+	second, err := s.NextRunAfter(first)
+	if err != nil {
+		return MinScheduleInterval / 2
+	}
+	return max(second.Sub(first), MinScheduleInterval) / 2
+}
+
+// CheckCronFrequency returns an error if the scheduler's cron expression
+// matches twice within minGap at any point in the next year. Gaps are
+// measured in wall-clock time in the scheduler's time zone, so a daylight
+// saving change does not make "0 */6 * * *" count as too frequent.
+func (s *Scheduler) CheckCronFrequency(now time.Time, minGap time.Duration) error {
+	if s.CronExpression == "" {
+		return nil
+	}
+	schedule, err := util.ParseCron(s.CronExpression)
+	if err != nil {
+		return err
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return err
+	}
+
+	wallClock := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	}
+
+	start := now.In(loc)
+	previous := schedule.Next(start)
+	for !previous.IsZero() && previous.Sub(start) < cronFrequencyHorizon {
+		next := schedule.Next(previous)
+		if next.IsZero() {
+			break
+		}
+		// A zero gap is the same wall-clock time repeated when clocks go back
+		if gap := wallClock(next).Sub(wallClock(previous)); gap > 0 && gap < minGap {
+			return fmt.Errorf("cron expression %q runs %s apart at %s; runs must be at least %s apart",
+				s.CronExpression, gap, previous.Format(time.RFC3339), minGap)
+		}
+		previous = next
+	}
+	return nil
+}
+
+// UseDeltaRun reports whether the scheduler's next job, which has a baseline
+// to compare against, should only warm sitemap URLs changed since it. Every
+// FullCrawlEvery-th run is a full crawl.
+func (s *Scheduler) UseDeltaRun(deltaRunsSinceFull int) bool {
+	if !s.DeltaMode {
+		return false
+	}
+	if s.FullCrawlEvery <= 0 {
+		return true
+	}
+	return deltaRunsSinceFull+1 < s.FullCrawlEvery
+}
+
+func nullableInterval(hours int) any {
+	if hours <= 0 {
+		return nil
+	}
+	return hours
+}
+
+func nullableCron(expr string) any {
+	if expr == "" {
+		return nil
+	}
+	return expr
+}
+
+func schedulerTimezone(tz string) string {
+	if tz == "" {
+		return "UTC"
+	}
+	return tz
+}
+
 // CreateScheduler creates a new scheduler
 func (db *DB) CreateScheduler(ctx context.Context, scheduler *Scheduler) error {
 	query := `
 		INSERT INTO schedulers (
-			id, domain_id, organisation_id, schedule_interval_hours, next_run_at,
-			is_enabled, concurrency, find_links, max_pages, include_paths,
-			exclude_paths, required_workers, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			id, domain_id, organisation_id, schedule_interval_hours, cron_expression,
+			timezone, next_run_at, is_enabled, concurrency, find_links, max_pages,
+			include_paths, exclude_paths, required_workers, keep_warm, delta_mode, full_crawl_every,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 	`
 
 	_, err := db.client.ExecContext(ctx, query,
 		scheduler.ID, scheduler.DomainID, scheduler.OrganisationID,
-		scheduler.ScheduleIntervalHours, scheduler.NextRunAt, scheduler.IsEnabled,
+		nullableInterval(scheduler.ScheduleIntervalHours), nullableCron(scheduler.CronExpression),
+		schedulerTimezone(scheduler.Timezone), scheduler.NextRunAt, scheduler.IsEnabled,
 		scheduler.Concurrency, scheduler.FindLinks, scheduler.MaxPages,
 		Serialise(scheduler.IncludePaths), Serialise(scheduler.ExcludePaths),
-		scheduler.RequiredWorkers, scheduler.CreatedAt, scheduler.UpdatedAt,
+		scheduler.RequiredWorkers, scheduler.KeepWarm, scheduler.DeltaMode, scheduler.FullCrawlEvery,
+		scheduler.CreatedAt, scheduler.UpdatedAt,
 	)
 	if err != nil {
 		log.Error().Err(err).Str("scheduler_id", scheduler.ID).Str("organisation_id", scheduler.OrganisationID).Msg("Failed to create scheduler")
@@ -61,21 +213,25 @@ func (db *DB) CreateScheduler(ctx context.Context, scheduler *Scheduler) error {
 // GetScheduler retrieves a scheduler by ID
 func (db *DB) GetScheduler(ctx context.Context, schedulerID string) (*Scheduler, error) {
 	scheduler := &Scheduler{}
-	var includePaths, excludePaths sql.NullString
+	var includePaths, excludePaths, cronExpression sql.NullString
+	var intervalHours sql.NullInt64
 
 	query := `
-		SELECT id, domain_id, organisation_id, schedule_interval_hours, next_run_at,
-		       is_enabled, concurrency, find_links, max_pages, include_paths,
-		       exclude_paths, required_workers, created_at, updated_at
+		SELECT id, domain_id, organisation_id, schedule_interval_hours, cron_expression,
+		       timezone, next_run_at, is_enabled, concurrency, find_links, max_pages, include_paths,
+		       exclude_paths, required_workers, keep_warm, delta_mode, full_crawl_every,
+		       created_at, updated_at
 		FROM schedulers
 		WHERE id = $1
 	`
 
 	err := db.client.QueryRowContext(ctx, query, schedulerID).Scan(
 		&scheduler.ID, &scheduler.DomainID, &scheduler.OrganisationID,
-		&scheduler.ScheduleIntervalHours, &scheduler.NextRunAt, &scheduler.IsEnabled,
+		&intervalHours, &cronExpression, &scheduler.Timezone,
+		&scheduler.NextRunAt, &scheduler.IsEnabled,
 		&scheduler.Concurrency, &scheduler.FindLinks, &scheduler.MaxPages,
-		&includePaths, &excludePaths, &scheduler.RequiredWorkers,
+		&includePaths, &excludePaths, &scheduler.RequiredWorkers, &scheduler.KeepWarm,
+		&scheduler.DeltaMode, &scheduler.FullCrawlEvery,
 		&scheduler.CreatedAt, &scheduler.UpdatedAt,
 	)
 	if err != nil {
@@ -86,6 +242,9 @@ func (db *DB) GetScheduler(ctx context.Context, schedulerID string) (*Scheduler,
 		return nil, fmt.Errorf("failed to get scheduler: %w", err)
 	}
 
+	scheduler.ScheduleIntervalHours = int(intervalHours.Int64)
+	scheduler.CronExpression = cronExpression.String
+
 	if includePaths.Valid && includePaths.String != "" {
 		if err := json.Unmarshal([]byte(includePaths.String), &scheduler.IncludePaths); err != nil {
 			log.Warn().Err(err).Str("scheduler_id", schedulerID).Msg("Failed to deserialise include_paths")
@@ -109,9 +268,10 @@ func (db *DB) GetScheduler(ctx context.Context, schedulerID string) (*Scheduler,
 // ListSchedulers retrieves all schedulers for an organisation
 func (db *DB) ListSchedulers(ctx context.Context, organisationID string) ([]*Scheduler, error) {
 	query := `
-		SELECT id, domain_id, organisation_id, schedule_interval_hours, next_run_at,
-		       is_enabled, concurrency, find_links, max_pages, include_paths,
-		       exclude_paths, required_workers, created_at, updated_at
+		SELECT id, domain_id, organisation_id, schedule_interval_hours, cron_expression,
+		       timezone, next_run_at, is_enabled, concurrency, find_links, max_pages, include_paths,
+		       exclude_paths, required_workers, keep_warm, delta_mode, full_crawl_every,
+		       created_at, updated_at
 		FROM schedulers
 		WHERE organisation_id = $1
 		ORDER BY created_at DESC
@@ -128,13 +288,16 @@ func (db *DB) ListSchedulers(ctx context.Context, organisationID string) ([]*Sch
 	schedulers := make([]*Scheduler, 0)
 	for rows.Next() {
 		scheduler := &Scheduler{}
-		var includePaths, excludePaths sql.NullString
+		var includePaths, excludePaths, cronExpression sql.NullString
+		var intervalHours sql.NullInt64
 
 		err := rows.Scan(
 			&scheduler.ID, &scheduler.DomainID, &scheduler.OrganisationID,
-			&scheduler.ScheduleIntervalHours, &scheduler.NextRunAt, &scheduler.IsEnabled,
+			&intervalHours, &cronExpression, &scheduler.Timezone,
+			&scheduler.NextRunAt, &scheduler.IsEnabled,
 			&scheduler.Concurrency, &scheduler.FindLinks, &scheduler.MaxPages,
-			&includePaths, &excludePaths, &scheduler.RequiredWorkers,
+			&includePaths, &excludePaths, &scheduler.RequiredWorkers, &scheduler.KeepWarm,
+			&scheduler.DeltaMode, &scheduler.FullCrawlEvery,
 			&scheduler.CreatedAt, &scheduler.UpdatedAt,
 		)
 		if err != nil {
@@ -142,6 +305,9 @@ func (db *DB) ListSchedulers(ctx context.Context, organisationID string) ([]*Sch
 			return nil, fmt.Errorf("failed to scan scheduler: %w", err)
 		}
 
+		scheduler.ScheduleIntervalHours = int(intervalHours.Int64)
+		scheduler.CronExpression = cronExpression.String
+
 		if includePaths.Valid && includePaths.String != "" {
 			if err := json.Unmarshal([]byte(includePaths.String), &scheduler.IncludePaths); err != nil {
 				log.Warn().Err(err).Str("scheduler_id", scheduler.ID).Msg("Failed to deserialise include_paths")
@@ -172,34 +338,43 @@ func (db *DB) UpdateScheduler(ctx context.Context, schedulerID string, updates *
 	query := `
 		UPDATE schedulers
 		SET schedule_interval_hours = $1,
-		    next_run_at = $2,
-		    is_enabled = $3,
-		    concurrency = $4,
-		    find_links = $5,
-		    max_pages = $6,
-		    include_paths = $7,
-		    exclude_paths = $8,
-		    required_workers = $9,
-		    updated_at = $10
-		WHERE id = $11
+		    cron_expression = $2,
+		    timezone = $3,
+		    next_run_at = $4,
+		    is_enabled = $5,
+		    concurrency = $6,
+		    find_links = $7,
+		    max_pages = $8,
+		    include_paths = $9,
+		    exclude_paths = $10,
+		    required_workers = $11,
+		    keep_warm = $12,
+		    delta_mode = $13,
+		    full_crawl_every = $14,
+		    updated_at = $15
+		WHERE id = $16
 	`
 
 	var result sql.Result
 	var err error
 	if expectedIsEnabled != nil {
-		query = query + " AND is_enabled = $12"
+		query = query + " AND is_enabled = $17"
 		result, err = db.client.ExecContext(ctx, query,
-			updates.ScheduleIntervalHours, updates.NextRunAt, updates.IsEnabled,
+			nullableInterval(updates.ScheduleIntervalHours), nullableCron(updates.CronExpression),
+			schedulerTimezone(updates.Timezone), updates.NextRunAt, updates.IsEnabled,
 			updates.Concurrency, updates.FindLinks, updates.MaxPages,
 			Serialise(updates.IncludePaths), Serialise(updates.ExcludePaths),
-			updates.RequiredWorkers, time.Now().UTC(), schedulerID, *expectedIsEnabled,
+			updates.RequiredWorkers, updates.KeepWarm, updates.DeltaMode, updates.FullCrawlEvery,
+			time.Now().UTC(), schedulerID, *expectedIsEnabled,
 		)
 	} else {
 		result, err = db.client.ExecContext(ctx, query,
-			updates.ScheduleIntervalHours, updates.NextRunAt, updates.IsEnabled,
+			nullableInterval(updates.ScheduleIntervalHours), nullableCron(updates.CronExpression),
+			schedulerTimezone(updates.Timezone), updates.NextRunAt, updates.IsEnabled,
 			updates.Concurrency, updates.FindLinks, updates.MaxPages,
 			Serialise(updates.IncludePaths), Serialise(updates.ExcludePaths),
-			updates.RequiredWorkers, time.Now().UTC(), schedulerID,
+			updates.RequiredWorkers, updates.KeepWarm, updates.DeltaMode, updates.FullCrawlEvery,
+			time.Now().UTC(), schedulerID,
 		)
 	}
 	if err != nil {
@@ -259,9 +434,10 @@ func (db *DB) DeleteScheduler(ctx context.Context, schedulerID string) error {
 // GetSchedulersReadyToRun retrieves schedulers that are ready to run
 func (db *DB) GetSchedulersReadyToRun(ctx context.Context, limit int) ([]*Scheduler, error) {
 	query := `
-		SELECT id, domain_id, organisation_id, schedule_interval_hours, next_run_at,
-		       is_enabled, concurrency, find_links, max_pages, include_paths,
-		       exclude_paths, required_workers, created_at, updated_at
+		SELECT id, domain_id, organisation_id, schedule_interval_hours, cron_expression,
+		       timezone, next_run_at, is_enabled, concurrency, find_links, max_pages, include_paths,
+		       exclude_paths, required_workers, keep_warm, delta_mode, full_crawl_every,
+		       created_at, updated_at
 		FROM schedulers
 		WHERE is_enabled = TRUE
 		  AND next_run_at <= NOW()
@@ -276,17 +452,165 @@ func (db *DB) GetSchedulersReadyToRun(ctx context.Context, limit int) ([]*Schedu
 	}
 	defer rows.Close()
 
+	return scanSchedulerRows(rows)
+}
+
+// ClaimSchedulersReadyToRun atomically claims due schedulers so only one
+// instance fires each of them. Claimed rows have next_run_at pushed out by
+// claimFor; the caller sets the real next run once the job is created; if it
+// crashes first, the scheduler becomes due again when the claim lapses.
+// Rows locked by another instance's claim are skipped rather than waited on.
+// The returned schedulers carry their original next_run_at.
+func (db *DB) ClaimSchedulersReadyToRun(ctx context.Context, limit int, claimFor time.Duration) ([]*Scheduler, error) {
+	query := `
+		WITH due AS (
+			SELECT id, next_run_at
+			FROM schedulers
+			WHERE is_enabled = TRUE
+			  AND next_run_at <= NOW()
+			ORDER BY next_run_at ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE schedulers s
+		SET next_run_at = NOW() + make_interval(secs => $2)
+		FROM due
+		WHERE s.id = due.id
+		RETURNING s.id, s.domain_id, s.organisation_id, s.schedule_interval_hours, s.cron_expression,
+		          s.timezone, due.next_run_at, s.is_enabled, s.concurrency, s.find_links, s.max_pages,
+		          s.include_paths, s.exclude_paths, s.required_workers, s.keep_warm, s.delta_mode,
+		          s.full_crawl_every, s.created_at, s.updated_at
+	`
+
+	rows, err := db.client.QueryContext(ctx, query, limit, claimFor.Seconds())
+	if err != nil {
+		log.Error().Err(err).Int("limit", limit).Msg("Failed to claim schedulers ready to run")
+		return nil, fmt.Errorf("failed to claim schedulers ready to run: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSchedulerRows(rows)
+}
+
+// ClaimKeepWarmSchedulers claims keep-warm schedulers that have not been
+// checked within interval, skipping domains that already have an active job
+// since that job is warming the site anyway. last_keep_warm_at is set on
+// claim so each scheduler is checked by one instance per interval.
+func (db *DB) ClaimKeepWarmSchedulers(ctx context.Context, interval time.Duration, limit int) ([]*Scheduler, error) {
+	query := `
+		WITH due AS (
+			SELECT s.id
+			FROM schedulers s
+			WHERE s.is_enabled = TRUE
+			  AND s.keep_warm = TRUE
+			  AND (s.last_keep_warm_at IS NULL OR s.last_keep_warm_at <= NOW() - make_interval(secs => $1))
+			  AND NOT EXISTS (
+				SELECT 1
+				FROM jobs j
+				WHERE j.domain_id = s.domain_id
+				  AND j.organisation_id = s.organisation_id
+				  AND j.status IN ('pending', 'running', 'paused')
+			  )
+			ORDER BY s.last_keep_warm_at ASC NULLS FIRST
+			LIMIT $2
+			FOR UPDATE OF s SKIP LOCKED
+		)
+		UPDATE schedulers s
+		SET last_keep_warm_at = NOW()
+		FROM due
+		WHERE s.id = due.id
+		RETURNING s.id, s.domain_id, s.organisation_id, s.schedule_interval_hours, s.cron_expression,
+		          s.timezone, s.next_run_at, s.is_enabled, s.concurrency, s.find_links, s.max_pages,
+		          s.include_paths, s.exclude_paths, s.required_workers, s.keep_warm, s.delta_mode,
+		          s.full_crawl_every, s.created_at, s.updated_at
+	`
+
+	rows, err := db.client.QueryContext(ctx, query, interval.Seconds(), limit)
+	if err != nil {
+		log.Error().Err(err).Int("limit", limit).Msg("Failed to claim keep-warm schedulers")
+		return nil, fmt.Errorf("failed to claim keep-warm schedulers: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSchedulerRows(rows)
+}
+
+// keepWarmLookback limits how far back keep-warm looks for a page's last crawl
+const keepWarmLookback = 7 * 24 * time.Hour
+
+// GetKeepWarmPages returns the pages crawled by a scheduler's jobs whose
+// cached copy is expected to expire before horizon, highest traffic score
+// first. Only each page's most recent task is considered, so pages that are
+// already queued for re-warming, or whose last crawl failed, are skipped.
+// Each page's Priority is its traffic score. Jobs are found through the
+// (domain_id, completed_at) index first so only their tasks are scanned;
+// active jobs are excluded by ClaimKeepWarmSchedulers anyway.
+func (db *DB) GetKeepWarmPages(ctx context.Context, schedulerID string, horizon time.Time, limit int) ([]Page, error) {
+	query := `
+		WITH recent_jobs AS (
+			SELECT j.id
+			FROM jobs j
+			JOIN schedulers s ON s.id = $1
+			WHERE j.domain_id = s.domain_id
+			  AND j.completed_at >= NOW() - make_interval(secs => $4)
+			  AND j.scheduler_id = $1
+		),
+		latest AS (
+			SELECT DISTINCT ON (t.page_id) t.page_id, t.host, t.path, t.status, t.cache_expires_at
+			FROM tasks t
+			WHERE t.job_id IN (SELECT id FROM recent_jobs)
+			  AND t.created_at >= NOW() - make_interval(secs => $4)
+			  AND COALESCE(t.source_type, '') <> 'asset'
+			ORDER BY t.page_id, t.created_at DESC
+		)
+		SELECT l.page_id, l.host, l.path, COALESCE(pa.traffic_score, 0) AS traffic_score
+		FROM latest l
+		JOIN schedulers s ON s.id = $1
+		LEFT JOIN page_analytics pa
+		  ON pa.organisation_id = s.organisation_id
+		 AND pa.domain_id = s.domain_id
+		 AND pa.path = l.path
+		WHERE l.status = 'completed'
+		  AND l.cache_expires_at IS NOT NULL
+		  AND l.cache_expires_at <= $2
+		ORDER BY traffic_score DESC, l.cache_expires_at ASC
+		LIMIT $3
+	`
+
+	rows, err := db.client.QueryContext(ctx, query, schedulerID, horizon, limit, keepWarmLookback.Seconds())
+	if err != nil {
+		log.Error().Err(err).Str("scheduler_id", schedulerID).Msg("Failed to query keep-warm pages")
+		return nil, fmt.Errorf("failed to get keep-warm pages: %w", err)
+	}
+	defer rows.Close()
+
+	pages := make([]Page, 0)
+	for rows.Next() {
+		var page Page
+		if err := rows.Scan(&page.ID, &page.Host, &page.Path, &page.Priority); err != nil {
+			return nil, fmt.Errorf("failed to scan keep-warm page: %w", err)
+		}
+		pages = append(pages, page)
+	}
+
+	return pages, rows.Err()
+}
+
+func scanSchedulerRows(rows *sql.Rows) ([]*Scheduler, error) {
 	// Initialize slice to return empty array instead of null in JSON
 	schedulers := make([]*Scheduler, 0)
 	for rows.Next() {
 		scheduler := &Scheduler{}
-		var includePaths, excludePaths sql.NullString
+		var includePaths, excludePaths, cronExpression sql.NullString
+		var intervalHours sql.NullInt64
 
 		err := rows.Scan(
 			&scheduler.ID, &scheduler.DomainID, &scheduler.OrganisationID,
-			&scheduler.ScheduleIntervalHours, &scheduler.NextRunAt, &scheduler.IsEnabled,
+			&intervalHours, &cronExpression, &scheduler.Timezone,
+			&scheduler.NextRunAt, &scheduler.IsEnabled,
 			&scheduler.Concurrency, &scheduler.FindLinks, &scheduler.MaxPages,
-			&includePaths, &excludePaths, &scheduler.RequiredWorkers,
+			&includePaths, &excludePaths, &scheduler.RequiredWorkers, &scheduler.KeepWarm,
+			&scheduler.DeltaMode, &scheduler.FullCrawlEvery,
 			&scheduler.CreatedAt, &scheduler.UpdatedAt,
 		)
 		if err != nil {
@@ -294,6 +618,9 @@ func (db *DB) GetSchedulersReadyToRun(ctx context.Context, limit int) ([]*Schedu
 			return nil, fmt.Errorf("failed to scan scheduler: %w", err)
 		}
 
+		scheduler.ScheduleIntervalHours = int(intervalHours.Int64)
+		scheduler.CronExpression = cronExpression.String
+
 		if includePaths.Valid && includePaths.String != "" {
 			if err := json.Unmarshal([]byte(includePaths.String), &scheduler.IncludePaths); err != nil {
 				log.Warn().Err(err).Str("scheduler_id", scheduler.ID).Msg("Failed to deserialise include_paths")
@@ -317,7 +644,8 @@ func (db *DB) GetSchedulersReadyToRun(ctx context.Context, limit int) ([]*Schedu
 	return schedulers, rows.Err()
 }
 
-// GetLastJobStartTimeForScheduler retrieves the most recent started_at time for jobs created by a scheduler
+// GetLastJobStartTimeForScheduler retrieves the most recent started_at time for jobs created by a scheduler.
+// Keep-warm jobs are ignored since they only re-warm a handful of pages.
 func (db *DB) GetLastJobStartTimeForScheduler(ctx context.Context, schedulerID string) (*time.Time, error) {
 	var startedAt sql.NullTime
 
@@ -326,6 +654,7 @@ func (db *DB) GetLastJobStartTimeForScheduler(ctx context.Context, schedulerID s
 		FROM jobs
 		WHERE scheduler_id = $1
 		  AND started_at IS NOT NULL
+		  AND COALESCE(source_type, '') <> 'keep_warm'
 		ORDER BY started_at DESC
 		LIMIT 1
 	`
@@ -347,6 +676,51 @@ func (db *DB) GetLastJobStartTimeForScheduler(ctx context.Context, schedulerID s
 	return &startedAt.Time, nil
 }
 
+// GetDeltaBaseline returns the scheduler's latest completed job with a
+// sitemap snapshot to compare the next run against ("" if there is none), and
+// the number of completed delta runs since its last completed full crawl.
+// Keep-warm jobs are ignored.
+func (db *DB) GetDeltaBaseline(ctx context.Context, schedulerID string) (string, int, error) {
+	var baselineJobID sql.NullString
+	var deltaRuns int
+
+	query := `
+		WITH last_full AS (
+			SELECT MAX(created_at) AS created_at
+			FROM jobs
+			WHERE scheduler_id = $1
+			  AND status = 'completed'
+			  AND crawl_mode = 'full'
+			  AND COALESCE(source_type, '') <> 'keep_warm'
+		)
+		SELECT (
+			SELECT j.id
+			FROM jobs j
+			WHERE j.scheduler_id = $1
+			  AND j.status = 'completed'
+			  AND COALESCE(j.source_type, '') <> 'keep_warm'
+			  AND EXISTS (SELECT 1 FROM job_sitemap_entries e WHERE e.job_id = j.id)
+			ORDER BY j.created_at DESC
+			LIMIT 1
+		), (
+			SELECT COUNT(*)
+			FROM jobs j, last_full
+			WHERE j.scheduler_id = $1
+			  AND j.status = 'completed'
+			  AND j.crawl_mode = 'delta'
+			  AND (last_full.created_at IS NULL OR j.created_at > last_full.created_at)
+		)
+		FROM last_full
+	`
+
+	if err := db.client.QueryRowContext(ctx, query, schedulerID).Scan(&baselineJobID, &deltaRuns); err != nil {
+		log.Error().Err(err).Str("scheduler_id", schedulerID).Msg("Failed to get delta baseline")
+		return "", 0, fmt.Errorf("failed to get delta baseline: %w", err)
+	}
+
+	return baselineJobID.String, deltaRuns, nil
+}
+
 // UpdateSchedulerNextRun updates only the next_run_at timestamp
 func (db *DB) UpdateSchedulerNextRun(ctx context.Context, schedulerID string, nextRun time.Time) error {
 	query := `
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedulerNextRunAfterIntervalKeepsSlot(t *testing.T) {
+	slot := time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)
+	scheduler := &Scheduler{ScheduleIntervalHours: 6, NextRunAt: slot}
+
+	// A tick 40 seconds late should not shift future runs
+	next, err := scheduler.NextRunAfter(slot.Add(40 * time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := slot.Add(6 * time.Hour); !next.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, next)
+	}
+
+	// Missed slots are skipped rather than replayed
+	next, err = scheduler.NextRunAfter(slot.Add(13 * time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := slot.Add(18 * time.Hour); !next.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, next)
+	}
+}
+
+func TestSchedulerNextRunAfterCronTimezone(t *testing.T) {
+	scheduler := &Scheduler{CronExpression: "0 6 * * MON-FRI", Timezone: "Australia/Melbourne"}
+
+	// Friday 10:00 Melbourne (AEDT, UTC+11) -> Monday 06:00 Melbourne
+	now := time.Date(2026, 3, 5, 23, 0, 0, 0, time.UTC)
+	next, err := scheduler.NextRunAfter(now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2026, 3, 8, 19, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, next)
+	}
+
+	if gap := scheduler.MinRunGap(now); gap != 12*time.Hour {
+		t.Fatalf("expected 12h minimum gap between weekday runs, got %s", gap)
+	}
+}
+
+func TestSchedulerNextRunAfterInvalidTimezone(t *testing.T) {
+	scheduler := &Scheduler{CronExpression: "@daily", Timezone: "Mars/Olympus"}
+	if _, err := scheduler.NextRunAfter(time.Now()); err == nil {
+		t.Fatal("expected error for unknown timezone")
+	}
+}
+
+func TestSchedulerUseDeltaRun(t *testing.T) {
+	scheduler := &Scheduler{DeltaMode: true, FullCrawlEvery: 3}
+
+	// Full, delta, delta, full, ...
+	for runs, want := range []bool{true, true, false} {
+		if got := scheduler.UseDeltaRun(runs); got != want {
+			t.Fatalf("after %d delta runs: expected delta=%v, got %v", runs, want, got)
+		}
+	}
+
+	scheduler.FullCrawlEvery = 1
+	if scheduler.UseDeltaRun(0) {
+		t.Fatal("expected every run to be full when full_crawl_every is 1")
+	}
+
+	scheduler.FullCrawlEvery = 0
+	if !scheduler.UseDeltaRun(100) {
+		t.Fatal("expected full crawls never to be forced when full_crawl_every is 0")
+	}
+
+	scheduler.DeltaMode = false
+	if scheduler.UseDeltaRun(0) {
+		t.Fatal("expected a full crawl when delta mode is off")
+	}
+}
+
+func TestSchedulerCheckCronFrequency(t *testing.T) {
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expr     string
+		timezone string
+		wantErr  bool
+	}{
+		{"* * * * *", "UTC", true},
+		{"0 * * * *", "UTC", true},
+		{"0 0,3 * * *", "UTC", true},
+		{"* 9 1 1 *", "UTC", true}, // Only too frequent on 1 January
+		{"0 */6 * * *", "UTC", false},
+		{"0 */6 * * *", "Australia/Melbourne", false}, // Across both daylight saving changes
+		{"0 2 * * *", "Australia/Melbourne", false},
+		{"30 9 * * MON-FRI", "UTC", false},
+	}
+
+	for _, tt := range tests {
+		scheduler := &Scheduler{CronExpression: tt.expr, Timezone: tt.timezone}
+		err := scheduler.CheckCronFrequency(now, MinScheduleInterval)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q in %s: expected error=%v, got %v", tt.expr, tt.timezone, tt.wantErr, err)
+		}
+	}
+}
+
+func TestSchedulerMinRunGapFloor(t *testing.T) {
+	// Schedulers saved before the frequency check still get a sane guard
+	scheduler := &Scheduler{CronExpression: "* * * * *"}
+	if gap := scheduler.MinRunGap(time.Now()); gap != MinScheduleInterval/2 {
+		t.Fatalf("expected %s minimum gap, got %s", MinScheduleInterval/2, gap)
+	}
+}
@@ -0,0 +1,239 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// How a sitemap URL compares with the job's baseline
+const (
+	SitemapChangeNew       = "new"        // Not in the baseline sitemap
+	SitemapChangeChanged   = "changed"    // lastmod, or content crawled since the baseline, differs
+	SitemapChangeNoLastMod = "no_lastmod" // lastmod missing now or in the baseline, so unknown
+	SitemapChangeNotWarmed = "not_warmed" // Same lastmod, but the baseline's task did not complete
+	SitemapChangeUnchanged = "unchanged"  // Same lastmod as the baseline
+	SitemapChangeRemoved   = "removed"    // In the baseline sitemap but not this one
+)
+
+// SitemapSnapshotEntry is a page listed in a job's sitemap with its lastmod
+type SitemapSnapshotEntry struct {
+	PageID  int
+	LastMod *time.Time
+}
+
+// SitemapChange is a sitemap URL that differs from the job's baseline
+type SitemapChange struct {
+	Host            string
+	Path            string
+	Change          string
+	LastMod         *time.Time
+	PreviousLastMod *time.Time
+	CreatedAt       time.Time // When the job was created
+}
+
+// SitemapChangeNeedsWarming reports whether a page with the given change
+// should be warmed by a delta job
+func SitemapChangeNeedsWarming(change string) bool {
+	switch change {
+	case SitemapChangeNew, SitemapChangeChanged, SitemapChangeNoLastMod, SitemapChangeNotWarmed:
+		return true
+	}
+	return false
+}
+
+// RecordSitemapEntries stores sitemap pages seen by a job, classifying each
+// against the baseline job's entries. A page with the same lastmod still
+// needs warming when the baseline's task for it did not complete (it failed,
+// was skipped or was never reached), or when a later crawl stored content
+// differing from what the baseline saw. Pages the baseline had no task for,
+// such as those an earlier delta run skipped, are compared by lastmod alone. It returns the change
+// for each page recorded; pages the job already recorded are skipped and left
+// out. Changes are empty when there is no baseline.
+func RecordSitemapEntries(ctx context.Context, q TransactionExecutor, jobID, baselineJobID string, entries []SitemapSnapshotEntry) (map[int]string, error) {
+	if len(entries) == 0 {
+		return map[int]string{}, nil
+	}
+
+	pageIDs := make([]int64, 0, len(entries))
+	lastMods := make([]sql.NullTime, 0, len(entries))
+	seen := make(map[int]struct{}, len(entries))
+	for _, entry := range entries {
+		if _, ok := seen[entry.PageID]; ok {
+			continue
+		}
+		seen[entry.PageID] = struct{}{}
+		pageIDs = append(pageIDs, int64(entry.PageID))
+		lastMod := sql.NullTime{}
+		if entry.LastMod != nil {
+			lastMod = sql.NullTime{Time: *entry.LastMod, Valid: true}
+		}
+		lastMods = append(lastMods, lastMod)
+	}
+
+	changes := make(map[int]string, len(pageIDs))
+	err := q.Execute(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `
+			INSERT INTO job_sitemap_entries (job_id, page_id, lastmod, change)
+			SELECT $1, cur.page_id, cur.lastmod,
+			       CASE
+			         WHEN $2::text IS NULL THEN NULL
+			         WHEN prev.page_id IS NULL THEN 'new'
+			         WHEN cur.lastmod IS NULL OR prev.lastmod IS NULL THEN 'no_lastmod'
+			         WHEN cur.lastmod <> prev.lastmod THEN 'changed'
+			         WHEN baseline_task.id IS NOT NULL AND baseline_task.status <> 'completed' THEN 'not_warmed'
+			         WHEN baseline_task.content_hash IS NOT NULL
+			          AND p.content_hash IS DISTINCT FROM baseline_task.content_hash THEN 'changed'
+			         ELSE 'unchanged'
+			       END
+			FROM UNNEST($3::int[], $4::timestamptz[]) AS cur(page_id, lastmod)
+			LEFT JOIN job_sitemap_entries prev
+			  ON prev.job_id = $2
+			 AND prev.page_id = cur.page_id
+			 AND prev.change IS DISTINCT FROM 'removed'
+			LEFT JOIN tasks baseline_task
+			  ON baseline_task.job_id = $2
+			 AND baseline_task.page_id = cur.page_id
+			LEFT JOIN pages p ON p.id = cur.page_id
+			ON CONFLICT (job_id, page_id) DO NOTHING
+			RETURNING page_id, COALESCE(change, '')
+		`, jobID, sql.NullString{String: baselineJobID, Valid: baselineJobID != ""}, pq.Array(pageIDs), pq.Array(lastMods))
+		if err != nil {
+			return fmt.Errorf("failed to record sitemap entries: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var pageID int
+			var change string
+			if err := rows.Scan(&pageID, &change); err != nil {
+				return fmt.Errorf("failed to scan sitemap entry: %w", err)
+			}
+			changes[pageID] = change
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// FinishSitemapSnapshot completes a job's sitemap snapshot once every batch
+// is recorded. When the sitemaps were read in full, baseline pages missing
+// from this job's sitemap are recorded as removed; a partial read would
+// report pages in unread sitemaps as removed, so they are left out. The
+// counts of each change are stored on the job and returned. Without a
+// baseline there is nothing to compare and nil is returned.
+func FinishSitemapSnapshot(ctx context.Context, q TransactionExecutor, jobID, baselineJobID string, complete bool) (map[string]int, error) {
+	if baselineJobID == "" {
+		return nil, nil
+	}
+
+	counts := make(map[string]int)
+	err := q.Execute(ctx, func(tx *sql.Tx) error {
+		if complete {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO job_sitemap_entries (job_id, page_id, lastmod, change)
+				SELECT $1, prev.page_id, NULL, 'removed'
+				FROM job_sitemap_entries prev
+				WHERE prev.job_id = $2
+				  AND prev.change IS DISTINCT FROM 'removed'
+				ON CONFLICT (job_id, page_id) DO NOTHING
+			`, jobID, baselineJobID); err != nil {
+				return fmt.Errorf("failed to record removed sitemap entries: %w", err)
+			}
+		}
+
+		rows, err := tx.QueryContext(ctx, `
+			SELECT change, COUNT(*)
+			FROM job_sitemap_entries
+			WHERE job_id = $1
+			  AND change IS NOT NULL
+			GROUP BY change
+		`, jobID)
+		if err != nil {
+			return fmt.Errorf("failed to count sitemap changes: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var change string
+			var count int
+			if err := rows.Scan(&change, &count); err != nil {
+				return fmt.Errorf("failed to scan sitemap change count: %w", err)
+			}
+			counts[change] = count
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to iterate sitemap change counts: %w", err)
+		}
+
+		countsJSON, err := json.Marshal(counts)
+		if err != nil {
+			return fmt.Errorf("failed to marshal sitemap changes: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE jobs
+			SET sitemap_changes = $2
+			WHERE id = $1
+		`, jobID, countsJSON); err != nil {
+			return fmt.Errorf("failed to store sitemap changes: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// ListJobSitemapChanges returns the sitemap URLs a job found new, changed,
+// without a lastmod, or removed compared with its baseline
+func (db *DB) ListJobSitemapChanges(ctx context.Context, jobID string, limit int) ([]SitemapChange, error) {
+	rows, err := db.client.QueryContext(ctx, `
+		SELECT p.host, p.path, e.change, e.lastmod, prev.lastmod, j.created_at
+		FROM job_sitemap_entries e
+		JOIN jobs j ON j.id = e.job_id
+		JOIN pages p ON p.id = e.page_id
+		LEFT JOIN job_sitemap_entries prev
+		  ON prev.job_id = j.baseline_job_id
+		 AND prev.page_id = e.page_id
+		WHERE e.job_id = $1
+		  AND e.change IS NOT NULL
+		  AND e.change <> 'unchanged'
+		ORDER BY e.change, p.host, p.path
+		LIMIT $2
+	`, jobID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sitemap changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := make([]SitemapChange, 0)
+	for rows.Next() {
+		var change SitemapChange
+		var lastMod, previousLastMod sql.NullTime
+		if err := rows.Scan(&change.Host, &change.Path, &change.Change, &lastMod, &previousLastMod, &change.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan sitemap change: %w", err)
+		}
+		if lastMod.Valid {
+			change.LastMod = &lastMod.Time
+		}
+		if previousLastMod.Valid {
+			change.PreviousLastMod = &previousLastMod.Time
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sitemap changes: %w", err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,90 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Harvey-AU/adapt/internal/storage"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// contentDiffContext is the number of unchanged lines shown around each change
+	contentDiffContext = 3
+	// contentTextReadLimit caps the stored content text read back for a diff
+	contentTextReadLimit = 1 << 20
+)
+
+// contentTextPath is where a page's main content text is stored, keyed by its
+// content hash so unchanged pages are never uploaded twice
+func contentTextPath(pageID int, hash string) string {
+	return fmt.Sprintf("pages/%d/content/%s.txt", pageID, hash)
+}
+
+// contentDiffPath is where the diff between two versions of a page is stored
+func contentDiffPath(pageID int, previousHash, hash string) string {
+	return fmt.Sprintf("pages/%d/diffs/%s-%s.diff", pageID, previousHash, hash)
+}
+
+// contentDiff returns a unified diff of a page's main content between two
+// versions, or "" when the main content is the same (the hash can change
+// because of the header, footer or published version alone)
+func contentDiff(previousHash, hash, previous, current string) (string, error) {
+	if previous == current {
+		return "", nil
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(strings.TrimSuffix(previous, "\n") + "\n"),
+		B:        difflib.SplitLines(strings.TrimSuffix(current, "\n") + "\n"),
+		FromFile: previousHash,
+		ToFile:   hash,
+		Context:  contentDiffContext,
+	})
+}
+
+// storeContentDiff uploads a page's main content text under its hash and,
+// when the text of the previous version is stored, a diff against it whose
+// path is recorded on the task. Failures are logged and otherwise ignored.
+func (wp *WorkerPool) storeContentDiff(ctx context.Context, taskID string, pageID int, previousHash, hash, mainText string) {
+	if _, err := wp.storageBackend.Put(ctx, contentTextPath(pageID, hash), strings.NewReader(mainText), int64(len(mainText)), "text/plain; charset=utf-8"); err != nil {
+		log.Warn().Err(err).Str("task_id", taskID).Int("page_id", pageID).Msg("Failed to upload page content text")
+		return
+	}
+	if previousHash == "" {
+		return // First crawl of the page, nothing to compare against
+	}
+
+	previous, err := storage.ReadAll(ctx, wp.storageBackend, contentTextPath(pageID, previousHash), contentTextReadLimit)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Debug().Str("task_id", taskID).Int("page_id", pageID).Msg("No stored content for the previous version, skipping diff")
+		} else {
+			log.Warn().Err(err).Str("task_id", taskID).Int("page_id", pageID).Msg("Failed to download previous page content")
+		}
+		return
+	}
+
+	diff, err := contentDiff(previousHash, hash, string(previous), mainText)
+	if err != nil {
+		log.Warn().Err(err).Str("task_id", taskID).Int("page_id", pageID).Msg("Failed to diff page content")
+		return
+	}
+	if diff == "" {
+		return
+	}
+
+	path, err := wp.storageBackend.Put(ctx, contentDiffPath(pageID, previousHash, hash), strings.NewReader(diff), int64(len(diff)), "text/x-diff; charset=utf-8")
+	if err != nil {
+		log.Warn().Err(err).Str("task_id", taskID).Int("page_id", pageID).Msg("Failed to upload page content diff")
+		return
+	}
+	if err := wp.dbQueue.SetTaskContentDiffPath(ctx, taskID, path, previousHash); err != nil {
+		log.Error().Err(err).Str("task_id", taskID).Str("path", path).Msg("Failed to record page content diff")
+		return
+	}
+
+	log.Debug().Str("task_id", taskID).Int("page_id", pageID).Str("path", path).Msg("Stored page content diff")
+}
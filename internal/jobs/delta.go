@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Harvey-AU/adapt/internal/crawler"
+	"github.com/Harvey-AU/adapt/internal/db"
+	"github.com/rs/zerolog/log"
+)
+
+// recordSitemapBatch stores a batch of a tracked job's sitemap URLs and their
+// lastmod dates, compared against the job's baseline, and returns the URLs to
+// warm: all of them for a full crawl, or for a delta crawl only those that are
+// new, changed or have no lastmod to compare
+func (jm *JobManager) recordSitemapBatch(ctx context.Context, job *Job, domain string, urls []string, entries []crawler.SitemapEntry) ([]string, error) {
+	domainID, err := jm.jobDomainID(ctx, job.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	pageIDs, hosts, paths, err := db.CreatePageRecords(ctx, jm.dbQueue, domainID, domain, urls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create page records: %w", err)
+	}
+
+	// Page records are keyed by host and path, which may differ from the URL
+	lastMods := make(map[string]*time.Time, len(entries))
+	for _, entry := range entries {
+		if host, path, err := db.NormalisePageURL(entry.Loc, domain); err == nil && entry.LastMod != nil {
+			lastMods[host+"|"+path] = entry.LastMod
+		}
+	}
+
+	pageKeys := make(map[string]int, len(pageIDs))
+	snapshot := make([]db.SitemapSnapshotEntry, len(pageIDs))
+	for i, pageID := range pageIDs {
+		key := hosts[i] + "|" + paths[i]
+		pageKeys[key] = pageID
+		snapshot[i] = db.SitemapSnapshotEntry{PageID: pageID, LastMod: lastMods[key]}
+	}
+
+	baselineJobID := ""
+	if job.BaselineJobID != nil {
+		baselineJobID = *job.BaselineJobID
+	}
+	changes, err := db.RecordSitemapEntries(ctx, jm.dbQueue, job.ID, baselineJobID, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.CrawlMode != CrawlModeDelta {
+		return urls, nil
+	}
+	return changedSitemapURLs(urls, domain, pageKeys, changes), nil
+}
+
+// changedSitemapURLs returns the URLs whose pages need warming in a delta
+// crawl. Pages missing from changes were already recorded from an earlier
+// batch and are left to that batch.
+func changedSitemapURLs(urls []string, domain string, pageKeys map[string]int, changes map[int]string) []string {
+	changed := make([]string, 0, len(urls))
+	for _, pageURL := range urls {
+		host, path, err := db.NormalisePageURL(pageURL, domain)
+		if err != nil {
+			continue
+		}
+		pageID, ok := pageKeys[host+"|"+path]
+		if !ok {
+			continue
+		}
+		if change, ok := changes[pageID]; ok && db.SitemapChangeNeedsWarming(change) {
+			changed = append(changed, pageURL)
+		}
+	}
+	return changed
+}
+
+// finishSitemapSnapshot records baseline URLs that left the sitemap and the
+// job's change counts. complete is false when some sitemaps could not be read
+// in full, so missing URLs are not reported as removed.
+func (jm *JobManager) finishSitemapSnapshot(ctx context.Context, job *Job, complete bool) {
+	if job.BaselineJobID == nil {
+		return
+	}
+
+	counts, err := db.FinishSitemapSnapshot(ctx, jm.dbQueue, job.ID, *job.BaselineJobID, complete)
+	if err != nil {
+		log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to finish sitemap snapshot")
+		return
+	}
+
+	log.Info().
+		Str("job_id", job.ID).
+		Str("baseline_job_id", *job.BaselineJobID).
+		Str("crawl_mode", job.CrawlMode).
+		Int("new", counts[db.SitemapChangeNew]).
+		Int("changed", counts[db.SitemapChangeChanged]).
+		Int("no_lastmod", counts[db.SitemapChangeNoLastMod]).
+		Int("not_warmed", counts[db.SitemapChangeNotWarmed]).
+		Int("unchanged", counts[db.SitemapChangeUnchanged]).
+		Int("removed", counts[db.SitemapChangeRemoved]).
+		Bool("complete", complete).
+		Msg("Compared sitemap with baseline job")
+}
@@ -0,0 +1,241 @@
+package jobs
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Harvey-AU/adapt/internal/crawler"
+	"github.com/Harvey-AU/adapt/internal/db"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultExternalLinkCacheTTL is how long a check of an external link is
+	// reused by other jobs before the link is checked again
+	defaultExternalLinkCacheTTL = 24 * time.Hour
+	// defaultExternalLinkDelay is the robots-style delay applied per external
+	// host, so checks never hammer another site
+	defaultExternalLinkDelay = 2 * time.Second
+	// externalLinkCheckers is the number of goroutines checking external links
+	externalLinkCheckers = 4
+	// externalLinkClaimBatch is the number of links a checker claims at once
+	externalLinkClaimBatch = 10
+	// externalLinkClaimFor is how long a claimed link waits for its result
+	// before another checker may claim it, e.g. after a crash
+	externalLinkClaimFor = 10 * time.Minute
+	// externalLinkPollInterval is how often idle checkers look for new links
+	externalLinkPollInterval = 5 * time.Second
+	// maxExternalLinksPerJob caps the distinct external links checked per job
+	maxExternalLinksPerJob = 5000
+)
+
+func externalLinkCacheTTLFromEnv() time.Duration {
+	if raw := strings.TrimSpace(os.Getenv("BBB_EXTERNAL_LINK_CACHE_TTL_HOURS")); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 {
+			return time.Duration(parsed) * time.Hour
+		}
+	}
+	return defaultExternalLinkCacheTTL
+}
+
+func externalLinkDelayFromEnv() time.Duration {
+	if raw := strings.TrimSpace(os.Getenv("BBB_EXTERNAL_LINK_DELAY_SECONDS")); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 {
+			return time.Duration(parsed) * time.Second
+		}
+	}
+	return defaultExternalLinkDelay
+}
+
+// externalLinks returns the http(s) links on a page that point outside the
+// job's registrable domain, resolved against the page URL and without
+// fragments, in the order found
+func externalLinks(links map[string][]string, sourceURL, domain string) []string {
+	baseURL, baseErr := url.Parse(sourceURL)
+	seen := make(map[string]bool)
+	var external []string
+
+	for _, category := range []string{"header", "footer", "body"} {
+		for _, link := range links[category] {
+			linkURL, err := url.Parse(strings.TrimSpace(link))
+			if err != nil {
+				continue
+			}
+			if !linkURL.IsAbs() {
+				if baseErr != nil {
+					continue
+				}
+				linkURL = baseURL.ResolveReference(linkURL)
+			}
+			scheme := strings.ToLower(linkURL.Scheme)
+			if (scheme != "http" && scheme != "https") || linkURL.Hostname() == "" {
+				continue
+			}
+			if sameRegistrableDomain(linkURL.Hostname(), domain) {
+				continue
+			}
+
+			linkURL.Fragment = ""
+			linkURL.RawFragment = ""
+			normalised := linkURL.String()
+			if seen[normalised] {
+				continue
+			}
+			seen[normalised] = true
+			external = append(external, normalised)
+		}
+	}
+	return external
+}
+
+// markExternalLink records that a job has stored an external link, returning
+// false if it already has or the job has reached its external link cap
+func (wp *WorkerPool) markExternalLink(jobID, linkURL string) bool {
+	wp.externalLinksMutex.Lock()
+	defer wp.externalLinksMutex.Unlock()
+
+	if wp.externalLinksSeen == nil {
+		wp.externalLinksSeen = make(map[string]map[string]bool)
+	}
+	links, ok := wp.externalLinksSeen[jobID]
+	if !ok {
+		links = make(map[string]bool)
+		wp.externalLinksSeen[jobID] = links
+	}
+	if links[linkURL] || len(links) >= maxExternalLinksPerJob {
+		return false
+	}
+	links[linkURL] = true
+	return true
+}
+
+func (wp *WorkerPool) unmarkExternalLink(jobID, linkURL string) {
+	wp.externalLinksMutex.Lock()
+	defer wp.externalLinksMutex.Unlock()
+	delete(wp.externalLinksSeen[jobID], linkURL)
+}
+
+// queueExternalLinks stores a page's external links for the background
+// checkers to claim, and wakes an idle checker
+func (wp *WorkerPool) queueExternalLinks(ctx context.Context, task *Task, result *crawler.CrawlResult, sourceURL string) {
+	var links []db.JobExternalLink
+	for _, link := range externalLinks(result.Links, sourceURL, task.DomainName) {
+		parsed, err := url.Parse(link)
+		if err != nil || !wp.markExternalLink(task.JobID, link) {
+			continue
+		}
+		links = append(links, db.JobExternalLink{
+			JobID:     task.JobID,
+			URL:       link,
+			Host:      strings.ToLower(parsed.Hostname()),
+			SourceURL: sourceURL,
+		})
+	}
+	if len(links) == 0 {
+		return
+	}
+
+	if err := wp.dbQueue.AddJobExternalLinks(ctx, links); err != nil {
+		// Forget the links so they are stored if found again
+		for _, link := range links {
+			wp.unmarkExternalLink(task.JobID, link.URL)
+		}
+		log.Warn().
+			Err(err).
+			Str("job_id", task.JobID).
+			Str("task_id", task.ID).
+			Int("links", len(links)).
+			Msg("Failed to store external links")
+		return
+	}
+
+	select {
+	case wp.externalLinkWake <- struct{}{}:
+	default:
+	}
+}
+
+// startExternalLinkCheckers starts the goroutines that claim stored external
+// links and check them
+func (wp *WorkerPool) startExternalLinkCheckers(ctx context.Context) {
+	for range externalLinkCheckers {
+		wp.wg.Go(func() {
+			for {
+				links, err := wp.dbQueue.ClaimJobExternalLinks(ctx, externalLinkClaimBatch, externalLinkClaimFor)
+				if err != nil && ctx.Err() == nil {
+					log.Warn().Err(err).Msg("Failed to claim external links")
+				}
+				for _, link := range links {
+					wp.checkExternalLink(ctx, link)
+				}
+				if len(links) == externalLinkClaimBatch {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-wp.stopCh:
+					return
+				case <-wp.externalLinkWake:
+				case <-time.After(externalLinkPollInterval):
+				}
+			}
+		})
+	}
+}
+
+// checkExternalLink checks a claimed external link. A recent result from any
+// job is reused; otherwise the link is requested, paced per external host by
+// the domain limiter, and the result cached for other jobs. If the check is
+// cancelled the claim lapses and the link is checked later.
+func (wp *WorkerPool) checkExternalLink(ctx context.Context, link db.JobExternalLink) {
+	if wp.externalLinkCacheTTL > 0 {
+		cached, err := wp.dbQueue.GetExternalLinkCheck(ctx, link.URL, wp.externalLinkCacheTTL)
+		if err != nil {
+			log.Warn().Err(err).Str("url", link.URL).Msg("Failed to read external link cache")
+		}
+		if cached != nil {
+			if err := wp.dbQueue.RecordExternalLinkCheck(ctx, link.JobID, cached, true); err != nil {
+				log.Warn().Err(err).Str("job_id", link.JobID).Str("url", link.URL).Msg("Failed to record cached external link check")
+			}
+			return
+		}
+	}
+
+	permit, err := wp.ensureDomainLimiter().Acquire(ctx, DomainRequest{
+		Domain:         link.Host,
+		JobID:          link.JobID,
+		RobotsDelay:    wp.externalLinkDelay,
+		JobConcurrency: 1,
+	})
+	if err != nil {
+		log.Debug().Err(err).Str("job_id", link.JobID).Str("url", link.URL).Msg("External link check cancelled")
+		return
+	}
+
+	result, checkErr := wp.crawler.CheckLink(ctx, link.URL)
+	rateLimited := IsRateLimitError(checkErr)
+	if result != nil && result.StatusCode == http.StatusTooManyRequests {
+		rateLimited = true
+	}
+	permit.Release(checkErr == nil, rateLimited)
+
+	check := &db.ExternalLinkCheck{URL: link.URL, CheckedAt: time.Now().UTC()}
+	if result != nil {
+		check.StatusCode = result.StatusCode
+		check.ResponseTime = result.ResponseTime
+	}
+	if checkErr != nil {
+		check.Error = checkErr.Error()
+	}
+
+	if err := wp.dbQueue.RecordExternalLinkCheck(ctx, link.JobID, check, false); err != nil {
+		log.Warn().Err(err).Str("job_id", link.JobID).Str("url", link.URL).Msg("Failed to record external link check")
+	}
+}
@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/Harvey-AU/adapt/internal/crawler"
+	"github.com/Harvey-AU/adapt/internal/db"
+	"github.com/rs/zerolog/log"
+)
+
+// maxFragmentLinksPerPage caps the fragment links recorded from one page
+const maxFragmentLinksPerPage = 500
+
+// fragmentPageKey identifies the page a fragment points into: the host
+// (ignoring www) and path, without query, fragment or trailing slash
+func fragmentPageKey(u *url.URL) string {
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	if path != "/" {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return normaliseComparableHost(u.Hostname()) + path
+}
+
+// isCheckableFragment reports whether a fragment should match an anchor on
+// its page. Empty and "top" fragments always resolve, text fragments
+// (#:~:text=) are not anchors and "#/" or "#!" fragments are client-side routes.
+func isCheckableFragment(fragment string) bool {
+	if fragment == "" || strings.EqualFold(fragment, "top") {
+		return false
+	}
+	return !strings.HasPrefix(fragment, ":~:") && !strings.HasPrefix(fragment, "/") && !strings.HasPrefix(fragment, "!")
+}
+
+// fragmentLinks returns the links on a page that point at a section of a
+// page on the job's site, including the page itself
+func fragmentLinks(links map[string][]string, sourceURL string, task *Task) []db.FragmentLink {
+	baseURL, baseErr := url.Parse(sourceURL)
+	seen := make(map[string]bool)
+	var found []db.FragmentLink
+
+	for _, category := range []string{"header", "footer", "body"} {
+		for _, link := range links[category] {
+			if len(found) >= maxFragmentLinksPerPage {
+				return found
+			}
+			linkURL, err := url.Parse(strings.TrimSpace(link))
+			if err != nil || !isCheckableFragment(linkURL.Fragment) {
+				continue
+			}
+			if !linkURL.IsAbs() {
+				if baseErr != nil {
+					continue
+				}
+				linkURL = baseURL.ResolveReference(linkURL)
+			}
+			scheme := strings.ToLower(linkURL.Scheme)
+			if (scheme != "http" && scheme != "https") || !isLinkAllowedForTask(linkURL.Hostname(), task) {
+				continue
+			}
+
+			targetKey := fragmentPageKey(linkURL)
+			if seen[targetKey+"#"+linkURL.Fragment] {
+				continue
+			}
+			seen[targetKey+"#"+linkURL.Fragment] = true
+			found = append(found, db.FragmentLink{
+				URL:       linkURL.String(),
+				TargetKey: targetKey,
+				Fragment:  linkURL.Fragment,
+				SourceURL: sourceURL,
+			})
+		}
+	}
+	return found
+}
+
+// recordPageFragments stores the anchors found on a crawled page and the
+// fragment links it contains, which are validated against each other when
+// the job's broken links are exported
+func (wp *WorkerPool) recordPageFragments(ctx context.Context, task *Task, result *crawler.CrawlResult, sourceURL string) {
+	pageURL, err := url.Parse(sourceURL)
+	if err != nil {
+		return
+	}
+
+	links := fragmentLinks(result.Links, sourceURL, task)
+	if result.Anchors == nil && len(links) == 0 {
+		return
+	}
+
+	dbCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), discoveredLinksDBTimeout)
+	defer cancel()
+
+	if err := wp.dbQueue.RecordPageFragments(dbCtx, task.JobID, fragmentPageKey(pageURL), result.Anchors, links); err != nil {
+		log.Warn().
+			Err(err).
+			Str("job_id", task.JobID).
+			Str("task_id", task.ID).
+			Msg("Failed to record page anchors and fragment links")
+	}
+}
@@ -3,6 +3,7 @@ package jobs
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/Harvey-AU/adapt/internal/crawler"
 	"github.com/Harvey-AU/adapt/internal/db"
@@ -11,8 +12,12 @@ import (
 // CrawlerInterface defines the methods we need from the crawler
 type CrawlerInterface interface {
 	WarmURL(ctx context.Context, url string, findLinks bool) (*crawler.CrawlResult, error)
+	WarmAsset(ctx context.Context, url string) (*crawler.CrawlResult, error)
+	CheckLink(ctx context.Context, url string) (*crawler.CrawlResult, error)
+	ProbeNotFound(ctx context.Context, pageURL string) (*crawler.PageFingerprint, error)
 	DiscoverSitemapsAndRobots(ctx context.Context, domain string) (*crawler.SitemapDiscoveryResult, error)
 	ParseSitemap(ctx context.Context, sitemapURL string) ([]string, error)
+	StreamSitemap(ctx context.Context, sitemapURL string, limits crawler.SitemapLimits, emit func(crawler.SitemapEntry) error) (*crawler.SitemapStats, error)
 	FilterURLs(urls []string, includePaths, excludePaths []string) []string
 	GetUserAgent() string
 }
@@ -28,4 +33,12 @@ type DbQueueInterface interface {
 	ExecuteMaintenance(ctx context.Context, fn func(*sql.Tx) error) error
 	SetConcurrencyOverride(fn db.ConcurrencyOverrideFunc)
 	UpdateDomainTechnologies(ctx context.Context, domainID int, technologies, headers []byte, htmlPath string) error
+	RecordHostTLS(ctx context.Context, info *db.HostTLS) (bool, error)
+	CreateCertificateExpiryNotification(ctx context.Context, info *db.HostTLS, daysLeft int) (bool, error)
+	AddJobExternalLinks(ctx context.Context, links []db.JobExternalLink) error
+	ClaimJobExternalLinks(ctx context.Context, limit int, claimFor time.Duration) ([]db.JobExternalLink, error)
+	GetExternalLinkCheck(ctx context.Context, linkURL string, maxAge time.Duration) (*db.ExternalLinkCheck, error)
+	RecordExternalLinkCheck(ctx context.Context, jobID string, check *db.ExternalLinkCheck, cached bool) error
+	RecordPageFragments(ctx context.Context, jobID, pageKey string, anchors []string, links []db.FragmentLink) error
+	SetTaskContentDiffPath(ctx context.Context, taskID, path, baseHash string) error
 }
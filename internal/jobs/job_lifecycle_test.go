@@ -3,10 +3,12 @@ package jobs
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Harvey-AU/adapt/internal/crawler"
 	"github.com/Harvey-AU/adapt/internal/db"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -292,6 +294,38 @@ func TestJobLifecycleStatusTransitions(t *testing.T) {
 			toStatus:   JobStatusRunning,
 			isValid:    true, // Restart is allowed
 		},
+		{
+			name:       "running_to_paused",
+			fromStatus: JobStatusRunning,
+			toStatus:   JobStatusPaused,
+			isValid:    true,
+		},
+		{
+			name:       "paused_to_running_resume",
+			fromStatus: JobStatusPaused,
+			toStatus:   JobStatusRunning,
+			isValid:    true,
+		},
+		{
+			name:       "paused_to_cancelled",
+			fromStatus: JobStatusPaused,
+			toStatus:   JobStatusCancelled,
+			isValid:    true,
+		},
+		{
+			name:          "paused_to_completed_invalid",
+			fromStatus:    JobStatusPaused,
+			toStatus:      JobStatusCompleted,
+			isValid:       false,
+			expectedError: "invalid status transition",
+		},
+		{
+			name:          "pending_to_paused_invalid",
+			fromStatus:    JobStatusPending,
+			toStatus:      JobStatusPaused,
+			isValid:       false,
+			expectedError: "invalid status transition",
+		},
 		{
 			name:          "completed_to_pending_invalid",
 			fromStatus:    JobStatusCompleted,
@@ -408,3 +442,491 @@ func TestJobManagerUpdateJobStatus(t *testing.T) {
 		})
 	}
 }
+
+// expectGetJob queues the GetJob lookup used by job actions
+func expectGetJob(mock sqlmock.Sqlmock, jobID string, status JobStatus) {
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT(.+)FROM jobs j(.+)JOIN domains d").
+		WithArgs(jobID).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "status", "progress", "total_tasks", "completed_tasks", "failed_tasks", "skipped_tasks",
+			"created_at", "started_at", "completed_at", "concurrency", "find_links",
+			"include_paths", "exclude_paths", "error_message", "required_workers",
+			"found_tasks", "sitemap_tasks", "duration_seconds", "avg_time_per_task_seconds",
+			"user_id", "organisation_id", "max_pages", "allow_cross_subdomain_links", "crawl_assets",
+			"warm_variants", "ignore_robots_directives", "check_external_links", "purpose",
+		}).AddRow(
+			jobID, "example.com", string(status), 50.0, 10, 5, 0, 0,
+			time.Now(), time.Now(), nil, 5, true,
+			[]byte(`["/blog/*"]`), nil, nil, 1,
+			0, 10, nil, nil,
+			nil, nil, 250, false, true,
+			[]byte(`{"devices":["mobile"]}`), true, true, JobPurposeIntegrity,
+		))
+	mock.ExpectCommit()
+}
+
+// TestJobManagerPauseResume tests pausing and resuming jobs
+func TestJobManagerPauseResume(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		action      func(jm *JobManager) error
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectError string
+	}{
+		{
+			name: "pause_running_job",
+			action: func(jm *JobManager) error {
+				return jm.PauseJob(ctx, "job-1")
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectGetJob(mock, "job-1", JobStatusRunning)
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE jobs\\s+SET status = \\$1\\s+WHERE id = \\$2 AND status = \\$3").
+					WithArgs(JobStatusPaused, "job-1", JobStatusRunning).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "pause_completed_job_rejected",
+			action: func(jm *JobManager) error {
+				return jm.PauseJob(ctx, "job-2")
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectGetJob(mock, "job-2", JobStatusCompleted)
+			},
+			expectError: "job cannot be paused",
+		},
+		{
+			name: "pause_loses_race_with_completion",
+			action: func(jm *JobManager) error {
+				return jm.PauseJob(ctx, "job-3")
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectGetJob(mock, "job-3", JobStatusRunning)
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE jobs").
+					WithArgs(JobStatusPaused, "job-3", JobStatusRunning).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			expectError: "status changed",
+		},
+		{
+			name: "resume_paused_job",
+			action: func(jm *JobManager) error {
+				return jm.ResumeJob(ctx, "job-4")
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectGetJob(mock, "job-4", JobStatusPaused)
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE jobs\\s+SET status = \\$1, resumed_at = \\$2").
+					WithArgs(JobStatusRunning, sqlmock.AnyArg(), "job-4", JobStatusPaused).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "resume_running_job_rejected",
+			action: func(jm *JobManager) error {
+				return jm.ResumeJob(ctx, "job-5")
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectGetJob(mock, "job-5", JobStatusRunning)
+			},
+			expectError: "job cannot be resumed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			jm := &JobManager{
+				db:      mockDB,
+				dbQueue: &mockDbQueueWrapper{mockDB: mockDB},
+			}
+
+			tt.setupMock(mock)
+
+			err = tt.action(jm)
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				assert.ErrorIs(t, err, ErrInvalidJobTransition)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// recordingDbQueueWrapper records EnqueueURLs calls on top of mockDbQueueWrapper
+type recordingDbQueueWrapper struct {
+	mockDbQueueWrapper
+	enqueued map[string][]db.Page
+}
+
+func (m *recordingDbQueueWrapper) EnqueueURLs(ctx context.Context, jobID string, pages []db.Page, sourceType string, sourceURL string) error {
+	m.enqueued[sourceType] = append(m.enqueued[sourceType], pages...)
+	return nil
+}
+
+// TestJobManagerRetryFailedTasks tests creating a child job from failed tasks
+func TestJobManagerRetryFailedTasks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects_active_job", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+
+		jm := &JobManager{db: mockDB, dbQueue: &mockDbQueueWrapper{mockDB: mockDB}}
+		expectGetJob(mock, "job-1", JobStatusRunning)
+
+		_, err = jm.RetryFailedTasks(ctx, "job-1")
+		assert.ErrorIs(t, err, ErrJobNotFinished)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rejects_job_without_failures", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+
+		jm := &JobManager{db: mockDB, dbQueue: &mockDbQueueWrapper{mockDB: mockDB}}
+		expectGetJob(mock, "job-2", JobStatusCompleted)
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT page_id, host, path").
+			WithArgs("job-2", TaskStatusFailed).
+			WillReturnRows(sqlmock.NewRows([]string{"page_id", "host", "path", "source_type", "source_url", "priority_score"}))
+		mock.ExpectCommit()
+
+		_, err = jm.RetryFailedTasks(ctx, "job-2")
+		assert.ErrorIs(t, err, ErrNoFailedTasks)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("creates_child_job", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDB.Close()
+
+		queue := &recordingDbQueueWrapper{
+			mockDbQueueWrapper: mockDbQueueWrapper{mockDB: mockDB},
+			enqueued:           make(map[string][]db.Page),
+		}
+		jm := &JobManager{db: mockDB, dbQueue: queue, processedPages: make(map[string]struct{})}
+
+		expectGetJob(mock, "job-3", JobStatusCompleted)
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT page_id, host, path").
+			WithArgs("job-3", TaskStatusFailed).
+			WillReturnRows(sqlmock.NewRows([]string{"page_id", "host", "path", "source_type", "source_url", "priority_score"}).
+				AddRow(11, "example.com", "/broken", "link", "https://example.com/", 0.9).
+				AddRow(12, "example.com", "/gone", "sitemap", "", 0.1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO domains").
+			WithArgs("example.com").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectExec("INSERT INTO jobs").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec("SELECT recalculate_job_stats").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		job, err := jm.RetryFailedTasks(ctx, "job-3")
+		require.NoError(t, err)
+		require.NotNil(t, job.ParentJobID)
+		assert.Equal(t, "job-3", *job.ParentJobID)
+		assert.False(t, job.FindLinks)
+		assert.Equal(t, 250, job.MaxPages)
+		assert.False(t, job.AllowCrossSubdomainLinks)
+		assert.True(t, job.CrawlAssets)
+		assert.True(t, job.IgnoreRobotsDirectives)
+		assert.True(t, job.CheckExternalLinks)
+		assert.Equal(t, []string{"/blog/*"}, job.IncludePaths)
+		require.NotNil(t, job.WarmVariants)
+		assert.Equal(t, JobPurposeIntegrity, job.Purpose)
+
+		require.Len(t, queue.enqueued["link"], 1)
+		assert.Equal(t, "https://example.com/", queue.enqueued["link"][0].SourceURL)
+		assert.InDelta(t, 0.9, queue.enqueued["link"][0].Priority, 0.001)
+		require.Len(t, queue.enqueued["sitemap"], 1)
+		assert.Equal(t, "/gone", queue.enqueued["sitemap"][0].Path)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateJobCancelsWhenBeforeStartFails(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	jm := &JobManager{db: mockDB, dbQueue: &mockDbQueueWrapper{mockDB: mockDB}, processedPages: make(map[string]struct{})}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO domains").
+		WithArgs("example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec("INSERT INTO jobs").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE jobs").
+		WithArgs(JobStatusCancelled, sqlmock.AnyArg(), "purge rejected", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	purgeErr := errors.New("purge rejected")
+	var recorded *Job
+	job, err := jm.CreateJob(context.Background(), &JobOptions{
+		Domain:      "example.com",
+		Concurrency: 5,
+		UseSitemap:  true,
+		BeforeStart: func(ctx context.Context, job *Job) error {
+			recorded = job
+			return purgeErr
+		},
+	})
+	assert.ErrorIs(t, err, purgeErr)
+	assert.Nil(t, job)
+	require.NotNil(t, recorded, "the hook should run once the job is recorded")
+	assert.Equal(t, JobStatusPending, recorded.Status)
+	assert.NoError(t, mock.ExpectationsWereMet(), "no URLs should be discovered for a cancelled job")
+}
+
+func TestCreateJobRejectsInvalidWarmVariants(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	jm := &JobManager{db: mockDB, dbQueue: &mockDbQueueWrapper{mockDB: mockDB}, processedPages: make(map[string]struct{})}
+
+	orgID := "org-1"
+	job, err := jm.CreateJob(context.Background(), &JobOptions{
+		Domain:         "example.com",
+		OrganisationID: &orgID,
+		WarmVariants: &crawler.VariantMatrix{
+			Devices:   []string{"desktop", "mobile", "tablet"},
+			Encodings: []string{"br", "gzip", "zstd"},
+			Languages: []string{"en", "fr"},
+		},
+	})
+	assert.ErrorIs(t, err, ErrInvalidJobOptions)
+	assert.Nil(t, job)
+	assert.NoError(t, mock.ExpectationsWereMet(), "existing jobs should not be cancelled for an invalid request")
+}
+
+func TestStreamSitemapURLs(t *testing.T) {
+	sitemapURLs := map[string][]string{
+		"https://example.com/a.xml": {"https://example.com/1", "https://example.com/2", "https://example.com/3"},
+		"https://example.com/b.xml": {"https://example.com/4", "https://example.com/5"},
+	}
+	mockCrawler := &MockCrawler{
+		StreamSitemapFunc: func(ctx context.Context, sitemapURL string, limits crawler.SitemapLimits, emit func(crawler.SitemapEntry) error) (*crawler.SitemapStats, error) {
+			if sitemapURL == "https://example.com/missing.xml" {
+				return nil, errors.New("failed to fetch sitemap: 404")
+			}
+			stats := &crawler.SitemapStats{Sitemaps: 1}
+			for _, loc := range sitemapURLs[sitemapURL] {
+				if stats.URLs >= limits.MaxURLs {
+					stats.Truncated = true
+					break
+				}
+				if err := emit(crawler.SitemapEntry{Loc: loc}); err != nil {
+					return stats, err
+				}
+				stats.URLs++
+			}
+			return stats, nil
+		},
+	}
+	sitemaps := []string{"https://example.com/a.xml", "https://example.com/missing.xml", "https://example.com/b.xml"}
+
+	t.Run("batches_across_sitemaps", func(t *testing.T) {
+		var batches [][]string
+		total, sitemapErrors := streamSitemapURLs(context.Background(), mockCrawler, sitemaps, crawler.DefaultSitemapLimits(), 2, func(batch []crawler.SitemapEntry) {
+			var locs []string
+			for _, entry := range batch {
+				locs = append(locs, entry.Loc)
+			}
+			batches = append(batches, locs)
+		})
+
+		assert.Equal(t, 5, total)
+		assert.Equal(t, [][]string{
+			{"https://example.com/1", "https://example.com/2"},
+			{"https://example.com/3", "https://example.com/4"},
+			{"https://example.com/5"},
+		}, batches)
+		require.Len(t, sitemapErrors, 1)
+		assert.Equal(t, "https://example.com/missing.xml", sitemapErrors[0].URL)
+	})
+
+	t.Run("url_limit_spans_sitemaps", func(t *testing.T) {
+		limits := crawler.DefaultSitemapLimits()
+		limits.MaxURLs = 4
+		var urls []string
+		total, _ := streamSitemapURLs(context.Background(), mockCrawler, sitemaps, limits, 10, func(batch []crawler.SitemapEntry) {
+			for _, entry := range batch {
+				urls = append(urls, entry.Loc)
+			}
+		})
+
+		assert.Equal(t, 4, total)
+		assert.Len(t, urls, 4)
+	})
+}
+
+func TestSitemapPriorityScore(t *testing.T) {
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	weights := sitemapPriorityWeights{priority: 1, lastMod: 1, halfLife: 30 * 24 * time.Hour}
+	priority := func(p float64) *float64 { return &p }
+	daysAgo := func(days int) *time.Time {
+		modified := now.AddDate(0, 0, -days)
+		return &modified
+	}
+
+	tests := []struct {
+		name     string
+		weights  sitemapPriorityWeights
+		entry    crawler.SitemapEntry
+		expected float64
+	}{
+		{"no_hints", weights, crawler.SitemapEntry{}, defaultSitemapPriority},
+		{"top_priority_only", weights, crawler.SitemapEntry{Priority: priority(1)}, maxSitemapPriority},
+		{"zero_priority_only", weights, crawler.SitemapEntry{Priority: priority(0)}, defaultSitemapPriority},
+		{"modified_today", weights, crawler.SitemapEntry{LastMod: daysAgo(0)}, maxSitemapPriority},
+		{"modified_future", weights, crawler.SitemapEntry{LastMod: daysAgo(-5)}, maxSitemapPriority},
+		{"modified_one_half_life_ago", weights, crawler.SitemapEntry{LastMod: daysAgo(30)}, 0.5},
+		{"priority_and_lastmod_averaged", weights, crawler.SitemapEntry{Priority: priority(1), LastMod: daysAgo(30)}, 0.7},
+		{
+			name:     "lastmod_weighted_higher",
+			weights:  sitemapPriorityWeights{priority: 1, lastMod: 3, halfLife: 30 * 24 * time.Hour},
+			entry:    crawler.SitemapEntry{Priority: priority(0), LastMod: daysAgo(0)},
+			expected: 0.7,
+		},
+		{
+			name:     "weights_disabled",
+			weights:  sitemapPriorityWeights{},
+			entry:    crawler.SitemapEntry{Priority: priority(1), LastMod: daysAgo(0)},
+			expected: defaultSitemapPriority,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, tt.weights.score(tt.entry, now), 0.001)
+		})
+	}
+
+	t.Run("recent_pages_rank_first", func(t *testing.T) {
+		assert.Greater(t, weights.score(crawler.SitemapEntry{LastMod: daysAgo(1)}, now),
+			weights.score(crawler.SitemapEntry{LastMod: daysAgo(90)}, now))
+	})
+}
+
+func TestChangedSitemapURLs(t *testing.T) {
+	pageKeys := map[string]int{
+		"example.com|/new":       1,
+		"example.com|/changed":   2,
+		"example.com|/same":      3,
+		"example.com|/undated":   4,
+		"example.com|/duplicate": 5,
+		"example.com|/failed":    6,
+		"example.com|/skipped":   7,
+	}
+	changes := map[int]string{
+		1: db.SitemapChangeNew,
+		2: db.SitemapChangeChanged,
+		3: db.SitemapChangeUnchanged,
+		4: db.SitemapChangeNoLastMod,
+		// 5 was recorded by an earlier batch
+		6: db.SitemapChangeNotWarmed,
+		7: db.SitemapChangeNotWarmed, // Skipped by the baseline's max_pages
+	}
+	urls := []string{
+		"https://example.com/new",
+		"https://example.com/changed",
+		"https://example.com/same",
+		"https://example.com/undated",
+		"https://example.com/duplicate",
+		"https://example.com/failed",
+		"https://example.com/skipped",
+	}
+
+	assert.Equal(t, []string{
+		"https://example.com/new",
+		"https://example.com/changed",
+		"https://example.com/undated",
+		"https://example.com/failed",
+		"https://example.com/skipped",
+	}, changedSitemapURLs(urls, "example.com", pageKeys, changes))
+}
+
+// TestRecoverRunningJobsResetsPausedJobTasks checks that tasks orphaned in a
+// paused job by a restart are reset without resuming the job
+func TestRecoverRunningJobsResetsPausedJobTasks(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	wrapper := &mockDbQueueWrapper{mockDB: mockDB}
+	wp := &WorkerPool{
+		db:      mockDB,
+		dbQueue: &MockDbQueue{ExecuteFunc: wrapper.Execute},
+		jobs:    make(map[string]bool),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT DISTINCT j.id, j.status").
+		WithArgs(JobStatusRunning, JobStatusPaused, TaskStatusRunning).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow("job-paused", string(JobStatusPaused)))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE tasks").
+		WithArgs(TaskStatusPending, "job-paused", TaskStatusRunning).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	mock.ExpectQuery("WITH actual_counts AS").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "old_value", "new_value", "leaked_tasks"}).AddRow("job-paused", 2, 0, 2))
+
+	require.NoError(t, wp.recoverRunningJobs(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Empty(t, wp.jobs, "paused jobs should not be added back to the pool")
+}
+
+func TestEnsureJobSafeToRemoveWaitsForSitemap(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	wrapper := &mockDbQueueWrapper{mockDB: mockDB}
+	wp := &WorkerPool{dbQueue: &MockDbQueue{ExecuteFunc: wrapper.Execute}}
+
+	// Every enqueued task is done, but more sitemap URLs may still arrive
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status, pending_tasks").
+		WithArgs("job-1").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"status", "pending_tasks", "waiting_tasks", "running_tasks", "total_tasks",
+			"completed_tasks", "failed_tasks", "skipped_tasks", "concurrency", "sitemap_processing",
+		}).AddRow(string(JobStatusRunning), 0, 0, 0, 1000, 1000, 0, 0, nil, true))
+	mock.ExpectCommit()
+
+	safe, err := wp.ensureJobSafeToRemove(context.Background(), "job-1")
+	require.NoError(t, err)
+	assert.False(t, safe)
+	assert.NoError(t, mock.ExpectationsWereMet(), "the job should not be marked completed")
+}
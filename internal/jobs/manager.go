@@ -31,6 +31,9 @@ type JobManagerInterface interface {
 	// Core job operations used by API layer
 	CreateJob(ctx context.Context, options *JobOptions) (*Job, error)
 	CancelJob(ctx context.Context, jobID string) error
+	RetryFailedTasks(ctx context.Context, jobID string) (*Job, error)
+	PauseJob(ctx context.Context, jobID string) error
+	ResumeJob(ctx context.Context, jobID string) error
 	GetJobStatus(ctx context.Context, jobID string) (*Job, error)
 
 	// Additional job operations
@@ -52,6 +55,9 @@ type JobManager struct {
 
 	workerPool *WorkerPool
 
+	// Weights for starting sitemap URLs at their lastmod and priority
+	sitemapPriority sitemapPriorityWeights
+
 	// Map to track which pages have been processed for each job
 	processedPages map[string]struct{} // Key format: "jobID_pageID"
 	pagesMutex     sync.RWMutex        // Mutex for thread-safe access
@@ -65,6 +71,8 @@ func NewJobManager(db *sql.DB, dbQueue DbQueueProvider, crawler CrawlerInterface
 		crawler:        crawler,
 		workerPool:     workerPool,
 		processedPages: make(map[string]struct{}),
+
+		sitemapPriority: sitemapPriorityWeightsFromEnv(),
 	}
 }
 
@@ -173,11 +181,49 @@ func createJobObject(options *JobOptions, normalisedDomain string) *Job {
 		ExcludePaths:             options.ExcludePaths,
 		RequiredWorkers:          options.RequiredWorkers,
 		AllowCrossSubdomainLinks: options.AllowCrossSubdomainLinks,
+		CrawlAssets:              options.CrawlAssets,
+		IgnoreRobotsDirectives:   options.IgnoreRobotsDirectives,
+		CheckExternalLinks:       options.CheckExternalLinks,
+		WarmVariants:             options.WarmVariants,
 		SourceType:               options.SourceType,
 		SourceDetail:             options.SourceDetail,
 		SourceInfo:               options.SourceInfo,
 		SchedulerID:              options.SchedulerID,
+		ParentJobID:              options.ParentJobID,
+		CrawlMode:                crawlMode(options.CrawlMode),
+		BaselineJobID:            options.BaselineJobID,
+		TrackSitemapChanges:      options.TrackSitemapChanges,
+		Purpose:                  jobPurpose(options.Purpose),
+	}
+}
+
+// jobPurpose defaults an unset purpose to warming
+func jobPurpose(purpose string) string {
+	if purpose == JobPurposeIntegrity {
+		return JobPurposeIntegrity
+	}
+	return JobPurposeWarm
+}
+
+// crawlMode defaults an unset crawl mode to a full crawl
+func crawlMode(mode string) string {
+	if mode == CrawlModeDelta {
+		return CrawlModeDelta
+	}
+	return CrawlModeFull
+}
+
+// serialiseVariantMatrix encodes a job's variant matrix for the warm_variants
+// JSONB column, returning nil (SQL NULL) when no variants are configured
+func serialiseVariantMatrix(matrix *crawler.VariantMatrix) []byte {
+	if len(matrix.Expand()) == 0 {
+		return nil
+	}
+	encoded, err := json.Marshal(matrix)
+	if err != nil {
+		return nil
 	}
+	return encoded
 }
 
 // setupJobDatabase creates domain and job records in the database
@@ -201,15 +247,19 @@ func (jm *JobManager) setupJobDatabase(ctx context.Context, job *Job, normalised
 				id, domain_id, user_id, organisation_id, status, progress, total_tasks, completed_tasks, failed_tasks, skipped_tasks,
 				created_at, concurrency, find_links, include_paths, exclude_paths,
 				required_workers, max_pages, allow_cross_subdomain_links,
-				found_tasks, sitemap_tasks, source_type, source_detail, source_info, scheduler_id
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)`,
+				found_tasks, sitemap_tasks, source_type, source_detail, source_info, scheduler_id,
+				parent_job_id, crawl_assets, warm_variants, ignore_robots_directives, check_external_links,
+				crawl_mode, baseline_job_id, purpose
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32)`,
 			job.ID, domainID, job.UserID, job.OrganisationID, string(job.Status), job.Progress,
 			job.TotalTasks, job.CompletedTasks, job.FailedTasks, job.SkippedTasks,
 			job.CreatedAt, job.Concurrency, job.FindLinks,
 			db.Serialise(job.IncludePaths), db.Serialise(job.ExcludePaths),
 			job.RequiredWorkers, job.MaxPages, job.AllowCrossSubdomainLinks,
 			job.FoundTasks, job.SitemapTasks, job.SourceType, job.SourceDetail, job.SourceInfo,
-			job.SchedulerID,
+			job.SchedulerID, job.ParentJobID, job.CrawlAssets, serialiseVariantMatrix(job.WarmVariants),
+			job.IgnoreRobotsDirectives, job.CheckExternalLinks, job.CrawlMode, job.BaselineJobID,
+			job.Purpose,
 		)
 		return err
 	})
@@ -344,7 +394,7 @@ func (jm *JobManager) setupJobURLDiscovery(ctx context.Context, job *Job, option
 		backgroundCtx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 		go func() {
 			defer cancel()
-			jm.processSitemap(backgroundCtx, job.ID, normalisedDomain, options.IncludePaths, options.ExcludePaths)
+			jm.processSitemap(backgroundCtx, job, normalisedDomain)
 		}()
 		return nil
 	}
@@ -385,6 +435,12 @@ func (jm *JobManager) CreateJob(ctx context.Context, options *JobOptions) (*Job,
 
 	normalisedDomain := util.NormaliseDomain(options.Domain)
 
+	// Every creation path is checked here, not only the API, since each
+	// variant adds requests to every task
+	if err := options.WarmVariants.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: warm_variants: %w", ErrInvalidJobOptions, err)
+	}
+
 	if options.Concurrency <= 0 {
 		defaultConcurrency := fallbackJobConcurrency
 		if jm.workerPool != nil && jm.workerPool.maxWorkers > 0 {
@@ -422,6 +478,13 @@ func (jm *JobManager) CreateJob(ctx context.Context, options *JobOptions) (*Job,
 		Int("max_pages", options.MaxPages).
 		Msg("Created new job")
 
+	if options.BeforeStart != nil {
+		if err := options.BeforeStart(ctx, job); err != nil {
+			jm.cancelUnstartedJob(context.WithoutCancel(ctx), job.ID, err.Error())
+			return nil, err
+		}
+	}
+
 	// Setup URL discovery (sitemap or manual root URL)
 	if err := jm.setupJobURLDiscovery(ctx, job, options, domainID, normalisedDomain); err != nil {
 		span.SetTag("error", "true")
@@ -540,7 +603,7 @@ func (jm *JobManager) CancelJob(ctx context.Context, jobID string) error {
 
 	// Check if job can be canceled
 	if job.Status != JobStatusRunning && job.Status != JobStatusPending && job.Status != JobStatusPaused {
-		return fmt.Errorf("job cannot be canceled: %s", job.Status)
+		return fmt.Errorf("%w: job cannot be canceled: %s", ErrInvalidJobTransition, job.Status)
 	}
 
 	// Update job status to cancelled
@@ -594,6 +657,315 @@ func (jm *JobManager) CancelJob(ctx context.Context, jobID string) error {
 	return nil
 }
 
+// RetryFailedTasks creates a child job containing only the failed tasks of a
+// finished job. Each task keeps its source type, source URL and priority.
+func (jm *JobManager) RetryFailedTasks(ctx context.Context, jobID string) (*Job, error) {
+	span := sentry.StartSpan(ctx, "manager.retry_failed_tasks")
+	defer span.Finish()
+
+	span.SetTag("job_id", jobID)
+
+	parent, err := jm.GetJob(ctx, jobID)
+	if err != nil {
+		span.SetTag("error", "true")
+		span.SetData("error.message", err.Error())
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	switch parent.Status {
+	case JobStatusCompleted, JobStatusFailed, JobStatusCancelled:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFinished, parent.Status)
+	}
+
+	// Load failed tasks grouped by source type so each group can be
+	// enqueued with the original source metadata
+	pagesBySource := make(map[string][]db.Page)
+	var sourceTypes []string
+	var failedCount int
+	err = jm.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT page_id, host, path, COALESCE(source_type, ''), COALESCE(source_url, ''), priority_score
+			FROM tasks
+			WHERE job_id = $1 AND status = $2
+			ORDER BY priority_score DESC, created_at ASC
+		`, parent.ID, TaskStatusFailed)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var page db.Page
+			var sourceType string
+			if err := rows.Scan(&page.ID, &page.Host, &page.Path, &sourceType, &page.SourceURL, &page.Priority); err != nil {
+				return err
+			}
+			if _, ok := pagesBySource[sourceType]; !ok {
+				sourceTypes = append(sourceTypes, sourceType)
+			}
+			pagesBySource[sourceType] = append(pagesBySource[sourceType], page)
+			failedCount++
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		span.SetTag("error", "true")
+		span.SetData("error.message", err.Error())
+		return nil, fmt.Errorf("failed to load failed tasks: %w", err)
+	}
+
+	if failedCount == 0 {
+		return nil, ErrNoFailedTasks
+	}
+
+	if err := jm.handleExistingJobs(ctx, parent.Domain, parent.UserID, parent.OrganisationID); err != nil {
+		return nil, fmt.Errorf("failed to handle existing jobs: %w", err)
+	}
+
+	sourceType := "retry"
+	parentID := parent.ID
+	// Copy every scope option so the retry cannot crawl beyond the parent's limits
+	job := createJobObject(&JobOptions{
+		UserID:                   parent.UserID,
+		OrganisationID:           parent.OrganisationID,
+		Concurrency:              parent.Concurrency,
+		FindLinks:                false, // Only re-check the failed pages
+		AllowCrossSubdomainLinks: parent.AllowCrossSubdomainLinks,
+		CrawlAssets:              parent.CrawlAssets,
+		IgnoreRobotsDirectives:   parent.IgnoreRobotsDirectives,
+		CheckExternalLinks:       parent.CheckExternalLinks,
+		MaxPages:                 parent.MaxPages,
+		IncludePaths:             parent.IncludePaths,
+		ExcludePaths:             parent.ExcludePaths,
+		RequiredWorkers:          parent.RequiredWorkers,
+		WarmVariants:             parent.WarmVariants,
+		Purpose:                  parent.Purpose,
+		SourceType:               &sourceType,
+		SourceDetail:             &parentID,
+		ParentJobID:              &parentID,
+	}, parent.Domain)
+
+	if _, err := jm.setupJobDatabase(ctx, job, parent.Domain); err != nil {
+		span.SetTag("error", "true")
+		span.SetData("error.message", err.Error())
+		sentry.CaptureException(err)
+		return nil, err
+	}
+
+	for _, taskSourceType := range sourceTypes {
+		if err := jm.EnqueueJobURLs(ctx, job.ID, pagesBySource[taskSourceType], taskSourceType, ""); err != nil {
+			span.SetTag("error", "true")
+			span.SetData("error.message", err.Error())
+			jm.updateJobWithError(ctx, job.ID, fmt.Sprintf("Failed to enqueue failed tasks: %v", err))
+			return nil, fmt.Errorf("failed to enqueue failed tasks: %w", err)
+		}
+	}
+
+	if err := jm.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `SELECT recalculate_job_stats($1)`, job.ID)
+		return err
+	}); err != nil {
+		log.Error().
+			Err(err).
+			Str("job_id", job.ID).
+			Msg("Failed to recalculate job stats")
+	}
+
+	if jm.workerPool != nil {
+		jm.workerPool.NotifyNewTasks()
+	}
+
+	log.Info().
+		Str("job_id", job.ID).
+		Str("parent_job_id", parent.ID).
+		Str("domain", job.Domain).
+		Int("failed_tasks", failedCount).
+		Msg("Created retry job for failed tasks")
+
+	return job, nil
+}
+
+// CreateKeepWarmJob creates a small job that re-warms the given pages before
+// their cached copies expire. Unlike CreateJob it skips sitemap discovery and
+// does not cancel other jobs for the domain. Page priorities are kept so the
+// busiest pages are warmed first.
+func (jm *JobManager) CreateKeepWarmJob(ctx context.Context, options *JobOptions, pages []db.Page) (*Job, error) {
+	span := sentry.StartSpan(ctx, "manager.create_keep_warm_job")
+	defer span.Finish()
+
+	span.SetTag("domain", options.Domain)
+
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages to keep warm")
+	}
+
+	normalisedDomain := util.NormaliseDomain(options.Domain)
+	sourceType := "keep_warm"
+	options.SourceType = &sourceType
+	options.FindLinks = false
+	job := createJobObject(options, normalisedDomain)
+
+	if _, err := jm.setupJobDatabase(ctx, job, normalisedDomain); err != nil {
+		span.SetTag("error", "true")
+		span.SetData("error.message", err.Error())
+		sentry.CaptureException(err)
+		return nil, err
+	}
+
+	if err := jm.EnqueueJobURLs(ctx, job.ID, pages, sourceType, ""); err != nil {
+		span.SetTag("error", "true")
+		span.SetData("error.message", err.Error())
+		jm.updateJobWithError(ctx, job.ID, fmt.Sprintf("Failed to enqueue keep-warm pages: %v", err))
+		return nil, fmt.Errorf("failed to enqueue keep-warm pages: %w", err)
+	}
+
+	if err := jm.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `SELECT recalculate_job_stats($1)`, job.ID)
+		return err
+	}); err != nil {
+		log.Error().
+			Err(err).
+			Str("job_id", job.ID).
+			Msg("Failed to recalculate job stats")
+	}
+
+	if jm.workerPool != nil {
+		jm.workerPool.NotifyNewTasks()
+	}
+
+	log.Info().
+		Str("job_id", job.ID).
+		Str("domain", job.Domain).
+		Int("pages", len(pages)).
+		Msg("Created keep-warm job")
+
+	return job, nil
+}
+
+// PauseJob pauses a running job. Workers stop claiming its tasks while
+// in-flight tasks finish and release their running slots as normal.
+func (jm *JobManager) PauseJob(ctx context.Context, jobID string) error {
+	span := sentry.StartSpan(ctx, "manager.pause_job")
+	defer span.Finish()
+
+	span.SetTag("job_id", jobID)
+
+	job, err := jm.GetJob(ctx, jobID)
+	if err != nil {
+		span.SetTag("error", "true")
+		span.SetData("error.message", err.Error())
+		sentry.CaptureException(err)
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+
+	if err := jm.ValidateStatusTransition(job.Status, JobStatusPaused); err != nil {
+		return fmt.Errorf("%w: job cannot be paused: %s", ErrInvalidJobTransition, job.Status)
+	}
+
+	var rowsAffected int64
+	err = jm.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
+		// Guard on current status so a job that completes or is cancelled
+		// concurrently is not flipped back into a paused state
+		result, err := tx.ExecContext(ctx, `
+			UPDATE jobs
+			SET status = $1
+			WHERE id = $2 AND status = $3
+		`, JobStatusPaused, job.ID, JobStatusRunning)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err = result.RowsAffected()
+		return err
+	})
+
+	if err != nil {
+		span.SetTag("error", "true")
+		span.SetData("error.message", err.Error())
+		sentry.CaptureException(err)
+		log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to pause job")
+		return fmt.Errorf("failed to pause job: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: job cannot be paused: status changed", ErrInvalidJobTransition)
+	}
+
+	// Stop claiming tasks for this job; in-flight tasks are left to finish
+	if jm.workerPool != nil {
+		jm.workerPool.RemoveJob(job.ID)
+	}
+
+	log.Info().
+		Str("job_id", job.ID).
+		Str("domain", job.Domain).
+		Msg("Paused job")
+
+	return nil
+}
+
+// ResumeJob resumes a paused job so its pending tasks are claimed again
+func (jm *JobManager) ResumeJob(ctx context.Context, jobID string) error {
+	span := sentry.StartSpan(ctx, "manager.resume_job")
+	defer span.Finish()
+
+	span.SetTag("job_id", jobID)
+
+	job, err := jm.GetJob(ctx, jobID)
+	if err != nil {
+		span.SetTag("error", "true")
+		span.SetData("error.message", err.Error())
+		sentry.CaptureException(err)
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+
+	if job.Status != JobStatusPaused {
+		return fmt.Errorf("%w: job cannot be resumed: %s", ErrInvalidJobTransition, job.Status)
+	}
+
+	var rowsAffected int64
+	err = jm.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
+		// resumed_at restarts the stuck-job timeout so time spent paused
+		// does not count as a lack of progress
+		result, err := tx.ExecContext(ctx, `
+			UPDATE jobs
+			SET status = $1, resumed_at = $2
+			WHERE id = $3 AND status = $4
+		`, JobStatusRunning, time.Now().UTC(), job.ID, JobStatusPaused)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err = result.RowsAffected()
+		return err
+	})
+
+	if err != nil {
+		span.SetTag("error", "true")
+		span.SetData("error.message", err.Error())
+		sentry.CaptureException(err)
+		log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to resume job")
+		return fmt.Errorf("failed to resume job: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: job cannot be resumed: status changed", ErrInvalidJobTransition)
+	}
+
+	if jm.workerPool != nil {
+		jm.workerPool.AddJob(job.ID, nil)
+		jm.workerPool.NotifyNewTasks()
+	}
+
+	log.Info().
+		Str("job_id", job.ID).
+		Str("domain", job.Domain).
+		Msg("Resumed job")
+
+	return nil
+}
+
 // GetJob retrieves a job by ID
 func (jm *JobManager) GetJob(ctx context.Context, jobID string) (*Job, error) {
 	span := sentry.StartSpan(ctx, "jobs.get_job")
@@ -602,7 +974,7 @@ func (jm *JobManager) GetJob(ctx context.Context, jobID string) (*Job, error) {
 	span.SetTag("job_id", jobID)
 
 	var job Job
-	var includePaths, excludePaths []byte
+	var includePaths, excludePaths, warmVariants []byte
 	var startedAt, completedAt sql.NullTime
 	var errorMessage, userID, organisationID sql.NullString
 
@@ -615,7 +987,8 @@ func (jm *JobManager) GetJob(ctx context.Context, jobID string) (*Job, error) {
 				j.created_at, j.started_at, j.completed_at, j.concurrency, j.find_links,
 				j.include_paths, j.exclude_paths, j.error_message, j.required_workers,
 				j.found_tasks, j.sitemap_tasks, j.duration_seconds, j.avg_time_per_task_seconds,
-				j.user_id, j.organisation_id
+				j.user_id, j.organisation_id, j.max_pages, j.allow_cross_subdomain_links, j.crawl_assets,
+				j.warm_variants, j.ignore_robots_directives, j.check_external_links, j.purpose
 			FROM jobs j
 			JOIN domains d ON j.domain_id = d.id
 			WHERE j.id = $1
@@ -624,7 +997,8 @@ func (jm *JobManager) GetJob(ctx context.Context, jobID string) (*Job, error) {
 			&job.FailedTasks, &job.SkippedTasks, &job.CreatedAt, &startedAt, &completedAt, &job.Concurrency,
 			&job.FindLinks, &includePaths, &excludePaths, &errorMessage, &job.RequiredWorkers,
 			&job.FoundTasks, &job.SitemapTasks, &job.DurationSeconds, &job.AvgTimePerTaskSeconds,
-			&userID, &organisationID,
+			&userID, &organisationID, &job.MaxPages, &job.AllowCrossSubdomainLinks, &job.CrawlAssets,
+			&warmVariants, &job.IgnoreRobotsDirectives, &job.CheckExternalLinks, &job.Purpose,
 		)
 		return err
 	})
@@ -673,6 +1047,14 @@ func (jm *JobManager) GetJob(ctx context.Context, jobID string) (*Job, error) {
 		}
 	}
 
+	if len(warmVariants) > 0 {
+		var matrix crawler.VariantMatrix
+		if err := json.Unmarshal(warmVariants, &matrix); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal warm variants: %w", err)
+		}
+		job.WarmVariants = &matrix
+	}
+
 	return &job, nil
 }
 
@@ -699,20 +1081,16 @@ func (jm *JobManager) GetJobStatus(ctx context.Context, jobID string) (*Job, err
 	return job, nil
 }
 
-// discoverAndParseSitemaps discovers and parses all sitemaps for a domain
-func (jm *JobManager) discoverAndParseSitemaps(ctx context.Context, domain string) ([]string, *crawler.RobotsRules, error) {
-	// Use the injected crawler if available, otherwise create a new one
-	var sitemapCrawler CrawlerInterface
-	if jm.crawler != nil {
-		sitemapCrawler = jm.crawler
-	} else {
-		// Create a crawler config that allows skipping already cached URLs
-		crawlerConfig := crawler.DefaultConfig()
-		crawlerConfig.SkipCachedURLs = false
-		sitemapCrawler = crawler.New(crawlerConfig)
-	}
+const (
+	// sitemapBatchSize is the number of sitemap URLs enqueued at a time, to
+	// avoid database timeouts on large sitemaps
+	sitemapBatchSize = 1000
+	// maxSitemapErrors caps the unreadable sitemaps recorded on a job
+	maxSitemapErrors = 100
+)
 
-	// Discover sitemaps and robots.txt rules for the domain
+// discoverSitemaps discovers the sitemaps and robots.txt rules for a domain
+func (jm *JobManager) discoverSitemaps(ctx context.Context, sitemapCrawler CrawlerInterface, domain string) ([]string, *crawler.RobotsRules, error) {
 	discoveryResult, err := sitemapCrawler.DiscoverSitemapsAndRobots(ctx, domain)
 	if err != nil {
 		log.Error().
@@ -722,40 +1100,122 @@ func (jm *JobManager) discoverAndParseSitemaps(ctx context.Context, domain strin
 		return []string{}, &crawler.RobotsRules{}, err
 	}
 
-	sitemaps := discoveryResult.Sitemaps
-	robotsRules := discoveryResult.RobotsRules
-
 	// Log discovered sitemaps
 	log.Info().
 		Str("domain", domain).
-		Int("sitemap_count", len(sitemaps)).
+		Int("sitemap_count", len(discoveryResult.Sitemaps)).
 		Msg("Sitemaps discovered")
 
-	// Process each sitemap to extract URLs
-	var urls []string
+	return discoveryResult.Sitemaps, discoveryResult.RobotsRules, nil
+}
+
+// streamSitemapURLs reads each sitemap in turn, handing its entries to flush
+// in batches of up to batchSize as they are parsed, so a large sitemap is
+// never held in memory. The URL limit applies across all the sitemaps.
+// Returns the number of URLs read and the sitemaps that could not be read in
+// full.
+func streamSitemapURLs(ctx context.Context, sitemapCrawler CrawlerInterface, sitemaps []string, limits crawler.SitemapLimits, batchSize int, flush func([]crawler.SitemapEntry)) (int, []crawler.SitemapError) {
+	var sitemapErrors []crawler.SitemapError
+	batch := make([]crawler.SitemapEntry, 0, batchSize)
+	total := 0
+
 	for _, sitemapURL := range sitemaps {
+		remaining := limits
+		remaining.MaxURLs = limits.MaxURLs - total
+		if remaining.MaxURLs <= 0 {
+			log.Warn().
+				Str("sitemap_url", sitemapURL).
+				Int("limit", limits.MaxURLs).
+				Msg("Sitemap URL limit reached, skipping sitemap")
+			break
+		}
+
 		log.Info().
 			Str("sitemap_url", sitemapURL).
 			Msg("Processing sitemap")
 
-		sitemapURLs, err := sitemapCrawler.ParseSitemap(ctx, sitemapURL)
+		stats, err := sitemapCrawler.StreamSitemap(ctx, sitemapURL, remaining, func(entry crawler.SitemapEntry) error {
+			batch = append(batch, entry)
+			if len(batch) >= batchSize {
+				flush(batch)
+				batch = make([]crawler.SitemapEntry, 0, batchSize)
+			}
+			return nil
+		})
 		if err != nil {
 			log.Warn().
 				Err(err).
 				Str("sitemap_url", sitemapURL).
 				Msg("Error parsing sitemap")
+			sitemapErrors = append(sitemapErrors, crawler.SitemapError{URL: sitemapURL, Error: err.Error()})
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		if stats == nil {
 			continue
 		}
 
 		log.Info().
 			Str("sitemap_url", sitemapURL).
-			Int("url_count", len(sitemapURLs)).
+			Int("url_count", stats.URLs).
+			Int("sitemaps_read", stats.Sitemaps).
+			Int("error_count", len(stats.Errors)).
+			Bool("truncated", stats.Truncated).
 			Msg("Parsed URLs from sitemap")
 
-		urls = append(urls, sitemapURLs...)
+		total += stats.URLs
+		sitemapErrors = append(sitemapErrors, stats.Errors...)
+	}
+
+	if len(batch) > 0 {
+		flush(batch)
+	}
+
+	return total, sitemapErrors
+}
+
+// recordSitemapErrors stores the sitemaps a job could not read, so users can
+// see why pages they expected were not discovered
+func (jm *JobManager) recordSitemapErrors(ctx context.Context, jobID string, sitemapErrors []crawler.SitemapError) {
+	if len(sitemapErrors) == 0 {
+		return
+	}
+	if len(sitemapErrors) > maxSitemapErrors {
+		sitemapErrors = sitemapErrors[:maxSitemapErrors]
+	}
+
+	errorsJSON, err := json.Marshal(sitemapErrors)
+	if err != nil {
+		log.Error().Err(err).Str("job_id", jobID).Msg("Failed to marshal sitemap errors")
+		return
 	}
 
-	return urls, robotsRules, nil
+	if updateErr := jm.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE jobs
+			SET sitemap_errors = $1
+			WHERE id = $2
+		`, errorsJSON, jobID)
+		return err
+	}); updateErr != nil {
+		log.Error().Err(updateErr).Str("job_id", jobID).Msg("Failed to record sitemap errors")
+	}
+}
+
+// setSitemapProcessing sets whether a job's sitemaps are still being read.
+// While set, the job is not completed however many of its tasks are done.
+func (jm *JobManager) setSitemapProcessing(ctx context.Context, jobID string, processing bool) {
+	if err := jm.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE jobs
+			SET sitemap_processing = $1
+			WHERE id = $2
+		`, processing, jobID)
+		return err
+	}); err != nil {
+		log.Error().Err(err).Str("job_id", jobID).Bool("sitemap_processing", processing).Msg("Failed to update sitemap processing flag")
+	}
 }
 
 // filterURLsAgainstRobots filters URLs against robots.txt rules and path patterns
@@ -796,13 +1256,8 @@ func (jm *JobManager) filterURLsAgainstRobots(urls []string, robotsRules *crawle
 	return filteredURLs
 }
 
-// enqueueURLsForJob creates page records and enqueues URLs for a job
-func (jm *JobManager) enqueueURLsForJob(ctx context.Context, jobID, domain string, urls []string, sourceType string) error {
-	if len(urls) == 0 {
-		return nil
-	}
-
-	// Get domain ID from the job
+// jobDomainID returns the ID of a job's domain
+func (jm *JobManager) jobDomainID(ctx context.Context, jobID string) (int, error) {
 	var domainID int
 	err := jm.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
 		return tx.QueryRowContext(ctx, `
@@ -810,7 +1265,22 @@ func (jm *JobManager) enqueueURLsForJob(ctx context.Context, jobID, domain strin
 		`, jobID).Scan(&domainID)
 	})
 	if err != nil {
-		return fmt.Errorf("failed to get domain ID: %w", err)
+		return 0, fmt.Errorf("failed to get domain ID: %w", err)
+	}
+	return domainID, nil
+}
+
+// enqueueURLsForJob creates page records and enqueues URLs for a job.
+// priorities optionally maps URLs to their starting priority; others start at
+// the default sitemap priority.
+func (jm *JobManager) enqueueURLsForJob(ctx context.Context, jobID, domain string, urls []string, priorities map[string]float64, sourceType string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	domainID, err := jm.jobDomainID(ctx, jobID)
+	if err != nil {
+		return err
 	}
 
 	// Create page records and get their IDs
@@ -819,6 +1289,15 @@ func (jm *JobManager) enqueueURLsForJob(ctx context.Context, jobID, domain strin
 		return fmt.Errorf("failed to create page records: %w", err)
 	}
 
+	// Page records are keyed by host and path, which may differ from the URL
+	pagePriorities := make(map[string]float64, len(priorities))
+	for pageURL, priority := range priorities {
+		if host, path, err := db.NormalisePageURL(pageURL, domain); err == nil {
+			key := host + "|" + path
+			pagePriorities[key] = max(pagePriorities[key], priority)
+		}
+	}
+
 	// Prepare pages with priorities
 	pagesWithPriority := make([]db.Page, len(pageIDs))
 	for i, pageID := range pageIDs {
@@ -826,7 +1305,10 @@ func (jm *JobManager) enqueueURLsForJob(ctx context.Context, jobID, domain strin
 			ID:       pageID,
 			Host:     hosts[i],
 			Path:     paths[i],
-			Priority: 0.1, // Default sitemap priority
+			Priority: defaultSitemapPriority,
+		}
+		if priority, ok := pagePriorities[hosts[i]+"|"+paths[i]]; ok {
+			pagesWithPriority[i].Priority = priority
 		}
 		// Set homepage priority to 1.000
 		if paths[i] == "/" {
@@ -923,7 +1405,8 @@ func (jm *JobManager) ValidateStatusTransition(from, to JobStatus) error {
 	// Normal forward transitions
 	validTransitions := map[JobStatus][]JobStatus{
 		JobStatusPending:   {JobStatusRunning, JobStatusCancelled},
-		JobStatusRunning:   {JobStatusCompleted, JobStatusFailed, JobStatusCancelled},
+		JobStatusRunning:   {JobStatusCompleted, JobStatusFailed, JobStatusCancelled, JobStatusPaused},
+		JobStatusPaused:    {JobStatusRunning, JobStatusCancelled},
 		JobStatusCompleted: {JobStatusRunning}, // Restart
 		JobStatusFailed:    {JobStatusRunning}, // Retry
 		JobStatusCancelled: {JobStatusRunning}, // Restart
@@ -978,6 +1461,21 @@ func (jm *JobManager) updateJobWithError(ctx context.Context, jobID, errorMessag
 	}
 }
 
+// cancelUnstartedJob cancels a job that has no tasks yet, recording why it
+// never started
+func (jm *JobManager) cancelUnstartedJob(ctx context.Context, jobID, reason string) {
+	if err := jm.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE jobs
+			SET status = $1, completed_at = $2, error_message = $3
+			WHERE id = $4
+		`, JobStatusCancelled, time.Now().UTC(), reason, jobID)
+		return err
+	}); err != nil {
+		log.Error().Err(err).Str("job_id", jobID).Msg("Failed to cancel unstarted job")
+	}
+}
+
 // enqueueFallbackURL creates and enqueues a fallback root URL when no sitemap URLs are found
 func (jm *JobManager) enqueueFallbackURL(ctx context.Context, jobID, domain string) error {
 	log.Info().
@@ -989,7 +1487,7 @@ func (jm *JobManager) enqueueFallbackURL(ctx context.Context, jobID, domain stri
 	rootURL := fmt.Sprintf("https://%s/", domain)
 	fallbackURLs := []string{rootURL}
 
-	if err := jm.enqueueURLsForJob(ctx, jobID, domain, fallbackURLs, "fallback"); err != nil {
+	if err := jm.enqueueURLsForJob(ctx, jobID, domain, fallbackURLs, nil, "fallback"); err != nil {
 		log.Error().
 			Err(err).
 			Str("job_id", jobID).
@@ -1009,8 +1507,9 @@ func (jm *JobManager) enqueueFallbackURL(ctx context.Context, jobID, domain stri
 	return nil
 }
 
-// enqueueSitemapURLs enqueues discovered sitemap URLs for processing
-func (jm *JobManager) enqueueSitemapURLs(ctx context.Context, jobID, domain string, urls []string) error {
+// enqueueSitemapURLs enqueues discovered sitemap URLs for processing, each
+// starting at the priority given for it
+func (jm *JobManager) enqueueSitemapURLs(ctx context.Context, jobID, domain string, urls []string, priorities map[string]float64) error {
 	// Log URLs for debugging
 	for i, url := range urls {
 		log.Debug().
@@ -1021,7 +1520,7 @@ func (jm *JobManager) enqueueSitemapURLs(ctx context.Context, jobID, domain stri
 			Msg("URL from sitemap")
 	}
 
-	if err := jm.enqueueURLsForJob(ctx, jobID, domain, urls, "sitemap"); err != nil {
+	if err := jm.enqueueURLsForJob(ctx, jobID, domain, urls, priorities, "sitemap"); err != nil {
 		log.Error().
 			Err(err).
 			Str("job_id", jobID).
@@ -1033,8 +1532,11 @@ func (jm *JobManager) enqueueSitemapURLs(ctx context.Context, jobID, domain stri
 	return nil
 }
 
-// processSitemap fetches and processes a sitemap for a domain
-func (jm *JobManager) processSitemap(ctx context.Context, jobID, domain string, includePaths, excludePaths []string) {
+// processSitemap fetches and processes a sitemap for a domain. Jobs that
+// track sitemap changes record each URL's lastmod against their baseline, and
+// delta jobs only enqueue the URLs that changed.
+func (jm *JobManager) processSitemap(ctx context.Context, job *Job, domain string) {
+	jobID := job.ID
 	// Guard against nil dependencies (e.g., in test environments)
 	if jm.crawler == nil || jm.dbQueue == nil || jm.db == nil {
 		log.Warn().
@@ -1050,13 +1552,18 @@ func (jm *JobManager) processSitemap(ctx context.Context, jobID, domain string,
 	span.SetTag("job_id", jobID)
 	span.SetTag("domain", domain)
 
+	// Workers can finish every task enqueued so far before the next batch
+	// arrives, so the job is held open until discovery is done
+	jm.setSitemapProcessing(ctx, jobID, true)
+	defer jm.setSitemapProcessing(context.WithoutCancel(ctx), jobID, false)
+
 	log.Info().
 		Str("job_id", jobID).
 		Str("domain", domain).
 		Msg("Starting sitemap processing")
 
-	// Step 1: Discover and parse sitemaps
-	urls, robotsRules, err := jm.discoverAndParseSitemaps(ctx, domain)
+	// Step 1: Discover sitemaps and robots.txt rules
+	sitemaps, robotsRules, err := jm.discoverSitemaps(ctx, jm.crawler, domain)
 	if err != nil {
 		span.SetTag("error", "true")
 		span.SetData("error.message", err.Error())
@@ -1073,48 +1580,86 @@ func (jm *JobManager) processSitemap(ctx context.Context, jobID, domain string,
 	// Step 2: Update domain crawl delay if present
 	jm.updateDomainCrawlDelay(ctx, domain, robotsRules.CrawlDelay)
 
-	// Step 3: Filter URLs against robots.txt and path patterns
-	urls = jm.filterURLsAgainstRobots(urls, robotsRules, includePaths, excludePaths)
-
-	// Step 4: Enqueue URLs in batches or create fallback
-	if len(urls) > 0 {
-		// Process URLs in batches to avoid database timeouts on large sitemaps
-		const batchSize = 1000
-		totalBatches := (len(urls) + batchSize - 1) / batchSize
-
-		log.Info().
-			Str("job_id", jobID).
-			Int("total_urls", len(urls)).
-			Int("batch_size", batchSize).
-			Int("total_batches", totalBatches).
-			Msg("Enqueueing sitemap URLs in batches")
+	// Step 3: Stream sitemap URLs, filtering each batch against robots.txt and
+	// path patterns and enqueueing it before the next is read, so large
+	// sitemaps never sit in memory and workers can start on the first batch
+	batchNum := 0
+	enqueued := 0
+	limits := crawler.DefaultSitemapLimits()
+	total, sitemapErrors := streamSitemapURLs(ctx, jm.crawler, sitemaps, limits, sitemapBatchSize, func(batch []crawler.SitemapEntry) {
+		batchNum++
+		now := time.Now()
+		locs := make([]string, len(batch))
+		priorities := make(map[string]float64, len(batch))
+		for i, entry := range batch {
+			locs[i] = entry.Loc
+			// Keep the highest when a URL is listed more than once
+			priorities[entry.Loc] = max(priorities[entry.Loc], jm.sitemapPriority.score(entry, now))
+		}
 
-		for i := 0; i < len(urls); i += batchSize {
-			end := min(i+batchSize, len(urls))
-			batch := urls[i:end]
-			batchNum := (i / batchSize) + 1
+		urls := jm.filterURLsAgainstRobots(locs, robotsRules, job.IncludePaths, job.ExcludePaths)
+		if len(urls) == 0 {
+			return
+		}
 
-			if err := jm.enqueueSitemapURLs(ctx, jobID, domain, batch); err != nil {
+		if job.TrackSitemapChanges {
+			// If the batch cannot be compared, warm all of it rather than
+			// risk leaving changed pages cold
+			if warm, err := jm.recordSitemapBatch(ctx, job, domain, urls, batch); err != nil {
 				log.Warn().
 					Err(err).
 					Str("job_id", jobID).
 					Int("batch_number", batchNum).
-					Int("batch_start", i).
-					Int("batch_size", len(batch)).
-					Msg("Failed to enqueue URL batch, continuing with next batch")
-				// Continue to next batch even if one fails
-				continue
+					Msg("Failed to record sitemap batch, warming all of its URLs")
+			} else {
+				urls = warm
+			}
+			if len(urls) == 0 {
+				return
 			}
+		}
+		enqueued += len(urls)
 
-			log.Info().
+		if err := jm.enqueueSitemapURLs(ctx, jobID, domain, urls, priorities); err != nil {
+			log.Warn().
+				Err(err).
 				Str("job_id", jobID).
 				Int("batch_number", batchNum).
-				Int("total_batches", totalBatches).
-				Int("urls_enqueued", end).
-				Int("total_urls", len(urls)).
-				Msg("Enqueued URL batch")
+				Int("batch_size", len(urls)).
+				Msg("Failed to enqueue URL batch, continuing with next batch")
+			// Continue to next batch even if one fails
+			return
 		}
-	} else {
+
+		log.Info().
+			Str("job_id", jobID).
+			Int("batch_number", batchNum).
+			Int("urls_enqueued", enqueued).
+			Msg("Enqueued URL batch")
+
+		if jm.workerPool != nil {
+			jm.workerPool.NotifyNewTasks()
+		}
+	})
+
+	log.Info().
+		Str("job_id", jobID).
+		Int("sitemap_urls", total).
+		Int("enqueued_urls", enqueued).
+		Int("sitemap_errors", len(sitemapErrors)).
+		Msg("Finished streaming sitemap URLs")
+
+	// Step 4: Record sitemaps that could not be read
+	jm.recordSitemapErrors(ctx, jobID, sitemapErrors)
+
+	// Step 5: Record baseline URLs that left the sitemap
+	if job.TrackSitemapChanges {
+		jm.finishSitemapSnapshot(ctx, job, len(sitemapErrors) == 0 && total < limits.MaxURLs)
+	}
+
+	// Step 6: Fall back to the root page when the sitemaps gave nothing to
+	// crawl, including delta jobs where nothing changed
+	if enqueued == 0 {
 		if err := jm.enqueueFallbackURL(ctx, jobID, domain); err != nil {
 			return
 		}
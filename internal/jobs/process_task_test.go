@@ -448,3 +448,22 @@ func TestHandleTaskSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestContentDiff(t *testing.T) {
+	diff, err := contentDiff("aaa", "bbb", "Pricing\nPlans from $10\nContact us", "Pricing\nPlans from $12\nContact us")
+	assert.NoError(t, err)
+	assert.Contains(t, diff, "--- aaa")
+	assert.Contains(t, diff, "+++ bbb")
+	assert.Contains(t, diff, "-Plans from $10\n")
+	assert.Contains(t, diff, "+Plans from $12\n")
+	assert.Contains(t, diff, " Contact us\n")
+
+	diff, err = contentDiff("aaa", "bbb", "Same text", "Same text")
+	assert.NoError(t, err)
+	assert.Empty(t, diff, "identical main content should produce no diff")
+}
+
+func TestContentStoragePaths(t *testing.T) {
+	assert.Equal(t, "pages/42/content/abc.txt", contentTextPath(42, "abc"))
+	assert.Equal(t, "pages/42/diffs/abc-def.diff", contentDiffPath(42, "abc", "def"))
+}
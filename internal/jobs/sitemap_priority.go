@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Harvey-AU/adapt/internal/crawler"
+)
+
+const (
+	// defaultSitemapPriority is the starting priority of a sitemap URL that
+	// declares neither lastmod nor priority
+	defaultSitemapPriority = 0.1
+	// maxSitemapPriority keeps sitemap hints below the homepage (1.0)
+	maxSitemapPriority = 0.9
+	// defaultSitemapLastModHalfLife is the page age at which lastmod counts
+	// for half as much as a page modified today
+	defaultSitemapLastModHalfLife = 30 * 24 * time.Hour
+)
+
+// sitemapPriorityWeights controls how a sitemap entry's priority and lastmod
+// set its task's starting priority. GA4 traffic scores are applied on top
+// when tasks are enqueued, keeping whichever is higher.
+type sitemapPriorityWeights struct {
+	priority float64       // Weight of the declared <priority>
+	lastMod  float64       // Weight of how recently <lastmod> says the page changed
+	halfLife time.Duration // Age at which a lastmod counts for half
+}
+
+func floatFromEnv(name string, fallback float64) float64 {
+	if raw := strings.TrimSpace(os.Getenv(name)); raw != "" {
+		if parsed, err := strconv.ParseFloat(raw, 64); err == nil && parsed >= 0 {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+func sitemapPriorityWeightsFromEnv() sitemapPriorityWeights {
+	weights := sitemapPriorityWeights{
+		priority: floatFromEnv("BBB_SITEMAP_PRIORITY_WEIGHT", 1),
+		lastMod:  floatFromEnv("BBB_SITEMAP_LASTMOD_WEIGHT", 1),
+		halfLife: defaultSitemapLastModHalfLife,
+	}
+	if raw := strings.TrimSpace(os.Getenv("BBB_SITEMAP_LASTMOD_HALF_LIFE_DAYS")); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+			weights.halfLife = time.Duration(parsed) * 24 * time.Hour
+		}
+	}
+	return weights
+}
+
+// score returns the starting priority for a sitemap entry: the weighted mean
+// of its declared priority and the recency of its lastmod, scaled between
+// the default and maximum sitemap priority. Entries with neither, or with
+// both weights set to zero, get the default.
+func (w sitemapPriorityWeights) score(entry crawler.SitemapEntry, now time.Time) float64 {
+	var weighted, total float64
+
+	if entry.Priority != nil && w.priority > 0 {
+		weighted += w.priority * *entry.Priority
+		total += w.priority
+	}
+	if entry.LastMod != nil && w.lastMod > 0 && w.halfLife > 0 {
+		recency := 1.0
+		if age := now.Sub(*entry.LastMod); age > 0 {
+			recency = math.Pow(0.5, float64(age)/float64(w.halfLife))
+		}
+		weighted += w.lastMod * recency
+		total += w.lastMod
+	}
+
+	if total == 0 {
+		return defaultSitemapPriority
+	}
+	score := defaultSitemapPriority + (maxSitemapPriority-defaultSitemapPriority)*weighted/total
+	return math.Round(score*1000) / 1000
+}
@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Harvey-AU/adapt/internal/crawler"
+	"github.com/rs/zerolog/log"
+)
+
+// soft404Template is what one host in a job serves for missing pages,
+// probed once per job
+type soft404Template struct {
+	once        sync.Once
+	fingerprint *crawler.PageFingerprint // Nil when the host returns real 404s or the homepage matches
+}
+
+// soft404TemplateFor returns the job's not-found template entry for an origin
+func (wp *WorkerPool) soft404TemplateFor(jobID, origin string) *soft404Template {
+	wp.soft404Mutex.Lock()
+	defer wp.soft404Mutex.Unlock()
+
+	if wp.soft404Templates == nil {
+		wp.soft404Templates = make(map[string]map[string]*soft404Template)
+	}
+	origins, ok := wp.soft404Templates[jobID]
+	if !ok {
+		origins = make(map[string]*soft404Template)
+		wp.soft404Templates[jobID] = origins
+	}
+	tmpl, ok := origins[origin]
+	if !ok {
+		tmpl = &soft404Template{}
+		origins[origin] = tmpl
+	}
+	return tmpl
+}
+
+// probeSoft404Template fetches a URL that cannot exist on the page's host,
+// paced by the domain limiter like any other request to the site
+func (wp *WorkerPool) probeSoft404Template(ctx context.Context, task *Task, pageURL string) *crawler.PageFingerprint {
+	permit, err := wp.ensureDomainLimiter().Acquire(ctx, DomainRequest{
+		Domain:         task.DomainName,
+		JobID:          task.JobID,
+		RobotsDelay:    time.Duration(task.CrawlDelay) * time.Second,
+		JobConcurrency: max(task.JobConcurrency, 1),
+	})
+	if err != nil {
+		log.Debug().Err(err).Str("job_id", task.JobID).Msg("Soft-404 probe cancelled")
+		return nil
+	}
+
+	fingerprint, err := wp.crawler.ProbeNotFound(ctx, pageURL)
+	permit.Release(err == nil, IsRateLimitError(err))
+	if err != nil {
+		log.Debug().Err(err).Str("job_id", task.JobID).Str("url", pageURL).Msg("Soft-404 probe failed")
+		return nil
+	}
+	return fingerprint
+}
+
+// detectSoft404 returns why a page served with a 200 looks like the site's
+// not-found page, or "" if it does not. The first page crawled on each host
+// triggers a probe of a URL that cannot exist to learn the host's template;
+// the probe also checks the homepage, so no page is matched against a
+// template every route serves.
+func (wp *WorkerPool) detectSoft404(ctx context.Context, task *Task, result *crawler.CrawlResult, pageURL string) string {
+	if result == nil || result.Fingerprint == nil || result.StatusCode != http.StatusOK || task.SourceType == assetSourceType {
+		return ""
+	}
+
+	parsed, err := url.Parse(pageURL)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+
+	tmpl := wp.soft404TemplateFor(task.JobID, parsed.Scheme+"://"+parsed.Host)
+	tmpl.once.Do(func() {
+		tmpl.fingerprint = wp.probeSoft404Template(ctx, task, pageURL)
+	})
+
+	return result.Fingerprint.Soft404Reason(tmpl.fingerprint)
+}
@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Harvey-AU/adapt/internal/crawler"
+	"github.com/Harvey-AU/adapt/internal/db"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultCertExpiryWarningDays is how close to expiry a crawled host's
+// certificate must be before the organisation is notified
+const defaultCertExpiryWarningDays = 14
+
+func certExpiryWarningDaysFromEnv() int {
+	if raw := strings.TrimSpace(os.Getenv("BBB_CERT_EXPIRY_WARNING_DAYS")); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 {
+			return parsed
+		}
+	}
+	return defaultCertExpiryWarningDays
+}
+
+// tlsHost returns the host whose certificate a crawl result carries; the
+// final URL after redirects, as that is the connection the TLS state is from
+func tlsHost(result *crawler.CrawlResult) string {
+	raw := result.URL
+	if result.RedirectURL != "" {
+		raw = result.RedirectURL
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// claimTLSHost marks a job's host as having its TLS state recorded, returning
+// false if another task already claimed it. A claim whose write fails is
+// released so a later task can record the host instead.
+func (wp *WorkerPool) claimTLSHost(jobID, host string) bool {
+	wp.tlsRecordedMutex.Lock()
+	defer wp.tlsRecordedMutex.Unlock()
+
+	if wp.tlsRecordedHosts == nil {
+		wp.tlsRecordedHosts = make(map[string]map[string]bool)
+	}
+	hosts, ok := wp.tlsRecordedHosts[jobID]
+	if !ok {
+		hosts = make(map[string]bool)
+		wp.tlsRecordedHosts[jobID] = hosts
+	}
+	if hosts[host] {
+		return false
+	}
+	hosts[host] = true
+	return true
+}
+
+// releaseTLSHost drops a claim made by claimTLSHost
+func (wp *WorkerPool) releaseTLSHost(jobID, host string) {
+	wp.tlsRecordedMutex.Lock()
+	defer wp.tlsRecordedMutex.Unlock()
+
+	delete(wp.tlsRecordedHosts[jobID], host)
+}
+
+// recordHostTLS stores the TLS state of the first HTTPS response per job and
+// host, and notifies the organisation if the certificate is close to expiry.
+func (wp *WorkerPool) recordHostTLS(ctx context.Context, jobID, host string, info *crawler.TLSInfo) {
+	leaf := info.Leaf()
+	if leaf == nil {
+		return
+	}
+
+	chain, err := json.Marshal(info.Certificates)
+	if err != nil {
+		log.Error().Err(err).Str("job_id", jobID).Str("host", host).Msg("Failed to marshal certificate chain")
+		return
+	}
+
+	hostTLS := &db.HostTLS{
+		JobID:       jobID,
+		Host:        host,
+		Version:     info.Version,
+		CipherSuite: info.CipherSuite,
+		Subject:     leaf.Subject,
+		Issuer:      leaf.Issuer,
+		DNSNames:    leaf.DNSNames,
+		NotAfter:    leaf.NotAfter,
+		Chain:       chain,
+		CapturedAt:  time.Now().UTC(),
+	}
+
+	stored, err := wp.dbQueue.RecordHostTLS(ctx, hostTLS)
+	if err != nil {
+		log.Error().Err(err).Str("job_id", jobID).Str("host", host).Msg("Failed to record host TLS")
+		wp.releaseTLSHost(jobID, host)
+		return
+	}
+	if !stored || wp.certExpiryWarningDays <= 0 {
+		return
+	}
+
+	daysLeft := leaf.ExpiresInDays(hostTLS.CapturedAt)
+	if daysLeft > wp.certExpiryWarningDays {
+		return
+	}
+
+	created, err := wp.dbQueue.CreateCertificateExpiryNotification(ctx, hostTLS, daysLeft)
+	if err != nil {
+		log.Error().Err(err).Str("job_id", jobID).Str("host", host).Msg("Failed to create certificate expiry notification")
+		return
+	}
+	if created {
+		log.Info().
+			Str("job_id", jobID).
+			Str("host", host).
+			Int("days_remaining", daysLeft).
+			Time("not_after", leaf.NotAfter).
+			Msg("Certificate expiry notification created")
+	}
+}
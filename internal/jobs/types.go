@@ -1,7 +1,11 @@
 package jobs
 
 import (
+	"context"
+	"errors"
 	"time"
+
+	"github.com/Harvey-AU/adapt/internal/crawler"
 )
 
 // JobStatus represents the current status of a job
@@ -29,6 +33,18 @@ const (
 	TaskStatusSkipped   TaskStatus = "skipped"
 )
 
+// How much of the site a job warms
+const (
+	CrawlModeFull  = "full"  // Every URL found
+	CrawlModeDelta = "delta" // Only sitemap URLs changed since the baseline job
+)
+
+// What a job's requests are for
+const (
+	JobPurposeWarm      = "warm"      // Full GETs that fill the edge cache
+	JobPurposeIntegrity = "integrity" // Conditional GETs that check pages are healthy and unchanged
+)
+
 // Maximum time a task can be "in progress" before being considered stale
 const (
 	TaskStaleTimeout = 3 * time.Minute
@@ -60,11 +76,27 @@ type Job struct {
 	ExcludePaths             []string  `json:"exclude_paths,omitempty"`
 	RequiredWorkers          int       `json:"required_workers"`
 	AllowCrossSubdomainLinks bool      `json:"allow_cross_subdomain_links"`
+	CrawlAssets              bool      `json:"crawl_assets"`
+	IgnoreRobotsDirectives   bool      `json:"ignore_robots_directives"`
+	CheckExternalLinks       bool      `json:"check_external_links"`
 	SourceType               *string   `json:"source_type,omitempty"`
 	SourceDetail             *string   `json:"source_detail,omitempty"`
 	SourceInfo               *string   `json:"source_info,omitempty"`
 	ErrorMessage             string    `json:"error_message,omitempty"`
 	SchedulerID              *string   `json:"scheduler_id,omitempty"`
+	ParentJobID              *string   `json:"parent_job_id,omitempty"`
+
+	// Cache variants warmed for each page, in addition to the default request
+	WarmVariants *crawler.VariantMatrix `json:"warm_variants,omitempty"`
+
+	// Sitemap change tracking against a previous run of the same scheduler
+	CrawlMode           string  `json:"crawl_mode"`
+	BaselineJobID       *string `json:"baseline_job_id,omitempty"`
+	TrackSitemapChanges bool    `json:"-"` // Record the sitemap so later runs can compare against it
+
+	// JobPurposeWarm or JobPurposeIntegrity
+	Purpose string `json:"purpose"`
+
 	// Calculated fields from database
 	DurationSeconds       *int     `json:"duration_seconds,omitempty"`
 	AvgTimePerTaskSeconds *float64 `json:"avg_time_per_task_seconds,omitempty"`
@@ -87,7 +119,7 @@ type Task struct {
 	Error       string     `json:"error,omitempty"`
 
 	// Source information
-	SourceType string `json:"source_type"`          // "sitemap", "link", "manual"
+	SourceType string `json:"source_type"`          // "sitemap", "link", "asset", "manual"
 	SourceURL  string `json:"source_url,omitempty"` // URL where this was discovered (for find_links)
 
 	// Result data
@@ -108,6 +140,19 @@ type Task struct {
 	AdaptiveDelay            int  `json:"-"`
 	AdaptiveDelayFloor       int  `json:"-"`
 	AllowCrossSubdomainLinks bool `json:"-"`
+	CrawlAssets              bool `json:"-"`
+	IgnoreRobotsDirectives   bool `json:"-"` // Follow links from nofollow/noindex pages
+	CheckExternalLinks       bool `json:"-"` // Validate links to other sites without crawling them
+	ConditionalRequests      bool `json:"-"` // Revalidate with the page's stored ETag and Last-Modified
+
+	// ETag and Last-Modified stored on the page, sent when revalidating it
+	PageValidators crawler.Validators `json:"-"`
+
+	// Expanded cache variant matrix from the job, if any
+	WarmVariants []crawler.WarmVariant `json:"-"`
+
+	// Organisation-defined CDN cache header mappings
+	CacheHeaderMappings []crawler.HeaderMapping `json:"-"`
 }
 
 // JobOptions defines configuration options for a crawl job
@@ -119,6 +164,9 @@ type JobOptions struct {
 	Concurrency              int      `json:"concurrency"`
 	FindLinks                bool     `json:"find_links"`
 	AllowCrossSubdomainLinks bool     `json:"allow_cross_subdomain_links"`
+	CrawlAssets              bool     `json:"crawl_assets"`
+	IgnoreRobotsDirectives   bool     `json:"ignore_robots_directives"`
+	CheckExternalLinks       bool     `json:"check_external_links"`
 	MaxPages                 int      `json:"max_pages"`
 	IncludePaths             []string `json:"include_paths,omitempty"`
 	ExcludePaths             []string `json:"exclude_paths,omitempty"`
@@ -127,8 +175,43 @@ type JobOptions struct {
 	SourceDetail             *string  `json:"source_detail,omitempty"`
 	SourceInfo               *string  `json:"source_info,omitempty"`
 	SchedulerID              *string  `json:"scheduler_id,omitempty"`
+	ParentJobID              *string  `json:"parent_job_id,omitempty"`
+
+	// WarmVariants optionally warms every device/encoding/language combination
+	WarmVariants *crawler.VariantMatrix `json:"warm_variants,omitempty"`
+
+	// CrawlMode is CrawlModeFull (the default) or CrawlModeDelta, which only
+	// warms sitemap URLs that are new or changed since BaselineJobID
+	CrawlMode     string  `json:"crawl_mode,omitempty"`
+	BaselineJobID *string `json:"baseline_job_id,omitempty"`
+	// TrackSitemapChanges records the sitemap's URLs and lastmod dates so
+	// later runs can be compared against this one
+	TrackSitemapChanges bool `json:"track_sitemap_changes,omitempty"`
+
+	// Purpose is JobPurposeWarm (the default) or JobPurposeIntegrity, which
+	// sends conditional requests and treats a 304 as an unchanged, healthy page
+	Purpose string `json:"purpose,omitempty"`
+
+	// BeforeStart runs once the job is recorded but before any URLs are
+	// discovered, such as to purge the CDN the job will warm. If it fails the
+	// job is cancelled and CreateJob returns its error.
+	BeforeStart func(ctx context.Context, job *Job) error `json:"-"`
 }
 
+// ErrJobNotFinished is returned when an action requires a finished job
+var ErrJobNotFinished = errors.New("job has not finished")
+
+// ErrNoFailedTasks is returned when retrying a job that has no failed tasks
+var ErrNoFailedTasks = errors.New("job has no failed tasks")
+
+// ErrInvalidJobOptions is returned when a job is created with options that
+// cannot be run, such as a cache variant matrix over the limit
+var ErrInvalidJobOptions = errors.New("invalid job options")
+
+// ErrInvalidJobTransition is returned when a job's current status does not
+// allow the requested action, such as pausing a completed job
+var ErrInvalidJobTransition = errors.New("invalid job status transition")
+
 // QuotaExceededError represents when an org has exceeded their daily quota
 type QuotaExceededError struct {
 	Used     int       `json:"used"`
@@ -1,6 +1,7 @@
 package jobs
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -46,6 +47,10 @@ const (
 	discoveredLinksMinRemain   = 8 * time.Second
 	discoveredLinksMinTimeout  = 5 * time.Second
 
+	// assetSourceType marks tasks for images, stylesheets, scripts and fonts
+	// referenced by crawled pages (opt-in via the job's crawl_assets option)
+	assetSourceType = "asset"
+
 	// concurrencyBufferFactor controls the headroom applied when converting
 	// job-level concurrency into worker capacity so we keep a small cushion
 	// without overshooting.
@@ -151,7 +156,23 @@ type WorkerPool struct {
 	techDetector        *techdetect.Detector
 	techDetectedDomains map[int]bool // Domains already detected in this session
 	techDetectedMutex   sync.RWMutex
-	storageClient       *storage.Client // For uploading HTML samples
+	storageBackend      storage.Backend // For HTML samples and content diffs; nil when not configured
+
+	// TLS certificate capture
+	tlsRecordedHosts      map[string]map[string]bool // Job ID -> hosts already recorded
+	tlsRecordedMutex      sync.Mutex
+	certExpiryWarningDays int // from BBB_CERT_EXPIRY_WARNING_DAYS (default 14, 0 = no notifications)
+
+	// External link checking
+	externalLinkWake     chan struct{}              // Signals idle checkers that links were stored
+	externalLinksSeen    map[string]map[string]bool // Job ID -> external links already stored
+	externalLinksMutex   sync.Mutex
+	externalLinkCacheTTL time.Duration // from BBB_EXTERNAL_LINK_CACHE_TTL_HOURS (default 24h, 0 = no reuse)
+	externalLinkDelay    time.Duration // from BBB_EXTERNAL_LINK_DELAY_SECONDS (default 2s)
+
+	// Soft-404 detection
+	soft404Templates map[string]map[string]*soft404Template // Job ID -> origin -> not-found template
+	soft404Mutex     sync.Mutex
 }
 
 func (wp *WorkerPool) ensureDomainLimiter() *DomainLimiter {
@@ -207,17 +228,25 @@ func (wp *WorkerPool) fetchJobInfoFromDB(ctx context.Context, jobID string) (*Jo
 		adaptiveFloor            sql.NullInt64
 		findLinks                bool
 		allowCrossSubdomainLinks bool
+		crawlAssets              bool
+		ignoreRobotsDirectives   bool
+		checkExternalLinks       bool
+		purpose                  string
+		warmVariants             []byte
+		cacheHeaderMappings      []byte
 		concurrency              int
 	)
 
 	err := wp.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
 		return tx.QueryRowContext(ctx, `
 			SELECT d.id, d.name, d.crawl_delay_seconds, d.adaptive_delay_seconds, d.adaptive_delay_floor_seconds,
-			       j.find_links, j.allow_cross_subdomain_links, j.concurrency
+			       j.find_links, j.allow_cross_subdomain_links, j.crawl_assets, j.warm_variants, j.concurrency,
+			       o.cache_header_mappings, j.ignore_robots_directives, j.check_external_links, j.purpose
 			FROM domains d
 			JOIN jobs j ON j.domain_id = d.id
+			LEFT JOIN organisations o ON o.id = j.organisation_id
 			WHERE j.id = $1
-		`, jobID).Scan(&domainID, &domainName, &crawlDelay, &adaptiveDelay, &adaptiveFloor, &findLinks, &allowCrossSubdomainLinks, &concurrency)
+		`, jobID).Scan(&domainID, &domainName, &crawlDelay, &adaptiveDelay, &adaptiveFloor, &findLinks, &allowCrossSubdomainLinks, &crawlAssets, &warmVariants, &concurrency, &cacheHeaderMappings, &ignoreRobotsDirectives, &checkExternalLinks, &purpose)
 	})
 	if err != nil {
 		return nil, err
@@ -228,8 +257,28 @@ func (wp *WorkerPool) fetchJobInfoFromDB(ctx context.Context, jobID string) (*Jo
 		DomainName:               domainName,
 		FindLinks:                findLinks,
 		AllowCrossSubdomainLinks: allowCrossSubdomainLinks,
+		CrawlAssets:              crawlAssets,
+		IgnoreRobotsDirectives:   ignoreRobotsDirectives,
+		CheckExternalLinks:       checkExternalLinks,
+		ConditionalRequests:      purpose == JobPurposeIntegrity,
 		Concurrency:              concurrency,
 	}
+	if len(warmVariants) > 0 {
+		var matrix crawler.VariantMatrix
+		if err := json.Unmarshal(warmVariants, &matrix); err != nil {
+			log.Warn().Err(err).Str("job_id", jobID).Msg("Ignoring invalid warm_variants on job")
+		} else if err := matrix.Validate(); err != nil {
+			log.Warn().Err(err).Str("job_id", jobID).Msg("Ignoring warm_variants outside the allowed matrix")
+		} else {
+			info.WarmVariants = matrix.Expand()
+		}
+	}
+	if len(cacheHeaderMappings) > 0 {
+		if err := json.Unmarshal(cacheHeaderMappings, &info.CacheHeaderMappings); err != nil {
+			log.Warn().Err(err).Str("job_id", jobID).Msg("Ignoring invalid organisation cache header mappings")
+			info.CacheHeaderMappings = nil
+		}
+	}
 	if crawlDelay.Valid {
 		info.CrawlDelay = int(crawlDelay.Int64)
 	}
@@ -263,6 +312,12 @@ func (wp *WorkerPool) loadJobInfo(ctx context.Context, jobID string, options *Jo
 		if options != nil {
 			info.FindLinks = options.FindLinks
 			info.AllowCrossSubdomainLinks = options.AllowCrossSubdomainLinks
+			info.CrawlAssets = options.CrawlAssets
+			info.IgnoreRobotsDirectives = options.IgnoreRobotsDirectives
+			info.CheckExternalLinks = options.CheckExternalLinks
+			if options.WarmVariants != nil {
+				info.WarmVariants = options.WarmVariants.Expand()
+			}
 			if options.Concurrency > 0 {
 				info.Concurrency = options.Concurrency
 			}
@@ -351,6 +406,12 @@ type JobInfo struct {
 	DomainName               string
 	FindLinks                bool
 	AllowCrossSubdomainLinks bool
+	CrawlAssets              bool
+	IgnoreRobotsDirectives   bool
+	CheckExternalLinks       bool
+	ConditionalRequests      bool                    // Integrity-check job: revalidate instead of full GETs
+	WarmVariants             []crawler.WarmVariant   // Expanded cache variant matrix
+	CacheHeaderMappings      []crawler.HeaderMapping // Organisation's custom CDN header mappings
 	CrawlDelay               int
 	Concurrency              int
 	AdaptiveDelay            int
@@ -526,6 +587,19 @@ func NewWorkerPool(sqlDB *sql.DB, dbQueue DbQueueInterface, crawler CrawlerInter
 
 		// Technology detection (initialised lazily to avoid startup errors)
 		techDetectedDomains: make(map[int]bool),
+
+		// TLS certificate capture
+		tlsRecordedHosts:      make(map[string]map[string]bool),
+		certExpiryWarningDays: certExpiryWarningDaysFromEnv(),
+
+		// External link checking
+		externalLinkWake:     make(chan struct{}, 1),
+		externalLinksSeen:    make(map[string]map[string]bool),
+		externalLinkCacheTTL: externalLinkCacheTTLFromEnv(),
+		externalLinkDelay:    externalLinkDelayFromEnv(),
+
+		// Soft-404 detection
+		soft404Templates: make(map[string]map[string]*soft404Template),
 	}
 
 	// Initialise technology detector (non-fatal if it fails)
@@ -536,17 +610,6 @@ func NewWorkerPool(sqlDB *sql.DB, dbQueue DbQueueInterface, crawler CrawlerInter
 		log.Info().Msg("Technology detector initialised")
 	}
 
-	// Initialise storage client for HTML uploads (non-fatal if not configured)
-	// Uses existing SUPABASE_URL from project config
-	supabaseURL := strings.TrimSuffix(os.Getenv("SUPABASE_URL"), "/")
-	supabaseServiceKey := os.Getenv("SUPABASE_SERVICE_ROLE_KEY")
-	if supabaseURL != "" && supabaseServiceKey != "" {
-		wp.storageClient = storage.New(supabaseURL, supabaseServiceKey)
-		log.Info().Msg("Storage client initialised for page crawl uploads")
-	} else {
-		log.Debug().Msg("Storage client not configured - page HTML will not be stored (set SUPABASE_SERVICE_ROLE_KEY)")
-	}
-
 	// Start the notification listener when we have connection details available.
 	if hasNotificationConfig(dbConfig) {
 		wp.wg.Go(func() {
@@ -601,6 +664,7 @@ func (wp *WorkerPool) Start(ctx context.Context) {
 	wp.StartCleanupMonitor(ctx)
 	wp.StartQuotaPromotionMonitor(ctx)
 	wp.startRunningTaskReleaseLoop(ctx)
+	wp.startExternalLinkCheckers(ctx)
 
 	// Start orphaned task cleanup loop
 	wp.wg.Go(func() {
@@ -647,7 +711,7 @@ func (wp *WorkerPool) reconcileRunningTaskCounters(ctx context.Context) error {
 			SET running_tasks = COALESCE(ac.actual_running, 0)
 			FROM actual_counts ac
 			WHERE jobs.id = ac.job_id
-			  AND jobs.status IN ('running', 'pending')
+			  AND jobs.status IN ('running', 'pending', 'paused')
 			  AND jobs.running_tasks != COALESCE(ac.actual_running, 0)
 			RETURNING
 				jobs.id,
@@ -657,7 +721,7 @@ func (wp *WorkerPool) reconcileRunningTaskCounters(ctx context.Context) error {
 		zero_out_jobs AS (
 			UPDATE jobs
 			SET running_tasks = 0
-			WHERE status IN ('running', 'pending')
+			WHERE status IN ('running', 'pending', 'paused')
 			  AND running_tasks > 0
 			  AND id NOT IN (SELECT job_id FROM actual_counts)
 			RETURNING
@@ -1062,6 +1126,18 @@ func (wp *WorkerPool) RemoveJob(jobID string) {
 	delete(wp.jobFailureCounters, jobID)
 	wp.jobFailureMutex.Unlock()
 
+	wp.tlsRecordedMutex.Lock()
+	delete(wp.tlsRecordedHosts, jobID)
+	wp.tlsRecordedMutex.Unlock()
+
+	wp.externalLinksMutex.Lock()
+	delete(wp.externalLinksSeen, jobID)
+	wp.externalLinksMutex.Unlock()
+
+	wp.soft404Mutex.Lock()
+	delete(wp.soft404Templates, jobID)
+	wp.soft404Mutex.Unlock()
+
 	// Simple scaling: remove 5 workers per job + any performance boost, minimum of base count
 	wp.workersMutex.Lock()
 	oldWorkers := wp.currentWorkers
@@ -1481,6 +1557,10 @@ func (wp *WorkerPool) prepareTaskForProcessing(ctx context.Context, task *db.Tas
 		SourceType:    task.SourceType,
 		SourceURL:     task.SourceURL,
 		PriorityScore: task.PriorityScore,
+		PageValidators: crawler.Validators{
+			ETag:         task.PageETag,
+			LastModified: task.PageLastModified,
+		},
 	}
 
 	// Get job info from cache
@@ -1493,6 +1573,12 @@ func (wp *WorkerPool) prepareTaskForProcessing(ctx context.Context, task *db.Tas
 		jobsTask.DomainName = jobInfo.DomainName
 		jobsTask.FindLinks = jobInfo.FindLinks
 		jobsTask.AllowCrossSubdomainLinks = jobInfo.AllowCrossSubdomainLinks
+		jobsTask.CrawlAssets = jobInfo.CrawlAssets
+		jobsTask.IgnoreRobotsDirectives = jobInfo.IgnoreRobotsDirectives
+		jobsTask.CheckExternalLinks = jobInfo.CheckExternalLinks
+		jobsTask.ConditionalRequests = jobInfo.ConditionalRequests
+		jobsTask.WarmVariants = jobInfo.WarmVariants
+		jobsTask.CacheHeaderMappings = jobInfo.CacheHeaderMappings
 		jobsTask.CrawlDelay = jobInfo.CrawlDelay
 		jobsTask.JobConcurrency = jobInfo.Concurrency
 		jobsTask.AdaptiveDelay = jobInfo.AdaptiveDelay
@@ -1509,6 +1595,12 @@ func (wp *WorkerPool) prepareTaskForProcessing(ctx context.Context, task *db.Tas
 			jobsTask.DomainName = info.DomainName
 			jobsTask.FindLinks = info.FindLinks
 			jobsTask.AllowCrossSubdomainLinks = info.AllowCrossSubdomainLinks
+			jobsTask.CrawlAssets = info.CrawlAssets
+			jobsTask.IgnoreRobotsDirectives = info.IgnoreRobotsDirectives
+			jobsTask.CheckExternalLinks = info.CheckExternalLinks
+			jobsTask.ConditionalRequests = info.ConditionalRequests
+			jobsTask.WarmVariants = info.WarmVariants
+			jobsTask.CacheHeaderMappings = info.CacheHeaderMappings
 			jobsTask.CrawlDelay = info.CrawlDelay
 			jobsTask.JobConcurrency = info.Concurrency
 			jobsTask.AdaptiveDelay = info.AdaptiveDelay
@@ -1562,6 +1654,11 @@ func (wp *WorkerPool) processNextTask(ctx context.Context) (err error) {
 
 		result, err := wp.processTask(taskCtx, jobsTask)
 		if err != nil {
+			// Keep the redirect chain for failures too; loops surface as errors
+			applyRedirectChain(task, result)
+			if result != nil {
+				task.Soft404 = result.Soft404
+			}
 			return wp.handleTaskError(ctx, task, err)
 		} else {
 			return wp.handleTaskSuccess(ctx, task, result)
@@ -1707,12 +1804,15 @@ func (wp *WorkerPool) checkForPendingTasks(ctx context.Context) error {
 			// Get job options
 			var findLinks bool
 			var allowCrossSubdomainLinks bool
+			var crawlAssets bool
+			var ignoreRobotsDirectives bool
+			var checkExternalLinks bool
 			err := wp.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
 				return tx.QueryRowContext(ctx, `
-					SELECT find_links, allow_cross_subdomain_links
+					SELECT find_links, allow_cross_subdomain_links, crawl_assets, ignore_robots_directives, check_external_links
 					FROM jobs
 					WHERE id = $1
-				`, jobID).Scan(&findLinks, &allowCrossSubdomainLinks)
+				`, jobID).Scan(&findLinks, &allowCrossSubdomainLinks, &crawlAssets, &ignoreRobotsDirectives, &checkExternalLinks)
 			})
 
 			if err != nil {
@@ -1723,6 +1823,9 @@ func (wp *WorkerPool) checkForPendingTasks(ctx context.Context) error {
 			options := &JobOptions{
 				FindLinks:                findLinks,
 				AllowCrossSubdomainLinks: allowCrossSubdomainLinks,
+				CrawlAssets:              crawlAssets,
+				IgnoreRobotsDirectives:   ignoreRobotsDirectives,
+				CheckExternalLinks:       checkExternalLinks,
 			}
 
 			wp.AddJob(jobID, options)
@@ -1784,15 +1887,16 @@ func (wp *WorkerPool) checkForPendingTasks(ctx context.Context) error {
 }
 
 type jobQueueState struct {
-	Status      string
-	Pending     int
-	Waiting     int
-	Running     int
-	Total       int
-	Completed   int
-	Failed      int
-	Skipped     int
-	Concurrency sql.NullInt64
+	Status            string
+	Pending           int
+	Waiting           int
+	Running           int
+	Total             int
+	Completed         int
+	Failed            int
+	Skipped           int
+	Concurrency       sql.NullInt64
+	SitemapProcessing bool
 }
 
 func (s jobQueueState) remainingWork() int {
@@ -1828,6 +1932,14 @@ func (wp *WorkerPool) ensureJobSafeToRemove(ctx context.Context, jobID string) (
 	switch JobStatus(state.Status) {
 	case JobStatusCompleted, JobStatusCancelled, JobStatusFailed:
 		return true, nil
+	case JobStatusPaused:
+		// Paused jobs keep their remaining work until resumed
+		return true, nil
+	}
+
+	// More sitemap URLs may still be enqueued
+	if state.SitemapProcessing {
+		return false, nil
 	}
 
 	if state.remainingWork() == 0 && state.Pending == 0 && state.Waiting == 0 && state.Running == 0 {
@@ -1885,7 +1997,8 @@ func (wp *WorkerPool) loadJobQueueState(ctx context.Context, jobID string) (*job
 	err := wp.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
 		return tx.QueryRowContext(ctx, `
 			SELECT status, pending_tasks, waiting_tasks, running_tasks,
-			       total_tasks, completed_tasks, failed_tasks, skipped_tasks, concurrency
+			       total_tasks, completed_tasks, failed_tasks, skipped_tasks, concurrency,
+			       sitemap_processing
 			FROM jobs
 			WHERE id = $1
 		`, jobID).Scan(
@@ -1898,6 +2011,7 @@ func (wp *WorkerPool) loadJobQueueState(ctx context.Context, jobID string) (*job
 			&state.Failed,
 			&state.Skipped,
 			&state.Concurrency,
+			&state.SitemapProcessing,
 		)
 	})
 	if err != nil {
@@ -1912,15 +2026,17 @@ func (wp *WorkerPool) markJobCompleted(ctx context.Context, jobID string) error
 	// created regardless of which code path completes the job.
 	return wp.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
 		// Only update if job is not already in a terminal state (cancelled, failed)
-		// This prevents race conditions where a job was cancelled but running tasks complete
+		// or paused, or still reading its sitemaps. This prevents race conditions
+		// where a job was cancelled or paused but running tasks complete
 		_, err := tx.ExecContext(ctx, `
 			UPDATE jobs
 			SET status = $1,
 				completed_at = COALESCE(completed_at, $2),
 				progress = 100.0
 			WHERE id = $3
-			  AND status NOT IN ($4, $5)
-		`, JobStatusCompleted, time.Now().UTC(), jobID, JobStatusCancelled, JobStatusFailed)
+			  AND status NOT IN ($4, $5, $6)
+			  AND NOT sitemap_processing
+		`, JobStatusCompleted, time.Now().UTC(), jobID, JobStatusCancelled, JobStatusFailed, JobStatusPaused)
 		return err
 	})
 }
@@ -1992,6 +2108,12 @@ func (wp *WorkerPool) SetJobManager(jm *JobManager) {
 	wp.jobManager = jm
 }
 
+// SetStorage sets where crawl artefacts are uploaded. Without a backend,
+// page HTML and content diffs are not stored.
+func (wp *WorkerPool) SetStorage(backend storage.Backend) {
+	wp.storageBackend = backend
+}
+
 // recoverStaleTasks checks for and resets stale tasks in batches
 // Processes oldest tasks first, handles cancelled/failed jobs separately
 func (wp *WorkerPool) recoverStaleTasks(ctx context.Context) error {
@@ -2234,21 +2356,25 @@ func (wp *WorkerPool) recoverStaleBatch(ctx context.Context, staleTime time.Time
 	return recovered, failed, err
 }
 
-// recoverRunningJobs finds jobs that were in 'running' state when the server shut down
-// and resets their 'running' tasks to 'pending', then adds them to the worker pool
+// recoverRunningJobs finds jobs that were in 'running' or 'paused' state when the
+// server shut down and resets their 'running' tasks to 'pending', then adds the
+// running jobs back to the worker pool
 func (wp *WorkerPool) recoverRunningJobs(ctx context.Context) error {
 	log.Info().Msg("Recovering jobs that were running before restart")
 
 	var jobIDs []string
+	jobStatuses := make(map[string]JobStatus)
 	err := wp.dbQueue.Execute(ctx, func(tx *sql.Tx) error {
-		// Find jobs with 'running' status that have 'running' tasks
+		// Find running or paused jobs that have 'running' tasks. Paused jobs
+		// are included so their orphaned tasks stop holding concurrency slots
+		// until the job is resumed.
 		rows, err := tx.QueryContext(ctx, `
-			SELECT DISTINCT j.id
+			SELECT DISTINCT j.id, j.status
 			FROM jobs j
 			JOIN tasks t ON j.id = t.job_id
-			WHERE j.status = $1
-			AND t.status = $2
-		`, JobStatusRunning, TaskStatusRunning)
+			WHERE j.status IN ($1, $2)
+			AND t.status = $3
+		`, JobStatusRunning, JobStatusPaused, TaskStatusRunning)
 
 		if err != nil {
 			return err
@@ -2256,11 +2382,12 @@ func (wp *WorkerPool) recoverRunningJobs(ctx context.Context) error {
 		defer rows.Close()
 
 		for rows.Next() {
-			var jobID string
-			if err := rows.Scan(&jobID); err != nil {
+			var jobID, status string
+			if err := rows.Scan(&jobID, &status); err != nil {
 				return err
 			}
 			jobIDs = append(jobIDs, jobID)
+			jobStatuses[jobID] = JobStatus(status)
 		}
 
 		return rows.Err()
@@ -2272,6 +2399,7 @@ func (wp *WorkerPool) recoverRunningJobs(ctx context.Context) error {
 	}
 
 	var recoveredJobs []string
+	pausedJobs := 0
 	for _, jobID := range jobIDs {
 
 		// Reset running tasks to pending for this job
@@ -2303,6 +2431,12 @@ func (wp *WorkerPool) recoverRunningJobs(ctx context.Context) error {
 			continue
 		}
 
+		// Paused jobs are added back to the worker pool on resume
+		if jobStatuses[jobID] == JobStatusPaused {
+			pausedJobs++
+			continue
+		}
+
 		// Add job back to worker pool
 		wp.AddJob(jobID, nil)
 		recoveredJobs = append(recoveredJobs, jobID)
@@ -2310,9 +2444,10 @@ func (wp *WorkerPool) recoverRunningJobs(ctx context.Context) error {
 		log.Info().Str("job_id", jobID).Msg("Recovered running job and added to worker pool")
 	}
 
-	if len(recoveredJobs) > 0 {
+	if len(recoveredJobs) > 0 || pausedJobs > 0 {
 		log.Info().
 			Int("count", len(recoveredJobs)).
+			Int("paused", pausedJobs).
 			Strs("job_ids", recoveredJobs).
 			Msg("Successfully recovered running jobs from restart")
 
@@ -2891,6 +3026,7 @@ func (wp *WorkerPool) CleanupStuckJobs(ctx context.Context) error {
 		// - Pending jobs with 0 tasks for 5 minutes (sitemap processing likely failed)
 		// - Running jobs with no task progress for 30 minutes (excluding jobs with waiting tasks)
 		// - Jobs running for all tasks failed
+		// Paused jobs are never touched; progress is measured from resumed_at once resumed.
 		result, err = tx.ExecContext(ctx, `
 			UPDATE jobs
 			SET status = $1,
@@ -2916,11 +3052,11 @@ func (wp *WorkerPool) CleanupStuckJobs(ctx context.Context) error {
 						AND organisation_id IS NOT NULL
 						AND get_daily_quota_remaining(organisation_id) <= 0
 					)
-					AND COALESCE((
+					AND GREATEST(COALESCE((
 						SELECT MAX(GREATEST(started_at, completed_at))
 						FROM tasks
 						WHERE job_id = jobs.id
-					), created_at) < $6)
+					), created_at), resumed_at) < $6)
 			)
 		`, JobStatusFailed, time.Now().UTC(), JobStatusPending, time.Now().UTC().Add(-5*time.Minute), JobStatusRunning, time.Now().UTC().Add(-30*time.Minute))
 
@@ -3285,6 +3421,8 @@ func (wp *WorkerPool) processDiscoveredLinks(ctx context.Context, task *Task, re
 		Str("task_id", task.ID).
 		Int("total_links_found", len(result.Links["header"])+len(result.Links["footer"])+len(result.Links["body"])).
 		Bool("find_links_enabled", task.FindLinks).
+		Int("assets_found", len(result.Assets)).
+		Bool("crawl_assets_enabled", task.CrawlAssets).
 		Msg("Starting link processing and priority assignment")
 
 	// Use domain ID from task (already populated from job cache)
@@ -3307,7 +3445,7 @@ func (wp *WorkerPool) processDiscoveredLinks(ctx context.Context, task *Task, re
 
 	isHomepage := task.Path == "/"
 
-	processLinkCategory := func(links []string, priority float64) {
+	processLinkCategory := func(links []string, priority float64, sourceType string, allowed func(host string) bool) {
 		if len(links) == 0 {
 			return
 		}
@@ -3340,7 +3478,7 @@ func (wp *WorkerPool) processDiscoveredLinks(ctx context.Context, task *Task, re
 				linkURL = baseURL.ResolveReference(linkURL)
 			}
 
-			if isLinkAllowedForTask(linkURL.Hostname(), task) {
+			if allowed(linkURL.Hostname()) {
 				linkURL.Fragment = ""
 				if linkURL.Path != "/" && strings.HasSuffix(linkURL.Path, "/") {
 					linkURL.Path = strings.TrimSuffix(linkURL.Path, "/")
@@ -3423,7 +3561,7 @@ func (wp *WorkerPool) processDiscoveredLinks(ctx context.Context, task *Task, re
 		}
 
 		// 4. Enqueue new tasks
-		if err := wp.EnqueueURLs(linkCtx, task.JobID, pagesToEnqueue, "link", sourceURL); err != nil {
+		if err := wp.EnqueueURLs(linkCtx, task.JobID, pagesToEnqueue, sourceType, sourceURL); err != nil {
 			log.Error().Err(err).Msg("Failed to enqueue discovered links")
 			return // Stop if enqueuing fails
 		}
@@ -3434,19 +3572,71 @@ func (wp *WorkerPool) processDiscoveredLinks(ctx context.Context, task *Task, re
 		}
 	}
 
+	linkAllowed := func(host string) bool { return isLinkAllowedForTask(host, task) }
+	links := followableLinks(result, task.IgnoreRobotsDirectives)
+	if len(links) == 0 && len(result.Links) > 0 {
+		log.Debug().
+			Str("task_id", task.ID).
+			Msg("Page is nofollow; not following its links")
+	}
+
 	// Apply priorities based on page type and link category
-	if isHomepage {
-		log.Debug().Str("task_id", task.ID).Msg("Processing links from HOMEPAGE")
-		processLinkCategory(result.Links["header"], 1.000)
-		processLinkCategory(result.Links["footer"], 0.990)
-		processLinkCategory(result.Links["body"], task.PriorityScore*0.9) // Children of homepage
-	} else {
-		log.Debug().Str("task_id", task.ID).Msg("Processing links from regular page")
-		// For all other pages, only process body links
-		processLinkCategory(result.Links["body"], task.PriorityScore*0.9) // Children of other pages
+	if task.FindLinks {
+		if isHomepage {
+			log.Debug().Str("task_id", task.ID).Msg("Processing links from HOMEPAGE")
+			processLinkCategory(links["header"], 1.000, "link", linkAllowed)
+			processLinkCategory(links["footer"], 0.990, "link", linkAllowed)
+			processLinkCategory(links["body"], task.PriorityScore*0.9, "link", linkAllowed) // Children of homepage
+		} else {
+			log.Debug().Str("task_id", task.ID).Msg("Processing links from regular page")
+			// For all other pages, only process body links
+			processLinkCategory(links["body"], task.PriorityScore*0.9, "link", linkAllowed) // Children of other pages
+		}
+	}
+
+	// Assets rank below the pages that reference them so pages are warmed first
+	if task.CrawlAssets {
+		processLinkCategory(result.Assets, task.PriorityScore*0.5, assetSourceType, func(host string) bool {
+			return isAssetAllowedForTask(host, task)
+		})
 	}
 }
 
+// followableLinks returns the page's links that discovery should follow.
+// Pages marked nofollow (meta robots or X-Robots-Tag) contribute no links,
+// and rel="nofollow" anchors are dropped, unless the job ignores robots
+// directives. noindex alone only keeps the page itself out of the index, so
+// its links are still followed.
+func followableLinks(result *crawler.CrawlResult, ignoreRobotsDirectives bool) map[string][]string {
+	if ignoreRobotsDirectives || result.SEO == nil {
+		return result.Links
+	}
+	if result.SEO.NoFollow {
+		return nil
+	}
+	if len(result.SEO.NofollowLinks) == 0 {
+		return result.Links
+	}
+
+	links := make(map[string][]string, len(result.Links))
+	for category, categoryLinks := range result.Links {
+		// Count occurrences so a URL that is also linked without nofollow
+		// elsewhere in the category is still followed
+		nofollow := make(map[string]int)
+		for _, link := range result.SEO.NofollowLinks[category] {
+			nofollow[link]++
+		}
+		for _, link := range categoryLinks {
+			if nofollow[link] > 0 {
+				nofollow[link]--
+				continue
+			}
+			links[category] = append(links[category], link)
+		}
+	}
+	return links
+}
+
 // handleTaskError processes task failures with appropriate retry logic and status updates
 func (wp *WorkerPool) handleTaskError(ctx context.Context, task *db.Task, taskErr error) error {
 	now := time.Now().UTC()
@@ -3535,6 +3725,55 @@ func (wp *WorkerPool) handleTaskError(ctx context.Context, task *db.Task, taskEr
 	return nil
 }
 
+// applyRedirectChain copies the crawler's redirect hops and loop/limit flags onto the task
+func applyRedirectChain(task *db.Task, result *crawler.CrawlResult) {
+	if result == nil {
+		return
+	}
+
+	task.RedirectLoop = result.RedirectLoop
+	task.RedirectLimitHit = result.RedirectLimitHit
+	task.RedirectChain = nil
+	if len(result.RedirectChain) == 0 {
+		return
+	}
+
+	chain, err := json.Marshal(result.RedirectChain)
+	if err != nil {
+		log.Warn().Err(err).Str("task_id", task.ID).Msg("Failed to marshal redirect chain")
+		return
+	}
+	task.RedirectChain = chain
+}
+
+// withPageValidators returns a context that makes the crawler revalidate the
+// task's page with the ETag and Last-Modified it was last served with, loaded
+// when the task was claimed. Pages without stored validators get a full GET.
+func withPageValidators(ctx context.Context, task *Task) context.Context {
+	if task.PageValidators.IsZero() {
+		return ctx
+	}
+	return crawler.WithConditionalRequest(ctx, task.PageValidators)
+}
+
+// cacheExpiresAt estimates when the warmed object leaves the edge cache, from
+// the remaining TTL the CDN reported. It is zero when there is no TTL or the
+// CDN did not cache the response, since re-warming cannot help those pages.
+func cacheExpiresAt(now time.Time, result *crawler.CrawlResult) time.Time {
+	if result.CacheTTL == nil || *result.CacheTTL <= 0 {
+		return time.Time{}
+	}
+	status := result.CacheStatus
+	if result.SecondCacheStatus != "" {
+		status = result.SecondCacheStatus
+	}
+	switch strings.ToUpper(status) {
+	case "BYPASS", "DYNAMIC", "PASS":
+		return time.Time{}
+	}
+	return now.Add(time.Duration(*result.CacheTTL) * time.Second)
+}
+
 // handleTaskSuccess processes successful task completion with metrics and database updates
 func (wp *WorkerPool) handleTaskSuccess(ctx context.Context, task *db.Task, result *crawler.CrawlResult) error {
 	now := time.Now().UTC()
@@ -3547,12 +3786,59 @@ func (wp *WorkerPool) handleTaskSuccess(ctx context.Context, task *db.Task, resu
 	task.StatusCode = result.StatusCode
 	task.ResponseTime = result.ResponseTime
 	task.CacheStatus = result.CacheStatus
+	task.CDNProvider = result.CDNProvider
+	task.CacheExpiresAt = cacheExpiresAt(now, result)
 	task.ContentType = result.ContentType
 	task.ContentLength = result.ContentLength
+	task.ETag = result.ETag
+	task.LastModified = result.LastModified
+	task.NotModified = result.NotModified
+	task.ContentHash = result.ContentHash
 	// Only store redirect_url if it's a significant redirect (different domain or path)
 	if util.IsSignificantRedirect(result.URL, result.RedirectURL) {
 		task.RedirectURL = result.RedirectURL
 	}
+	applyRedirectChain(task, result)
+	task.CacheVariants = nil
+	if len(result.Variants) > 0 {
+		if variantBytes, err := json.Marshal(result.Variants); err == nil {
+			task.CacheVariants = variantBytes
+		} else {
+			log.Error().Err(err).Str("task_id", task.ID).Msg("Failed to marshal cache variants")
+		}
+	}
+	task.SEO = nil
+	if result.SEO != nil {
+		if seoBytes, err := json.Marshal(result.SEO); err == nil {
+			task.SEO = seoBytes
+		} else {
+			log.Error().Err(err).Str("task_id", task.ID).Msg("Failed to marshal SEO signals")
+		}
+	}
+	task.PageAudit = nil
+	if result.Audit != nil {
+		if auditBytes, err := json.Marshal(result.Audit); err == nil {
+			task.PageAudit = auditBytes
+		} else {
+			log.Error().Err(err).Str("task_id", task.ID).Msg("Failed to marshal page audit")
+		}
+	}
+	task.StructuredData = nil
+	if result.StructuredData != nil {
+		if dataBytes, err := json.Marshal(result.StructuredData); err == nil {
+			task.StructuredData = dataBytes
+		} else {
+			log.Error().Err(err).Str("task_id", task.ID).Msg("Failed to marshal structured data")
+		}
+	}
+	task.InsecureContent = nil
+	if len(result.InsecureContent) > 0 {
+		if insecureBytes, err := json.Marshal(result.InsecureContent); err == nil {
+			task.InsecureContent = insecureBytes
+		} else {
+			log.Error().Err(err).Str("task_id", task.ID).Msg("Failed to marshal insecure content")
+		}
+	}
 
 	// Performance metrics
 	task.DNSLookupTime = result.Performance.DNSLookupTime
@@ -3626,6 +3912,12 @@ func (wp *WorkerPool) handleTaskSuccess(ctx context.Context, task *db.Task, resu
 		// Don't return error here; failed decrements are buffered/retried and reconciliation keeps counters accurate
 	}
 
+	// The page's content hash was read when the task was claimed, before
+	// this task's update replaces it, so a diff is stored when it changed
+	previousContentHash := task.PageContentHash
+	storeDiff := wp.storageBackend != nil && task.ContentHash != "" && task.PageID != 0 &&
+		previousContentHash != task.ContentHash
+
 	// Queue task update for batch processing (detailed field updates)
 	wp.batchManager.QueueTaskUpdate(task)
 
@@ -3644,12 +3936,31 @@ func (wp *WorkerPool) handleTaskSuccess(ctx context.Context, task *db.Task, resu
 		}()
 	}
 
+	// Store the page's main content and a diff against its previous version
+	if storeDiff {
+		taskID, pageID, hash, mainText := task.ID, task.PageID, task.ContentHash, result.MainText
+		go func() {
+			diffCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			wp.storeContentDiff(diffCtx, taskID, pageID, previousContentHash, hash, mainText)
+		}()
+	}
+
+	// Record the host's TLS certificate from its first HTTPS response in this job
+	if host := tlsHost(result); result.TLS != nil && host != "" && wp.claimTLSHost(task.JobID, host) {
+		go func() {
+			tlsCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			wp.recordHostTLS(tlsCtx, task.JobID, host, result.TLS)
+		}()
+	}
+
 	return nil
 }
 
 // detectTechnologies runs wappalyzer detection on the crawl result and updates the domain.
 // Only runs once per domain per worker pool session to avoid redundant detection.
-// If storage is configured, uploads the full HTML body to Supabase Storage.
+// If storage is configured, uploads the full HTML body under a content-addressed key.
 func (wp *WorkerPool) detectTechnologies(ctx context.Context, task *db.Task, result *crawler.CrawlResult) {
 	if wp.techDetector == nil {
 		return
@@ -3705,10 +4016,10 @@ func (wp *WorkerPool) detectTechnologies(ctx context.Context, task *db.Task, res
 
 	// Upload full HTML body to storage if configured
 	var htmlPath string
-	if wp.storageClient != nil && len(result.Body) > 0 {
-		// Create a unique path: domains/{domain_id}/{timestamp}.html
-		storagePath := fmt.Sprintf("domains/%d/%d.html", domainID, time.Now().Unix())
-		path, uploadErr := wp.storageClient.Upload(ctx, "page-crawls", storagePath, result.Body, "text/html")
+	if wp.storageBackend != nil && len(result.Body) > 0 {
+		// Content-addressed, so an unchanged homepage is stored once per domain
+		storageKey := storage.ContentKey(fmt.Sprintf("domains/%d", domainID), result.Body, ".html")
+		path, uploadErr := wp.storageBackend.Put(ctx, storageKey, bytes.NewReader(result.Body), int64(len(result.Body)), "text/html")
 		if uploadErr != nil {
 			log.Warn().Err(uploadErr).Int("domain_id", domainID).Msg("Failed to upload HTML to storage - continuing without")
 		} else {
@@ -3795,7 +4106,28 @@ func (wp *WorkerPool) processTask(ctx context.Context, task *Task) (*crawler.Cra
 		}
 	}()
 
-	result, err := wp.crawler.WarmURL(ctx, urlStr, task.FindLinks)
+	var result *crawler.CrawlResult
+	crawlCtx := ctx
+	if len(task.CacheHeaderMappings) > 0 {
+		crawlCtx = crawler.WithCacheHeaderMappings(crawlCtx, task.CacheHeaderMappings)
+	}
+	if task.SourceType == assetSourceType {
+		// Assets get a lightweight status/cache check with no link extraction
+		result, err = wp.crawler.WarmAsset(crawlCtx, urlStr)
+	} else {
+		if task.CrawlAssets {
+			crawlCtx = crawler.WithAssetExtraction(crawlCtx)
+		}
+		if len(task.WarmVariants) > 0 {
+			crawlCtx = crawler.WithWarmVariants(crawlCtx, task.WarmVariants)
+		}
+		if task.ConditionalRequests {
+			crawlCtx = withPageValidators(crawlCtx, task)
+		}
+		// Links are extracted for external link checks too; only find_links
+		// enqueues them
+		result, err = wp.crawler.WarmURL(crawlCtx, urlStr, task.FindLinks || task.CheckExternalLinks)
+	}
 	if err != nil {
 		status = "error"
 		span.RecordError(err)
@@ -3843,8 +4175,28 @@ func (wp *WorkerPool) processTask(ctx context.Context, task *Task) (*crawler.Cra
 		Str("content_type", result.ContentType).
 		Msg("Crawler completed")
 
-	// Process discovered links if find_links is enabled
-	if task.FindLinks && len(result.Links) > 0 {
+	// Pages served with a 200 that look like the site's not-found page fail
+	// as soft 404s, and their links are not followed
+	if reason := wp.detectSoft404(ctx, task, result, urlStr); reason != "" {
+		result.Soft404 = reason
+		return result, fmt.Errorf("crawler error: soft 404, status 200 served for a page not found (%s)", reason)
+	}
+
+	// Only links back to the site itself count as insecure links
+	result.InsecureContent = internalInsecureContent(result.InsecureContent, task)
+
+	// Anchors and fragment links are recorded so in-page links can be validated
+	if task.FindLinks {
+		wp.recordPageFragments(ctx, task, result, urlStr)
+	}
+
+	// Links to other sites are checked in the background, never crawled
+	if task.CheckExternalLinks && len(result.Links) > 0 {
+		wp.queueExternalLinks(ctx, task, result, urlStr)
+	}
+
+	// Process discovered links if find_links is enabled, and referenced assets if crawl_assets is enabled
+	if (task.FindLinks && len(result.Links) > 0) || (task.CrawlAssets && len(result.Assets) > 0) {
 		wp.processDiscoveredLinks(ctx, task, result, urlStr)
 	}
 
@@ -3990,6 +4342,16 @@ func sameHostWithWWWEquivalence(hostA, hostB string) bool {
 	return normaliseComparableHost(hostA) == normaliseComparableHost(hostB)
 }
 
+// isAssetAllowedForTask determines whether a referenced asset's hostname should be
+// queued. Assets are commonly served from a sibling subdomain (cdn.example.com),
+// so any host on the job's registrable domain is allowed; third-party hosts are not.
+func isAssetAllowedForTask(assetHost string, task *Task) bool {
+	if task == nil {
+		return false
+	}
+	return sameRegistrableDomain(assetHost, task.DomainName)
+}
+
 // isLinkAllowedForTask determines whether a discovered hostname should be queued.
 func isLinkAllowedForTask(discoveredHost string, task *Task) bool {
 	if task == nil {
@@ -4007,6 +4369,28 @@ func isLinkAllowedForTask(discoveredHost string, task *Task) bool {
 	return sameHostWithWWWEquivalence(discoveredHost, task.DomainName)
 }
 
+// internalInsecureContent drops insecure links to other sites, keeping mixed
+// content whatever its host, then caps what is left
+func internalInsecureContent(refs []crawler.InsecureReference, task *Task) []crawler.InsecureReference {
+	kept := refs[:0]
+	for _, ref := range refs {
+		if ref.Kind == crawler.InsecureLink {
+			u, err := url.Parse(ref.URL)
+			if err != nil || !isLinkAllowedForTask(u.Hostname(), task) {
+				continue
+			}
+		}
+		kept = append(kept, ref)
+		if len(kept) == crawler.MaxInsecureReferences {
+			break
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
+
 // updateTaskPriorities updates the priority scores for tasks of linked pages
 func (wp *WorkerPool) updateTaskPriorities(ctx context.Context, jobID string, domainID int, newPriority float64, paths []string) error {
 	if len(paths) == 0 {
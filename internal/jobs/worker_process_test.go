@@ -20,6 +20,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -32,7 +35,13 @@ import (
 
 // MockCrawler implements CrawlerInterface for testing
 type MockCrawler struct {
-	WarmURLFunc func(ctx context.Context, url string, findLinks bool) (*crawler.CrawlResult, error)
+	WarmURLFunc   func(ctx context.Context, url string, findLinks bool) (*crawler.CrawlResult, error)
+	WarmAssetFunc func(ctx context.Context, url string) (*crawler.CrawlResult, error)
+	CheckLinkFunc func(ctx context.Context, url string) (*crawler.CrawlResult, error)
+
+	ProbeNotFoundFunc func(ctx context.Context, pageURL string) (*crawler.PageFingerprint, error)
+
+	StreamSitemapFunc func(ctx context.Context, sitemapURL string, limits crawler.SitemapLimits, emit func(crawler.SitemapEntry) error) (*crawler.SitemapStats, error)
 }
 
 func (m *MockCrawler) WarmURL(ctx context.Context, url string, findLinks bool) (*crawler.CrawlResult, error) {
@@ -66,6 +75,31 @@ func (m *MockCrawler) WarmURL(ctx context.Context, url string, findLinks bool) (
 	}, nil
 }
 
+func (m *MockCrawler) WarmAsset(ctx context.Context, url string) (*crawler.CrawlResult, error) {
+	if m.WarmAssetFunc != nil {
+		return m.WarmAssetFunc(ctx, url)
+	}
+	return &crawler.CrawlResult{
+		StatusCode:  200,
+		CacheStatus: "HIT",
+		ContentType: "image/png",
+	}, nil
+}
+
+func (m *MockCrawler) CheckLink(ctx context.Context, url string) (*crawler.CrawlResult, error) {
+	if m.CheckLinkFunc != nil {
+		return m.CheckLinkFunc(ctx, url)
+	}
+	return &crawler.CrawlResult{URL: url, StatusCode: 200}, nil
+}
+
+func (m *MockCrawler) ProbeNotFound(ctx context.Context, pageURL string) (*crawler.PageFingerprint, error) {
+	if m.ProbeNotFoundFunc != nil {
+		return m.ProbeNotFoundFunc(ctx, pageURL)
+	}
+	return nil, nil
+}
+
 func (m *MockCrawler) DiscoverSitemapsAndRobots(ctx context.Context, domain string) (*crawler.SitemapDiscoveryResult, error) {
 	return &crawler.SitemapDiscoveryResult{}, nil
 }
@@ -74,6 +108,13 @@ func (m *MockCrawler) ParseSitemap(ctx context.Context, sitemapURL string) ([]st
 	return []string{}, nil
 }
 
+func (m *MockCrawler) StreamSitemap(ctx context.Context, sitemapURL string, limits crawler.SitemapLimits, emit func(crawler.SitemapEntry) error) (*crawler.SitemapStats, error) {
+	if m.StreamSitemapFunc != nil {
+		return m.StreamSitemapFunc(ctx, sitemapURL, limits, emit)
+	}
+	return &crawler.SitemapStats{}, nil
+}
+
 func (m *MockCrawler) FilterURLs(urls []string, includePaths, excludePaths []string) []string {
 	return urls
 }
@@ -90,6 +131,18 @@ type MockDbQueue struct {
 	DecrementRunningTasksByFunc func(ctx context.Context, jobID string, count int) error
 	ExecuteFunc                 func(ctx context.Context, fn func(*sql.Tx) error) error
 	ExecuteMaintenanceFunc      func(ctx context.Context, fn func(*sql.Tx) error) error
+
+	RecordHostTLSFunc     func(ctx context.Context, info *db.HostTLS) (bool, error)
+	CertificateExpiryFunc func(ctx context.Context, info *db.HostTLS, daysLeft int) (bool, error)
+
+	AddJobExternalLinksFunc     func(ctx context.Context, links []db.JobExternalLink) error
+	ClaimJobExternalLinksFunc   func(ctx context.Context, limit int, claimFor time.Duration) ([]db.JobExternalLink, error)
+	GetExternalLinkCheckFunc    func(ctx context.Context, linkURL string, maxAge time.Duration) (*db.ExternalLinkCheck, error)
+	RecordExternalLinkCheckFunc func(ctx context.Context, jobID string, check *db.ExternalLinkCheck, cached bool) error
+
+	RecordPageFragmentsFunc func(ctx context.Context, jobID, pageKey string, anchors []string, links []db.FragmentLink) error
+
+	SetTaskContentDiffPathFunc func(ctx context.Context, taskID, path, baseHash string) error
 }
 
 func (m *MockDbQueue) GetNextTask(ctx context.Context, jobID string) (*db.Task, error) {
@@ -156,6 +209,62 @@ func (m *MockDbQueue) UpdateDomainTechnologies(ctx context.Context, domainID int
 	return nil
 }
 
+func (m *MockDbQueue) RecordHostTLS(ctx context.Context, info *db.HostTLS) (bool, error) {
+	if m.RecordHostTLSFunc != nil {
+		return m.RecordHostTLSFunc(ctx, info)
+	}
+	return true, nil
+}
+
+func (m *MockDbQueue) CreateCertificateExpiryNotification(ctx context.Context, info *db.HostTLS, daysLeft int) (bool, error) {
+	if m.CertificateExpiryFunc != nil {
+		return m.CertificateExpiryFunc(ctx, info, daysLeft)
+	}
+	return false, nil
+}
+
+func (m *MockDbQueue) AddJobExternalLinks(ctx context.Context, links []db.JobExternalLink) error {
+	if m.AddJobExternalLinksFunc != nil {
+		return m.AddJobExternalLinksFunc(ctx, links)
+	}
+	return nil
+}
+
+func (m *MockDbQueue) ClaimJobExternalLinks(ctx context.Context, limit int, claimFor time.Duration) ([]db.JobExternalLink, error) {
+	if m.ClaimJobExternalLinksFunc != nil {
+		return m.ClaimJobExternalLinksFunc(ctx, limit, claimFor)
+	}
+	return nil, nil
+}
+
+func (m *MockDbQueue) GetExternalLinkCheck(ctx context.Context, linkURL string, maxAge time.Duration) (*db.ExternalLinkCheck, error) {
+	if m.GetExternalLinkCheckFunc != nil {
+		return m.GetExternalLinkCheckFunc(ctx, linkURL, maxAge)
+	}
+	return nil, nil
+}
+
+func (m *MockDbQueue) RecordExternalLinkCheck(ctx context.Context, jobID string, check *db.ExternalLinkCheck, cached bool) error {
+	if m.RecordExternalLinkCheckFunc != nil {
+		return m.RecordExternalLinkCheckFunc(ctx, jobID, check, cached)
+	}
+	return nil
+}
+
+func (m *MockDbQueue) RecordPageFragments(ctx context.Context, jobID, pageKey string, anchors []string, links []db.FragmentLink) error {
+	if m.RecordPageFragmentsFunc != nil {
+		return m.RecordPageFragmentsFunc(ctx, jobID, pageKey, anchors, links)
+	}
+	return nil
+}
+
+func (m *MockDbQueue) SetTaskContentDiffPath(ctx context.Context, taskID, path, baseHash string) error {
+	if m.SetTaskContentDiffPathFunc != nil {
+		return m.SetTaskContentDiffPathFunc(ctx, taskID, path, baseHash)
+	}
+	return nil
+}
+
 // TestWorkerPoolProcessTask demonstrates the test structure for processTask
 // NOTE: This test cannot actually execute processTask due to concrete type dependencies.
 // It documents the test cases we would run if WorkerPool used interfaces instead of concrete types.
@@ -300,6 +409,498 @@ func TestWorkerPoolProcessTask(t *testing.T) {
 	}
 }
 
+func TestWorkerPoolProcessTaskRoutesAssets(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	var warmedAsset string
+	mockCrawler := &MockCrawler{
+		WarmURLFunc: func(ctx context.Context, url string, findLinks bool) (*crawler.CrawlResult, error) {
+			t.Fatalf("asset task should not be crawled as a page: %s", url)
+			return nil, nil
+		},
+		WarmAssetFunc: func(ctx context.Context, url string) (*crawler.CrawlResult, error) {
+			warmedAsset = url
+			return &crawler.CrawlResult{StatusCode: 200, CacheStatus: "HIT", ContentType: "image/png"}, nil
+		},
+	}
+
+	wp := &WorkerPool{
+		db:           mockDB,
+		dbQueue:      &MockDbQueue{},
+		crawler:      mockCrawler,
+		jobInfoCache: make(map[string]*JobInfo),
+	}
+
+	result, err := wp.processTask(context.Background(), &Task{
+		ID:         "asset-task",
+		JobID:      "test-job-1",
+		Path:       "/img/logo.png",
+		DomainName: "example.com",
+		SourceType: assetSourceType,
+		FindLinks:  true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/img/logo.png", warmedAsset)
+	assert.Equal(t, "HIT", result.CacheStatus)
+}
+
+func TestWorkerPoolProcessTaskChecksExternalLinksWithoutFindLinks(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	var stored []db.JobExternalLink
+	wp := &WorkerPool{
+		db:               mockDB,
+		externalLinkWake: make(chan struct{}, 1),
+		dbQueue: &MockDbQueue{
+			AddJobExternalLinksFunc: func(ctx context.Context, links []db.JobExternalLink) error {
+				stored = links
+				return nil
+			},
+		},
+		crawler: &MockCrawler{
+			WarmURLFunc: func(ctx context.Context, url string, findLinks bool) (*crawler.CrawlResult, error) {
+				assert.True(t, findLinks, "links should be extracted for external link checks")
+				return &crawler.CrawlResult{StatusCode: 200, Links: map[string][]string{
+					"body": {"https://example.com/about", "https://other.net/a"},
+				}}, nil
+			},
+		},
+		jobInfoCache: make(map[string]*JobInfo),
+	}
+
+	_, err = wp.processTask(context.Background(), &Task{
+		ID:                 "task-1",
+		JobID:              "job-1",
+		Path:               "/",
+		DomainName:         "example.com",
+		CheckExternalLinks: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []db.JobExternalLink{
+		{JobID: "job-1", URL: "https://other.net/a", Host: "other.net", SourceURL: "https://example.com/"},
+	}, stored, "only links to other sites should be stored")
+}
+
+func TestIsAssetAllowedForTask(t *testing.T) {
+	task := &Task{DomainName: "example.com", Host: "www.example.com"}
+
+	assert.True(t, isAssetAllowedForTask("www.example.com", task))
+	assert.True(t, isAssetAllowedForTask("cdn.example.com", task))
+	assert.False(t, isAssetAllowedForTask("fonts.gstatic.com", task))
+	assert.False(t, isAssetAllowedForTask("example.com", nil))
+}
+
+func TestCacheExpiresAt(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	ttl := func(seconds int64) *int64 { return &seconds }
+
+	tests := []struct {
+		name   string
+		result *crawler.CrawlResult
+		want   time.Time
+	}{
+		{"remaining ttl", &crawler.CrawlResult{CacheStatus: "HIT", CacheTTL: ttl(600)}, now.Add(10 * time.Minute)},
+		{"warmed on second request", &crawler.CrawlResult{CacheStatus: "MISS", SecondCacheStatus: "HIT", CacheTTL: ttl(3600)}, now.Add(time.Hour)},
+		{"no ttl", &crawler.CrawlResult{CacheStatus: "HIT"}, time.Time{}},
+		{"expired", &crawler.CrawlResult{CacheStatus: "HIT", CacheTTL: ttl(0)}, time.Time{}},
+		{"bypassed", &crawler.CrawlResult{CacheStatus: "BYPASS", CacheTTL: ttl(600)}, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cacheExpiresAt(now, tt.result))
+		})
+	}
+}
+
+func TestRecordHostTLS(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name       string
+		notAfter   time.Time
+		stored     bool
+		wantNotify bool
+		wantDays   int
+	}{
+		{"expiring soon", now.Add(5*24*time.Hour + time.Hour), true, true, 5},
+		{"expired", now.Add(-time.Hour), true, true, -1},
+		{"plenty of time", now.Add(90 * 24 * time.Hour), true, false, 0},
+		{"already recorded", now.Add(5 * 24 * time.Hour), false, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recorded *db.HostTLS
+			notified := false
+			var notifiedDays int
+			wp := &WorkerPool{
+				certExpiryWarningDays: 14,
+				dbQueue: &MockDbQueue{
+					RecordHostTLSFunc: func(ctx context.Context, info *db.HostTLS) (bool, error) {
+						recorded = info
+						return tt.stored, nil
+					},
+					CertificateExpiryFunc: func(ctx context.Context, info *db.HostTLS, daysLeft int) (bool, error) {
+						notified = true
+						notifiedDays = daysLeft
+						return true, nil
+					},
+				},
+			}
+
+			wp.recordHostTLS(context.Background(), "job-1", "example.com", &crawler.TLSInfo{
+				Version:      "TLS 1.3",
+				CipherSuite:  "TLS_AES_128_GCM_SHA256",
+				Certificates: []crawler.CertificateInfo{{Subject: "example.com", Issuer: "R3", NotAfter: tt.notAfter}},
+			})
+
+			require.NotNil(t, recorded)
+			assert.Equal(t, "example.com", recorded.Host)
+			assert.Equal(t, "R3", recorded.Issuer)
+			assert.JSONEq(t, `[{"subject":"example.com","issuer":"R3","not_before":"0001-01-01T00:00:00Z","not_after":"`+
+				tt.notAfter.Format(time.RFC3339Nano)+`"}]`, string(recorded.Chain))
+			assert.Equal(t, tt.wantNotify, notified)
+			if tt.wantNotify {
+				assert.Equal(t, tt.wantDays, notifiedDays)
+			}
+		})
+	}
+}
+
+func TestClaimTLSHost(t *testing.T) {
+	wp := &WorkerPool{}
+
+	assert.True(t, wp.claimTLSHost("job-1", "example.com"))
+	assert.False(t, wp.claimTLSHost("job-1", "example.com"))
+	assert.True(t, wp.claimTLSHost("job-1", "www.example.com"))
+	assert.True(t, wp.claimTLSHost("job-2", "example.com"))
+
+	wp.dbQueue = &MockDbQueue{
+		RecordHostTLSFunc: func(ctx context.Context, info *db.HostTLS) (bool, error) {
+			return false, errors.New("connection reset")
+		},
+	}
+	wp.recordHostTLS(context.Background(), "job-1", "example.com", &crawler.TLSInfo{
+		Certificates: []crawler.CertificateInfo{{Subject: "example.com", Issuer: "R3"}},
+	})
+	assert.True(t, wp.claimTLSHost("job-1", "example.com"), "a failed write should release the claim")
+
+	assert.Equal(t, "www.example.com", tlsHost(&crawler.CrawlResult{
+		URL:         "https://example.com/",
+		RedirectURL: "https://WWW.example.com/",
+	}))
+}
+
+func TestInternalInsecureContent(t *testing.T) {
+	task := &Task{DomainName: "example.com", Host: "www.example.com"}
+	refs := []crawler.InsecureReference{
+		{URL: "http://cdn.other.net/app.js", Element: "script", Kind: crawler.InsecureMixedContent},
+		{URL: "http://example.com/about", Element: "a", Kind: crawler.InsecureLink},
+		{URL: "http://blog.example.com/", Element: "a", Kind: crawler.InsecureLink},
+		{URL: "http://other.net/", Element: "a", Kind: crawler.InsecureLink},
+	}
+
+	assert.Equal(t, []crawler.InsecureReference{refs[0], refs[1]}, internalInsecureContent(slices.Clone(refs), task))
+	assert.Nil(t, internalInsecureContent(slices.Clone(refs[3:]), task))
+
+	// External links must not use up the cap before internal ones are seen
+	var many []crawler.InsecureReference
+	for i := range crawler.MaxInsecureReferences {
+		many = append(many, crawler.InsecureReference{URL: fmt.Sprintf("http://other.net/%d", i), Element: "a", Kind: crawler.InsecureLink})
+	}
+	for i := range crawler.MaxInsecureReferences + 1 {
+		many = append(many, crawler.InsecureReference{URL: fmt.Sprintf("http://example.com/%d", i), Element: "a", Kind: crawler.InsecureLink})
+	}
+	kept := internalInsecureContent(many, task)
+	assert.Len(t, kept, crawler.MaxInsecureReferences)
+	assert.Equal(t, "http://example.com/0", kept[0].URL)
+}
+
+func TestExternalLinks(t *testing.T) {
+	links := map[string][]string{
+		"header": {"https://other.net/a#top", "/about"},
+		"body": {
+			"https://blog.example.com/post",
+			"https://other.net/a",
+			"http://partner.org/page?ref=1",
+			"mailto:hello@other.net",
+			"//cdn.other.net/file.pdf",
+		},
+	}
+
+	assert.Equal(t, []string{
+		"https://other.net/a",
+		"http://partner.org/page?ref=1",
+		"https://cdn.other.net/file.pdf",
+	}, externalLinks(links, "https://www.example.com/", "example.com"))
+}
+
+func TestQueueExternalLinks(t *testing.T) {
+	var stored []db.JobExternalLink
+	fail := true
+	wp := &WorkerPool{
+		externalLinkWake: make(chan struct{}, 1),
+		dbQueue: &MockDbQueue{
+			AddJobExternalLinksFunc: func(ctx context.Context, links []db.JobExternalLink) error {
+				if fail {
+					return errors.New("connection refused")
+				}
+				stored = links
+				return nil
+			},
+		},
+	}
+	task := &Task{ID: "task-1", JobID: "job-1", DomainName: "example.com"}
+	result := &crawler.CrawlResult{Links: map[string][]string{
+		"body": {"https://other.net/a", "https://partner.org/b"},
+	}}
+
+	// Links that could not be stored are stored when found again
+	wp.queueExternalLinks(context.Background(), task, result, "https://example.com/")
+	assert.Empty(t, wp.externalLinkWake)
+
+	fail = false
+	wp.queueExternalLinks(context.Background(), task, result, "https://example.com/")
+	assert.Equal(t, []db.JobExternalLink{
+		{JobID: "job-1", URL: "https://other.net/a", Host: "other.net", SourceURL: "https://example.com/"},
+		{JobID: "job-1", URL: "https://partner.org/b", Host: "partner.org", SourceURL: "https://example.com/"},
+	}, stored)
+	assert.Len(t, wp.externalLinkWake, 1, "an idle checker should be woken")
+
+	stored = nil
+	wp.queueExternalLinks(context.Background(), task, result, "https://example.com/")
+	assert.Nil(t, stored, "links already stored for the job should not be stored again")
+}
+
+func TestCheckExternalLink(t *testing.T) {
+	link := db.JobExternalLink{JobID: "job-1", URL: "https://other.net/a", Host: "other.net", SourceURL: "https://example.com/"}
+
+	tests := []struct {
+		name        string
+		cached      *db.ExternalLinkCheck
+		checkStatus int
+		wantChecked bool
+		wantCached  bool
+		wantStatus  int
+		wantError   bool
+	}{
+		{"cached result", &db.ExternalLinkCheck{URL: link.URL, StatusCode: 404}, 0, false, true, 404, false},
+		{"fresh ok", nil, 200, true, false, 200, false},
+		{"fresh broken", nil, 500, true, false, 500, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checked := false
+			var recorded *db.ExternalLinkCheck
+			var recordedCached bool
+			wp := &WorkerPool{
+				externalLinkCacheTTL: time.Hour,
+				crawler: &MockCrawler{
+					CheckLinkFunc: func(ctx context.Context, url string) (*crawler.CrawlResult, error) {
+						checked = true
+						result := &crawler.CrawlResult{URL: url, StatusCode: tt.checkStatus, ResponseTime: 120}
+						if tt.checkStatus >= 400 {
+							return result, errors.New("Internal Server Error")
+						}
+						return result, nil
+					},
+				},
+				dbQueue: &MockDbQueue{
+					GetExternalLinkCheckFunc: func(ctx context.Context, linkURL string, maxAge time.Duration) (*db.ExternalLinkCheck, error) {
+						return tt.cached, nil
+					},
+					RecordExternalLinkCheckFunc: func(ctx context.Context, jobID string, check *db.ExternalLinkCheck, cached bool) error {
+						recorded = check
+						recordedCached = cached
+						return nil
+					},
+				},
+			}
+
+			wp.checkExternalLink(context.Background(), link)
+
+			assert.Equal(t, tt.wantChecked, checked)
+			require.NotNil(t, recorded)
+			assert.Equal(t, tt.wantCached, recordedCached)
+			assert.Equal(t, tt.wantStatus, recorded.StatusCode)
+			assert.Equal(t, tt.wantError, recorded.Error != "")
+		})
+	}
+}
+
+func TestExternalLinkCheckersClaimStoredLinks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	pending := []db.JobExternalLink{{JobID: "job-1", URL: "https://other.net/a", Host: "other.net"}}
+	recorded := make(chan string, 1)
+	wp := &WorkerPool{
+		stopCh:           make(chan struct{}),
+		externalLinkWake: make(chan struct{}, 1),
+		crawler: &MockCrawler{
+			CheckLinkFunc: func(ctx context.Context, url string) (*crawler.CrawlResult, error) {
+				return &crawler.CrawlResult{URL: url, StatusCode: 200}, nil
+			},
+		},
+		dbQueue: &MockDbQueue{
+			ClaimJobExternalLinksFunc: func(ctx context.Context, limit int, claimFor time.Duration) ([]db.JobExternalLink, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				claimed := pending
+				pending = nil
+				return claimed, nil
+			},
+			RecordExternalLinkCheckFunc: func(ctx context.Context, jobID string, check *db.ExternalLinkCheck, cached bool) error {
+				recorded <- check.URL
+				return nil
+			},
+		},
+	}
+
+	wp.startExternalLinkCheckers(ctx)
+	select {
+	case got := <-recorded:
+		assert.Equal(t, "https://other.net/a", got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stored link was not checked")
+	}
+	cancel()
+	wp.wg.Wait()
+}
+
+func TestFragmentLinks(t *testing.T) {
+	task := &Task{DomainName: "example.com", Host: "www.example.com"}
+	links := map[string][]string{
+		"header": {"#main", "https://www.example.com/#top"},
+		"body": {
+			"https://example.com/pricing/#enterprise",
+			"https://www.example.com/pricing#enterprise",
+			"/pricing?plan=pro#enterprise",
+			"https://example.com/docs#:~:text=install",
+			"https://example.com/app#/settings",
+			"https://example.com/about",
+			"https://other.net/page#section",
+			"https://example.com/guide#caf%C3%A9",
+		},
+	}
+
+	assert.Equal(t, []db.FragmentLink{
+		{URL: "https://www.example.com/blog#main", TargetKey: "example.com/blog", Fragment: "main", SourceURL: "https://www.example.com/blog"},
+		{URL: "https://example.com/pricing/#enterprise", TargetKey: "example.com/pricing", Fragment: "enterprise", SourceURL: "https://www.example.com/blog"},
+		{URL: "https://example.com/guide#caf%C3%A9", TargetKey: "example.com/guide", Fragment: "café", SourceURL: "https://www.example.com/blog"},
+	}, fragmentLinks(links, "https://www.example.com/blog", task))
+}
+
+func TestRecordPageFragments(t *testing.T) {
+	var gotKey string
+	var gotAnchors []string
+	var gotLinks []db.FragmentLink
+	calls := 0
+	wp := &WorkerPool{dbQueue: &MockDbQueue{
+		RecordPageFragmentsFunc: func(ctx context.Context, jobID, pageKey string, anchors []string, links []db.FragmentLink) error {
+			calls++
+			gotKey, gotAnchors, gotLinks = pageKey, anchors, links
+			return nil
+		},
+	}}
+	task := &Task{JobID: "job-1", DomainName: "example.com", Host: "example.com"}
+
+	wp.recordPageFragments(context.Background(), task, &crawler.CrawlResult{
+		Anchors: []string{"pricing"},
+		Links:   map[string][]string{"body": {"/about#team"}},
+	}, "https://example.com/pricing/")
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "example.com/pricing", gotKey)
+	assert.Equal(t, []string{"pricing"}, gotAnchors)
+	require.Len(t, gotLinks, 1)
+	assert.Equal(t, "example.com/about", gotLinks[0].TargetKey)
+
+	// Non-HTML responses with no fragment links are skipped
+	wp.recordPageFragments(context.Background(), task, &crawler.CrawlResult{}, "https://example.com/file.pdf")
+	assert.Equal(t, 1, calls)
+}
+
+func TestWithPageValidators(t *testing.T) {
+	ctx := context.Background()
+	stored := crawler.Validators{ETag: `"abc"`, LastModified: "Mon, 02 Mar 2026 10:00:00 GMT"}
+
+	assert.NotEqual(t, ctx, withPageValidators(ctx, &Task{ID: "t1", PageValidators: stored}), "stored validators are sent")
+	assert.Equal(t, ctx, withPageValidators(ctx, &Task{ID: "t2"}), "pages without validators get a full GET")
+}
+
+func TestDetectSoft404(t *testing.T) {
+	template := &crawler.PageFingerprint{Title: "Acme", SimHash: 0xF0F0F0F0F0F0F0F0, TextSize: 900}
+	page := func(simHash uint64, title string) *crawler.CrawlResult {
+		return &crawler.CrawlResult{
+			StatusCode:  200,
+			Fingerprint: &crawler.PageFingerprint{Title: title, SimHash: simHash, TextSize: 2000},
+		}
+	}
+
+	probes := 0
+	wp := &WorkerPool{crawler: &MockCrawler{
+		ProbeNotFoundFunc: func(ctx context.Context, pageURL string) (*crawler.PageFingerprint, error) {
+			probes++
+			return template, nil
+		},
+	}}
+	task := &Task{JobID: "job-1", DomainName: "example.com"}
+	ctx := context.Background()
+
+	assert.Equal(t, crawler.Soft404TemplateMatch, wp.detectSoft404(ctx, task, page(template.SimHash^0b11, "Acme"), "https://example.com/old-page"))
+	assert.Empty(t, wp.detectSoft404(ctx, task, page(^template.SimHash, "Blue widgets"), "https://example.com/widgets"))
+	assert.Equal(t, crawler.Soft404NotFoundTitle, wp.detectSoft404(ctx, task, page(^template.SimHash, "404 Not Found"), "https://example.com/gone"))
+	assert.Equal(t, 1, probes, "template is probed once per host")
+
+	// Assets and non-200 responses are never soft 404s
+	asset := &Task{JobID: "job-1", DomainName: "example.com", SourceType: assetSourceType}
+	assert.Empty(t, wp.detectSoft404(ctx, asset, page(template.SimHash, "Acme"), "https://example.com/logo.png"))
+	notFound := page(template.SimHash, "Acme")
+	notFound.StatusCode = 404
+	assert.Empty(t, wp.detectSoft404(ctx, task, notFound, "https://example.com/missing"))
+
+}
+
+func TestFollowableLinks(t *testing.T) {
+	links := map[string][]string{
+		"header": {"https://example.com/login"},
+		"body":   {"https://example.com/about", "https://example.com/login", "https://example.com/login"},
+	}
+	nofollow := map[string][]string{"body": {"https://example.com/login"}}
+
+	tests := []struct {
+		name   string
+		seo    *crawler.SEOSignals
+		ignore bool
+		want   map[string][]string
+	}{
+		{"no signals", nil, false, links},
+		{"nofollow page", &crawler.SEOSignals{NoFollow: true}, false, nil},
+		{"noindex page", &crawler.SEOSignals{NoIndex: true}, false, links},
+		{"noindex nofollow page", &crawler.SEOSignals{NoIndex: true, NoFollow: true}, false, nil},
+		{"override", &crawler.SEOSignals{NoIndex: true, NofollowLinks: nofollow}, true, links},
+		{"nofollow anchor", &crawler.SEOSignals{NofollowLinks: nofollow}, false, map[string][]string{
+			"header": {"https://example.com/login"},
+			"body":   {"https://example.com/about", "https://example.com/login"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &crawler.CrawlResult{Links: links, SEO: tt.seo}
+			assert.Equal(t, tt.want, followableLinks(result, tt.ignore))
+		})
+	}
+}
+
 // TestWorkerPoolProcessNextTask demonstrates the test structure for processNextTask
 // NOTE: Cannot execute due to concrete dbQueue dependency. Documents intended test coverage.
 func TestWorkerPoolProcessNextTask(t *testing.T) {
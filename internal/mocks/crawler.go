@@ -23,6 +23,39 @@ func (m *MockCrawler) WarmURL(ctx context.Context, url string, findLinks bool) (
 	return args.Get(0).(*crawler.CrawlResult), args.Error(1)
 }
 
+// WarmAsset mocks the WarmAsset method
+func (m *MockCrawler) WarmAsset(ctx context.Context, url string) (*crawler.CrawlResult, error) {
+	args := m.Called(ctx, url)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*crawler.CrawlResult), args.Error(1)
+}
+
+// CheckLink mocks the CheckLink method
+func (m *MockCrawler) CheckLink(ctx context.Context, url string) (*crawler.CrawlResult, error) {
+	args := m.Called(ctx, url)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*crawler.CrawlResult), args.Error(1)
+}
+
+// ProbeNotFound mocks the ProbeNotFound method
+func (m *MockCrawler) ProbeNotFound(ctx context.Context, pageURL string) (*crawler.PageFingerprint, error) {
+	args := m.Called(ctx, pageURL)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*crawler.PageFingerprint), args.Error(1)
+}
+
 // DiscoverSitemapsAndRobots mocks the DiscoverSitemapsAndRobots method
 func (m *MockCrawler) DiscoverSitemapsAndRobots(ctx context.Context, domain string) (*crawler.SitemapDiscoveryResult, error) {
 	args := m.Called(ctx, domain)
@@ -45,6 +78,17 @@ func (m *MockCrawler) ParseSitemap(ctx context.Context, sitemapURL string) ([]st
 	return args.Get(0).([]string), args.Error(1)
 }
 
+// StreamSitemap mocks the StreamSitemap method
+func (m *MockCrawler) StreamSitemap(ctx context.Context, sitemapURL string, limits crawler.SitemapLimits, emit func(crawler.SitemapEntry) error) (*crawler.SitemapStats, error) {
+	args := m.Called(ctx, sitemapURL, limits, emit)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*crawler.SitemapStats), args.Error(1)
+}
+
 // FilterURLs mocks the FilterURLs method
 func (m *MockCrawler) FilterURLs(urls []string, includePaths, excludePaths []string) []string {
 	args := m.Called(urls, includePaths, excludePaths)
@@ -253,6 +253,15 @@ func (m *MockDB) GetDomainNames(ctx context.Context, domainIDs []int) (map[int]s
 	return args.Get(0).(map[int]string), args.Error(1)
 }
 
+// GetLeaderLease mocks the GetLeaderLease method
+func (m *MockDB) GetLeaderLease(ctx context.Context, name string) (*db.LeaderLease, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.LeaderLease), args.Error(1)
+}
+
 // GetDomainsForOrganisation mocks the GetDomainsForOrganisation method
 func (m *MockDB) GetDomainsForOrganisation(ctx context.Context, organisationID string) ([]db.OrganisationDomain, error) {
 	args := m.Called(ctx, organisationID)
@@ -406,6 +415,21 @@ func (m *MockDB) GetOrganisationPlanID(ctx context.Context, organisationID strin
 	return args.String(0), args.Error(1)
 }
 
+// GetOrganisationCacheHeaderMappings mocks cache header mapping retrieval
+func (m *MockDB) GetOrganisationCacheHeaderMappings(ctx context.Context, organisationID string) ([]byte, error) {
+	args := m.Called(ctx, organisationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+// SetOrganisationCacheHeaderMappings mocks cache header mapping updates
+func (m *MockDB) SetOrganisationCacheHeaderMappings(ctx context.Context, organisationID string, mappings []byte) error {
+	args := m.Called(ctx, organisationID, mappings)
+	return args.Error(0)
+}
+
 // ListDailyUsage mocks daily usage history
 func (m *MockDB) ListDailyUsage(ctx context.Context, organisationID string, startDate, endDate time.Time) ([]db.DailyUsageEntry, error) {
 	args := m.Called(ctx, organisationID, startDate, endDate)
@@ -538,6 +562,101 @@ func (m *MockDB) GetWebflowToken(ctx context.Context, connectionID string) (stri
 	return args.String(0), args.Error(1)
 }
 
+// CDN purge integration mock methods
+func (m *MockDB) CreateCDNConnection(ctx context.Context, conn *db.CDNConnection) (bool, error) {
+	args := m.Called(ctx, conn)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDB) GetCDNConnection(ctx context.Context, connectionID, organisationID string) (*db.CDNConnection, error) {
+	args := m.Called(ctx, connectionID, organisationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.CDNConnection), args.Error(1)
+}
+
+func (m *MockDB) GetCDNConnectionForDomain(ctx context.Context, organisationID, domain string) (*db.CDNConnection, error) {
+	args := m.Called(ctx, organisationID, domain)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.CDNConnection), args.Error(1)
+}
+
+func (m *MockDB) ListCDNConnections(ctx context.Context, organisationID string) ([]*db.CDNConnection, error) {
+	args := m.Called(ctx, organisationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*db.CDNConnection), args.Error(1)
+}
+
+func (m *MockDB) DeleteCDNConnection(ctx context.Context, connectionID, organisationID string) error {
+	args := m.Called(ctx, connectionID, organisationID)
+	return args.Error(0)
+}
+
+func (m *MockDB) StoreCDNToken(ctx context.Context, connectionID, token string) error {
+	args := m.Called(ctx, connectionID, token)
+	return args.Error(0)
+}
+
+func (m *MockDB) GetCDNToken(ctx context.Context, connectionID string) (string, error) {
+	args := m.Called(ctx, connectionID)
+	return args.String(0), args.Error(1)
+}
+
+// SEO audit findings methods
+
+func (m *MockDB) ListJobFindings(ctx context.Context, jobID string) ([]db.TaskFinding, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.TaskFinding), args.Error(1)
+}
+
+func (m *MockDB) ListJobHostTLS(ctx context.Context, jobID string) ([]*db.HostTLS, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*db.HostTLS), args.Error(1)
+}
+
+func (m *MockDB) ListJobExternalLinks(ctx context.Context, jobID string, brokenOnly bool, limit int) ([]db.JobExternalLink, error) {
+	args := m.Called(ctx, jobID, brokenOnly, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.JobExternalLink), args.Error(1)
+}
+
+func (m *MockDB) ListJobMissingAnchors(ctx context.Context, jobID string, limit int) ([]db.MissingAnchor, error) {
+	args := m.Called(ctx, jobID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.MissingAnchor), args.Error(1)
+}
+
+func (m *MockDB) ListJobSitemapChanges(ctx context.Context, jobID string, limit int) ([]db.SitemapChange, error) {
+	args := m.Called(ctx, jobID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.SitemapChange), args.Error(1)
+}
+
+func (m *MockDB) ListJobContentChanges(ctx context.Context, jobID, sinceJobID string, limit int) ([]db.ContentChange, error) {
+	args := m.Called(ctx, jobID, sinceJobID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.ContentChange), args.Error(1)
+}
+
 // Platform integration methods
 
 func (m *MockDB) UpsertPlatformOrgMapping(ctx context.Context, mapping *db.PlatformOrgMapping) error {
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ContentKey returns a content-addressed key for data under prefix: the
+// SHA-256 of the data, fanned out by its first two characters, so identical
+// uploads share one object
+func ContentKey(prefix string, data []byte, ext string) string {
+	sum := sha256.Sum256(data)
+	return contentKey(prefix, hex.EncodeToString(sum[:]), ext)
+}
+
+func contentKey(prefix, digest, ext string) string {
+	key := digest[:2] + "/" + digest + ext
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		key = prefix + "/" + key
+	}
+	return key
+}
+
+// PutContentAddressed streams body to a content-addressed key under prefix
+// (see ContentKey). The body is hashed while it is spooled to a temporary
+// file, since the key is only known once it has been read in full. It
+// returns the key and the object's location.
+func PutContentAddressed(ctx context.Context, backend Backend, prefix, ext string, body io.Reader, contentType string) (string, string, error) {
+	hash := sha256.New()
+	spooled, size, err := spool(io.TeeReader(body, hash))
+	if err != nil {
+		return "", "", err
+	}
+	defer spooled.Close()
+
+	key := contentKey(prefix, hex.EncodeToString(hash.Sum(nil)), ext)
+	loc, err := backend.Put(ctx, key, spooled, size, contentType)
+	if err != nil {
+		return "", "", err
+	}
+	return key, loc, nil
+}
+
+// ReadAll reads an object in full, up to limit bytes
+func ReadAll(ctx context.Context, backend Backend, key string, limit int64) ([]byte, error) {
+	body, err := backend.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s is larger than %d bytes", key, limit)
+	}
+	return data, nil
+}
+
+// spooledFile is a temporary file removed when closed
+type spooledFile struct {
+	*os.File
+}
+
+func (f spooledFile) Close() error {
+	err := f.File.Close()
+	_ = os.Remove(f.Name())
+	return err
+}
+
+// spool copies body to a temporary file and rewinds it, so it can be
+// uploaded with a known length
+func spool(body io.Reader) (spooledFile, int64, error) {
+	file, err := os.CreateTemp("", "adapt-storage-*")
+	if err != nil {
+		return spooledFile{}, 0, fmt.Errorf("failed to create spool file: %w", err)
+	}
+	spooled := spooledFile{file}
+
+	size, err := io.Copy(file, body)
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		spooled.Close()
+		return spooledFile{}, 0, fmt.Errorf("failed to spool upload: %w", err)
+	}
+	return spooled, size, nil
+}
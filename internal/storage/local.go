@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// localTempPrefix marks partially written files, which Walk skips
+const localTempPrefix = ".upload-"
+
+// Local stores objects as files under a directory, for development and
+// self-hosted installs. Signed URLs are served by its ServeHTTP method.
+type Local struct {
+	root       string
+	bucket     string
+	publicURL  string
+	signingKey []byte
+	now        func() time.Time
+}
+
+// NewLocal creates a filesystem backend storing a bucket's objects under
+// dir/bucket. publicURL is where the backend is mounted for signed URLs and
+// must have a path, such as "/storage", since the site is served from the
+// root; without a signing key a random one is used, so URLs do not survive
+// a restart.
+func NewLocal(dir, bucket, publicURL, signingKey string) (*Local, error) {
+	if publicURL != "" {
+		parsed, err := url.Parse(publicURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid local storage public URL: %w", err)
+		}
+		if strings.Trim(parsed.Path, "/") == "" {
+			return nil, fmt.Errorf("local storage public URL %q needs a path such as /storage", publicURL)
+		}
+	}
+
+	root, err := filepath.Abs(filepath.Join(dir, bucket))
+	if err != nil {
+		return nil, fmt.Errorf("invalid local storage directory: %w", err)
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create local storage directory: %w", err)
+	}
+
+	key := []byte(signingKey)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+	}
+
+	return &Local{
+		root:       root,
+		bucket:     bucket,
+		publicURL:  strings.TrimSuffix(publicURL, "/"),
+		signingKey: key,
+		now:        time.Now,
+	}, nil
+}
+
+// Bucket returns the name of the bucket objects are stored in
+func (l *Local) Bucket() string {
+	return l.bucket
+}
+
+// MountPath returns the URL path signed URLs point at, such as "/storage/",
+// or "" when no public URL is configured
+func (l *Local) MountPath() string {
+	if l.publicURL == "" {
+		return ""
+	}
+	parsed, err := url.Parse(l.publicURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(parsed.Path, "/") + "/"
+}
+
+func (l *Local) filePath(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+// Put streams body to a temporary file next to the object and renames it
+// into place, so readers never see a partial object
+func (l *Local) Put(_ context.Context, key string, body io.Reader, size int64, _ string) (string, error) {
+	target, err := l.filePath(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), localTempPrefix+"*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	written, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if size >= 0 && written != size {
+		return "", fmt.Errorf("short write: expected %d bytes, got %d", size, written)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", fmt.Errorf("failed to store file: %w", err)
+	}
+
+	return location(l.bucket, key), nil
+}
+
+// Get opens an object for reading
+func (l *Local) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	target, err := l.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return file, nil
+}
+
+// Delete removes objects
+func (l *Local) Delete(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		target, err := l.filePath(key)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to delete file: %w", err)
+		}
+	}
+	return nil
+}
+
+// Walk calls fn for every object under prefix
+func (l *Local) Walk(ctx context.Context, prefix string, fn func(Object) error) error {
+	// Start from the deepest directory the prefix names
+	start := l.root
+	if dir := path.Dir(prefix); strings.HasSuffix(prefix, "/") {
+		start = filepath.Join(l.root, filepath.FromSlash(strings.TrimSuffix(prefix, "/")))
+	} else if dir != "." {
+		start = filepath.Join(l.root, filepath.FromSlash(dir))
+	}
+	if start != l.root && !strings.HasPrefix(start, l.root+string(filepath.Separator)) {
+		return fmt.Errorf("invalid storage prefix %q", prefix)
+	}
+
+	err := filepath.WalkDir(start, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), localTempPrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(l.root, name)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		return fn(Object{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil // Nothing stored under the prefix yet
+	}
+	return err
+}
+
+// SignedURL returns a URL under the public URL that ServeHTTP accepts until
+// it expires
+func (l *Local) SignedURL(_ context.Context, key string, expiresIn time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	if l.publicURL == "" {
+		return "", fmt.Errorf("local storage signed URLs require BBB_STORAGE_PUBLIC_URL")
+	}
+
+	expires := strconv.FormatInt(l.now().Add(expiresIn).Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {l.sign(key, expires)}}
+	return l.publicURL + "/" + escapeKey(key) + "?" + query.Encode(), nil
+}
+
+func (l *Local) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, l.signingKey)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ServeHTTP serves objects requested through signed URLs. Mount it with the
+// MountPath prefix stripped.
+func (l *Local) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	expires := r.URL.Query().Get("expires")
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || l.now().Unix() > expiresAt ||
+		!hmac.Equal([]byte(l.sign(key, expires)), []byte(r.URL.Query().Get("signature"))) {
+		http.Error(w, "invalid or expired signature", http.StatusForbidden)
+		return
+	}
+
+	target, err := l.filePath(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	file, err := os.Open(target)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalPutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	backend, err := NewLocal(t.TempDir(), "page-crawls", "", "secret")
+	require.NoError(t, err)
+
+	loc, err := backend.Put(ctx, "domains/1/home.html", strings.NewReader("<html></html>"), -1, "text/html")
+	require.NoError(t, err)
+	assert.Equal(t, "page-crawls/domains/1/home.html", loc)
+
+	data, err := ReadAll(ctx, backend, "domains/1/home.html", 1024)
+	require.NoError(t, err)
+	assert.Equal(t, "<html></html>", string(data))
+
+	_, err = ReadAll(ctx, backend, "domains/1/home.html", 4)
+	assert.Error(t, err, "objects over the limit should not be read")
+
+	require.NoError(t, backend.Delete(ctx, "domains/1/home.html", "domains/1/missing.html"))
+	_, err = backend.Get(ctx, "domains/1/home.html")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalRejectsUnsafeKeys(t *testing.T) {
+	backend, err := NewLocal(t.TempDir(), "page-crawls", "", "secret")
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "a/../../b", "folder/"} {
+		_, err := backend.Put(context.Background(), key, strings.NewReader("x"), 1, "text/plain")
+		assert.Error(t, err, "key %q should be rejected", key)
+	}
+}
+
+func TestLocalWalkAndRetention(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	backend, err := NewLocal(dir, "page-crawls", "", "secret")
+	require.NoError(t, err)
+
+	for _, key := range []string{"pages/1/content/a.txt", "pages/1/content/b.txt", "pages/1/diffs/a-b.diff", "pages/2/content/c.txt", "domains/1/x.html"} {
+		_, err := backend.Put(ctx, key, strings.NewReader(key), int64(len(key)), "text/plain")
+		require.NoError(t, err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "page-crawls", "pages", "1", "content", "a.txt"), old, old))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "page-crawls", "pages", "1", "content", "b.txt"), old, old))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "page-crawls", "pages", "1", "diffs", "a-b.diff"), old, old))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "page-crawls", "domains", "1", "x.html"), old, old))
+
+	var keys []string
+	require.NoError(t, backend.Walk(ctx, "pages/1", func(obj Object) error {
+		keys = append(keys, obj.Key)
+		return nil
+	}))
+	assert.ElementsMatch(t, []string{"pages/1/content/a.txt", "pages/1/content/b.txt", "pages/1/diffs/a-b.diff"}, keys)
+
+	var checked []string
+	deleted, err := DeleteOlderThan(ctx, backend, "pages/", time.Now().Add(-24*time.Hour), func(ctx context.Context, keys []string) (map[string]bool, error) {
+		checked = append(checked, keys...)
+		return nil, errors.New("connection reset")
+	})
+	require.Error(t, err)
+	assert.Zero(t, deleted, "nothing should be deleted when keys cannot be checked")
+	assert.ElementsMatch(t, []string{"pages/1/content/a.txt", "pages/1/content/b.txt", "pages/1/diffs/a-b.diff"}, checked,
+		"only expired keys should be checked")
+
+	inUse := func(ctx context.Context, keys []string) (map[string]bool, error) {
+		return map[string]bool{"pages/1/content/b.txt": true}, nil
+	}
+	deleted, err = DeleteOlderThan(ctx, backend, "pages/", time.Now().Add(-24*time.Hour), inUse)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted, "only old objects under the prefix should be deleted")
+
+	_, err = backend.Get(ctx, "pages/1/content/a.txt")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = backend.Get(ctx, "pages/1/diffs/a-b.diff")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = backend.Get(ctx, "pages/1/content/b.txt")
+	assert.NoError(t, err, "objects still in use should be kept")
+	_, err = backend.Get(ctx, "domains/1/x.html")
+	assert.NoError(t, err, "objects outside the prefix should be kept")
+}
+
+func TestLocalSignedURL(t *testing.T) {
+	ctx := context.Background()
+	backend, err := NewLocal(t.TempDir(), "page-crawls", "http://localhost:8080/storage", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "/storage/", backend.MountPath())
+
+	_, err = backend.Put(ctx, "pages/1/diffs/a-b.diff", strings.NewReader("-old\n+new\n"), -1, "text/x-diff")
+	require.NoError(t, err)
+
+	signed, err := backend.SignedURL(ctx, "pages/1/diffs/a-b.diff", time.Minute)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(signed, "http://localhost:8080/storage/pages/1/diffs/a-b.diff?"))
+
+	handler := http.StripPrefix("/storage", backend)
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := get(strings.TrimPrefix(signed, "http://localhost:8080"))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, _ := io.ReadAll(rec.Body)
+	assert.Equal(t, "-old\n+new\n", string(body))
+
+	_, err = NewLocal(t.TempDir(), "page-crawls", "http://localhost:8080", "secret")
+	assert.Error(t, err, "mounting at the site root should be refused")
+	_, err = NewLocal(t.TempDir(), "page-crawls", "http://localhost:8080/", "secret")
+	assert.Error(t, err, "mounting at the site root should be refused")
+
+	tampered := strings.Replace(strings.TrimPrefix(signed, "http://localhost:8080"), "a-b.diff", "b-a.diff", 1)
+	assert.Equal(t, http.StatusForbidden, get(tampered).Code, "signature should cover the key")
+
+	backend.now = func() time.Time { return time.Now().Add(time.Hour) }
+	assert.Equal(t, http.StatusForbidden, get(strings.TrimPrefix(signed, "http://localhost:8080")).Code, "expired URLs should be refused")
+}
+
+func TestPutContentAddressed(t *testing.T) {
+	ctx := context.Background()
+	backend, err := NewLocal(t.TempDir(), "page-crawls", "", "secret")
+	require.NoError(t, err)
+
+	key, loc, err := PutContentAddressed(ctx, backend, "domains/7", ".html", strings.NewReader("<html></html>"), "text/html")
+	require.NoError(t, err)
+	assert.Equal(t, ContentKey("domains/7", []byte("<html></html>"), ".html"), key)
+	assert.Equal(t, "page-crawls/"+key, loc)
+	assert.Regexp(t, `^domains/7/[0-9a-f]{2}/[0-9a-f]{64}\.html$`, key)
+
+	again, _, err := PutContentAddressed(ctx, backend, "domains/7", ".html", strings.NewReader("<html></html>"), "text/html")
+	require.NoError(t, err)
+	assert.Equal(t, key, again, "identical content should share a key")
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// retentionDeleteBatch is how many expired objects are checked and deleted
+// per request
+const retentionDeleteBatch = 500
+
+// KeepFunc reports which of keys are still in use and must not be deleted.
+// It is called with at most retentionDeleteBatch keys at a time.
+type KeepFunc func(ctx context.Context, keys []string) (map[string]bool, error)
+
+// DeleteOlderThan deletes objects under prefix last modified before cutoff,
+// other than those keep reports as still in use, and returns how many were
+// deleted. keep may be nil. Expired keys are collected before any are
+// deleted, since deleting while listing can skip objects, then each batch is
+// checked with keep just before it is deleted.
+func DeleteOlderThan(ctx context.Context, backend Backend, prefix string, cutoff time.Time, keep KeepFunc) (int, error) {
+	var expired []string
+	err := backend.Walk(ctx, prefix, func(obj Object) error {
+		if obj.LastModified.IsZero() || !obj.LastModified.Before(cutoff) {
+			return nil
+		}
+		expired = append(expired, obj.Key)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for start := 0; start < len(expired); start += retentionDeleteBatch {
+		batch := expired[start:min(start+retentionDeleteBatch, len(expired))]
+		if keep != nil {
+			inUse, err := keep(ctx, batch)
+			if err != nil {
+				return deleted, err
+			}
+			unused := make([]string, 0, len(batch))
+			for _, key := range batch {
+				if !inUse[key] {
+					unused = append(unused, key)
+				}
+			}
+			batch = unused
+		}
+		if len(batch) == 0 {
+			continue
+		}
+		if err := backend.Delete(ctx, batch...); err != nil {
+			return deleted, err
+		}
+		deleted += len(batch)
+	}
+	return deleted, nil
+}
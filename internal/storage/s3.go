@@ -0,0 +1,441 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec // Content-MD5 is required by the S3 DeleteObjects API
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// s3DeleteBatch is the most keys S3 deletes in one request
+	s3DeleteBatch = 1000
+	// s3MaxPresignExpiry is the longest a SigV4 presigned URL can be valid for
+	s3MaxPresignExpiry = 7 * 24 * time.Hour
+
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+)
+
+// S3Config configures an S3-compatible backend such as AWS S3 or MinIO
+type S3Config struct {
+	Endpoint        string // e.g. http://localhost:9000; empty uses AWS S3 for the region
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	ForcePathStyle  bool // Address buckets as endpoint/bucket rather than bucket.endpoint, as MinIO expects
+}
+
+// S3 stores objects in an S3-compatible bucket, signing requests with AWS
+// Signature Version 4
+type S3 struct {
+	endpoint   *url.URL
+	region     string
+	accessKey  string
+	secretKey  string
+	pathStyle  bool
+	bucket     string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewS3 creates an S3-compatible backend for a bucket
+func NewS3(cfg S3Config, bucket string) (*S3, error) {
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3 storage requires BBB_S3_ACCESS_KEY_ID and BBB_S3_SECRET_ACCESS_KEY")
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := strings.TrimSuffix(cfg.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+
+	return &S3{
+		endpoint:  parsed,
+		region:    region,
+		accessKey: cfg.AccessKeyID,
+		secretKey: cfg.SecretAccessKey,
+		pathStyle: cfg.ForcePathStyle,
+		bucket:    bucket,
+		httpClient: &http.Client{
+			Timeout: 2 * time.Minute,
+		},
+		now: time.Now,
+	}, nil
+}
+
+// Bucket returns the name of the bucket objects are stored in
+func (s *S3) Bucket() string {
+	return s.bucket
+}
+
+// objectURL returns the URL of a key, or of the bucket when key is empty
+func (s *S3) objectURL(key string, query url.Values) *url.URL {
+	u := *s.endpoint
+	rawPath := strings.TrimSuffix(u.EscapedPath(), "/")
+	if s.pathStyle {
+		rawPath += "/" + awsURIEncode(s.bucket, true)
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	if key != "" {
+		rawPath += "/" + awsURIEncode(key, false)
+	} else if rawPath == "" {
+		rawPath = "/"
+	}
+
+	u.RawPath = rawPath
+	u.Path, _ = url.PathUnescape(rawPath)
+	u.RawQuery = canonicalQuery(query)
+	return &u
+}
+
+func (s *S3) do(ctx context.Context, method string, u *url.URL, body io.Reader, size int64, payloadHash string, headers http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.URL = u
+	if size >= 0 {
+		req.ContentLength = size
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	s.sign(req, payloadHash)
+	return s.httpClient.Do(req)
+}
+
+// Put streams body to key. S3 needs the length up front, so a body of
+// unknown size is spooled to a temporary file first.
+func (s *S3) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	if size < 0 {
+		spooled, spooledSize, err := spool(body)
+		if err != nil {
+			return "", err
+		}
+		defer spooled.Close()
+		body, size = spooled, spooledSize
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, s.objectURL(key, nil), body, size, s3UnsignedPayload, http.Header{
+		"Content-Type": {contentType},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", s3Error("upload", resp)
+	}
+	return location(s.bucket, key), nil
+}
+
+// Get opens an object for reading
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, s.objectURL(key, nil), nil, 0, emptyPayloadHash, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	defer resp.Body.Close()
+	return nil, s3Error("download", resp)
+}
+
+// Delete removes objects, up to s3DeleteBatch per request
+func (s *S3) Delete(ctx context.Context, keys ...string) error {
+	for start := 0; start < len(keys); start += s3DeleteBatch {
+		if err := s.deleteBatch(ctx, keys[start:min(start+s3DeleteBatch, len(keys))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type s3DeleteRequest struct {
+	XMLName xml.Name         `xml:"Delete"`
+	Quiet   bool             `xml:"Quiet"`
+	Objects []s3DeleteObject `xml:"Object"`
+}
+
+type s3DeleteObject struct {
+	Key string `xml:"Key"`
+}
+
+type s3DeleteResult struct {
+	Errors []struct {
+		Key     string `xml:"Key"`
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+}
+
+func (s *S3) deleteBatch(ctx context.Context, keys []string) error {
+	request := s3DeleteRequest{Quiet: true}
+	for _, key := range keys {
+		request.Objects = append(request.Objects, s3DeleteObject{Key: key})
+	}
+	payload, err := xml.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode delete request: %w", err)
+	}
+	digest := md5.Sum(payload) //nolint:gosec // Required by the API, not used for security
+
+	resp, err := s.do(ctx, http.MethodPost, s.objectURL("", url.Values{"delete": {""}}), bytes.NewReader(payload), int64(len(payload)), sha256Hex(payload), http.Header{
+		"Content-Type": {"application/xml"},
+		"Content-Md5":  {base64.StdEncoding.EncodeToString(digest[:])},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete files: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s3Error("delete", resp)
+	}
+	var result s3DeleteResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to parse delete response: %w", err)
+	}
+	for _, failure := range result.Errors {
+		if failure.Code != "NoSuchKey" {
+			return fmt.Errorf("failed to delete %s: %s: %s", failure.Key, failure.Code, failure.Message)
+		}
+	}
+	return nil
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		Size         int64     `xml:"Size"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// Walk calls fn for every object under prefix, a page of ListObjectsV2 at a time
+func (s *S3) Walk(ctx context.Context, prefix string, fn func(Object) error) error {
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		result, err := s.list(ctx, query)
+		if err != nil {
+			return err
+		}
+		for _, entry := range result.Contents {
+			if err := fn(Object{Key: entry.Key, Size: entry.Size, LastModified: entry.LastModified}); err != nil {
+				return err
+			}
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (s *S3) list(ctx context.Context, query url.Values) (*s3ListResult, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.objectURL("", query), nil, 0, emptyPayloadHash, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, s3Error("list", resp)
+	}
+	var result s3ListResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse list response: %w", err)
+	}
+	return &result, nil
+}
+
+// SignedURL returns a presigned GET URL, valid for at most seven days
+func (s *S3) SignedURL(_ context.Context, key string, expiresIn time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	if expiresIn <= 0 || expiresIn > s3MaxPresignExpiry {
+		return "", fmt.Errorf("signed URL expiry must be between 1s and %s", s3MaxPresignExpiry)
+	}
+
+	now := s.now().UTC()
+	u := s.objectURL(key, url.Values{
+		"X-Amz-Algorithm":     {"AWS4-HMAC-SHA256"},
+		"X-Amz-Credential":    {s.accessKey + "/" + s.scope(now)},
+		"X-Amz-Date":          {now.Format(s3TimeFormat)},
+		"X-Amz-Expires":       {strconv.Itoa(int(expiresIn.Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	})
+
+	canonical := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		u.RawQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+	signature := s.signature(now, canonical)
+
+	u.RawQuery += "&X-Amz-Signature=" + signature
+	return u.String(), nil
+}
+
+// sign adds SigV4 authorisation headers to a request
+func (s *S3) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// Sign the host and every x-amz-*, content-type and content-md5 header
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "content-type" || lower == "content-md5" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, s.scope(now), signedHeaders, s.signature(now, canonical),
+	))
+}
+
+func (s *S3) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature signs a canonical request with a key derived for the day
+func (s *S3) signature(now time.Time, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format(s3TimeFormat),
+		s.scope(now),
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+var emptyPayloadHash = sha256Hex(nil)
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery encodes query parameters sorted by name, as SigV4 expects
+func canonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(query))
+	for _, name := range names {
+		for _, value := range query[name] {
+			pairs = append(pairs, awsURIEncode(name, true)+"="+awsURIEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsURIEncode percent-encodes everything except unreserved characters, and
+// slashes unless encodeSlash is set
+func awsURIEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(value) {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func s3Error(action string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("%s failed with status %d: %s", action, resp.StatusCode, string(body))
+}
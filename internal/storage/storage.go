@@ -0,0 +1,166 @@
+// Package storage stores crawl artefacts, such as HTML samples and content
+// diffs, in a Supabase Storage bucket, an S3-compatible bucket or a local
+// directory, chosen by configuration
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Storage drivers
+const (
+	DriverSupabase = "supabase"
+	DriverS3       = "s3"
+	DriverLocal    = "local"
+)
+
+// DefaultBucket is the bucket crawl artefacts are stored in unless configured
+const DefaultBucket = "page-crawls"
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("storage object not found")
+
+// ErrNotConfigured is returned by New when no storage driver is configured
+var ErrNotConfigured = errors.New("storage not configured")
+
+// Backend stores objects under keys within a single bucket
+type Backend interface {
+	// Put streams body to key, replacing any existing object. size is the
+	// body length in bytes, or -1 if unknown. It returns the object's
+	// location as "bucket/key".
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (string, error)
+	// Get opens an object for reading, or returns ErrNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes objects; keys that do not exist are ignored
+	Delete(ctx context.Context, keys ...string) error
+	// Walk calls fn for every object whose key starts with prefix
+	Walk(ctx context.Context, prefix string, fn func(Object) error) error
+	// SignedURL returns a URL granting read access to an object until it
+	// expires
+	SignedURL(ctx context.Context, key string, expiresIn time.Duration) (string, error)
+	// Bucket returns the name of the bucket objects are stored in
+	Bucket() string
+}
+
+// Object describes a stored object
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Config selects and configures a storage driver
+type Config struct {
+	Driver        string // DriverSupabase, DriverS3 or DriverLocal; empty picks Supabase when its credentials are set
+	Bucket        string
+	RetentionDays int // Objects older than this are deleted; 0 keeps them forever
+
+	// Supabase Storage
+	SupabaseURL        string
+	SupabaseServiceKey string
+
+	// S3-compatible storage
+	S3 S3Config
+
+	// Local filesystem
+	LocalDir   string // Directory holding one subdirectory per bucket
+	PublicURL  string // Base URL local objects are served from, for signed URLs
+	SigningKey string // Secret used to sign local URLs
+}
+
+// ConfigFromEnv reads the storage configuration from the environment
+func ConfigFromEnv() Config {
+	retentionDays, _ := strconv.Atoi(os.Getenv("BBB_STORAGE_RETENTION_DAYS"))
+	forcePathStyle, _ := strconv.ParseBool(getEnvWithDefault("BBB_S3_FORCE_PATH_STYLE", "true"))
+
+	return Config{
+		Driver:             strings.ToLower(strings.TrimSpace(os.Getenv("BBB_STORAGE_BACKEND"))),
+		Bucket:             getEnvWithDefault("BBB_STORAGE_BUCKET", DefaultBucket),
+		RetentionDays:      max(retentionDays, 0),
+		SupabaseURL:        strings.TrimSuffix(os.Getenv("SUPABASE_URL"), "/"),
+		SupabaseServiceKey: os.Getenv("SUPABASE_SERVICE_ROLE_KEY"),
+		S3: S3Config{
+			Endpoint:        os.Getenv("BBB_S3_ENDPOINT"),
+			Region:          getEnvWithDefault("BBB_S3_REGION", "us-east-1"),
+			AccessKeyID:     os.Getenv("BBB_S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("BBB_S3_SECRET_ACCESS_KEY"),
+			ForcePathStyle:  forcePathStyle,
+		},
+		LocalDir:   getEnvWithDefault("BBB_STORAGE_LOCAL_DIR", "data/storage"),
+		PublicURL:  strings.TrimSuffix(os.Getenv("BBB_STORAGE_PUBLIC_URL"), "/"),
+		SigningKey: os.Getenv("BBB_STORAGE_SIGNING_KEY"),
+	}
+}
+
+// Retention returns how long objects are kept, or 0 to keep them forever
+func (c Config) Retention() time.Duration {
+	return time.Duration(c.RetentionDays) * 24 * time.Hour
+}
+
+// SelectedDriver returns the driver New opens: Driver when set, otherwise
+// Supabase when its credentials are set, otherwise ""
+func (c Config) SelectedDriver() string {
+	if c.Driver != "" {
+		return c.Driver
+	}
+	if c.SupabaseURL != "" && c.SupabaseServiceKey != "" {
+		return DriverSupabase
+	}
+	return ""
+}
+
+// New opens the configured storage driver. It returns ErrNotConfigured when
+// no driver is selected and Supabase credentials are not set.
+func New(cfg Config) (Backend, error) {
+	if cfg.Bucket == "" {
+		cfg.Bucket = DefaultBucket
+	}
+
+	switch driver := cfg.SelectedDriver(); driver {
+	case "":
+		return nil, ErrNotConfigured
+	case DriverSupabase:
+		if cfg.SupabaseURL == "" || cfg.SupabaseServiceKey == "" {
+			return nil, fmt.Errorf("supabase storage requires SUPABASE_URL and SUPABASE_SERVICE_ROLE_KEY")
+		}
+		return NewSupabase(cfg.SupabaseURL, cfg.SupabaseServiceKey, cfg.Bucket), nil
+	case DriverS3:
+		return NewS3(cfg.S3, cfg.Bucket)
+	case DriverLocal:
+		return NewLocal(cfg.LocalDir, cfg.Bucket, cfg.PublicURL, cfg.SigningKey)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (want %s, %s or %s)", driver, DriverSupabase, DriverS3, DriverLocal)
+	}
+}
+
+// location formats where an object is stored, as returned by Put
+func location(bucket, key string) string {
+	return bucket + "/" + key
+}
+
+// validateKey rejects keys that could escape the bucket or address a folder
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.HasSuffix(key, "/") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	for segment := range strings.SplitSeq(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	return nil
+}
+
+func getEnvWithDefault(key, defaultValue string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,279 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// supabaseListLimit is the page size used when listing a folder
+const supabaseListLimit = 1000
+
+// Supabase stores objects in a Supabase Storage bucket through its REST API
+type Supabase struct {
+	baseURL    string
+	serviceKey string
+	bucket     string
+	httpClient *http.Client
+}
+
+// NewSupabase creates a Supabase Storage backend for a bucket
+func NewSupabase(supabaseURL, serviceKey, bucket string) *Supabase {
+	return &Supabase{
+		baseURL:    supabaseURL + "/storage/v1",
+		serviceKey: serviceKey,
+		bucket:     bucket,
+		httpClient: &http.Client{
+			Timeout: 2 * time.Minute,
+		},
+	}
+}
+
+// Bucket returns the name of the bucket objects are stored in
+func (s *Supabase) Bucket() string {
+	return s.bucket
+}
+
+func (s *Supabase) objectURL(key string) string {
+	return fmt.Sprintf("%s/object/%s/%s", s.baseURL, url.PathEscape(s.bucket), escapeKey(key))
+}
+
+func (s *Supabase) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.serviceKey)
+	return req, nil
+}
+
+// Put streams body to key, overwriting any existing object
+func (s *Supabase) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPost, s.objectURL(key), body)
+	if err != nil {
+		return "", err
+	}
+	if size >= 0 {
+		req.ContentLength = size
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-upsert", "true") // Overwrite if exists
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return location(s.bucket, key), nil
+}
+
+// Get opens an object for reading
+func (s *Supabase) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp.Body, nil
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	// Supabase reports missing objects as a 400 with a not_found error
+	if resp.StatusCode == http.StatusNotFound || (resp.StatusCode == http.StatusBadRequest && bytes.Contains(respBody, []byte("not_found"))) {
+		return nil, ErrNotFound
+	}
+	return nil, fmt.Errorf("download failed with status %d: %s", resp.StatusCode, string(respBody))
+}
+
+// Delete removes objects in a single request
+func (s *Supabase) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string][]string{"prefixes": keys})
+	if err != nil {
+		return fmt.Errorf("failed to encode delete request: %w", err)
+	}
+	req, err := s.newRequest(ctx, http.MethodDelete, fmt.Sprintf("%s/object/%s", s.baseURL, url.PathEscape(s.bucket)), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete files: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("delete failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// supabaseListEntry is a file or folder returned by the list endpoint.
+// Folders have no ID.
+type supabaseListEntry struct {
+	ID        *string   `json:"id"`
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Metadata  struct {
+		Size int64 `json:"size"`
+	} `json:"metadata"`
+}
+
+// Walk calls fn for every object under prefix. Supabase lists one folder at
+// a time, so folders are walked recursively.
+func (s *Supabase) Walk(ctx context.Context, prefix string, fn func(Object) error) error {
+	folder := strings.TrimSuffix(prefix, "/")
+	if !strings.HasSuffix(prefix, "/") {
+		if folder = path.Dir(prefix); folder == "." {
+			folder = ""
+		}
+	}
+	return s.walkFolder(ctx, folder, prefix, fn)
+}
+
+func (s *Supabase) walkFolder(ctx context.Context, folder, prefix string, fn func(Object) error) error {
+	for offset := 0; ; offset += supabaseListLimit {
+		entries, err := s.list(ctx, folder, offset)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			key := entry.Name
+			if folder != "" {
+				key = folder + "/" + entry.Name
+			}
+			if entry.ID == nil {
+				if strings.HasPrefix(key+"/", prefix) || strings.HasPrefix(prefix, key+"/") {
+					if err := s.walkFolder(ctx, key, prefix, fn); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			if err := fn(Object{Key: key, Size: entry.Metadata.Size, LastModified: entry.UpdatedAt}); err != nil {
+				return err
+			}
+		}
+
+		if len(entries) < supabaseListLimit {
+			return nil
+		}
+	}
+}
+
+func (s *Supabase) list(ctx context.Context, folder string, offset int) ([]supabaseListEntry, error) {
+	payload, err := json.Marshal(map[string]any{
+		"prefix": folder,
+		"limit":  supabaseListLimit,
+		"offset": offset,
+		"sortBy": map[string]string{"column": "name", "order": "asc"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode list request: %w", err)
+	}
+	req, err := s.newRequest(ctx, http.MethodPost, fmt.Sprintf("%s/object/list/%s", s.baseURL, url.PathEscape(s.bucket)), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("list failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var entries []supabaseListEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to parse list response: %w", err)
+	}
+	return entries, nil
+}
+
+// SignedURL returns a signed URL for temporary access to a private file
+func (s *Supabase) SignedURL(ctx context.Context, key string, expiresIn time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	body := fmt.Sprintf(`{"expiresIn":%d}`, int(expiresIn.Seconds()))
+	req, err := s.newRequest(ctx, http.MethodPost, fmt.Sprintf("%s/object/sign/%s/%s", s.baseURL, url.PathEscape(s.bucket), escapeKey(key)), strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get signed URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("get signed URL failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	// Parse the signed URL from response
+	var result struct {
+		SignedURL string `json:"signedURL"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse signed URL response: %w", err)
+	}
+
+	// SignedURL is relative path, prepend base URL
+	return s.baseURL + result.SignedURL, nil
+}
+
+// escapeKey escapes each segment of a key for use in a URL path
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSupabaseStorage is a minimal in-memory Supabase Storage server, which
+// like the real one lists a single folder per request
+type fakeSupabaseStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeSupabaseStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer service-key" {
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/storage/v1/object/list/page-crawls":
+		var request struct {
+			Prefix string `json:"prefix"`
+			Limit  int    `json:"limit"`
+			Offset int    `json:"offset"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.list(request.Prefix, request.Limit, request.Offset))
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/storage/v1/object/page-crawls/"):
+		if r.Header.Get("x-upsert") != "true" {
+			http.Error(w, `{"error":"Duplicate"}`, http.StatusConflict)
+			return
+		}
+		key, _ := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), "/storage/v1/object/page-crawls/"))
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/storage/v1/object/page-crawls/"):
+		body, ok := f.objects[strings.TrimPrefix(r.URL.Path, "/storage/v1/object/page-crawls/")]
+		if !ok {
+			http.Error(w, `{"statusCode":"404","error":"not_found","message":"Object not found"}`, http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write(body)
+	case r.Method == http.MethodDelete && r.URL.Path == "/storage/v1/object/page-crawls":
+		var request struct {
+			Prefixes []string `json:"prefixes"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, key := range request.Prefixes {
+			delete(f.objects, key)
+		}
+		fmt.Fprint(w, "[]")
+	default:
+		http.Error(w, "unsupported", http.StatusMethodNotAllowed)
+	}
+}
+
+// list returns the files and folders directly inside folder, sorted by name
+func (f *fakeSupabaseStorage) list(folder string, limit, offset int) []map[string]any {
+	seen := map[string]bool{}
+	var entries []map[string]any
+	for key, body := range f.objects {
+		rest := key
+		if folder != "" {
+			var ok bool
+			if rest, ok = strings.CutPrefix(key, folder+"/"); !ok {
+				continue
+			}
+		}
+		name, _, isFolder := strings.Cut(rest, "/")
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if isFolder {
+			entries = append(entries, map[string]any{"name": name, "id": nil})
+			continue
+		}
+		entries = append(entries, map[string]any{
+			"name":       name,
+			"id":         "id-" + key,
+			"updated_at": "2026-01-01T00:00:00Z",
+			"metadata":   map[string]any{"size": len(body)},
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i]["name"].(string) < entries[j]["name"].(string) })
+
+	if offset >= len(entries) {
+		return []map[string]any{}
+	}
+	return entries[offset:min(offset+limit, len(entries))]
+}
+
+func newFakeSupabase(t *testing.T) (*fakeSupabaseStorage, *Supabase) {
+	server := &fakeSupabaseStorage{objects: map[string][]byte{}}
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	return server, NewSupabase(ts.URL, "service-key", "page-crawls")
+}
+
+func TestSupabasePutGet(t *testing.T) {
+	ctx := context.Background()
+	server, backend := newFakeSupabase(t)
+
+	loc, err := backend.Put(ctx, "pages/1/content/a b.txt", strings.NewReader("hello"), -1, "text/plain")
+	require.NoError(t, err)
+	assert.Equal(t, "page-crawls/pages/1/content/a b.txt", loc)
+	assert.Equal(t, []byte("hello"), server.objects["pages/1/content/a b.txt"])
+
+	_, err = backend.Put(ctx, "pages/1/content/a b.txt", strings.NewReader("hello again"), -1, "text/plain")
+	require.NoError(t, err, "existing objects should be overwritten")
+
+	data, err := ReadAll(ctx, backend, "pages/1/content/a b.txt", 1024)
+	require.NoError(t, err)
+	assert.Equal(t, "hello again", string(data))
+
+	_, err = backend.Get(ctx, "pages/1/content/missing.txt")
+	assert.ErrorIs(t, err, ErrNotFound, "a 400 not_found response should map to ErrNotFound")
+
+	unauthorised := NewSupabase(strings.TrimSuffix(backend.baseURL, "/storage/v1"), "wrong-key", "page-crawls")
+	_, err = unauthorised.Get(ctx, "pages/1/content/a b.txt")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
+
+func TestSupabaseWalkRecursesIntoFolders(t *testing.T) {
+	ctx := context.Background()
+	server, backend := newFakeSupabase(t)
+
+	for _, key := range []string{
+		"pages/1/content/a.txt",
+		"pages/1/diffs/a-b.diff",
+		"pages/12/content/c.txt",
+		"pages/2/content/d.txt",
+		"domains/1/ab/abc.html",
+		"root.txt",
+	} {
+		server.objects[key] = []byte(key)
+	}
+
+	walk := func(prefix string) []string {
+		var keys []string
+		require.NoError(t, backend.Walk(ctx, prefix, func(obj Object) error {
+			assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), obj.LastModified)
+			assert.Equal(t, int64(len(obj.Key)), obj.Size)
+			keys = append(keys, obj.Key)
+			return nil
+		}))
+		return keys
+	}
+
+	assert.ElementsMatch(t, []string{
+		"pages/1/content/a.txt", "pages/1/diffs/a-b.diff", "pages/12/content/c.txt",
+		"pages/2/content/d.txt", "domains/1/ab/abc.html", "root.txt",
+	}, walk(""))
+	assert.ElementsMatch(t, []string{"pages/1/content/a.txt", "pages/1/diffs/a-b.diff"}, walk("pages/1/"))
+	assert.ElementsMatch(t, []string{"pages/1/content/a.txt", "pages/1/diffs/a-b.diff", "pages/12/content/c.txt"}, walk("pages/1"),
+		"a prefix without a trailing slash should match partial names")
+
+	deleted, err := DeleteOlderThan(ctx, backend, "pages/", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), func(ctx context.Context, keys []string) (map[string]bool, error) {
+		return map[string]bool{"pages/2/content/d.txt": true}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+	assert.ElementsMatch(t, []string{"pages/2/content/d.txt", "domains/1/ab/abc.html", "root.txt"}, walk(""))
+}
+
+func TestSupabaseWalkPagesLargeFolders(t *testing.T) {
+	server, backend := newFakeSupabase(t)
+	for i := range supabaseListLimit + 5 {
+		server.objects[fmt.Sprintf("pages/%04d.txt", i)] = []byte("x")
+	}
+
+	count := 0
+	require.NoError(t, backend.Walk(context.Background(), "pages/", func(Object) error {
+		count++
+		return nil
+	}))
+	assert.Equal(t, supabaseListLimit+5, count)
+}
@@ -0,0 +1,215 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embed the IANA time zone database; the runtime image ships without zoneinfo
+	_ "time/tzdata"
+)
+
+// cronSearchLimit bounds how far ahead Next searches before giving up on an
+// expression that can never match (e.g. 30 February)
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// CronSchedule is a parsed five-field cron expression
+// (minute, hour, day of month, month, day of week).
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar track unrestricted day fields. When both day fields
+	// are restricted, a time matches if either one matches (standard cron).
+	domStar, dowStar bool
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{min: 0, max: 59}
+	cronHour   = cronField{min: 0, max: 23}
+	cronDom    = cronField{min: 1, max: 31}
+	cronMonth  = cronField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	cronDow = cronField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a standard five-field cron expression. Fields support
+// '*', lists (1,15), ranges (1-5), steps (*/15, 9-17/2) and month/day names
+// (JAN, MON-FRI). The common @daily-style macros are also accepted.
+func ParseCron(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("cron expression cannot be empty")
+	}
+
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression must have 5 fields, got %d", len(fields))
+	}
+
+	schedule := &CronSchedule{}
+	var err error
+
+	if schedule.minute, err = parseCronField(fields[0], cronMinute); err != nil {
+		return nil, fmt.Errorf("invalid minute field: %w", err)
+	}
+	if schedule.hour, err = parseCronField(fields[1], cronHour); err != nil {
+		return nil, fmt.Errorf("invalid hour field: %w", err)
+	}
+	if schedule.dom, err = parseCronField(fields[2], cronDom); err != nil {
+		return nil, fmt.Errorf("invalid day-of-month field: %w", err)
+	}
+	if schedule.month, err = parseCronField(fields[3], cronMonth); err != nil {
+		return nil, fmt.Errorf("invalid month field: %w", err)
+	}
+	if schedule.dow, err = parseCronField(fields[4], cronDow); err != nil {
+		return nil, fmt.Errorf("invalid day-of-week field: %w", err)
+	}
+
+	// Sunday may be written as 0 or 7
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+
+	schedule.domStar = strings.HasPrefix(fields[2], "*")
+	schedule.dowStar = strings.HasPrefix(fields[4], "*")
+
+	return schedule, nil
+}
+
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+
+	for part := range strings.SplitSeq(field, ",") {
+		if part == "" {
+			return 0, fmt.Errorf("empty list item in %q", field)
+		}
+
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			parsed, err := strconv.Atoi(stepPart)
+			if err != nil || parsed <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = parsed
+		}
+
+		var start, end int
+		switch {
+		case rangePart == "*":
+			start, end = spec.min, spec.max
+		case strings.Contains(rangePart, "-"):
+			lo, hi, _ := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseCronValue(lo, spec); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(hi, spec); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("range start %d is after end %d", start, end)
+			}
+		default:
+			value, err := parseCronValue(rangePart, spec)
+			if err != nil {
+				return 0, err
+			}
+			start = value
+			end = value
+			// "5/15" means starting at 5, every 15 until the field maximum
+			if hasStep {
+				end = spec.max
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseCronValue(value string, spec cronField) (int, error) {
+	if spec.names != nil {
+		if named, ok := spec.names[strings.ToLower(value)]; ok {
+			return named, nil
+		}
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if parsed < spec.min || parsed > spec.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", parsed, spec.min, spec.max)
+	}
+	return parsed, nil
+}
+
+// Next returns the first matching time strictly after t, evaluated in t's
+// location. It returns the zero time if no match exists within five years.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	limit := t.Add(cronSearchLimit)
+
+	// Start at the next whole minute
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			// Step in absolute time so DST transitions cannot map back to the same hour
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
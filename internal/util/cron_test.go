@@ -0,0 +1,112 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{name: "empty", expr: ""},
+		{name: "too_few_fields", expr: "0 6 * *"},
+		{name: "six_fields", expr: "0 0 6 * * *"},
+		{name: "minute_out_of_range", expr: "60 * * * *"},
+		{name: "hour_out_of_range", expr: "0 24 * * *"},
+		{name: "zero_day_of_month", expr: "0 0 0 * *"},
+		{name: "bad_step", expr: "*/0 * * * *"},
+		{name: "reversed_range", expr: "0 9-5 * * *"},
+		{name: "unknown_name", expr: "0 6 * * MOO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCron(tt.expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	melbourne, err := time.LoadLocation("Australia/Melbourne")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		expr     string
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			name:     "every_fifteen_minutes",
+			expr:     "*/15 * * * *",
+			from:     time.Date(2026, 3, 2, 10, 7, 30, 0, time.UTC),
+			expected: time.Date(2026, 3, 2, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:     "strictly_after_match",
+			expr:     "0 6 * * *",
+			from:     time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 3, 3, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekday_mornings_skip_weekend",
+			expr:     "0 6 * * MON-FRI",
+			from:     time.Date(2026, 3, 6, 7, 0, 0, 0, melbourne), // Friday
+			expected: time.Date(2026, 3, 9, 6, 0, 0, 0, melbourne), // Monday
+		},
+		{
+			name:     "sunday_as_seven",
+			expr:     "30 9 * * 7",
+			from:     time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), // Monday
+			expected: time.Date(2026, 3, 8, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "day_of_month_or_day_of_week",
+			expr:     "0 0 1 * MON",
+			from:     time.Date(2026, 3, 25, 0, 0, 0, 0, time.UTC), // Wednesday
+			expected: time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC), // Monday before 1 April
+		},
+		{
+			name:     "monthly_macro",
+			expr:     "@monthly",
+			from:     time.Date(2026, 12, 15, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "leap_day",
+			expr:     "0 0 29 2 *",
+			from:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// Melbourne clocks jump from 02:00 to 03:00 on 4 October 2026
+			name:     "dst_gap_skips_missing_hour",
+			expr:     "30 2 * * *",
+			from:     time.Date(2026, 10, 3, 12, 0, 0, 0, melbourne),
+			expected: time.Date(2026, 10, 5, 2, 30, 0, 0, melbourne),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr)
+			require.NoError(t, err)
+
+			next := schedule.Next(tt.from)
+			assert.True(t, tt.expected.Equal(next), "expected %s, got %s", tt.expected, next)
+		})
+	}
+}
+
+func TestCronScheduleNextNeverMatches(t *testing.T) {
+	schedule, err := ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+
+	assert.True(t, schedule.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero())
+}
@@ -0,0 +1,81 @@
+-- Support pausing and resuming running jobs
+--
+-- 1. resumed_at records when a paused job was last resumed. CleanupStuckJobs
+--    measures "no progress" from this timestamp so time spent paused does not
+--    cause a resumed job to be failed as stuck.
+-- 2. update_job_progress previously flipped any job with completed tasks back
+--    to 'running'. In-flight tasks finishing after a pause would silently
+--    un-pause the job, so 'paused' is now preserved alongside terminal states.
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS resumed_at TIMESTAMPTZ;
+
+CREATE OR REPLACE FUNCTION update_job_progress()
+RETURNS TRIGGER AS $$
+DECLARE
+    job_id_to_update TEXT;
+    total_tasks INTEGER;
+    completed_count INTEGER;
+    failed_count INTEGER;
+    skipped_count INTEGER;
+    current_status TEXT;
+    new_progress REAL;
+BEGIN
+    -- Determine which job to update
+    IF TG_OP = 'DELETE' THEN
+        job_id_to_update = OLD.job_id;
+    ELSE
+        job_id_to_update = NEW.job_id;
+    END IF;
+
+    -- Get the total tasks and current status for this job
+    SELECT j.total_tasks, j.status INTO total_tasks, current_status
+    FROM jobs j
+    WHERE j.id = job_id_to_update;
+
+    -- Count completed, failed, and skipped tasks
+    SELECT
+        COUNT(*) FILTER (WHERE status = 'completed'),
+        COUNT(*) FILTER (WHERE status = 'failed'),
+        COUNT(*) FILTER (WHERE status = 'skipped')
+    INTO completed_count, failed_count, skipped_count
+    FROM tasks
+    WHERE job_id = job_id_to_update;
+
+    -- Calculate progress percentage (only count completed + failed, not skipped)
+    IF total_tasks > 0 AND (total_tasks - skipped_count) > 0 THEN
+        new_progress = (completed_count + failed_count)::REAL / (total_tasks - skipped_count)::REAL * 100.0;
+    ELSE
+        new_progress = 0.0;
+    END IF;
+
+    -- Update the job with new counts and progress
+    UPDATE jobs
+    SET
+        completed_tasks = completed_count,
+        failed_tasks = failed_count,
+        skipped_tasks = skipped_count,
+        progress = new_progress,
+        status = CASE
+            -- Preserve terminal states and paused jobs - only explicit actions change these
+            WHEN current_status IN ('cancelled', 'failed', 'paused') THEN current_status
+            WHEN new_progress >= 100.0 THEN 'completed'
+            WHEN completed_count > 0 OR failed_count > 0 THEN 'running'
+            ELSE status
+        END
+    WHERE id = job_id_to_update;
+
+    -- Return the appropriate record based on operation
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    ELSE
+        RETURN NEW;
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION update_job_progress() IS
+  'Updates job progress counters when task status changes.
+   Preserves terminal states (cancelled, failed) and paused jobs to prevent race conditions.
+
+   Updated in migration: 20260302090000
+   Issue: In-flight tasks completing after pause flipped the job back to running';
@@ -0,0 +1,10 @@
+-- Link retry jobs back to the job whose failed tasks they re-run
+ALTER TABLE jobs
+  ADD COLUMN IF NOT EXISTS parent_job_id TEXT REFERENCES jobs(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_parent_job_id
+  ON jobs(parent_job_id)
+  WHERE parent_job_id IS NOT NULL;
+
+COMMENT ON COLUMN jobs.parent_job_id IS
+  'Original job when this job was created by POST /v1/jobs/:id/retry-failed';
@@ -0,0 +1,24 @@
+-- Allow schedulers to run on a cron expression in a specific time zone
+--
+-- Schedulers previously only supported fixed 6/12/24/48 hour intervals
+-- measured from creation time. A scheduler now uses either an interval or a
+-- five-field cron expression evaluated in an IANA time zone (e.g.
+-- "0 6 * * 1-5" in Australia/Melbourne for weekday mornings).
+
+ALTER TABLE schedulers ADD COLUMN IF NOT EXISTS cron_expression TEXT;
+ALTER TABLE schedulers ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE schedulers ALTER COLUMN schedule_interval_hours DROP NOT NULL;
+
+ALTER TABLE schedulers DROP CONSTRAINT IF EXISTS schedulers_schedule_interval_hours_check;
+ALTER TABLE schedulers ADD CONSTRAINT schedulers_schedule_interval_hours_check
+    CHECK (schedule_interval_hours IS NULL OR schedule_interval_hours IN (6, 12, 24, 48));
+
+-- Exactly one of interval or cron expression must be set
+ALTER TABLE schedulers DROP CONSTRAINT IF EXISTS schedulers_schedule_mode_check;
+ALTER TABLE schedulers ADD CONSTRAINT schedulers_schedule_mode_check
+    CHECK ((schedule_interval_hours IS NULL) <> (cron_expression IS NULL));
+
+COMMENT ON COLUMN schedulers.schedule_interval_hours IS 'Recurring job interval in hours (6, 12, 24, or 48). NULL when cron_expression is set';
+COMMENT ON COLUMN schedulers.cron_expression IS 'Five-field cron expression evaluated in timezone. NULL when schedule_interval_hours is set';
+COMMENT ON COLUMN schedulers.timezone IS 'IANA time zone used to evaluate cron_expression';
@@ -0,0 +1,23 @@
+-- Leader election for background loops
+--
+-- Every app instance runs the job scheduler and health monitoring loops.
+-- Instances now compete for a named lease row: the holder renews it before
+-- expires_at and only the holder runs the loops. Leases are used instead of
+-- session advisory locks because connections go through Supabase's
+-- transaction-mode pooler, which does not pin sessions to a backend.
+
+CREATE TABLE IF NOT EXISTS leader_leases (
+    name TEXT PRIMARY KEY,
+    holder_id TEXT NOT NULL,
+    acquired_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    renewed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- Backend-only table: RLS enabled with no policies so only the service role
+-- can read or write leases
+ALTER TABLE leader_leases ENABLE ROW LEVEL SECURITY;
+
+COMMENT ON TABLE leader_leases IS 'Named leadership leases used to elect a single instance for background loops';
+COMMENT ON COLUMN leader_leases.holder_id IS 'Instance identity (Fly machine ID or hostname, plus PID)';
+COMMENT ON COLUMN leader_leases.expires_at IS 'Lease is free to take over once this time has passed without renewal';
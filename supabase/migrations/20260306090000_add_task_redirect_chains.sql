@@ -0,0 +1,16 @@
+-- Record full redirect chains per task
+--
+-- tasks.redirect_url only holds the final destination (and only when it is a
+-- significant redirect). The crawler now records every hop so chained
+-- redirects after site migrations can be reported:
+--   redirect_chain          - JSON array of {url, status_code, location, duration_ms}
+--   redirect_loop           - the chain revisited a URL and was stopped
+--   redirect_limit_exceeded - the chain hit the crawler's redirect hop limit
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS redirect_chain JSONB;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS redirect_loop BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS redirect_limit_exceeded BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN tasks.redirect_chain IS 'Redirect hops followed for this task (NULL when the URL did not redirect)';
+COMMENT ON COLUMN tasks.redirect_loop IS 'True when the redirect chain revisited a URL';
+COMMENT ON COLUMN tasks.redirect_limit_exceeded IS 'True when the redirect chain reached the crawler hop limit';
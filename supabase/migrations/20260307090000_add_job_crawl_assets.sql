@@ -0,0 +1,10 @@
+-- Opt-in asset crawling per job
+--
+-- When enabled, pages also queue the images, stylesheets, scripts, media and
+-- fonts they reference as tasks with source_type = 'asset'. Asset tasks are
+-- fetched with a HEAD request (GET only when warming is needed) and never
+-- have their links extracted.
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS crawl_assets BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN jobs.crawl_assets IS 'Queue assets referenced by crawled pages as lightweight asset tasks';
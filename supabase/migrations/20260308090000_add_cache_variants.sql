@@ -0,0 +1,13 @@
+-- Per-job cache variant warming
+--
+-- A job can define a matrix of devices, Accept-Encoding values and
+-- Accept-Language values. Each page is re-requested once per combination so
+-- CDN caches that vary on those headers are warmed for every variant, and the
+-- per-variant cache status and timings are stored on the task.
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS warm_variants JSONB;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS cache_variants JSONB;
+
+COMMENT ON COLUMN jobs.warm_variants IS 'Variant matrix to warm: {"devices": [...], "encodings": [...], "languages": [...]}';
+COMMENT ON COLUMN tasks.cache_variants IS 'Per-variant cache status and response times recorded while warming';
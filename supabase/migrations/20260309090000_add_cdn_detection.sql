@@ -0,0 +1,14 @@
+-- CDN provider detection
+--
+-- The crawler now identifies which CDN served each response through a
+-- registry of detectors. The detected provider is stored per task, and
+-- organisations can add their own header-to-status mappings for CDNs the
+-- built-in detectors don't recognise.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS cdn_provider TEXT;
+
+ALTER TABLE organisations
+  ADD COLUMN IF NOT EXISTS cache_header_mappings JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+COMMENT ON COLUMN tasks.cdn_provider IS 'CDN detected from the response headers, e.g. cloudflare, fastly, netlify';
+COMMENT ON COLUMN organisations.cache_header_mappings IS 'Custom cache status mappings: [{"header": "...", "provider": "...", "values": {"substring": "HIT"}}]';
@@ -0,0 +1,19 @@
+-- Keep-warm schedulers
+--
+-- Each completed task now records when its cached copy is expected to leave
+-- the edge cache. Schedulers in keep-warm mode periodically enqueue just the
+-- pages about to go cold, between their regular full crawls.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS cache_expires_at TIMESTAMPTZ;
+
+ALTER TABLE schedulers
+  ADD COLUMN IF NOT EXISTS keep_warm BOOLEAN NOT NULL DEFAULT FALSE,
+  ADD COLUMN IF NOT EXISTS last_keep_warm_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_schedulers_keep_warm
+  ON schedulers(last_keep_warm_at)
+  WHERE keep_warm = TRUE AND is_enabled = TRUE;
+
+COMMENT ON COLUMN tasks.cache_expires_at IS 'When the warmed object is expected to expire from the CDN cache (completion time + remaining TTL)';
+COMMENT ON COLUMN schedulers.keep_warm IS 'Re-warm individual pages shortly before their edge TTL expires';
+COMMENT ON COLUMN schedulers.last_keep_warm_at IS 'When the keep-warm loop last checked this scheduler for expiring pages';
@@ -0,0 +1,136 @@
+-- CDN purge integration with Supabase Vault
+-- Following the Webflow and Google Analytics integration pattern
+--
+-- Organisations connect a Cloudflare zone or Fastly service to one of their
+-- domains so warming jobs can purge the CDN first. API tokens live in Vault.
+
+-- ============================================================================
+-- 1. Create cdn_connections table
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS cdn_connections (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+  domain_id INTEGER NOT NULL REFERENCES domains(id) ON DELETE CASCADE,
+  provider TEXT NOT NULL CHECK (provider IN ('cloudflare', 'fastly')),
+  zone_id TEXT NOT NULL,                  -- Cloudflare zone ID or Fastly service ID
+  purge_on_publish BOOLEAN NOT NULL DEFAULT FALSE,
+  vault_secret_name TEXT,                 -- Name of secret in Supabase Vault (stores API token)
+  installing_user_id UUID REFERENCES users(id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  -- One connection per org, domain and provider
+  UNIQUE(organisation_id, domain_id, provider)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cdn_connections_org ON cdn_connections(organisation_id);
+
+-- Enable RLS
+ALTER TABLE cdn_connections ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "cdn_connections_select_own_org" ON cdn_connections
+  FOR SELECT USING (organisation_id IN (SELECT public.user_organisations()));
+
+CREATE POLICY "cdn_connections_delete_own_org" ON cdn_connections
+  FOR DELETE USING (organisation_id IN (SELECT public.user_organisations()));
+
+-- Updated at trigger
+CREATE TRIGGER update_cdn_connections_updated_at
+  BEFORE UPDATE ON cdn_connections
+  FOR EACH ROW
+  EXECUTE FUNCTION public.update_updated_at_column();
+
+COMMENT ON COLUMN cdn_connections.zone_id IS 'Cloudflare zone ID or Fastly service ID';
+COMMENT ON COLUMN cdn_connections.purge_on_publish IS 'Purge the whole zone before Webflow publish warming jobs';
+
+-- ============================================================================
+-- 2. Vault helper functions for CDN API tokens
+-- ============================================================================
+-- These are only called by the backend (service_role), which authorises the
+-- request before storing or reading a token. Authenticated users can never
+-- read a token back.
+
+CREATE OR REPLACE FUNCTION store_cdn_token(connection_id UUID, token TEXT)
+RETURNS TEXT AS $$
+DECLARE
+  secret_name TEXT;
+  existing_secret_id UUID;
+BEGIN
+  IF auth.uid() IS NOT NULL THEN
+    RAISE EXCEPTION 'Access denied: CDN tokens can only be stored by the backend';
+  END IF;
+
+  secret_name := 'cdn_token_' || connection_id::TEXT;
+
+  SELECT id INTO existing_secret_id
+  FROM vault.secrets
+  WHERE name = secret_name;
+
+  IF existing_secret_id IS NOT NULL THEN
+    PERFORM vault.update_secret(existing_secret_id, token, secret_name, NULL);
+  ELSE
+    PERFORM vault.create_secret(token, secret_name);
+  END IF;
+
+  UPDATE cdn_connections
+  SET vault_secret_name = secret_name
+  WHERE id = connection_id;
+
+  RETURN secret_name;
+EXCEPTION
+  WHEN unique_violation THEN
+    -- Race condition: another call created the secret
+    SELECT id INTO existing_secret_id
+    FROM vault.secrets
+    WHERE name = secret_name;
+
+    IF existing_secret_id IS NOT NULL THEN
+      PERFORM vault.update_secret(existing_secret_id, token, secret_name, NULL);
+    END IF;
+
+    UPDATE cdn_connections
+    SET vault_secret_name = secret_name
+    WHERE id = connection_id;
+
+    RETURN secret_name;
+END;
+$$ LANGUAGE plpgsql SECURITY DEFINER SET search_path = public, vault;
+
+CREATE OR REPLACE FUNCTION get_cdn_token(connection_id UUID)
+RETURNS TEXT AS $$
+DECLARE
+  token TEXT;
+BEGIN
+  IF auth.uid() IS NOT NULL THEN
+    RAISE EXCEPTION 'Access denied: CDN tokens can only be read by the backend';
+  END IF;
+
+  SELECT decrypted_secret INTO token
+  FROM vault.decrypted_secrets
+  WHERE name = 'cdn_token_' || connection_id::TEXT;
+
+  RETURN token;
+END;
+$$ LANGUAGE plpgsql SECURITY DEFINER SET search_path = public, vault;
+
+-- Auto-delete vault secret when a connection is deleted
+CREATE OR REPLACE FUNCTION cleanup_cdn_vault_secret()
+RETURNS TRIGGER AS $$
+BEGIN
+  DELETE FROM vault.secrets WHERE name = 'cdn_token_' || OLD.id::TEXT;
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql SECURITY DEFINER SET search_path = public, vault;
+
+CREATE TRIGGER on_cdn_connection_delete
+  BEFORE DELETE ON cdn_connections
+  FOR EACH ROW
+  EXECUTE FUNCTION cleanup_cdn_vault_secret();
+
+ALTER FUNCTION store_cdn_token(UUID, TEXT) OWNER TO postgres;
+ALTER FUNCTION get_cdn_token(UUID) OWNER TO postgres;
+ALTER FUNCTION cleanup_cdn_vault_secret() OWNER TO postgres;
+
+REVOKE EXECUTE ON FUNCTION store_cdn_token(UUID, TEXT) FROM PUBLIC;
+REVOKE EXECUTE ON FUNCTION get_cdn_token(UUID) FROM PUBLIC;
+GRANT EXECUTE ON FUNCTION store_cdn_token(UUID, TEXT) TO service_role;
+GRANT EXECUTE ON FUNCTION get_cdn_token(UUID) TO service_role;
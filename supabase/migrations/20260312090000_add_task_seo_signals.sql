@@ -0,0 +1,13 @@
+-- Canonical, robots directive and hreflang extraction
+--
+-- HTML tasks now record the page's canonical URL, meta robots and
+-- X-Robots-Tag directives and hreflang alternates. Link discovery skips pages
+-- marked noindex or nofollow, and rel="nofollow" links, unless the job sets
+-- ignore_robots_directives.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS seo JSONB;
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS ignore_robots_directives BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN tasks.seo IS 'Canonical URL, meta robots, X-Robots-Tag, noindex/nofollow flags and hreflang alternates; NULL for non-HTML responses';
+COMMENT ON COLUMN jobs.ignore_robots_directives IS 'Follow links from noindex/nofollow pages and rel="nofollow" links during discovery';
@@ -0,0 +1,39 @@
+-- On-page SEO audit
+--
+-- HTML tasks now record their title, meta description, H1 count, word count,
+-- image alt coverage, Open Graph tags and JSON-LD blocks, plus the page-level
+-- findings derived from them. task_findings holds those findings together
+-- with job-wide ones (duplicate titles and descriptions), one row per finding,
+-- and is rebuilt for a job when its seo-audit export is requested.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS page_audit JSONB;
+
+COMMENT ON COLUMN tasks.page_audit IS 'Title, meta description, H1 count, word count, image alt coverage, Open Graph tags, JSON-LD count and page-level findings; NULL for non-HTML responses';
+
+CREATE TABLE IF NOT EXISTS task_findings (
+  id BIGSERIAL PRIMARY KEY,
+  job_id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+  task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  code TEXT NOT NULL,                     -- e.g. missing_title, duplicate_description
+  severity TEXT NOT NULL CHECK (severity IN ('error', 'warning', 'notice')),
+  detail TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_findings_job_code ON task_findings(job_id, code);
+CREATE INDEX IF NOT EXISTS idx_task_findings_task ON task_findings(task_id);
+
+-- Enable RLS
+ALTER TABLE task_findings ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "task_findings_select_own_org" ON task_findings
+  FOR SELECT USING (
+    EXISTS (
+      SELECT 1
+      FROM jobs
+      WHERE jobs.id = task_findings.job_id
+        AND jobs.organisation_id IN (SELECT public.user_organisations())
+    )
+  );
+
+COMMENT ON TABLE task_findings IS 'SEO audit findings per task, rebuilt from tasks.page_audit plus job-wide duplicate checks';
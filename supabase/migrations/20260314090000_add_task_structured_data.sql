@@ -0,0 +1,10 @@
+-- Structured data extraction and validation
+--
+-- HTML tasks now record every JSON-LD entity and top-level microdata item,
+-- validated against a bundled subset of schema.org required properties
+-- (Article, Product, BreadcrumbList, Organization, FAQPage), with malformed
+-- JSON and missing properties reported per page.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS structured_data JSONB;
+
+COMMENT ON COLUMN tasks.structured_data IS 'JSON-LD and microdata items (format, type, properties) and validation issues; NULL for non-HTML responses';
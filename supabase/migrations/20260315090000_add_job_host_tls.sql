@@ -0,0 +1,38 @@
+-- TLS certificate audit
+--
+-- Workers capture the negotiated protocol, cipher suite and certificate chain
+-- from the first HTTPS response for each host in a job. When the leaf
+-- certificate is close to expiry the job's organisation gets a
+-- certificate_expiring notification, once per certificate.
+
+CREATE TABLE IF NOT EXISTS job_host_tls (
+  id BIGSERIAL PRIMARY KEY,
+  job_id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+  host TEXT NOT NULL,
+  tls_version TEXT NOT NULL,              -- e.g. 'TLS 1.3'
+  cipher_suite TEXT NOT NULL,
+  subject TEXT NOT NULL,                  -- Leaf certificate
+  issuer TEXT NOT NULL,
+  dns_names TEXT[] NOT NULL DEFAULT '{}',
+  not_after TIMESTAMPTZ NOT NULL,
+  certificate_chain JSONB,                -- Served chain, leaf first
+  captured_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (job_id, host)
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_host_tls_not_after ON job_host_tls(not_after);
+
+-- Enable RLS
+ALTER TABLE job_host_tls ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "job_host_tls_select_own_org" ON job_host_tls
+  FOR SELECT USING (
+    EXISTS (
+      SELECT 1
+      FROM jobs
+      WHERE jobs.id = job_host_tls.job_id
+        AND jobs.organisation_id IN (SELECT public.user_organisations())
+    )
+  );
+
+COMMENT ON TABLE job_host_tls IS 'TLS protocol, cipher and certificate chain captured from the first HTTPS response per host in a job';
@@ -0,0 +1,10 @@
+-- Mixed-content and insecure-link detection
+--
+-- HTTPS pages now record any http:// subresource (images, scripts,
+-- stylesheets, iframes, media) or form action, plus links back to http://
+-- versions of the site, each with the element it came from. The
+-- mixed-content job export lists the affected pages.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS insecure_content JSONB;
+
+COMMENT ON COLUMN tasks.insecure_content IS 'http:// subresources, form actions and internal links (url, element, kind) found on an HTTPS page; NULL when there are none';
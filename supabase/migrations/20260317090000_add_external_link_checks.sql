@@ -0,0 +1,56 @@
+-- External link checking
+--
+-- Jobs can opt in to checking links that point at other sites. Each external
+-- link is checked once per job with a HEAD request (falling back to GET) and
+-- is never warmed or crawled. Results are cached across jobs so popular
+-- destinations are not re-checked by every job that links to them.
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS check_external_links BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN jobs.check_external_links IS 'Check links to other sites for broken responses (opt-in)';
+
+CREATE TABLE IF NOT EXISTS job_external_links (
+  id BIGSERIAL PRIMARY KEY,
+  job_id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+  url TEXT NOT NULL,
+  host TEXT NOT NULL,
+  source_url TEXT,                        -- First page found linking to it
+  status_code INTEGER,
+  error TEXT,
+  response_time BIGINT,                   -- Milliseconds
+  cached BOOLEAN NOT NULL DEFAULT FALSE,  -- Result reused from external_link_checks
+  checked_at TIMESTAMPTZ,                 -- NULL until checked
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (job_id, url)
+);
+
+-- Enable RLS
+ALTER TABLE job_external_links ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "job_external_links_select_own_org" ON job_external_links
+  FOR SELECT USING (
+    EXISTS (
+      SELECT 1
+      FROM jobs
+      WHERE jobs.id = job_external_links.job_id
+        AND jobs.organisation_id IN (SELECT public.user_organisations())
+    )
+  );
+
+COMMENT ON TABLE job_external_links IS 'External links found by a job and the result of checking each one';
+
+-- Cross-job cache, written and read by workers only
+CREATE TABLE IF NOT EXISTS external_link_checks (
+  url TEXT PRIMARY KEY,
+  status_code INTEGER,
+  error TEXT,
+  response_time BIGINT,
+  checked_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_external_link_checks_checked_at ON external_link_checks(checked_at);
+
+-- Enable RLS (no policies: service role only)
+ALTER TABLE external_link_checks ENABLE ROW LEVEL SECURITY;
+
+COMMENT ON TABLE external_link_checks IS 'Most recent check of each external link, reused by other jobs within the cache TTL';
@@ -0,0 +1,52 @@
+-- Fragment and anchor validation
+--
+-- With find_links enabled, crawled HTML pages record their anchors (every id
+-- attribute and named <a>) and the links they contain to sections of pages
+-- on the same site, e.g. /pricing#enterprise. The broken-links export
+-- validates each fragment link against its target page's anchors and lists
+-- missing anchors as warnings.
+
+CREATE TABLE IF NOT EXISTS job_page_anchors (
+  job_id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+  page_key TEXT NOT NULL,                 -- Host (without www) and path
+  anchors TEXT[] NOT NULL DEFAULT '{}',
+  PRIMARY KEY (job_id, page_key)
+);
+
+CREATE TABLE IF NOT EXISTS job_fragment_links (
+  id BIGSERIAL PRIMARY KEY,
+  job_id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+  url TEXT NOT NULL,                      -- As linked, including the fragment
+  target_key TEXT NOT NULL,               -- job_page_anchors.page_key of the target page
+  fragment TEXT NOT NULL,
+  source_url TEXT,                        -- First page found linking to it
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (job_id, target_key, fragment)
+);
+
+-- Enable RLS
+ALTER TABLE job_page_anchors ENABLE ROW LEVEL SECURITY;
+ALTER TABLE job_fragment_links ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "job_page_anchors_select_own_org" ON job_page_anchors
+  FOR SELECT USING (
+    EXISTS (
+      SELECT 1
+      FROM jobs
+      WHERE jobs.id = job_page_anchors.job_id
+        AND jobs.organisation_id IN (SELECT public.user_organisations())
+    )
+  );
+
+CREATE POLICY "job_fragment_links_select_own_org" ON job_fragment_links
+  FOR SELECT USING (
+    EXISTS (
+      SELECT 1
+      FROM jobs
+      WHERE jobs.id = job_fragment_links.job_id
+        AND jobs.organisation_id IN (SELECT public.user_organisations())
+    )
+  );
+
+COMMENT ON TABLE job_page_anchors IS 'id and name anchors found on each HTML page crawled by a job';
+COMMENT ON TABLE job_fragment_links IS 'Links to sections of pages on the job''s site, validated against job_page_anchors';
@@ -0,0 +1,11 @@
+-- Soft-404 detection
+--
+-- The first page crawled on each host triggers a request for a URL that
+-- cannot exist, to fingerprint what the site serves for missing pages. Pages
+-- returned with a 200 that match that fingerprint, have a "not found" title,
+-- or carry little content saying the page was not found now fail as soft
+-- 404s and appear in the broken-links export.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS soft_404 TEXT;
+
+COMMENT ON COLUMN tasks.soft_404 IS 'Why a 200 response was failed as a soft 404: template_match, not_found_title or not_found_text; NULL otherwise';
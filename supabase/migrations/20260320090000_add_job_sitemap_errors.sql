@@ -0,0 +1,10 @@
+-- Sitemap fetch errors
+--
+-- Sitemaps are now streamed with limits on nesting, child sitemaps, URL
+-- counts and decompressed size. Sitemaps that could not be fetched or were
+-- only partly read are recorded on the job, so users can see why pages they
+-- expected were not discovered.
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS sitemap_errors JSONB;
+
+COMMENT ON COLUMN jobs.sitemap_errors IS 'Sitemaps that could not be fetched or read in full, as [{url, error}]; NULL when every sitemap was read';
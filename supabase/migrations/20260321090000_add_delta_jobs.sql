@@ -0,0 +1,48 @@
+-- Delta jobs
+--
+-- Schedulers in delta mode record each run's sitemap URLs and lastmod dates.
+-- Later runs compare the current sitemap against the previous completed run
+-- and only warm URLs that are new or whose lastmod changed (or that have no
+-- lastmod to compare). URLs that left the sitemap are reported, not crawled.
+-- A full crawl is still forced every full_crawl_every runs.
+
+ALTER TABLE schedulers
+  ADD COLUMN IF NOT EXISTS delta_mode BOOLEAN NOT NULL DEFAULT FALSE,
+  ADD COLUMN IF NOT EXISTS full_crawl_every INTEGER NOT NULL DEFAULT 7 CHECK (full_crawl_every >= 0);
+
+ALTER TABLE jobs
+  ADD COLUMN IF NOT EXISTS crawl_mode TEXT NOT NULL DEFAULT 'full' CHECK (crawl_mode IN ('full', 'delta')),
+  ADD COLUMN IF NOT EXISTS baseline_job_id TEXT REFERENCES jobs(id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS sitemap_changes JSONB;
+
+CREATE TABLE IF NOT EXISTS job_sitemap_entries (
+  job_id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+  page_id INTEGER NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+  lastmod TIMESTAMPTZ,
+  change TEXT CHECK (change IN ('new', 'changed', 'no_lastmod', 'unchanged', 'removed')), -- NULL without a baseline
+  PRIMARY KEY (job_id, page_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_scheduler_crawl_mode
+  ON jobs(scheduler_id, created_at DESC)
+  WHERE scheduler_id IS NOT NULL;
+
+-- Enable RLS
+ALTER TABLE job_sitemap_entries ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "job_sitemap_entries_select_own_org" ON job_sitemap_entries
+  FOR SELECT USING (
+    EXISTS (
+      SELECT 1
+      FROM jobs
+      WHERE jobs.id = job_sitemap_entries.job_id
+        AND jobs.organisation_id IN (SELECT public.user_organisations())
+    )
+  );
+
+COMMENT ON COLUMN schedulers.delta_mode IS 'Only warm sitemap URLs that are new or changed since the previous completed run';
+COMMENT ON COLUMN schedulers.full_crawl_every IS 'Force a full crawl every N runs in delta mode (0 = never, 1 = always)';
+COMMENT ON COLUMN jobs.crawl_mode IS 'full warms every URL found; delta only warms sitemap URLs changed since baseline_job_id';
+COMMENT ON COLUMN jobs.baseline_job_id IS 'Previous run the sitemap was compared against';
+COMMENT ON COLUMN jobs.sitemap_changes IS 'Count of sitemap URLs by change against the baseline: new, changed, no_lastmod, unchanged, removed';
+COMMENT ON TABLE job_sitemap_entries IS 'Sitemap URLs and lastmod dates seen by a job, classified against its baseline job';
@@ -0,0 +1,29 @@
+-- Conditional requests
+--
+-- Every crawl now stores the ETag and Last-Modified a page was served with.
+-- Integrity-check jobs (purpose = 'integrity') send them back as
+-- If-None-Match and If-Modified-Since, and a 304 completes the task as an
+-- unchanged, healthy page with not_modified set. Warming jobs keep sending
+-- full GETs. Pages that have never returned either validator are listed by
+-- the missing-validators export.
+
+ALTER TABLE jobs
+  ADD COLUMN IF NOT EXISTS purpose TEXT NOT NULL DEFAULT 'warm' CHECK (purpose IN ('warm', 'integrity'));
+
+ALTER TABLE pages
+  ADD COLUMN IF NOT EXISTS etag TEXT,
+  ADD COLUMN IF NOT EXISTS last_modified TEXT,
+  ADD COLUMN IF NOT EXISTS validators_checked_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS validators_seen_at TIMESTAMPTZ;
+
+ALTER TABLE tasks
+  ADD COLUMN IF NOT EXISTS etag TEXT,
+  ADD COLUMN IF NOT EXISTS last_modified TEXT,
+  ADD COLUMN IF NOT EXISTS not_modified BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN jobs.purpose IS 'warm sends full GETs to fill the edge cache; integrity sends conditional GETs and treats 304 as unchanged and healthy';
+COMMENT ON COLUMN pages.etag IS 'ETag from the latest completed crawl of the page';
+COMMENT ON COLUMN pages.last_modified IS 'Last-Modified from the latest completed crawl of the page';
+COMMENT ON COLUMN pages.validators_checked_at IS 'When a completed crawl last recorded the page''s validators';
+COMMENT ON COLUMN pages.validators_seen_at IS 'When the page last returned an ETag or Last-Modified; NULL if it never has';
+COMMENT ON COLUMN tasks.not_modified IS 'The conditional request of an integrity check got a 304';
@@ -0,0 +1,27 @@
+-- Content change tracking
+--
+-- Every completed HTML crawl now stores a hash of the page's normalised
+-- content: scripts, styles, attributes (bar link targets, image sources,
+-- alt text and a few that identify the published version, such as
+-- data-wf-page), <time> elements, machine timestamps and nonce-like tokens
+-- are stripped first, so only changes a visitor would notice alter
+-- it. Each page keeps its latest hash and a capped history of the hashes it
+-- has had, newest first. GET /v1/jobs/:id/changes compares the hashes of two
+-- jobs' tasks. When the worker has storage configured, a text diff of the
+-- page's main content is uploaded for each change and its path recorded on
+-- the task.
+
+ALTER TABLE pages
+  ADD COLUMN IF NOT EXISTS content_hash TEXT,
+  ADD COLUMN IF NOT EXISTS content_changed_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS content_hash_history JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+ALTER TABLE tasks
+  ADD COLUMN IF NOT EXISTS content_hash TEXT,
+  ADD COLUMN IF NOT EXISTS content_diff_path TEXT;
+
+COMMENT ON COLUMN pages.content_hash IS 'SHA-256 of the normalised content from the latest crawl that hashed the page';
+COMMENT ON COLUMN pages.content_changed_at IS 'When a crawl first saw the page''s current content_hash';
+COMMENT ON COLUMN pages.content_hash_history IS 'Hashes the page has had, newest first, as {hash, job_id, seen_at}; capped at 20 entries';
+COMMENT ON COLUMN tasks.content_hash IS 'SHA-256 of the page''s normalised content; NULL for non-HTML and 304 responses';
+COMMENT ON COLUMN tasks.content_diff_path IS 'Storage path of the main content diff against the page''s previous hash, if one was stored';
@@ -0,0 +1,9 @@
+-- Keep-warm page lookup
+--
+-- GetKeepWarmPages finds a scheduler's recently completed jobs by domain
+-- before reading their tasks, instead of scanning a week of tasks for every
+-- keep-warm scheduler each cycle.
+
+CREATE INDEX IF NOT EXISTS idx_jobs_domain_completed_at
+  ON jobs(domain_id, completed_at DESC)
+  WHERE completed_at IS NOT NULL;
@@ -0,0 +1,46 @@
+-- SEO audit findings on task completion
+--
+-- Page-level findings are now written to task_findings when each task
+-- completes, and duplicate title and description findings are computed when
+-- findings are read, so the seo-audit export no longer rewrites the table.
+
+CREATE OR REPLACE FUNCTION job_duplicate_findings(p_job_id TEXT)
+RETURNS TABLE (task_id TEXT, code TEXT, detail TEXT)
+LANGUAGE sql
+STABLE
+SET search_path = public
+AS $$
+  SELECT candidates.id, candidates.code, 'shared with ' || (candidates.shared - 1) || ' other page(s)'
+  FROM (
+    SELECT t.id, d.code,
+           COUNT(*) OVER (PARTITION BY d.code, LOWER(TRIM(t.page_audit->>d.field))) AS shared
+    FROM tasks t
+    CROSS JOIN (VALUES
+      ('title', 'duplicate_title'),
+      ('meta_description', 'duplicate_description')
+    ) AS d(field, code)
+    WHERE t.job_id = p_job_id
+      AND t.status = 'completed'
+      AND COALESCE(t.page_audit->>d.field, '') <> ''
+      AND COALESCE(t.redirect_url, '') = ''
+      AND COALESCE((t.seo->>'noindex')::boolean, FALSE) = FALSE
+  ) candidates
+  WHERE candidates.shared > 1
+$$;
+
+COMMENT ON FUNCTION job_duplicate_findings(TEXT) IS 'Duplicate title and description findings across a job''s completed, indexable, non-redirected pages';
+
+-- Duplicates are no longer stored
+DELETE FROM task_findings WHERE code IN ('duplicate_title', 'duplicate_description');
+
+-- Backfill page-level findings for tasks completed before this change whose
+-- job was never exported
+INSERT INTO task_findings (job_id, task_id, code, severity, detail)
+SELECT t.job_id, t.id, f->>'code', f->>'severity', NULLIF(f->>'detail', '')
+FROM tasks t
+CROSS JOIN LATERAL jsonb_array_elements(COALESCE(t.page_audit->'findings', '[]'::jsonb)) AS f
+WHERE t.status = 'completed'
+  AND t.page_audit IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM task_findings tf WHERE tf.task_id = t.id);
+
+COMMENT ON TABLE task_findings IS 'Page-level SEO audit findings per task, written from tasks.page_audit when the task completes';
@@ -0,0 +1,16 @@
+-- Queue external link checks in the database
+--
+-- External links used to wait for a checker in an in-memory queue, so links
+-- found while it was full, or still queued when a worker stopped, were never
+-- checked. Links are now stored unchecked as soon as a page is crawled and
+-- checkers claim them from the table. A claim that is not followed by a
+-- result within the claim window (e.g. the worker crashed) lapses and the
+-- link is claimed again.
+
+ALTER TABLE job_external_links ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN job_external_links.claimed_at IS 'When a checker last claimed the link; NULL until claimed';
+
+CREATE INDEX IF NOT EXISTS idx_job_external_links_unchecked
+  ON job_external_links(id)
+  WHERE checked_at IS NULL;
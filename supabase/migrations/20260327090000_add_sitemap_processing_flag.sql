@@ -0,0 +1,85 @@
+-- Keep jobs open while their sitemaps are still being read
+--
+-- Sitemap URLs are enqueued in batches as they stream in, so workers can
+-- finish every task enqueued so far before the next batch arrives. The
+-- progress trigger and the stuck-job cleanup then marked the job completed
+-- mid-discovery. Jobs now carry sitemap_processing while their sitemaps are
+-- read; neither path completes a job with the flag set.
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS sitemap_processing BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN jobs.sitemap_processing IS 'Sitemaps are still being read and enqueued; the job cannot complete until cleared';
+
+CREATE OR REPLACE FUNCTION update_job_progress()
+RETURNS TRIGGER AS $$
+DECLARE
+    job_id_to_update TEXT;
+    total_tasks INTEGER;
+    completed_count INTEGER;
+    failed_count INTEGER;
+    skipped_count INTEGER;
+    current_status TEXT;
+    discovering BOOLEAN;
+    new_progress REAL;
+BEGIN
+    -- Determine which job to update
+    IF TG_OP = 'DELETE' THEN
+        job_id_to_update = OLD.job_id;
+    ELSE
+        job_id_to_update = NEW.job_id;
+    END IF;
+
+    -- Get the total tasks, current status and discovery state for this job
+    SELECT j.total_tasks, j.status, j.sitemap_processing INTO total_tasks, current_status, discovering
+    FROM jobs j
+    WHERE j.id = job_id_to_update;
+
+    -- Count completed, failed, and skipped tasks
+    SELECT
+        COUNT(*) FILTER (WHERE status = 'completed'),
+        COUNT(*) FILTER (WHERE status = 'failed'),
+        COUNT(*) FILTER (WHERE status = 'skipped')
+    INTO completed_count, failed_count, skipped_count
+    FROM tasks
+    WHERE job_id = job_id_to_update;
+
+    -- Calculate progress percentage (only count completed + failed, not skipped)
+    IF total_tasks > 0 AND (total_tasks - skipped_count) > 0 THEN
+        new_progress = (completed_count + failed_count)::REAL / (total_tasks - skipped_count)::REAL * 100.0;
+    ELSE
+        new_progress = 0.0;
+    END IF;
+
+    -- Update the job with new counts and progress
+    UPDATE jobs
+    SET
+        completed_tasks = completed_count,
+        failed_tasks = failed_count,
+        skipped_tasks = skipped_count,
+        progress = new_progress,
+        status = CASE
+            -- Preserve terminal states and paused jobs - only explicit actions change these
+            WHEN current_status IN ('cancelled', 'failed', 'paused') THEN current_status
+            -- More sitemap URLs may still be enqueued
+            WHEN new_progress >= 100.0 AND NOT COALESCE(discovering, FALSE) THEN 'completed'
+            WHEN completed_count > 0 OR failed_count > 0 THEN 'running'
+            ELSE status
+        END
+    WHERE id = job_id_to_update;
+
+    -- Return the appropriate record based on operation
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    ELSE
+        RETURN NEW;
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION update_job_progress() IS
+  'Updates job progress counters when task status changes.
+   Preserves terminal states (cancelled, failed) and paused jobs to prevent race conditions.
+   Does not complete jobs whose sitemaps are still being processed.
+
+   Updated in migration: 20260327090000
+   Issue: Jobs completed mid-discovery when workers caught up with streamed sitemap batches';
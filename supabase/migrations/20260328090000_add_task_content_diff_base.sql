@@ -0,0 +1,10 @@
+-- Record which version a stored content diff starts from
+--
+-- A task's content diff compares the page with its previous crawl, which is
+-- not necessarily in the job a change report compares against. Storing the
+-- hash the diff starts from lets GET /v1/jobs/:id/changes only return a
+-- diff when it spans exactly the two versions being compared.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS content_diff_base_hash TEXT;
+
+COMMENT ON COLUMN tasks.content_diff_base_hash IS 'Content hash of the version content_diff_path is diffed against';
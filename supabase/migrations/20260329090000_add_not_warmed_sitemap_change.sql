@@ -0,0 +1,14 @@
+-- Re-warm pages the baseline run did not warm
+--
+-- Delta runs now also warm unchanged sitemap URLs whose baseline task did not
+-- complete, such as failed tasks or tasks skipped by max_pages (not_warmed),
+-- or whose content has changed since (changed). The index on
+-- jobs(scheduler_id, created_at) is renamed to match what it covers.
+
+ALTER TABLE job_sitemap_entries DROP CONSTRAINT IF EXISTS job_sitemap_entries_change_check;
+ALTER TABLE job_sitemap_entries ADD CONSTRAINT job_sitemap_entries_change_check
+  CHECK (change IN ('new', 'changed', 'no_lastmod', 'not_warmed', 'unchanged', 'removed'));
+
+ALTER INDEX IF EXISTS idx_jobs_scheduler_crawl_mode RENAME TO idx_jobs_scheduler_created_at;
+
+COMMENT ON COLUMN jobs.sitemap_changes IS 'Count of sitemap URLs by change against the baseline: new, changed, no_lastmod, not_warmed, unchanged, removed';